@@ -1,6 +1,9 @@
 package abstraction
 
 import (
+	"cleancare/pkg/i18n"
+	"context"
+
 	"github.com/labstack/echo/v4"
 	"gorm.io/gorm"
 )
@@ -16,8 +19,64 @@ type AuthContext struct {
 	RoleID    int
 	Email     string
 	UuidLogin string
+	Language  string
 }
 
 type TrxContext struct {
 	Db *gorm.DB
 }
+
+// Lang resolves the language of the request: the lang query param first,
+// then the language stored for the logged in user, then Accept-Language.
+func (c *Context) Lang() string {
+	return c.langOr(i18n.Default)
+}
+
+// MessageLang is Lang for the API messages, they stay in English without a preference.
+func (c *Context) MessageLang() string {
+	return c.langOr(i18n.MessageDefault)
+}
+
+func (c *Context) langOr(fallback string) string {
+	if c == nil || c.Context == nil {
+		return fallback
+	}
+	if lang := i18n.Normalize(c.QueryParam("lang")); lang != "" {
+		return lang
+	}
+	if c.Auth != nil {
+		if lang := i18n.Normalize(c.Auth.Language); lang != "" {
+			return lang
+		}
+	}
+	return i18n.FromRequestOr(c.Request(), fallback)
+}
+
+type contextKey struct{}
+
+// StdContext returns a context.Context carrying c, it is attached to every query so
+// GORM callbacks can tell who made the change.
+func (c *Context) StdContext() context.Context {
+	parent := context.Background()
+	if c.Context != nil && c.Request() != nil {
+		parent = c.Request().Context()
+	}
+	return context.WithValue(parent, contextKey{}, c)
+}
+
+// FromStdContext returns the Context attached by StdContext, or nil.
+func FromStdContext(ctx context.Context) *Context {
+	if ctx == nil {
+		return nil
+	}
+	c, _ := ctx.Value(contextKey{}).(*Context)
+	return c
+}
+
+// RequestID returns the id given to the request by the RequestID middleware.
+func (c *Context) RequestID() string {
+	if c == nil || c.Context == nil {
+		return ""
+	}
+	return c.Response().Header().Get(echo.HeaderXRequestID)
+}
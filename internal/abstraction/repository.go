@@ -14,7 +14,7 @@ type Repository struct {
 
 func (r *Repository) CheckTrx(ctx *Context) *gorm.DB {
 	if ctx.Trx != nil {
-		return ctx.Trx.Db
+		return ctx.Trx.Db.WithContext(ctx.StdContext())
 	}
-	return r.Db
+	return r.Db.WithContext(ctx.StdContext())
 }
@@ -0,0 +1,59 @@
+package audit
+
+import (
+	"cleancare/internal/abstraction"
+	"cleancare/internal/dto"
+	"cleancare/internal/factory"
+	"cleancare/pkg/util/response"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+)
+
+type handler struct {
+	service Service
+}
+
+func NewHandler(f *factory.Factory) *handler {
+	return &handler{
+		service: NewService(f),
+	}
+}
+
+func (h handler) Find(c echo.Context) (err error) {
+	data, err := h.service.Find(c.(*abstraction.Context))
+	if err != nil {
+		return response.ErrorResponse(err).SendError(c)
+	}
+	return response.SuccessResponse(data).SendSuccess(c)
+}
+
+func (h handler) FindById(c echo.Context) (err error) {
+	payload := new(dto.AuditLogFindByIDRequest)
+	if err := c.Bind(payload); err != nil {
+		return response.ErrorBuilder(http.StatusBadRequest, err, "error bind payload").SendError(c)
+	}
+	if err = c.Validate(payload); err != nil {
+		return response.ErrorBuilder(http.StatusBadRequest, err, "error validate payload").SendError(c)
+	}
+	data, err := h.service.FindById(c.(*abstraction.Context), payload)
+	if err != nil {
+		return response.ErrorResponse(err).SendError(c)
+	}
+	return response.SuccessResponse(data).SendSuccess(c)
+}
+
+func (h handler) Export(c echo.Context) (err error) {
+	payload := new(dto.AuditLogExportRequest)
+	if err := c.Bind(payload); err != nil {
+		return response.ErrorBuilder(http.StatusBadRequest, err, "error bind payload").SendError(c)
+	}
+	if err = c.Validate(payload); err != nil {
+		return response.ErrorBuilder(http.StatusBadRequest, err, "error validate payload").SendError(c)
+	}
+	filename, data, format, err := h.service.Export(c.(*abstraction.Context), payload)
+	if err != nil {
+		return response.ErrorResponse(err).SendError(c)
+	}
+	return response.SendBlobData(c, filename, *data, format)
+}
@@ -0,0 +1,13 @@
+package audit
+
+import (
+	"cleancare/internal/middleware"
+
+	"github.com/labstack/echo/v4"
+)
+
+func (h *handler) Route(v *echo.Group) {
+	v.GET("", h.Find, middleware.Authentication)
+	v.GET("/export", h.Export, middleware.Authentication)
+	v.GET("/:id", h.FindById, middleware.Authentication)
+}
@@ -0,0 +1,168 @@
+package audit
+
+import (
+	"bytes"
+	"cleancare/internal/abstraction"
+	"cleancare/internal/dto"
+	"cleancare/internal/factory"
+	"cleancare/internal/model"
+	"cleancare/internal/repository"
+	"cleancare/pkg/constant"
+	"cleancare/pkg/util/export"
+	"cleancare/pkg/util/general"
+	"cleancare/pkg/util/response"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type Service interface {
+	Find(ctx *abstraction.Context) (map[string]interface{}, error)
+	FindById(ctx *abstraction.Context, payload *dto.AuditLogFindByIDRequest) (map[string]interface{}, error)
+	Export(ctx *abstraction.Context, payload *dto.AuditLogExportRequest) (string, *bytes.Buffer, string, error)
+}
+
+type service struct {
+	AuditLogRepository repository.AuditLog
+
+	DB *gorm.DB
+}
+
+func NewService(f *factory.Factory) Service {
+	return &service{
+		AuditLogRepository: f.AuditLogRepository,
+
+		DB: f.Db,
+	}
+}
+
+func (s *service) Find(ctx *abstraction.Context) (map[string]interface{}, error) {
+	if ctx.Auth.RoleID != constant.ROLE_ID_ADMIN {
+		return nil, response.ErrorBuilder(http.StatusBadRequest, errors.New("bad_request"), "this role is not permitted")
+	}
+
+	data, err := s.AuditLogRepository.Find(ctx, false)
+	if err != nil && err.Error() != "record not found" {
+		return nil, response.ErrorBuilder(http.StatusInternalServerError, err, "server_error")
+	}
+	count, err := s.AuditLogRepository.Count(ctx)
+	if err != nil && err.Error() != "record not found" {
+		return nil, response.ErrorBuilder(http.StatusInternalServerError, err, "server_error")
+	}
+
+	var res []map[string]interface{} = nil
+	for _, v := range data {
+		res = append(res, toMap(v))
+	}
+	return map[string]interface{}{
+		"count": count,
+		"meta":  general.OffsetMeta(ctx, false, len(data), count),
+		"data":  res,
+	}, nil
+}
+
+func (s *service) FindById(ctx *abstraction.Context, payload *dto.AuditLogFindByIDRequest) (map[string]interface{}, error) {
+	if ctx.Auth.RoleID != constant.ROLE_ID_ADMIN {
+		return nil, response.ErrorBuilder(http.StatusBadRequest, errors.New("bad_request"), "this role is not permitted")
+	}
+
+	data, err := s.AuditLogRepository.FindById(ctx, payload.ID)
+	if err != nil && err.Error() != "record not found" {
+		return nil, response.ErrorBuilder(http.StatusInternalServerError, err, "server_error")
+	}
+	if data == nil {
+		return nil, response.ErrorBuilder(http.StatusBadRequest, errors.New("bad_request"), "audit log not found")
+	}
+
+	return map[string]interface{}{
+		"data": toMap(data),
+	}, nil
+}
+
+var auditExportColumns = []string{
+	"id", "created_at", "actor_id", "actor_name", "action", "entity", "entity_id", "old_values", "new_values", "ip", "request_id",
+}
+
+func (s *service) Export(ctx *abstraction.Context, payload *dto.AuditLogExportRequest) (string, *bytes.Buffer, string, error) {
+	if ctx.Auth.RoleID != constant.ROLE_ID_ADMIN {
+		return "", nil, "", response.ErrorBuilder(http.StatusBadRequest, errors.New("bad_request"), "this role is not permitted")
+	}
+
+	columns, err := export.SelectColumns(auditExportColumns, payload.Columns)
+	if err != nil {
+		return "", nil, "", response.ErrorBuilder(http.StatusBadRequest, errors.New("bad_request"), err.Error())
+	}
+
+	data, err := s.AuditLogRepository.Find(ctx, true)
+	if err != nil && err.Error() != "record not found" {
+		return "", nil, "", response.ErrorBuilder(http.StatusInternalServerError, err, "server_error")
+	}
+
+	var rows []map[string]interface{}
+	for _, v := range data {
+		row := toMap(v)
+		row["actor_name"] = row["actor"].(map[string]interface{})["name"]
+		row["old_values"] = stringOrEmpty(v.OldValues)
+		row["new_values"] = stringOrEmpty(v.NewValues)
+		rows = append(rows, row)
+	}
+
+	buf, err := export.Write(payload.Format, columns, rows)
+	if err != nil {
+		return "", nil, "", response.ErrorBuilder(http.StatusInternalServerError, err, "server_error")
+	}
+
+	filename := fmt.Sprintf("CleanCare - Audit Log %s.%s", time.Now().Format("2006-01-02"), payload.Format)
+	return filename, buf, payload.Format, nil
+}
+
+func toMap(v *model.AuditLogEntityModel) map[string]interface{} {
+	actor := map[string]interface{}{
+		"id":        nil,
+		"name":      nil,
+		"number_id": nil,
+	}
+	if v.Actor != nil {
+		actor["id"] = v.Actor.ID
+		actor["name"] = v.Actor.Name
+		actor["number_id"] = v.Actor.NumberId
+	}
+	return map[string]interface{}{
+		"id":         v.ID,
+		"actor_id":   intOrNil(v.ActorId),
+		"actor":      actor,
+		"action":     v.Action,
+		"entity":     v.Entity,
+		"entity_id":  intOrNil(v.EntityId),
+		"old_values": rawJSON(v.OldValues),
+		"new_values": rawJSON(v.NewValues),
+		"ip":         stringOrEmpty(v.Ip),
+		"request_id": stringOrEmpty(v.RequestId),
+		"created_at": general.FormatWithZWithoutChangingTime(v.CreatedAt),
+	}
+}
+
+func rawJSON(val *string) interface{} {
+	if val == nil {
+		return nil
+	}
+	return json.RawMessage(*val)
+}
+
+func intOrNil(val *int) interface{} {
+	if val == nil {
+		return nil
+	}
+	return *val
+}
+
+func stringOrEmpty(val *string) string {
+	if val == nil {
+		return ""
+	}
+	return *val
+}
@@ -4,6 +4,9 @@ import (
 	"cleancare/internal/abstraction"
 	"cleancare/internal/dto"
 	"cleancare/internal/factory"
+	"cleancare/pkg/constant"
+	"cleancare/pkg/i18n"
+	"cleancare/pkg/password"
 	"cleancare/pkg/util/general"
 	"cleancare/pkg/util/response"
 	"net/http"
@@ -68,23 +71,95 @@ func (h *handler) SendEmailForgotPassword(c echo.Context) error {
 	return response.SuccessResponse(data).SendSuccess(c)
 }
 
-func (h *handler) ValidationResetPassword(c echo.Context) error {
-	payload := new(dto.AuthValidationResetPasswordRequest)
+func (h *handler) ResetPasswordForm(c echo.Context) error {
+	payload := new(dto.AuthResetPasswordFormRequest)
 	if err := c.Bind(payload); err != nil {
 		return response.ErrorBuilder(http.StatusBadRequest, err, "error bind payload").SendError(c)
 	}
 	if err := c.Validate(payload); err != nil {
 		return response.ErrorBuilder(http.StatusBadRequest, err, "error validate payload").SendError(c)
 	}
-	data, err := h.service.ValidationResetPassword(c.(*abstraction.Context), payload)
+	cc := c.(*abstraction.Context)
+	data, err := h.service.ResetPasswordForm(cc, payload)
 	if err != nil {
-		htmlContent := general.ProcessHTMLResponseEmail("assets/html/webview/reset_password_failed.html", "{{.Error}}", err.Error())
-		return c.HTML(200, htmlContent)
+		return c.HTML(200, resetPasswordFailed(cc, err))
+	}
+	return c.HTML(200, resetPasswordForm(cc, payload.Token, data, nil))
+}
+
+func (h *handler) ResetPassword(c echo.Context) error {
+	payload := new(dto.AuthResetPasswordRequest)
+	if err := c.Bind(payload); err != nil {
+		return response.ErrorBuilder(http.StatusBadRequest, err, "error bind payload").SendError(c)
+	}
+	if err := c.Validate(payload); err != nil {
+		return response.ErrorBuilder(http.StatusBadRequest, err, "error validate payload").SendError(c)
+	}
+	cc := c.(*abstraction.Context)
+	data, err := h.service.ResetPassword(cc, payload)
+	if err != nil {
+		// a password that does not pass the policy keeps the form open so it can be retried
+		if e, ok := err.(*response.MetaError); ok && e.Code == http.StatusBadRequest {
+			email, errForm := h.service.ResetPasswordForm(cc, &dto.AuthResetPasswordFormRequest{Token: payload.Token})
+			if errForm != nil {
+				return c.HTML(200, resetPasswordFailed(cc, errForm))
+			}
+			var messages []string
+			for _, v := range e.Fields() {
+				messages = append(messages, v.Message)
+			}
+			if messages == nil {
+				message, _ := e.Data.(map[string]interface{})["message"].(string)
+				messages = append(messages, message)
+			}
+			return c.HTML(200, resetPasswordForm(cc, payload.Token, email, messages))
+		}
+		return c.HTML(200, resetPasswordFailed(cc, err))
 	}
 	htmlContent := general.ProcessHTMLResponseEmail("assets/html/webview/reset_password_success.html", "{{.Data}}", data)
 	return c.HTML(200, htmlContent)
 }
 
+func resetPasswordForm(c *abstraction.Context, token, email string, messages []string) string {
+	lang := c.Lang()
+	for i, v := range messages {
+		messages[i] = i18n.Message(lang, v)
+	}
+
+	policy := password.CurrentPolicy()
+	rules := []string{i18n.T(lang, "webview.reset_password.min_length", policy.MinLength)}
+	if policy.RequireUpper {
+		rules = append(rules, i18n.Message(lang, "password must contain an uppercase letter"))
+	}
+	if policy.RequireLower {
+		rules = append(rules, i18n.Message(lang, "password must contain a lowercase letter"))
+	}
+	if policy.RequireNumber {
+		rules = append(rules, i18n.Message(lang, "password must contain a number"))
+	}
+	if policy.RequireSymbol {
+		rules = append(rules, i18n.Message(lang, "password must contain a symbol"))
+	}
+
+	return general.ParseTemplateEmailToHtml("assets/html/webview/reset_password_form.html", lang, struct {
+		EMAIL     string
+		ACTION    string
+		MINLENGTH int
+		ERRORS    []string
+		RULES     []string
+	}{
+		EMAIL:     email,
+		ACTION:    constant.BASE_URL + "/auth/reset-password/" + token,
+		MINLENGTH: policy.MinLength,
+		ERRORS:    messages,
+		RULES:     rules,
+	})
+}
+
+func resetPasswordFailed(c *abstraction.Context, err error) string {
+	return general.ProcessHTMLResponseEmail("assets/html/webview/reset_password_failed.html", "{{.Error}}", i18n.Message(c.Lang(), err.Error()))
+}
+
 func (h *handler) VerifyNumber(c echo.Context) error {
 	payload := new(dto.AuthVerifyNumberRequest)
 	if err := c.Bind(payload); err != nil {
@@ -124,3 +199,43 @@ func (h *handler) Register(c echo.Context) error {
 	}
 	return response.SuccessResponse(data).SendSuccess(c)
 }
+
+func (h *handler) SendEmailVerification(c echo.Context) error {
+	payload := new(dto.AuthSendEmailVerificationRequest)
+	if err := c.Bind(payload); err != nil {
+		return response.ErrorBuilder(http.StatusBadRequest, err, "error bind payload").SendError(c)
+	}
+	if err := c.Validate(payload); err != nil {
+		return response.ErrorBuilder(http.StatusBadRequest, err, "error validate payload").SendError(c)
+	}
+	data, err := h.service.SendEmailVerification(c.(*abstraction.Context), payload)
+	if err != nil {
+		return response.ErrorResponse(err).SendError(c)
+	}
+	return response.SuccessResponse(data).SendSuccess(c)
+}
+
+func (h *handler) VerifyEmail(c echo.Context) error {
+	payload := new(dto.AuthVerifyEmailRequest)
+	if err := c.Bind(payload); err != nil {
+		return response.ErrorBuilder(http.StatusBadRequest, err, "error bind payload").SendError(c)
+	}
+	if err := c.Validate(payload); err != nil {
+		return response.ErrorBuilder(http.StatusBadRequest, err, "error validate payload").SendError(c)
+	}
+	cc := c.(*abstraction.Context)
+	lang := cc.Lang()
+	data, err := h.service.VerifyEmail(cc, payload)
+	if err != nil {
+		return c.HTML(200, general.ParseTemplateEmailToHtml("assets/html/webview/verify_email_failed.html", lang, struct {
+			ERROR string
+		}{
+			ERROR: i18n.Message(lang, err.Error()),
+		}))
+	}
+	return c.HTML(200, general.ParseTemplateEmailToHtml("assets/html/webview/verify_email_success.html", lang, struct {
+		EMAIL string
+	}{
+		EMAIL: data,
+	}))
+}
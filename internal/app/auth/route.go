@@ -11,7 +11,10 @@ func (h *handler) Route(v *echo.Group) {
 	v.POST("/logout", h.Logout, middleware.Logout)
 	v.POST("/refresh-token", h.RefreshToken, middleware.RefreshToken)
 	v.POST("/send-email/forgot-password", h.SendEmailForgotPassword, middleware.ResetPasswordIpCheck)
-	v.GET("/validation/reset-password/:token", h.ValidationResetPassword)
+	v.GET("/reset-password/:token", h.ResetPasswordForm)
+	v.POST("/reset-password/:token", h.ResetPassword, middleware.ResetPasswordIpCheck)
 	v.POST("/verify-number", h.VerifyNumber, middleware.VerifyNumberIpCheck)
 	v.POST("/register", h.Register, middleware.RegisterIpCheck)
+	v.POST("/send-email/verification", h.SendEmailVerification, middleware.VerifyEmailIpCheck)
+	v.GET("/verify-email/:token", h.VerifyEmail)
 }
@@ -2,6 +2,7 @@ package auth
 
 import (
 	"cleancare/internal/abstraction"
+	"cleancare/internal/app/email"
 	"cleancare/internal/config"
 	"cleancare/internal/dto"
 	"cleancare/internal/factory"
@@ -11,6 +12,8 @@ import (
 	"cleancare/pkg/constant"
 	"cleancare/pkg/gdrive"
 	"cleancare/pkg/gomail"
+	"cleancare/pkg/i18n"
+	"cleancare/pkg/password"
 	"cleancare/pkg/util/aescrypt"
 	"cleancare/pkg/util/encoding"
 	"cleancare/pkg/util/general"
@@ -20,6 +23,7 @@ import (
 	"errors"
 	"fmt"
 	"net/http"
+	"strings"
 	"time"
 
 	"github.com/go-redis/redis/v8"
@@ -35,13 +39,18 @@ type Service interface {
 	Logout(ctx *abstraction.Context) (map[string]interface{}, error)
 	RefreshToken(ctx *abstraction.Context) (map[string]interface{}, error)
 	SendEmailForgotPassword(ctx *abstraction.Context, payload *dto.AuthSendEmailForgotPasswordRequest) (map[string]interface{}, error)
-	ValidationResetPassword(ctx *abstraction.Context, payload *dto.AuthValidationResetPasswordRequest) (string, error)
+	ResetPasswordForm(ctx *abstraction.Context, payload *dto.AuthResetPasswordFormRequest) (string, error)
+	ResetPassword(ctx *abstraction.Context, payload *dto.AuthResetPasswordRequest) (string, error)
 	VerifyNumber(ctx *abstraction.Context, payload *dto.AuthVerifyNumberRequest) (map[string]interface{}, error)
 	Register(ctx *abstraction.Context, payload *dto.AuthRegisterRequest) (map[string]interface{}, error)
+	SendEmailVerification(ctx *abstraction.Context, payload *dto.AuthSendEmailVerificationRequest) (map[string]interface{}, error)
+	VerifyEmail(ctx *abstraction.Context, payload *dto.AuthVerifyEmailRequest) (string, error)
 }
 
 type service struct {
-	UserRepository repository.User
+	UserRepository            repository.User
+	PasswordHistoryRepository repository.PasswordHistory
+	EmailOutboxRepository     repository.EmailOutbox
 
 	DB      *gorm.DB
 	DbRedis *redis.Client
@@ -51,7 +60,9 @@ type service struct {
 
 func NewService(f *factory.Factory) Service {
 	return &service{
-		UserRepository: f.UserRepository,
+		UserRepository:            f.UserRepository,
+		PasswordHistoryRepository: f.PasswordHistoryRepository,
+		EmailOutboxRepository:     f.EmailOutboxRepository,
 
 		DB:      f.Db,
 		DbRedis: f.DbRedis,
@@ -83,6 +94,9 @@ func (s *service) Login(ctx *abstraction.Context, payload *dto.AuthLoginRequest)
 		if err = bcrypt.CompareHashAndPassword([]byte(*data.Password), []byte(payload.Password)); err != nil {
 			return response.ErrorBuilder(http.StatusUnauthorized, errors.New("unauthorized"), "number id or password is incorrect")
 		}
+		if data.EmailVerifiedAt == nil {
+			return response.ErrorBuilder(http.StatusUnauthorized, errors.New("unauthorized"), "email is not verified")
+		}
 
 		var encryptedUserID string
 		if encryptedUserID, err = s.encryptTokenClaims(data.ID); err != nil {
@@ -110,6 +124,7 @@ func (s *service) Login(ctx *abstraction.Context, payload *dto.AuthLoginRequest)
 		}
 
 		general.AppendUUIDToRedisArray(s.DbRedis, general.GenerateRedisKeyUserLogin(data.ID), uuidUserLogin)
+		general.SetUserLanguageToRedis(s.DbRedis, data.ID, data.Language)
 
 		return nil
 	}); err != nil {
@@ -126,6 +141,7 @@ func (s *service) Login(ctx *abstraction.Context, payload *dto.AuthLoginRequest)
 		"profile":      data.Profile,
 		"profile_name": data.ProfileName,
 		"floor":        data.Floor,
+		"language":     data.Language,
 		"role": map[string]interface{}{
 			"id":   data.Role.ID,
 			"name": data.Role.Name,
@@ -222,6 +238,9 @@ func (s *service) SendEmailForgotPassword(ctx *abstraction.Context, payload *dto
 		if data == nil {
 			return response.ErrorBuilder(http.StatusUnauthorized, errors.New("unauthorized"), "email not found")
 		}
+		if data.EmailVerifiedAt == nil {
+			return response.ErrorBuilder(http.StatusUnauthorized, errors.New("unauthorized"), "email is not verified")
+		}
 
 		eksternalToken := new(modelToken.AuthEksternalToken)
 		eksternalToken.UserId = data.ID
@@ -230,17 +249,20 @@ func (s *service) SendEmailForgotPassword(ctx *abstraction.Context, payload *dto
 			return response.ErrorBuilder(http.StatusInternalServerError, err, "server_error")
 		}
 
-		s.DbRedis.Set(context.Background(), *token, *token, 0)
+		ttl := config.Get().App.ResetPasswordTokenTTL
+		s.DbRedis.Set(context.Background(), fmt.Sprintf(constant.REDIS_KEY_RESET_PASSWORD_TOKEN, data.ID), *token, time.Duration(ttl)*time.Minute)
 
-		if err = gomail.SendMail(*data.Email, "Forgot Password for CleanCare", general.ParseTemplateEmailToHtml("./assets/html/email/notif_forgot_password.html", struct {
-			NAME  string
-			EMAIL string
-			LINK  string
-		}{
-			NAME:  data.Name,
-			EMAIL: *data.Email,
-			LINK:  constant.BASE_URL + "/auth/validation/reset-password/" + *token,
-		})); err != nil {
+		lang := i18n.Resolve(data.Language, ctx.Lang())
+		subject, body, err := gomail.TemplateForgotPassword.Render(lang, gomail.ForgotPasswordData{
+			NAME:   data.Name,
+			EMAIL:  *data.Email,
+			LINK:   constant.BASE_URL + "/auth/reset-password/" + *token,
+			EXPIRE: ttl,
+		})
+		if err != nil {
+			return response.ErrorBuilder(http.StatusInternalServerError, err, "server_error")
+		}
+		if err = s.EmailOutboxRepository.Create(ctx, model.NewEmailOutbox(ctx, *data.Email, gomail.TemplateForgotPassword.Name, lang, subject, body)).Error; err != nil {
 			return response.ErrorBuilder(http.StatusInternalServerError, err, "server_error")
 		}
 
@@ -254,36 +276,49 @@ func (s *service) SendEmailForgotPassword(ctx *abstraction.Context, payload *dto
 	}, nil
 }
 
-func (s *service) ValidationResetPassword(ctx *abstraction.Context, payload *dto.AuthValidationResetPasswordRequest) (string, error) {
-	userData := new(model.UserEntityModel)
-	if err := trxmanager.New(s.DB).WithTrx(ctx, func(ctx *abstraction.Context) error {
-		_, err := s.DbRedis.Get(context.Background(), payload.Token).Result()
-		if err == redis.Nil {
-			return errors.New("your token is invalid")
-		} else {
-			s.DbRedis.Del(context.Background(), payload.Token)
-		}
+func (s *service) ResetPasswordForm(ctx *abstraction.Context, payload *dto.AuthResetPasswordFormRequest) (string, error) {
+	userData, err := s.validateResetPasswordToken(ctx, payload.Token)
+	if err != nil {
+		return "", err
+	}
 
-		data, err := modelToken.ValidateTokenEksternal(payload.Token)
+	return *userData.Email, nil
+}
+
+func (s *service) ResetPassword(ctx *abstraction.Context, payload *dto.AuthResetPasswordRequest) (string, error) {
+	var (
+		userData = new(model.UserEntityModel)
+		tokenTTL time.Duration
+	)
+	if err := trxmanager.New(s.DB).WithTrx(ctx, func(ctx *abstraction.Context) error {
+		var err error
+		userData, err = s.validateResetPasswordToken(ctx, payload.Token)
 		if err != nil {
-			return errors.New("your token is invalid")
+			return err
 		}
 
-		userData, err = s.UserRepository.FindById(ctx, data.UserId)
-		if err != nil && err.Error() != "record not found" {
+		policy := password.CurrentPolicy()
+		usedPasswords, err := s.PasswordHistoryRepository.FindLastPasswords(ctx, userData.ID, policy.History)
+		if err != nil {
 			return response.ErrorBuilder(http.StatusInternalServerError, err, "server_error")
 		}
-		if userData == nil {
-			return response.ErrorBuilder(http.StatusBadRequest, errors.New("bad_request"), "user not found")
+		if fields := policy.Validate("new_password", payload.NewPassword, usedPasswords); fields != nil {
+			return response.FieldErrorBuilder("password does not meet the policy", fields)
+		}
+		if payload.NewPassword != payload.ConfirmPassword {
+			return response.ErrorBuilder(http.StatusBadRequest, errors.New("bad_request"), "password confirmation does not match")
 		}
 
-		passwordString := general.GeneratePassword(8, 1, 1, 1, 1)
-		password := []byte(passwordString)
-		hashedPassword, err := bcrypt.GenerateFromPassword(password, bcrypt.DefaultCost)
+		hashedPassword, err := bcrypt.GenerateFromPassword([]byte(payload.NewPassword), bcrypt.DefaultCost)
 		if err != nil {
 			return response.ErrorBuilder(http.StatusInternalServerError, err, "server_error")
 		}
 
+		// the token is used up before the password is written, a second request with it stops here
+		if tokenTTL, err = s.consumeResetPasswordToken(userData.ID, payload.Token); err != nil {
+			return err
+		}
+
 		hashPwStr := string(hashedPassword)
 		newUserData := new(model.UserEntityModel)
 		newUserData.Context = ctx
@@ -293,18 +328,7 @@ func (s *service) ValidationResetPassword(ctx *abstraction.Context, payload *dto
 		if err = s.UserRepository.Update(ctx, newUserData).Error; err != nil {
 			return response.ErrorBuilder(http.StatusInternalServerError, err, "server_error")
 		}
-
-		if err = gomail.SendMail(*userData.Email, "Reset Password for CleanCare", general.ParseTemplateEmailToHtml("./assets/html/email/notif_reset_password.html", struct {
-			NAME      string
-			RESETNAME string
-			NUMBERID  string
-			PASSWORD  string
-		}{
-			NAME:      userData.Name,
-			RESETNAME: "System",
-			NUMBERID:  *userData.Email,
-			PASSWORD:  passwordString,
-		})); err != nil {
+		if err = s.PasswordHistoryRepository.Add(ctx, userData.ID, hashPwStr).Error; err != nil {
 			return response.ErrorBuilder(http.StatusInternalServerError, err, "server_error")
 		}
 
@@ -315,12 +339,69 @@ func (s *service) ValidationResetPassword(ctx *abstraction.Context, payload *dto
 
 		return nil
 	}); err != nil {
+		// the password was not changed, the token stays usable for the time it had left
+		if tokenTTL > 0 {
+			s.DbRedis.Set(context.Background(), fmt.Sprintf(constant.REDIS_KEY_RESET_PASSWORD_TOKEN, userData.ID), payload.Token, tokenTTL)
+		}
 		return "", err
 	}
 
 	return *userData.Email, nil
 }
 
+// consumeResetPasswordScript deletes the key only when it still holds the token, and returns the
+// milliseconds the key had left, or -3 when it did not hold the token.
+var consumeResetPasswordScript = redis.NewScript(`
+if redis.call("GET", KEYS[1]) ~= ARGV[1] then
+	return -3
+end
+local ttl = redis.call("PTTL", KEYS[1])
+redis.call("DEL", KEYS[1])
+return ttl
+`)
+
+// consumeResetPasswordToken uses up the token of the user in one step, so two requests with the
+// same token cannot both reset the password.
+func (s *service) consumeResetPasswordToken(userId int, token string) (time.Duration, error) {
+	ttl, err := consumeResetPasswordScript.Run(context.Background(), s.DbRedis, []string{fmt.Sprintf(constant.REDIS_KEY_RESET_PASSWORD_TOKEN, userId)}, token).Int64()
+	if err != nil {
+		return 0, response.ErrorBuilder(http.StatusInternalServerError, err, "server_error")
+	}
+	if ttl == -3 {
+		return 0, errors.New("your token is invalid")
+	}
+	return time.Duration(ttl) * time.Millisecond, nil
+}
+
+// validateResetPasswordToken only accepts the last token sent to the user, requesting
+// a new one replaces the previous token and the key expires with the configured ttl.
+func (s *service) validateResetPasswordToken(ctx *abstraction.Context, token string) (*model.UserEntityModel, error) {
+	data, err := modelToken.ValidateTokenEksternal(token)
+	if err != nil {
+		return nil, errors.New("your token is invalid")
+	}
+
+	activeToken, err := s.DbRedis.Get(context.Background(), fmt.Sprintf(constant.REDIS_KEY_RESET_PASSWORD_TOKEN, data.UserId)).Result()
+	if err == redis.Nil {
+		return nil, errors.New("your token has expired")
+	} else if err != nil {
+		return nil, response.ErrorBuilder(http.StatusInternalServerError, err, "server_error")
+	}
+	if activeToken != token {
+		return nil, errors.New("your token is invalid")
+	}
+
+	userData, err := s.UserRepository.FindById(ctx, data.UserId)
+	if err != nil && err.Error() != "record not found" {
+		return nil, response.ErrorBuilder(http.StatusInternalServerError, err, "server_error")
+	}
+	if userData == nil || userData.Email == nil {
+		return nil, errors.New("user not found")
+	}
+
+	return userData, nil
+}
+
 func (s *service) VerifyNumber(ctx *abstraction.Context, payload *dto.AuthVerifyNumberRequest) (map[string]interface{}, error) {
 	var (
 		err  error
@@ -372,22 +453,40 @@ func (s *service) Register(ctx *abstraction.Context, payload *dto.AuthRegisterRe
 		newUserData.Context = ctx
 		newUserData.ID = userData.ID
 		if payload.Email != nil {
+			if !general.IsValidEmail(*payload.Email) {
+				return response.ErrorBuilder(http.StatusBadRequest, errors.New("bad_request"), "email is not valid")
+			}
 			userEmail, err := s.UserRepository.FindByEmail(ctx, *payload.Email)
 			if err != nil && err.Error() != "record not found" {
 				return response.ErrorBuilder(http.StatusInternalServerError, err, "server_error")
 			}
-			if userEmail != nil {
+			if userEmail != nil && userEmail.ID != userData.ID {
 				return response.ErrorBuilder(http.StatusBadRequest, errors.New("bad_request"), "email already exist")
 			}
 			newUserData.Email = payload.Email
+			if err = s.UserRepository.UpdateToNull(ctx, newUserData, "email_verified_at").Error; err != nil {
+				return response.ErrorBuilder(http.StatusInternalServerError, err, "server_error")
+			}
 		}
 		if payload.Password != nil {
+			policy := password.CurrentPolicy()
+			usedPasswords, err := s.PasswordHistoryRepository.FindLastPasswords(ctx, userData.ID, policy.History)
+			if err != nil {
+				return response.ErrorBuilder(http.StatusInternalServerError, err, "server_error")
+			}
+			if fields := policy.Validate("password", *payload.Password, usedPasswords); fields != nil {
+				return response.FieldErrorBuilder("password does not meet the policy", fields)
+			}
+
 			hashedPassword, err := bcrypt.GenerateFromPassword([]byte(*payload.Password), bcrypt.DefaultCost)
 			if err != nil {
 				return response.ErrorBuilder(http.StatusInternalServerError, err, "server_error")
 			}
 			strHashPass := string(hashedPassword)
 			newUserData.Password = &strHashPass
+			if err = s.PasswordHistoryRepository.Add(ctx, userData.ID, strHashPass).Error; err != nil {
+				return response.ErrorBuilder(http.StatusInternalServerError, err, "server_error")
+			}
 		}
 		if payload.Profile != nil {
 			file := payload.Profile[0]
@@ -416,6 +515,12 @@ func (s *service) Register(ctx *abstraction.Context, payload *dto.AuthRegisterRe
 			return response.ErrorBuilder(http.StatusInternalServerError, err, "server_error")
 		}
 
+		if payload.Email != nil {
+			if err = email.SendVerification(ctx, s.EmailOutboxRepository, userData, *payload.Email); err != nil {
+				return err
+			}
+		}
+
 		return nil
 	}); err != nil {
 		for _, v := range allFileUploaded {
@@ -431,3 +536,92 @@ func (s *service) Register(ctx *abstraction.Context, payload *dto.AuthRegisterRe
 		"message": "success register!",
 	}, nil
 }
+
+func (s *service) SendEmailVerification(ctx *abstraction.Context, payload *dto.AuthSendEmailVerificationRequest) (map[string]interface{}, error) {
+	if err := trxmanager.New(s.DB).WithTrx(ctx, func(ctx *abstraction.Context) error {
+		data, err := s.UserRepository.FindByEmail(ctx, payload.Email)
+		if err != nil && err.Error() != "record not found" {
+			return response.ErrorBuilder(http.StatusInternalServerError, err, "server_error")
+		}
+		if data != nil && data.EmailVerifiedAt != nil {
+			return response.ErrorBuilder(http.StatusBadRequest, errors.New("bad_request"), "email is already verified")
+		}
+		if data == nil {
+			data, err = s.UserRepository.FindByPendingEmail(ctx, payload.Email)
+			if err != nil && err.Error() != "record not found" {
+				return response.ErrorBuilder(http.StatusInternalServerError, err, "server_error")
+			}
+		}
+		if data == nil {
+			return response.ErrorBuilder(http.StatusUnauthorized, errors.New("unauthorized"), "email not found")
+		}
+
+		return email.SendVerification(ctx, s.EmailOutboxRepository, data, payload.Email)
+	}); err != nil {
+		return nil, err
+	}
+
+	return map[string]interface{}{
+		"message": "success send email verification!",
+	}, nil
+}
+
+// VerifyEmail confirms either the address a user registered with or the pending address
+// of an email change, whichever the token was generated for.
+func (s *service) VerifyEmail(ctx *abstraction.Context, payload *dto.AuthVerifyEmailRequest) (string, error) {
+	var email string
+	if err := trxmanager.New(s.DB).WithTrx(ctx, func(ctx *abstraction.Context) error {
+		data, err := modelToken.ValidateEmailVerificationToken(payload.Token)
+		if err != nil {
+			if err.Error() == "your token has expired" {
+				return err
+			}
+			return errors.New("your token is invalid")
+		}
+
+		userData, err := s.UserRepository.FindById(ctx, data.UserId)
+		if err != nil && err.Error() != "record not found" {
+			return response.ErrorBuilder(http.StatusInternalServerError, err, "server_error")
+		}
+		if userData == nil {
+			return errors.New("user not found")
+		}
+
+		newUserData := new(model.UserEntityModel)
+		newUserData.Context = ctx
+		newUserData.ID = userData.ID
+		switch {
+		case userData.PendingEmail != nil && strings.EqualFold(*userData.PendingEmail, data.Email):
+			userEmail, err := s.UserRepository.FindByEmail(ctx, *userData.PendingEmail)
+			if err != nil && err.Error() != "record not found" {
+				return response.ErrorBuilder(http.StatusInternalServerError, err, "server_error")
+			}
+			if userEmail != nil && userEmail.ID != userData.ID {
+				return errors.New("email already exist")
+			}
+			newUserData.Email = userData.PendingEmail
+			if err = s.UserRepository.UpdateToNull(ctx, newUserData, "pending_email").Error; err != nil {
+				return response.ErrorBuilder(http.StatusInternalServerError, err, "server_error")
+			}
+		case userData.Email != nil && strings.EqualFold(*userData.Email, data.Email):
+			if userData.EmailVerifiedAt != nil {
+				return errors.New("email is already verified")
+			}
+			newUserData.Email = userData.Email
+		default:
+			return errors.New("your token is invalid")
+		}
+
+		newUserData.EmailVerifiedAt = general.Now()
+		if err = s.UserRepository.Update(ctx, newUserData).Error; err != nil {
+			return response.ErrorBuilder(http.StatusInternalServerError, err, "server_error")
+		}
+		email = *newUserData.Email
+
+		return nil
+	}); err != nil {
+		return "", err
+	}
+
+	return email, nil
+}
@@ -0,0 +1,14 @@
+package email
+
+import (
+	"cleancare/internal/middleware"
+
+	"github.com/labstack/echo/v4"
+)
+
+func (h *handler) Route(v *echo.Group) {
+	v.GET("/outbox", h.Find, middleware.Authentication)
+	v.GET("/outbox/:id", h.FindById, middleware.Authentication)
+	v.POST("/outbox/:id/retry", h.Retry, middleware.Authentication)
+	v.GET("/template", h.Templates, middleware.Authentication)
+}
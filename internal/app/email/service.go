@@ -0,0 +1,147 @@
+package email
+
+import (
+	"cleancare/internal/abstraction"
+	"cleancare/internal/dto"
+	"cleancare/internal/factory"
+	"cleancare/internal/model"
+	"cleancare/internal/repository"
+	"cleancare/pkg/constant"
+	"cleancare/pkg/gomail"
+	"cleancare/pkg/util/general"
+	"cleancare/pkg/util/response"
+	"cleancare/pkg/util/trxmanager"
+	"errors"
+	"net/http"
+
+	"gorm.io/gorm"
+)
+
+type Service interface {
+	Find(ctx *abstraction.Context) (map[string]interface{}, error)
+	FindById(ctx *abstraction.Context, payload *dto.EmailOutboxFindByIDRequest) (map[string]interface{}, error)
+	Retry(ctx *abstraction.Context, payload *dto.EmailOutboxRetryRequest) (map[string]interface{}, error)
+	Templates(ctx *abstraction.Context) (map[string]interface{}, error)
+}
+
+type service struct {
+	EmailOutboxRepository repository.EmailOutbox
+
+	DB *gorm.DB
+}
+
+func NewService(f *factory.Factory) Service {
+	return &service{
+		EmailOutboxRepository: f.EmailOutboxRepository,
+
+		DB: f.Db,
+	}
+}
+
+func (s *service) Find(ctx *abstraction.Context) (map[string]interface{}, error) {
+	if ctx.Auth.RoleID != constant.ROLE_ID_ADMIN {
+		return nil, response.ErrorBuilder(http.StatusBadRequest, errors.New("bad_request"), "this role is not permitted")
+	}
+
+	data, err := s.EmailOutboxRepository.Find(ctx, false)
+	if err != nil && err.Error() != "record not found" {
+		return nil, response.ErrorBuilder(http.StatusInternalServerError, err, "server_error")
+	}
+	count, err := s.EmailOutboxRepository.Count(ctx)
+	if err != nil && err.Error() != "record not found" {
+		return nil, response.ErrorBuilder(http.StatusInternalServerError, err, "server_error")
+	}
+
+	var res []map[string]interface{} = nil
+	for _, v := range data {
+		res = append(res, toMap(v))
+	}
+	return map[string]interface{}{
+		"count": count,
+		"meta":  general.OffsetMeta(ctx, false, len(data), count),
+		"data":  res,
+	}, nil
+}
+
+func (s *service) FindById(ctx *abstraction.Context, payload *dto.EmailOutboxFindByIDRequest) (map[string]interface{}, error) {
+	if ctx.Auth.RoleID != constant.ROLE_ID_ADMIN {
+		return nil, response.ErrorBuilder(http.StatusBadRequest, errors.New("bad_request"), "this role is not permitted")
+	}
+
+	data, err := s.EmailOutboxRepository.FindById(ctx, payload.ID)
+	if err != nil && err.Error() != "record not found" {
+		return nil, response.ErrorBuilder(http.StatusInternalServerError, err, "server_error")
+	}
+	if data == nil {
+		return nil, response.ErrorBuilder(http.StatusBadRequest, errors.New("bad_request"), "email not found")
+	}
+
+	res := toMap(data)
+	res["body"] = data.Body
+	return map[string]interface{}{
+		"data": res,
+	}, nil
+}
+
+func (s *service) Retry(ctx *abstraction.Context, payload *dto.EmailOutboxRetryRequest) (map[string]interface{}, error) {
+	if err := trxmanager.New(s.DB).WithTrx(ctx, func(ctx *abstraction.Context) error {
+		if ctx.Auth.RoleID != constant.ROLE_ID_ADMIN {
+			return response.ErrorBuilder(http.StatusBadRequest, errors.New("bad_request"), "this role is not permitted")
+		}
+
+		data, err := s.EmailOutboxRepository.FindById(ctx, payload.ID)
+		if err != nil && err.Error() != "record not found" {
+			return response.ErrorBuilder(http.StatusInternalServerError, err, "server_error")
+		}
+		if data == nil {
+			return response.ErrorBuilder(http.StatusBadRequest, errors.New("bad_request"), "email not found")
+		}
+		if data.Status != constant.EMAIL_STATUS_DEAD {
+			return response.ErrorBuilder(http.StatusBadRequest, errors.New("bad_request"), "only dead emails can be retried")
+		}
+
+		if err = s.EmailOutboxRepository.Requeue(ctx, data.ID).Error; err != nil {
+			return response.ErrorBuilder(http.StatusInternalServerError, err, "server_error")
+		}
+
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+	return map[string]interface{}{
+		"message": "success update!",
+	}, nil
+}
+
+func (s *service) Templates(ctx *abstraction.Context) (map[string]interface{}, error) {
+	if ctx.Auth.RoleID != constant.ROLE_ID_ADMIN {
+		return nil, response.ErrorBuilder(http.StatusBadRequest, errors.New("bad_request"), "this role is not permitted")
+	}
+	return map[string]interface{}{
+		"data": gomail.Templates(),
+	}, nil
+}
+
+func toMap(v *model.EmailOutboxEntityModel) map[string]interface{} {
+	res := map[string]interface{}{
+		"id":              v.ID,
+		"recipient":       v.Recipient,
+		"subject":         v.Subject,
+		"template":        v.Template,
+		"lang":            v.Lang,
+		"status":          v.Status,
+		"attempts":        v.Attempts,
+		"next_attempt_at": general.FormatWithZWithoutChangingTime(v.NextAttemptAt),
+		"last_error":      v.LastError,
+		"sent_at":         nil,
+		"created_at":      general.FormatWithZWithoutChangingTime(v.CreatedAt),
+		"updated_at":      nil,
+	}
+	if v.SentAt != nil {
+		res["sent_at"] = general.FormatWithZWithoutChangingTime(*v.SentAt)
+	}
+	if v.UpdatedAt != nil {
+		res["updated_at"] = general.FormatWithZWithoutChangingTime(*v.UpdatedAt)
+	}
+	return res
+}
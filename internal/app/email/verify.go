@@ -0,0 +1,39 @@
+package email
+
+import (
+	"cleancare/internal/abstraction"
+	"cleancare/internal/config"
+	"cleancare/internal/model"
+	modelToken "cleancare/internal/model/token"
+	"cleancare/internal/repository"
+	"cleancare/pkg/constant"
+	"cleancare/pkg/gomail"
+	"cleancare/pkg/i18n"
+	"cleancare/pkg/util/response"
+	"net/http"
+	"time"
+)
+
+// SendVerification queues the link that confirms address as the email of the user, in the
+// language of the user. It is shared by registration, profile updates and resends.
+func SendVerification(ctx *abstraction.Context, outbox repository.EmailOutbox, userData *model.UserEntityModel, address string) error {
+	ttl := config.Get().App.EmailVerificationTokenTTL
+	token, err := modelToken.NewEmailVerificationToken(userData.ID, address, time.Duration(ttl)*time.Minute).GenerateToken()
+	if err != nil {
+		return response.ErrorBuilder(http.StatusInternalServerError, err, "server_error")
+	}
+
+	lang := i18n.Resolve(userData.Language, ctx.Lang())
+	subject, body, err := gomail.TemplateVerifyEmail.Render(lang, gomail.VerifyEmailData{
+		NAME:   userData.Name,
+		LINK:   constant.BASE_URL + "/auth/verify-email/" + *token,
+		EXPIRE: max(ttl/60, 1),
+	})
+	if err != nil {
+		return response.ErrorBuilder(http.StatusInternalServerError, err, "server_error")
+	}
+	if err = outbox.Create(ctx, model.NewEmailOutbox(ctx, address, gomail.TemplateVerifyEmail.Name, lang, subject, body)).Error; err != nil {
+		return response.ErrorBuilder(http.StatusInternalServerError, err, "server_error")
+	}
+	return nil
+}
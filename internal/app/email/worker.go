@@ -0,0 +1,100 @@
+package email
+
+import (
+	"cleancare/internal/abstraction"
+	"cleancare/internal/config"
+	"cleancare/internal/factory"
+	"cleancare/internal/model"
+	"cleancare/internal/repository"
+	"cleancare/pkg/constant"
+	"cleancare/pkg/gomail"
+	"cleancare/pkg/scheduler"
+	"cleancare/pkg/util/general"
+	"context"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	workerBatch = 20
+	stuckAfter  = 10 * time.Minute
+	retryBase   = time.Minute
+	retryMax    = time.Hour
+)
+
+type worker struct {
+	EmailOutboxRepository repository.EmailOutbox
+}
+
+// StartWorker sends the queued emails every MAIL_WORKER_INTERVAL seconds until ctx is done.
+func StartWorker(ctx context.Context, f *factory.Factory) {
+	w := &worker{
+		EmailOutboxRepository: f.EmailOutboxRepository,
+	}
+	scheduler.Every(ctx, "email outbox", time.Duration(config.Get().Gomail.WorkerInterval)*time.Second, w.run)
+}
+
+func (w *worker) run(_ context.Context) {
+	ctx := &abstraction.Context{
+		Auth: &abstraction.AuthContext{},
+	}
+
+	data, err := w.EmailOutboxRepository.FindDue(ctx, time.Now().Add(-stuckAfter), workerBatch)
+	if err != nil {
+		logrus.Error("error find email outbox:", err.Error())
+		return
+	}
+
+	for _, v := range data {
+		claimed, err := w.EmailOutboxRepository.Claim(ctx, v)
+		if err != nil {
+			logrus.Error("error claim email outbox:", err.Error())
+			continue
+		}
+		if !claimed {
+			continue
+		}
+		v.Attempts++
+		w.send(ctx, v)
+	}
+}
+
+func (w *worker) send(ctx *abstraction.Context, data *model.EmailOutboxEntityModel) {
+	newData := new(model.EmailOutboxEntityModel)
+	newData.Context = ctx
+	newData.ID = data.ID
+
+	if err := gomail.SendMail(data.Recipient, data.Subject, data.Body); err != nil {
+		errMessage := err.Error()
+		newData.LastError = &errMessage
+		if data.Attempts >= config.Get().Gomail.MaxAttempts {
+			newData.Status = constant.EMAIL_STATUS_DEAD
+			logrus.Errorf("email outbox %d is dead after %d attempts: %s", data.ID, data.Attempts, errMessage)
+		} else {
+			newData.Status = constant.EMAIL_STATUS_PENDING
+			newData.NextAttemptAt = time.Now().Add(backoff(data.Attempts))
+		}
+		if err = w.EmailOutboxRepository.Update(ctx, newData).Error; err != nil {
+			logrus.Error("error update email outbox:", err.Error())
+		}
+		return
+	}
+
+	newData.Status = constant.EMAIL_STATUS_SENT
+	newData.SentAt = general.Now()
+	if err := w.EmailOutboxRepository.Update(ctx, newData).Error; err != nil {
+		logrus.Error("error update email outbox:", err.Error())
+	}
+	if err := w.EmailOutboxRepository.UpdateToNull(ctx, newData, "last_error").Error; err != nil {
+		logrus.Error("error update email outbox:", err.Error())
+	}
+}
+
+// backoff doubles the wait after every failed attempt, 1m, 2m, 4m and so on up to an hour.
+func backoff(attempts int) time.Duration {
+	if attempts < 1 || attempts > 10 {
+		return retryMax
+	}
+	return min(retryBase<<(attempts-1), retryMax)
+}
@@ -0,0 +1,15 @@
+package feedback
+
+import (
+	"cleancare/internal/middleware"
+
+	"github.com/labstack/echo/v4"
+)
+
+func (h *handler) Route(v *echo.Group) {
+	// the feedback form is opened from the qr code of the area, without an account
+	v.GET("/public/area/:code", h.PublicArea)
+	v.POST("/public/area/:code", h.PublicCreate, middleware.FeedbackIpCheck)
+
+	v.GET("", h.Find, middleware.Authentication)
+}
@@ -0,0 +1,162 @@
+package feedback
+
+import (
+	"cleancare/internal/abstraction"
+	"cleancare/internal/config"
+	"cleancare/internal/dto"
+	"cleancare/internal/factory"
+	"cleancare/internal/model"
+	"cleancare/internal/repository"
+	"cleancare/pkg/constant"
+	"cleancare/pkg/util/general"
+	"cleancare/pkg/util/response"
+	"cleancare/pkg/util/trxmanager"
+	"errors"
+	"net/http"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type Service interface {
+	PublicArea(ctx *abstraction.Context, payload *dto.FeedbackPublicAreaRequest) (map[string]interface{}, error)
+	PublicCreate(ctx *abstraction.Context, payload *dto.FeedbackPublicCreateRequest) (map[string]interface{}, error)
+	Find(ctx *abstraction.Context) (map[string]interface{}, error)
+}
+
+type service struct {
+	FeedbackRepository repository.Feedback
+	AreaRepository     repository.Area
+	WorkRepository     repository.Work
+
+	DB *gorm.DB
+}
+
+func NewService(f *factory.Factory) Service {
+	return &service{
+		FeedbackRepository: f.FeedbackRepository,
+		AreaRepository:     f.AreaRepository,
+		WorkRepository:     f.WorkRepository,
+
+		DB: f.Db,
+	}
+}
+
+// PublicArea tells the feedback form which area and which cleaning is rated.
+func (s *service) PublicArea(ctx *abstraction.Context, payload *dto.FeedbackPublicAreaRequest) (map[string]interface{}, error) {
+	areaData, workData, err := s.findWork(ctx, payload.Code)
+	if err != nil {
+		return nil, err
+	}
+	return map[string]interface{}{
+		"data": map[string]interface{}{
+			"name":         areaData.Name,
+			"floor":        areaData.Floor,
+			"location":     areaData.Location,
+			"completed_at": general.FormatWithZWithoutChangingTime(*workData.CompletedAt),
+		},
+	}, nil
+}
+
+// PublicCreate takes a rating from an occupant without an account about the last work
+// completed for the area.
+func (s *service) PublicCreate(ctx *abstraction.Context, payload *dto.FeedbackPublicCreateRequest) (map[string]interface{}, error) {
+	var res map[string]interface{}
+	if err := trxmanager.New(s.DB).WithTrx(ctx, func(ctx *abstraction.Context) error {
+		areaData, workData, err := s.findWork(ctx, payload.Code)
+		if err != nil {
+			return err
+		}
+
+		modelFeedback := &model.FeedbackEntityModel{
+			Context: ctx,
+			FeedbackEntity: model.FeedbackEntity{
+				AreaId:  areaData.ID,
+				WorkId:  workData.ID,
+				UserId:  workData.UserId,
+				Floor:   areaData.Floor,
+				Rating:  payload.Rating,
+				Comment: payload.Comment,
+				Ip:      ctx.RealIP(),
+			},
+		}
+		if err = s.FeedbackRepository.Create(ctx, modelFeedback).Error; err != nil {
+			return response.ErrorBuilder(http.StatusInternalServerError, err, "server_error")
+		}
+
+		res = map[string]interface{}{
+			"id":     modelFeedback.ID,
+			"rating": modelFeedback.Rating,
+		}
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+	return map[string]interface{}{
+		"message": "success create!",
+		"data":    res,
+	}, nil
+}
+
+func (s *service) Find(ctx *abstraction.Context) (map[string]interface{}, error) {
+	if ctx.Auth.RoleID != constant.ROLE_ID_ADMIN {
+		return nil, response.ErrorBuilder(http.StatusBadRequest, errors.New("bad_request"), "this role is not permitted")
+	}
+
+	data, err := s.FeedbackRepository.Find(ctx, false)
+	if err != nil && err.Error() != "record not found" {
+		return nil, response.ErrorBuilder(http.StatusInternalServerError, err, "server_error")
+	}
+	count, err := s.FeedbackRepository.Count(ctx)
+	if err != nil && err.Error() != "record not found" {
+		return nil, response.ErrorBuilder(http.StatusInternalServerError, err, "server_error")
+	}
+
+	var res []map[string]interface{} = nil
+	for _, v := range data {
+		res = append(res, map[string]interface{}{
+			"id": v.ID,
+			"area": map[string]interface{}{
+				"id":       v.Area.ID,
+				"name":     v.Area.Name,
+				"location": v.Area.Location,
+			},
+			"work_id": v.WorkId,
+			"user": map[string]interface{}{
+				"id":   v.User.ID,
+				"name": v.User.Name,
+			},
+			"floor":      v.Floor,
+			"rating":     v.Rating,
+			"comment":    v.Comment,
+			"created_at": general.FormatWithZWithoutChangingTime(v.CreatedAt),
+		})
+	}
+	return map[string]interface{}{
+		"count": count,
+		"meta":  general.OffsetMeta(ctx, false, len(data), count),
+		"data":  res,
+	}, nil
+}
+
+// findWork returns the area of the code and the last work completed for it, a work too old to
+// remember is not rated anymore.
+func (s *service) findWork(ctx *abstraction.Context, code string) (*model.AreaEntityModel, *model.WorkEntityModel, error) {
+	areaData, err := s.AreaRepository.FindByCode(ctx, code)
+	if err != nil && err.Error() != "record not found" {
+		return nil, nil, response.ErrorBuilder(http.StatusInternalServerError, err, "server_error")
+	}
+	if areaData == nil {
+		return nil, nil, response.ErrorBuilder(http.StatusBadRequest, errors.New("bad_request"), "area not found")
+	}
+
+	since := time.Now().Add(-time.Duration(config.Get().App.FeedbackWorkMaxAge) * time.Hour)
+	workData, err := s.WorkRepository.FindLastCompletedByAreaId(ctx, areaData.ID, since)
+	if err != nil && err.Error() != "record not found" {
+		return nil, nil, response.ErrorBuilder(http.StatusInternalServerError, err, "server_error")
+	}
+	if workData == nil {
+		return nil, nil, response.ErrorBuilder(http.StatusBadRequest, errors.New("bad_request"), "area has no recently completed work")
+	}
+	return areaData, workData, nil
+}
@@ -0,0 +1,19 @@
+package incident
+
+import (
+	"cleancare/internal/middleware"
+
+	"github.com/labstack/echo/v4"
+)
+
+func (h *handler) Route(v *echo.Group) {
+	v.POST("", h.Create, middleware.Authentication, middleware.Idempotency)
+	v.GET("", h.Find, middleware.Authentication)
+	v.GET("/:id", h.FindById, middleware.Authentication)
+	v.PUT("/:id", h.Update, middleware.Authentication)
+	v.DELETE("/:id", h.Delete, middleware.Authentication)
+	v.PUT("/:id/status", h.Status, middleware.Authentication)
+	v.PUT("/:id/assign", h.Assign, middleware.Authentication)
+	v.POST("/:id/photo", h.CreatePhoto, middleware.Authentication, middleware.Idempotency)
+	v.DELETE("/:id/photo/:photo_id", h.DeletePhoto, middleware.Authentication)
+}
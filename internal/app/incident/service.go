@@ -0,0 +1,629 @@
+package incident
+
+import (
+	"cleancare/internal/abstraction"
+	"cleancare/internal/app/upload"
+	"cleancare/internal/dto"
+	"cleancare/internal/factory"
+	"cleancare/internal/model"
+	"cleancare/internal/repository"
+	"cleancare/pkg/constant"
+	"cleancare/pkg/gdrive"
+	"cleancare/pkg/gomail"
+	"cleancare/pkg/i18n"
+	"cleancare/pkg/util/general"
+	"cleancare/pkg/util/response"
+	"cleancare/pkg/util/trxmanager"
+	"errors"
+	"net/http"
+
+	"github.com/sirupsen/logrus"
+	"google.golang.org/api/drive/v3"
+	"gorm.io/gorm"
+)
+
+type Service interface {
+	Create(ctx *abstraction.Context, payload *dto.IncidentCreateRequest) (map[string]interface{}, error)
+	Find(ctx *abstraction.Context) (map[string]interface{}, error)
+	FindById(ctx *abstraction.Context, payload *dto.IncidentFindByIDRequest) (map[string]interface{}, error)
+	Update(ctx *abstraction.Context, payload *dto.IncidentUpdateRequest) (map[string]interface{}, error)
+	Status(ctx *abstraction.Context, payload *dto.IncidentStatusRequest) (map[string]interface{}, error)
+	Assign(ctx *abstraction.Context, payload *dto.IncidentAssignRequest) (map[string]interface{}, error)
+	Delete(ctx *abstraction.Context, payload *dto.IncidentDeleteByIDRequest) (map[string]interface{}, error)
+	CreatePhoto(ctx *abstraction.Context, payload *dto.IncidentPhotoCreateRequest) (map[string]interface{}, error)
+	DeletePhoto(ctx *abstraction.Context, payload *dto.IncidentPhotoDeleteRequest) (map[string]interface{}, error)
+}
+
+type service struct {
+	IncidentRepository      repository.Incident
+	UserRepository          repository.User
+	WorkRepository          repository.Work
+	UploadSessionRepository repository.UploadSession
+	EmailOutboxRepository   repository.EmailOutbox
+
+	DB     *gorm.DB
+	sDrive *drive.Service
+	fDrive *drive.File
+}
+
+func NewService(f *factory.Factory) Service {
+	return &service{
+		IncidentRepository:      f.IncidentRepository,
+		UserRepository:          f.UserRepository,
+		WorkRepository:          f.WorkRepository,
+		UploadSessionRepository: f.UploadSessionRepository,
+		EmailOutboxRepository:   f.EmailOutboxRepository,
+
+		DB:     f.Db,
+		sDrive: f.GDrive.Service,
+		fDrive: f.GDrive.FolderCleanCare,
+	}
+}
+
+func (s *service) Create(ctx *abstraction.Context, payload *dto.IncidentCreateRequest) (map[string]interface{}, error) {
+	var (
+		allFileUploaded []string = nil
+		res             map[string]interface{}
+	)
+	if err := trxmanager.New(s.DB).WithTrx(ctx, func(ctx *abstraction.Context) error {
+		if len(payload.Photo)+len(payload.PhotoUploadIds) > constant.INCIDENT_PHOTO_MAX {
+			return response.ErrorBuilder(http.StatusBadRequest, errors.New("bad_request"), "photo limit of the incident is reached")
+		}
+		if payload.WorkId != nil {
+			if err := s.validateWork(ctx, *payload.WorkId); err != nil {
+				return err
+			}
+		}
+
+		modelIncident := &model.IncidentEntityModel{
+			Context: ctx,
+			IncidentEntity: model.IncidentEntity{
+				Category:    payload.Category,
+				Severity:    payload.Severity,
+				Floor:       payload.Floor,
+				Location:    payload.Location,
+				Description: payload.Description,
+				WorkId:      payload.WorkId,
+				Status:      constant.INCIDENT_STATUS_OPEN,
+				IsDelete:    false,
+			},
+		}
+		if err := s.IncidentRepository.Create(ctx, modelIncident).Error; err != nil {
+			return response.ErrorBuilder(http.StatusInternalServerError, err, "server_error")
+		}
+
+		var images []string
+		for _, v := range payload.Photo {
+			newFile, _, err := upload.SaveImage(s.sDrive, s.fDrive, v)
+			if err != nil {
+				return err
+			}
+			allFileUploaded = append(allFileUploaded, newFile.Id)
+			images = append(images, general.JoinFileAndNameWithDelimiter(newFile.Id, newFile.Name))
+		}
+		for _, v := range payload.PhotoUploadIds {
+			image, _, err := upload.Claim(ctx, s.UploadSessionRepository, v)
+			if err != nil {
+				return err
+			}
+			images = append(images, *image)
+		}
+		for i, v := range images {
+			fileId, fileName := general.SplitFileAndNameWithDelimiter(v)
+			modelPhoto := &model.IncidentPhotoEntityModel{
+				Context: ctx,
+				IncidentPhotoEntity: model.IncidentPhotoEntity{
+					IncidentId: modelIncident.ID,
+					StorageKey: fileId,
+					FileName:   fileName,
+					SortOrder:  i,
+				},
+			}
+			if err := s.IncidentRepository.CreatePhoto(ctx, modelPhoto).Error; err != nil {
+				return response.ErrorBuilder(http.StatusInternalServerError, err, "server_error")
+			}
+		}
+
+		incidentData, err := s.IncidentRepository.FindById(ctx, modelIncident.ID)
+		if err != nil {
+			return response.ErrorBuilder(http.StatusInternalServerError, err, "server_error")
+		}
+		if err = s.notifyAdmin(ctx, incidentData); err != nil {
+			return err
+		}
+
+		res = toMap(incidentData)
+		return nil
+	}); err != nil {
+		for _, v := range allFileUploaded {
+			errDel := gdrive.DeleteFile(s.sDrive, v)
+			if errDel != nil {
+				logrus.Error("error delete file for error trxmanager:", errDel.Error())
+			}
+		}
+		return nil, err
+	}
+	return map[string]interface{}{
+		"message": "success create!",
+		"data":    res,
+	}, nil
+}
+
+func (s *service) Find(ctx *abstraction.Context) (map[string]interface{}, error) {
+	userId := 0
+	if ctx.Auth.RoleID != constant.ROLE_ID_ADMIN {
+		userId = ctx.Auth.ID
+	}
+
+	data, err := s.IncidentRepository.Find(ctx, userId, false)
+	if err != nil && err.Error() != "record not found" {
+		return nil, response.ErrorBuilder(http.StatusInternalServerError, err, "server_error")
+	}
+	count, err := s.IncidentRepository.Count(ctx, userId)
+	if err != nil && err.Error() != "record not found" {
+		return nil, response.ErrorBuilder(http.StatusInternalServerError, err, "server_error")
+	}
+
+	var res []map[string]interface{} = nil
+	for _, v := range data {
+		res = append(res, toMap(v))
+	}
+	return map[string]interface{}{
+		"count": count,
+		"meta":  general.OffsetMeta(ctx, false, len(data), count),
+		"data":  res,
+	}, nil
+}
+
+func (s *service) FindById(ctx *abstraction.Context, payload *dto.IncidentFindByIDRequest) (map[string]interface{}, error) {
+	data, err := s.findVisible(ctx, payload.ID)
+	if err != nil {
+		return nil, err
+	}
+	return map[string]interface{}{
+		"data": toMap(data),
+	}, nil
+}
+
+func (s *service) Update(ctx *abstraction.Context, payload *dto.IncidentUpdateRequest) (map[string]interface{}, error) {
+	if err := trxmanager.New(s.DB).WithTrx(ctx, func(ctx *abstraction.Context) error {
+		incidentData, err := s.findVisible(ctx, payload.ID)
+		if err != nil {
+			return err
+		}
+		// the reporter may correct the report until somebody picks it up
+		if ctx.Auth.RoleID != constant.ROLE_ID_ADMIN && (incidentData.CreatedBy != ctx.Auth.ID || incidentData.Status != constant.INCIDENT_STATUS_OPEN) {
+			return response.ErrorBuilder(http.StatusBadRequest, errors.New("bad_request"), "this role is not permitted")
+		}
+
+		newIncidentData := new(model.IncidentEntityModel)
+		newIncidentData.Context = ctx
+		newIncidentData.ID = incidentData.ID
+		if payload.Category != nil {
+			newIncidentData.Category = *payload.Category
+		}
+		if payload.Severity != nil {
+			newIncidentData.Severity = *payload.Severity
+		}
+		if payload.Floor != nil {
+			newIncidentData.Floor = *payload.Floor
+		}
+		if payload.Location != nil {
+			newIncidentData.Location = *payload.Location
+		}
+		if payload.Description != nil {
+			newIncidentData.Description = *payload.Description
+		}
+		if payload.WorkId != nil && *payload.WorkId != 0 {
+			if err = s.validateWork(ctx, *payload.WorkId); err != nil {
+				return err
+			}
+			newIncidentData.WorkId = payload.WorkId
+		}
+
+		if err = s.IncidentRepository.Update(ctx, newIncidentData).Error; err != nil {
+			return response.ErrorBuilder(http.StatusInternalServerError, err, "server_error")
+		}
+		// work_id 0 removes the link to the work
+		if payload.WorkId != nil && *payload.WorkId == 0 {
+			if err = s.IncidentRepository.UpdateToNull(ctx, newIncidentData, "work_id").Error; err != nil {
+				return response.ErrorBuilder(http.StatusInternalServerError, err, "server_error")
+			}
+		}
+
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+	return map[string]interface{}{
+		"message": "success update!",
+	}, nil
+}
+
+// Status moves the incident through open, acknowledged and resolved. The admin and the
+// assignee acknowledge and resolve it, only the admin reopens it.
+func (s *service) Status(ctx *abstraction.Context, payload *dto.IncidentStatusRequest) (map[string]interface{}, error) {
+	if err := trxmanager.New(s.DB).WithTrx(ctx, func(ctx *abstraction.Context) error {
+		incidentData, err := s.findVisible(ctx, payload.ID)
+		if err != nil {
+			return err
+		}
+		isAssignee := incidentData.AssigneeId != nil && *incidentData.AssigneeId == ctx.Auth.ID
+		if ctx.Auth.RoleID != constant.ROLE_ID_ADMIN && (!isAssignee || payload.Status == constant.INCIDENT_STATUS_OPEN) {
+			return response.ErrorBuilder(http.StatusBadRequest, errors.New("bad_request"), "this role is not permitted")
+		}
+
+		newIncidentData := new(model.IncidentEntityModel)
+		newIncidentData.Context = ctx
+		newIncidentData.ID = incidentData.ID
+		newIncidentData.Status = payload.Status
+
+		switch payload.Status {
+		case constant.INCIDENT_STATUS_ACKNOWLEDGED:
+			if incidentData.Status != constant.INCIDENT_STATUS_OPEN {
+				return response.ErrorBuilder(http.StatusBadRequest, errors.New("bad_request"), "incident status cannot be changed")
+			}
+			newIncidentData.AcknowledgedBy = &ctx.Auth.ID
+			newIncidentData.AcknowledgedAt = general.Now()
+		case constant.INCIDENT_STATUS_RESOLVED:
+			if incidentData.Status == constant.INCIDENT_STATUS_RESOLVED {
+				return response.ErrorBuilder(http.StatusBadRequest, errors.New("bad_request"), "incident status cannot be changed")
+			}
+			newIncidentData.ResolvedBy = &ctx.Auth.ID
+			newIncidentData.ResolvedAt = general.Now()
+			newIncidentData.ResolutionNote = payload.Note
+		case constant.INCIDENT_STATUS_OPEN:
+			if incidentData.Status == constant.INCIDENT_STATUS_OPEN {
+				return response.ErrorBuilder(http.StatusBadRequest, errors.New("bad_request"), "incident status cannot be changed")
+			}
+		}
+
+		if err = s.IncidentRepository.Update(ctx, newIncidentData).Error; err != nil {
+			return response.ErrorBuilder(http.StatusInternalServerError, err, "server_error")
+		}
+		if payload.Status == constant.INCIDENT_STATUS_OPEN {
+			if err = s.IncidentRepository.UpdateToNull(ctx, newIncidentData, "acknowledged_by", "acknowledged_at", "resolved_by", "resolved_at", "resolution_note").Error; err != nil {
+				return response.ErrorBuilder(http.StatusInternalServerError, err, "server_error")
+			}
+		}
+
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+	return map[string]interface{}{
+		"message": "success update!",
+	}, nil
+}
+
+func (s *service) Assign(ctx *abstraction.Context, payload *dto.IncidentAssignRequest) (map[string]interface{}, error) {
+	if err := trxmanager.New(s.DB).WithTrx(ctx, func(ctx *abstraction.Context) error {
+		if ctx.Auth.RoleID != constant.ROLE_ID_ADMIN {
+			return response.ErrorBuilder(http.StatusBadRequest, errors.New("bad_request"), "this role is not permitted")
+		}
+
+		incidentData, err := s.findVisible(ctx, payload.ID)
+		if err != nil {
+			return err
+		}
+
+		newIncidentData := new(model.IncidentEntityModel)
+		newIncidentData.Context = ctx
+		newIncidentData.ID = incidentData.ID
+
+		// no assignee_id takes the incident back from the assignee
+		if payload.AssigneeId == nil {
+			if err = s.IncidentRepository.UpdateToNull(ctx, newIncidentData, "assignee_id").Error; err != nil {
+				return response.ErrorBuilder(http.StatusInternalServerError, err, "server_error")
+			}
+			return nil
+		}
+
+		userData, err := s.UserRepository.FindById(ctx, *payload.AssigneeId)
+		if err != nil && err.Error() != "record not found" {
+			return response.ErrorBuilder(http.StatusInternalServerError, err, "server_error")
+		}
+		if userData == nil {
+			return response.ErrorBuilder(http.StatusBadRequest, errors.New("bad_request"), "user not found")
+		}
+
+		newIncidentData.AssigneeId = &userData.ID
+		if err = s.IncidentRepository.Update(ctx, newIncidentData).Error; err != nil {
+			return response.ErrorBuilder(http.StatusInternalServerError, err, "server_error")
+		}
+
+		if userData.ID != ctx.Auth.ID && (incidentData.AssigneeId == nil || *incidentData.AssigneeId != userData.ID) {
+			if err = s.notifyAssignee(ctx, incidentData, userData); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+	return map[string]interface{}{
+		"message": "success update!",
+	}, nil
+}
+
+func (s *service) Delete(ctx *abstraction.Context, payload *dto.IncidentDeleteByIDRequest) (map[string]interface{}, error) {
+	if err := trxmanager.New(s.DB).WithTrx(ctx, func(ctx *abstraction.Context) error {
+		incidentData, err := s.findVisible(ctx, payload.ID)
+		if err != nil {
+			return err
+		}
+		if ctx.Auth.RoleID != constant.ROLE_ID_ADMIN && (incidentData.CreatedBy != ctx.Auth.ID || incidentData.Status != constant.INCIDENT_STATUS_OPEN) {
+			return response.ErrorBuilder(http.StatusBadRequest, errors.New("bad_request"), "this role is not permitted")
+		}
+
+		newIncidentData := new(model.IncidentEntityModel)
+		newIncidentData.Context = ctx
+		newIncidentData.ID = incidentData.ID
+		newIncidentData.IsDelete = true
+		newIncidentData.DeletedAt = general.Now()
+
+		if err = s.IncidentRepository.Update(ctx, newIncidentData).Error; err != nil {
+			return response.ErrorBuilder(http.StatusInternalServerError, err, "server_error")
+		}
+
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+	return map[string]interface{}{
+		"message": "success delete!",
+	}, nil
+}
+
+// CreatePhoto adds a photo to the incident, e.g. the assignee showing the fix.
+func (s *service) CreatePhoto(ctx *abstraction.Context, payload *dto.IncidentPhotoCreateRequest) (map[string]interface{}, error) {
+	var (
+		allFileUploaded []string = nil
+		res             map[string]interface{}
+	)
+	if err := trxmanager.New(s.DB).WithTrx(ctx, func(ctx *abstraction.Context) error {
+		incidentData, err := s.findVisible(ctx, payload.ID)
+		if err != nil {
+			return err
+		}
+
+		count, err := s.IncidentRepository.CountPhoto(ctx, incidentData.ID)
+		if err != nil {
+			return response.ErrorBuilder(http.StatusInternalServerError, err, "server_error")
+		}
+		if *count >= constant.INCIDENT_PHOTO_MAX {
+			return response.ErrorBuilder(http.StatusBadRequest, errors.New("bad_request"), "photo limit of the incident is reached")
+		}
+		sortOrder := 0
+		if len(incidentData.Photos) > 0 {
+			sortOrder = incidentData.Photos[len(incidentData.Photos)-1].SortOrder + 1
+		}
+
+		var image *string
+		if payload.File != nil {
+			newFile, _, err := upload.SaveImage(s.sDrive, s.fDrive, payload.File[0])
+			if err != nil {
+				return err
+			}
+			allFileUploaded = append(allFileUploaded, newFile.Id)
+
+			imgFileDelimiter := general.JoinFileAndNameWithDelimiter(newFile.Id, newFile.Name)
+			image = &imgFileDelimiter
+		} else if payload.UploadId != nil {
+			if image, _, err = upload.Claim(ctx, s.UploadSessionRepository, *payload.UploadId); err != nil {
+				return err
+			}
+		} else {
+			return response.ErrorBuilder(http.StatusBadRequest, errors.New("bad_request"), "photo is required")
+		}
+
+		fileId, fileName := general.SplitFileAndNameWithDelimiter(*image)
+		modelPhoto := &model.IncidentPhotoEntityModel{
+			Context: ctx,
+			IncidentPhotoEntity: model.IncidentPhotoEntity{
+				IncidentId: incidentData.ID,
+				StorageKey: fileId,
+				FileName:   fileName,
+				SortOrder:  sortOrder,
+			},
+		}
+		if err = s.IncidentRepository.CreatePhoto(ctx, modelPhoto).Error; err != nil {
+			return response.ErrorBuilder(http.StatusInternalServerError, err, "server_error")
+		}
+
+		res = photoToMap(modelPhoto)
+		return nil
+	}); err != nil {
+		for _, v := range allFileUploaded {
+			errDel := gdrive.DeleteFile(s.sDrive, v)
+			if errDel != nil {
+				logrus.Error("error delete file for error trxmanager:", errDel.Error())
+			}
+		}
+		return nil, err
+	}
+	return map[string]interface{}{
+		"message": "success create!",
+		"data":    res,
+	}, nil
+}
+
+func (s *service) DeletePhoto(ctx *abstraction.Context, payload *dto.IncidentPhotoDeleteRequest) (map[string]interface{}, error) {
+	var fileOld string
+	if err := trxmanager.New(s.DB).WithTrx(ctx, func(ctx *abstraction.Context) error {
+		incidentData, err := s.findVisible(ctx, payload.ID)
+		if err != nil {
+			return err
+		}
+
+		photoData, err := s.IncidentRepository.FindPhotoById(ctx, payload.PhotoId)
+		if err != nil && err.Error() != "record not found" {
+			return response.ErrorBuilder(http.StatusInternalServerError, err, "server_error")
+		}
+		if photoData == nil || photoData.IncidentId != incidentData.ID {
+			return response.ErrorBuilder(http.StatusBadRequest, errors.New("bad_request"), "photo not found")
+		}
+		if ctx.Auth.RoleID != constant.ROLE_ID_ADMIN && photoData.CreatedBy != ctx.Auth.ID {
+			return response.ErrorBuilder(http.StatusBadRequest, errors.New("bad_request"), "this role is not permitted")
+		}
+
+		if err = s.IncidentRepository.DeletePhoto(ctx, photoData).Error; err != nil {
+			return response.ErrorBuilder(http.StatusInternalServerError, err, "server_error")
+		}
+
+		fileOld = photoData.StorageKey
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+
+	if errDel := gdrive.DeleteFile(s.sDrive, fileOld); errDel != nil {
+		logrus.Error("error delete file old after trxmanager:", errDel.Error())
+	}
+
+	return map[string]interface{}{
+		"message": "success delete!",
+	}, nil
+}
+
+// findVisible returns the incident when the user may see it, the admin sees every incident
+// and the staff the ones they reported or are assigned to.
+func (s *service) findVisible(ctx *abstraction.Context, id int) (*model.IncidentEntityModel, error) {
+	data, err := s.IncidentRepository.FindById(ctx, id)
+	if err != nil && err.Error() != "record not found" {
+		return nil, response.ErrorBuilder(http.StatusInternalServerError, err, "server_error")
+	}
+	if data == nil {
+		return nil, response.ErrorBuilder(http.StatusBadRequest, errors.New("bad_request"), "incident not found")
+	}
+	if ctx.Auth.RoleID != constant.ROLE_ID_ADMIN && data.CreatedBy != ctx.Auth.ID && (data.AssigneeId == nil || *data.AssigneeId != ctx.Auth.ID) {
+		return nil, response.ErrorBuilder(http.StatusBadRequest, errors.New("bad_request"), "this user is not permitted")
+	}
+	return data, nil
+}
+
+// validateWork checks the work an incident links to, the staff can only link their own work.
+func (s *service) validateWork(ctx *abstraction.Context, work_id int) error {
+	workData, err := s.WorkRepository.FindById(ctx, work_id)
+	if err != nil && err.Error() != "record not found" {
+		return response.ErrorBuilder(http.StatusInternalServerError, err, "server_error")
+	}
+	if workData == nil {
+		return response.ErrorBuilder(http.StatusBadRequest, errors.New("bad_request"), "work not found")
+	}
+	if ctx.Auth.RoleID != constant.ROLE_ID_ADMIN && workData.UserId != ctx.Auth.ID {
+		return response.ErrorBuilder(http.StatusBadRequest, errors.New("bad_request"), "this user is not permitted")
+	}
+	return nil
+}
+
+// notifyAdmin queues an email to every admin with a verified email, except the reporter.
+func (s *service) notifyAdmin(ctx *abstraction.Context, data *model.IncidentEntityModel) error {
+	userAdmin, err := s.UserRepository.FindByRoleIdArr(ctx, constant.ROLE_ID_ADMIN, true)
+	if err != nil && err.Error() != "record not found" {
+		return response.ErrorBuilder(http.StatusInternalServerError, err, "server_error")
+	}
+	for _, v := range userAdmin {
+		if v.ID == ctx.Auth.ID || v.Email == nil || v.EmailVerifiedAt == nil {
+			continue
+		}
+		lang := i18n.Resolve(v.Language, ctx.Lang())
+		subject, body, err := gomail.TemplateIncidentReported.Render(lang, gomail.IncidentReportedData{
+			NAME:        v.Name,
+			REPORTER:    data.Reporter.Name,
+			CATEGORY:    data.Category,
+			SEVERITY:    data.Severity,
+			FLOOR:       data.Floor,
+			LOCATION:    data.Location,
+			DESCRIPTION: data.Description,
+		})
+		if err != nil {
+			return response.ErrorBuilder(http.StatusInternalServerError, err, "server_error")
+		}
+		if err = s.EmailOutboxRepository.Create(ctx, model.NewEmailOutbox(ctx, *v.Email, gomail.TemplateIncidentReported.Name, lang, subject, body)).Error; err != nil {
+			return response.ErrorBuilder(http.StatusInternalServerError, err, "server_error")
+		}
+	}
+	return nil
+}
+
+func (s *service) notifyAssignee(ctx *abstraction.Context, data *model.IncidentEntityModel, user *model.UserEntityModel) error {
+	if user.Email == nil || user.EmailVerifiedAt == nil {
+		return nil
+	}
+	lang := i18n.Resolve(user.Language, ctx.Lang())
+	subject, body, err := gomail.TemplateIncidentAssigned.Render(lang, gomail.IncidentAssignedData{
+		NAME:        user.Name,
+		CATEGORY:    data.Category,
+		SEVERITY:    data.Severity,
+		FLOOR:       data.Floor,
+		LOCATION:    data.Location,
+		DESCRIPTION: data.Description,
+	})
+	if err != nil {
+		return response.ErrorBuilder(http.StatusInternalServerError, err, "server_error")
+	}
+	if err = s.EmailOutboxRepository.Create(ctx, model.NewEmailOutbox(ctx, *user.Email, gomail.TemplateIncidentAssigned.Name, lang, subject, body)).Error; err != nil {
+		return response.ErrorBuilder(http.StatusInternalServerError, err, "server_error")
+	}
+	return nil
+}
+
+func toMap(v *model.IncidentEntityModel) map[string]interface{} {
+	res := map[string]interface{}{
+		"id":       v.ID,
+		"category": v.Category,
+		"severity": v.Severity,
+		"floor":    v.Floor,
+		"location": v.Location,
+		"reporter": map[string]interface{}{
+			"id":   v.Reporter.ID,
+			"name": v.Reporter.Name,
+		},
+		"assignee":        nil,
+		"description":     v.Description,
+		"work_id":         v.WorkId,
+		"status":          v.Status,
+		"acknowledged_by": v.AcknowledgedBy,
+		"acknowledged_at": nil,
+		"resolved_by":     v.ResolvedBy,
+		"resolved_at":     nil,
+		"resolution_note": v.ResolutionNote,
+		"created_at":      general.FormatWithZWithoutChangingTime(v.CreatedAt),
+		"updated_at":      nil,
+	}
+	if v.Assignee != nil {
+		res["assignee"] = map[string]interface{}{
+			"id":   v.Assignee.ID,
+			"name": v.Assignee.Name,
+		}
+	}
+	if v.AcknowledgedAt != nil {
+		res["acknowledged_at"] = general.FormatWithZWithoutChangingTime(*v.AcknowledgedAt)
+	}
+	if v.ResolvedAt != nil {
+		res["resolved_at"] = general.FormatWithZWithoutChangingTime(*v.ResolvedAt)
+	}
+	if v.UpdatedAt != nil {
+		res["updated_at"] = general.FormatWithZWithoutChangingTime(*v.UpdatedAt)
+	}
+	resPhotos := []map[string]interface{}{}
+	for i := range v.Photos {
+		resPhotos = append(resPhotos, photoToMap(&v.Photos[i]))
+	}
+	res["photos"] = resPhotos
+	return res
+}
+
+func photoToMap(v *model.IncidentPhotoEntityModel) map[string]interface{} {
+	return map[string]interface{}{
+		"id":         v.ID,
+		"sort_order": v.SortOrder,
+		"image": map[string]interface{}{
+			"view": "https://lh3.googleusercontent.com/d/" + v.StorageKey,
+			"name": v.FileName,
+			"id":   v.StorageKey,
+		},
+	}
+}
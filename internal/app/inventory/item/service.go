@@ -0,0 +1,179 @@
+package item
+
+import (
+	"cleancare/internal/abstraction"
+	"cleancare/internal/dto"
+	"cleancare/internal/factory"
+	"cleancare/internal/model"
+	"cleancare/internal/repository"
+	"cleancare/pkg/constant"
+	"cleancare/pkg/util/general"
+	"cleancare/pkg/util/response"
+	"cleancare/pkg/util/trxmanager"
+	"errors"
+	"net/http"
+
+	"gorm.io/gorm"
+)
+
+type Service interface {
+	Find(ctx *abstraction.Context) (map[string]interface{}, error)
+	Create(ctx *abstraction.Context, payload *dto.InventoryItemCreateRequest) (map[string]interface{}, error)
+	Update(ctx *abstraction.Context, payload *dto.InventoryItemUpdateRequest) (map[string]interface{}, error)
+	Delete(ctx *abstraction.Context, payload *dto.InventoryItemDeleteByIDRequest) (map[string]interface{}, error)
+}
+
+type service struct {
+	InventoryItemRepository  repository.InventoryItem
+	InventoryStockRepository repository.InventoryStock
+
+	DB *gorm.DB
+}
+
+func NewService(f *factory.Factory) Service {
+	return &service{
+		InventoryItemRepository:  f.InventoryItemRepository,
+		InventoryStockRepository: f.InventoryStockRepository,
+
+		DB: f.Db,
+	}
+}
+
+func (s *service) Find(ctx *abstraction.Context) (map[string]interface{}, error) {
+	data, err := s.InventoryItemRepository.Find(ctx, false)
+	if err != nil && err.Error() != "record not found" {
+		return nil, response.ErrorBuilder(http.StatusInternalServerError, err, "server_error")
+	}
+	count, err := s.InventoryItemRepository.Count(ctx)
+	if err != nil && err.Error() != "record not found" {
+		return nil, response.ErrorBuilder(http.StatusInternalServerError, err, "server_error")
+	}
+
+	var res []map[string]interface{} = nil
+	for _, v := range data {
+		res = append(res, map[string]interface{}{
+			"id":        v.ID,
+			"name":      v.Name,
+			"unit":      v.Unit,
+			"min_stock": v.MinStock,
+		})
+	}
+	return map[string]interface{}{
+		"count": count,
+		"meta":  general.OffsetMeta(ctx, false, len(data), count),
+		"data":  res,
+	}, nil
+}
+
+func (s *service) Create(ctx *abstraction.Context, payload *dto.InventoryItemCreateRequest) (map[string]interface{}, error) {
+	if err := trxmanager.New(s.DB).WithTrx(ctx, func(ctx *abstraction.Context) error {
+		if ctx.Auth.RoleID != constant.ROLE_ID_ADMIN {
+			return response.ErrorBuilder(http.StatusBadRequest, errors.New("bad_request"), "this role is not permitted")
+		}
+
+		modelInventoryItem := &model.InventoryItemEntityModel{
+			Context: ctx,
+			InventoryItemEntity: model.InventoryItemEntity{
+				Name:     payload.Name,
+				Unit:     payload.Unit,
+				MinStock: payload.MinStock,
+				IsDelete: false,
+			},
+		}
+		if err := s.InventoryItemRepository.Create(ctx, modelInventoryItem).Error; err != nil {
+			return response.ErrorBuilder(http.StatusInternalServerError, err, "server_error")
+		}
+
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+	return map[string]interface{}{
+		"message": "success create!",
+	}, nil
+}
+
+func (s *service) Update(ctx *abstraction.Context, payload *dto.InventoryItemUpdateRequest) (map[string]interface{}, error) {
+	if err := trxmanager.New(s.DB).WithTrx(ctx, func(ctx *abstraction.Context) error {
+		if ctx.Auth.RoleID != constant.ROLE_ID_ADMIN {
+			return response.ErrorBuilder(http.StatusBadRequest, errors.New("bad_request"), "this role is not permitted")
+		}
+
+		itemData, err := s.InventoryItemRepository.FindById(ctx, payload.ID)
+		if err != nil && err.Error() != "record not found" {
+			return response.ErrorBuilder(http.StatusInternalServerError, err, "server_error")
+		}
+		if itemData == nil {
+			return response.ErrorBuilder(http.StatusBadRequest, errors.New("bad_request"), "inventory item not found")
+		}
+
+		newItemData := new(model.InventoryItemEntityModel)
+		newItemData.Context = ctx
+		newItemData.ID = itemData.ID
+		if payload.Name != nil {
+			newItemData.Name = *payload.Name
+		}
+		if payload.Unit != nil {
+			newItemData.Unit = *payload.Unit
+		}
+
+		if err = s.InventoryItemRepository.Update(ctx, newItemData).Error; err != nil {
+			return response.ErrorBuilder(http.StatusInternalServerError, err, "server_error")
+		}
+		// min_stock 0 turns the low stock notification off
+		if payload.MinStock != nil {
+			if err = s.InventoryItemRepository.UpdateMinStock(ctx, newItemData, *payload.MinStock).Error; err != nil {
+				return response.ErrorBuilder(http.StatusInternalServerError, err, "server_error")
+			}
+		}
+
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+	return map[string]interface{}{
+		"message": "success update!",
+	}, nil
+}
+
+func (s *service) Delete(ctx *abstraction.Context, payload *dto.InventoryItemDeleteByIDRequest) (map[string]interface{}, error) {
+	if err := trxmanager.New(s.DB).WithTrx(ctx, func(ctx *abstraction.Context) error {
+		if ctx.Auth.RoleID != constant.ROLE_ID_ADMIN {
+			return response.ErrorBuilder(http.StatusBadRequest, errors.New("bad_request"), "this role is not permitted")
+		}
+
+		itemData, err := s.InventoryItemRepository.FindById(ctx, payload.ID)
+		if err != nil && err.Error() != "record not found" {
+			return response.ErrorBuilder(http.StatusInternalServerError, err, "server_error")
+		}
+		if itemData == nil {
+			return response.ErrorBuilder(http.StatusBadRequest, errors.New("bad_request"), "inventory item not found")
+		}
+
+		// the stock has to be taken out or moved first, it would drop out of the report
+		count, err := s.InventoryStockRepository.CountByItemId(ctx, itemData.ID)
+		if err != nil {
+			return response.ErrorBuilder(http.StatusInternalServerError, err, "server_error")
+		}
+		if *count > 0 {
+			return response.ErrorBuilder(http.StatusBadRequest, errors.New("bad_request"), "item is still in stock")
+		}
+
+		newItemData := new(model.InventoryItemEntityModel)
+		newItemData.Context = ctx
+		newItemData.ID = itemData.ID
+		newItemData.IsDelete = true
+		newItemData.DeletedAt = general.Now()
+
+		if err = s.InventoryItemRepository.Update(ctx, newItemData).Error; err != nil {
+			return response.ErrorBuilder(http.StatusInternalServerError, err, "server_error")
+		}
+
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+	return map[string]interface{}{
+		"message": "success delete!",
+	}, nil
+}
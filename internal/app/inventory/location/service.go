@@ -0,0 +1,171 @@
+package location
+
+import (
+	"cleancare/internal/abstraction"
+	"cleancare/internal/dto"
+	"cleancare/internal/factory"
+	"cleancare/internal/model"
+	"cleancare/internal/repository"
+	"cleancare/pkg/constant"
+	"cleancare/pkg/util/general"
+	"cleancare/pkg/util/response"
+	"cleancare/pkg/util/trxmanager"
+	"errors"
+	"net/http"
+
+	"gorm.io/gorm"
+)
+
+type Service interface {
+	Find(ctx *abstraction.Context) (map[string]interface{}, error)
+	Create(ctx *abstraction.Context, payload *dto.InventoryLocationCreateRequest) (map[string]interface{}, error)
+	Update(ctx *abstraction.Context, payload *dto.InventoryLocationUpdateRequest) (map[string]interface{}, error)
+	Delete(ctx *abstraction.Context, payload *dto.InventoryLocationDeleteByIDRequest) (map[string]interface{}, error)
+}
+
+type service struct {
+	InventoryLocationRepository repository.InventoryLocation
+	InventoryStockRepository    repository.InventoryStock
+
+	DB *gorm.DB
+}
+
+func NewService(f *factory.Factory) Service {
+	return &service{
+		InventoryLocationRepository: f.InventoryLocationRepository,
+		InventoryStockRepository:    f.InventoryStockRepository,
+
+		DB: f.Db,
+	}
+}
+
+func (s *service) Find(ctx *abstraction.Context) (map[string]interface{}, error) {
+	data, err := s.InventoryLocationRepository.Find(ctx, false)
+	if err != nil && err.Error() != "record not found" {
+		return nil, response.ErrorBuilder(http.StatusInternalServerError, err, "server_error")
+	}
+	count, err := s.InventoryLocationRepository.Count(ctx)
+	if err != nil && err.Error() != "record not found" {
+		return nil, response.ErrorBuilder(http.StatusInternalServerError, err, "server_error")
+	}
+
+	var res []map[string]interface{} = nil
+	for _, v := range data {
+		res = append(res, map[string]interface{}{
+			"id":    v.ID,
+			"name":  v.Name,
+			"floor": v.Floor,
+		})
+	}
+	return map[string]interface{}{
+		"count": count,
+		"meta":  general.OffsetMeta(ctx, false, len(data), count),
+		"data":  res,
+	}, nil
+}
+
+func (s *service) Create(ctx *abstraction.Context, payload *dto.InventoryLocationCreateRequest) (map[string]interface{}, error) {
+	if err := trxmanager.New(s.DB).WithTrx(ctx, func(ctx *abstraction.Context) error {
+		if ctx.Auth.RoleID != constant.ROLE_ID_ADMIN {
+			return response.ErrorBuilder(http.StatusBadRequest, errors.New("bad_request"), "this role is not permitted")
+		}
+
+		modelInventoryLocation := &model.InventoryLocationEntityModel{
+			Context: ctx,
+			InventoryLocationEntity: model.InventoryLocationEntity{
+				Name:     payload.Name,
+				Floor:    payload.Floor,
+				IsDelete: false,
+			},
+		}
+		if err := s.InventoryLocationRepository.Create(ctx, modelInventoryLocation).Error; err != nil {
+			return response.ErrorBuilder(http.StatusInternalServerError, err, "server_error")
+		}
+
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+	return map[string]interface{}{
+		"message": "success create!",
+	}, nil
+}
+
+func (s *service) Update(ctx *abstraction.Context, payload *dto.InventoryLocationUpdateRequest) (map[string]interface{}, error) {
+	if err := trxmanager.New(s.DB).WithTrx(ctx, func(ctx *abstraction.Context) error {
+		if ctx.Auth.RoleID != constant.ROLE_ID_ADMIN {
+			return response.ErrorBuilder(http.StatusBadRequest, errors.New("bad_request"), "this role is not permitted")
+		}
+
+		locationData, err := s.InventoryLocationRepository.FindById(ctx, payload.ID)
+		if err != nil && err.Error() != "record not found" {
+			return response.ErrorBuilder(http.StatusInternalServerError, err, "server_error")
+		}
+		if locationData == nil {
+			return response.ErrorBuilder(http.StatusBadRequest, errors.New("bad_request"), "inventory location not found")
+		}
+
+		newLocationData := new(model.InventoryLocationEntityModel)
+		newLocationData.Context = ctx
+		newLocationData.ID = locationData.ID
+		if payload.Name != nil {
+			newLocationData.Name = *payload.Name
+		}
+		if payload.Floor != nil {
+			newLocationData.Floor = *payload.Floor
+		}
+
+		if err = s.InventoryLocationRepository.Update(ctx, newLocationData).Error; err != nil {
+			return response.ErrorBuilder(http.StatusInternalServerError, err, "server_error")
+		}
+
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+	return map[string]interface{}{
+		"message": "success update!",
+	}, nil
+}
+
+func (s *service) Delete(ctx *abstraction.Context, payload *dto.InventoryLocationDeleteByIDRequest) (map[string]interface{}, error) {
+	if err := trxmanager.New(s.DB).WithTrx(ctx, func(ctx *abstraction.Context) error {
+		if ctx.Auth.RoleID != constant.ROLE_ID_ADMIN {
+			return response.ErrorBuilder(http.StatusBadRequest, errors.New("bad_request"), "this role is not permitted")
+		}
+
+		locationData, err := s.InventoryLocationRepository.FindById(ctx, payload.ID)
+		if err != nil && err.Error() != "record not found" {
+			return response.ErrorBuilder(http.StatusInternalServerError, err, "server_error")
+		}
+		if locationData == nil {
+			return response.ErrorBuilder(http.StatusBadRequest, errors.New("bad_request"), "inventory location not found")
+		}
+
+		// the stock has to be taken out or moved first, it would drop out of the report
+		count, err := s.InventoryStockRepository.CountByLocationId(ctx, locationData.ID)
+		if err != nil {
+			return response.ErrorBuilder(http.StatusInternalServerError, err, "server_error")
+		}
+		if *count > 0 {
+			return response.ErrorBuilder(http.StatusBadRequest, errors.New("bad_request"), "location still holds stock")
+		}
+
+		newLocationData := new(model.InventoryLocationEntityModel)
+		newLocationData.Context = ctx
+		newLocationData.ID = locationData.ID
+		newLocationData.IsDelete = true
+		newLocationData.DeletedAt = general.Now()
+
+		if err = s.InventoryLocationRepository.Update(ctx, newLocationData).Error; err != nil {
+			return response.ErrorBuilder(http.StatusInternalServerError, err, "server_error")
+		}
+
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+	return map[string]interface{}{
+		"message": "success delete!",
+	}, nil
+}
@@ -0,0 +1,18 @@
+package inventory
+
+import (
+	"cleancare/internal/middleware"
+
+	"github.com/labstack/echo/v4"
+)
+
+func (h *handler) Route(v *echo.Group) {
+	v.GET("/stock", h.FindStock, middleware.Authentication)
+	v.GET("/movement", h.FindMovement, middleware.Authentication)
+	v.POST("/movement", h.CreateMovement, middleware.Authentication, middleware.Idempotency)
+	v.GET("/report", h.Report, middleware.Authentication)
+	v.GET("/report/export", h.Export, middleware.Authentication)
+
+	h.ItemHandler.Route(v.Group("/item"))
+	h.LocationHandler.Route(v.Group("/location"))
+}
@@ -0,0 +1,484 @@
+package inventory
+
+import (
+	"bytes"
+	"cleancare/internal/abstraction"
+	"cleancare/internal/dto"
+	"cleancare/internal/factory"
+	"cleancare/internal/model"
+	"cleancare/internal/repository"
+	"cleancare/pkg/constant"
+	"cleancare/pkg/gomail"
+	"cleancare/pkg/i18n"
+	"cleancare/pkg/util/general"
+	"cleancare/pkg/util/response"
+	"cleancare/pkg/util/trxmanager"
+	"errors"
+	"fmt"
+	"math"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/jung-kurt/gofpdf"
+	"github.com/xuri/excelize/v2"
+	"gorm.io/gorm"
+)
+
+type Service interface {
+	FindStock(ctx *abstraction.Context) (map[string]interface{}, error)
+	CreateMovement(ctx *abstraction.Context, payload *dto.InventoryMovementCreateRequest) (map[string]interface{}, error)
+	FindMovement(ctx *abstraction.Context) (map[string]interface{}, error)
+	Report(ctx *abstraction.Context, payload *dto.InventoryReportRequest) (map[string]interface{}, error)
+	Export(ctx *abstraction.Context, payload *dto.InventoryReportExportRequest) (string, *bytes.Buffer, string, error)
+}
+
+type service struct {
+	InventoryItemRepository     repository.InventoryItem
+	InventoryLocationRepository repository.InventoryLocation
+	InventoryStockRepository    repository.InventoryStock
+	InventoryMovementRepository repository.InventoryMovement
+	WorkRepository              repository.Work
+	TaskTypeRepository          repository.TaskType
+	UserRepository              repository.User
+	EmailOutboxRepository       repository.EmailOutbox
+
+	DB *gorm.DB
+}
+
+func NewService(f *factory.Factory) Service {
+	return &service{
+		InventoryItemRepository:     f.InventoryItemRepository,
+		InventoryLocationRepository: f.InventoryLocationRepository,
+		InventoryStockRepository:    f.InventoryStockRepository,
+		InventoryMovementRepository: f.InventoryMovementRepository,
+		WorkRepository:              f.WorkRepository,
+		TaskTypeRepository:          f.TaskTypeRepository,
+		UserRepository:              f.UserRepository,
+		EmailOutboxRepository:       f.EmailOutboxRepository,
+
+		DB: f.Db,
+	}
+}
+
+func (s *service) FindStock(ctx *abstraction.Context) (map[string]interface{}, error) {
+	data, err := s.InventoryStockRepository.Find(ctx, false)
+	if err != nil && err.Error() != "record not found" {
+		return nil, response.ErrorBuilder(http.StatusInternalServerError, err, "server_error")
+	}
+	count, err := s.InventoryStockRepository.Count(ctx)
+	if err != nil && err.Error() != "record not found" {
+		return nil, response.ErrorBuilder(http.StatusInternalServerError, err, "server_error")
+	}
+
+	var res []map[string]interface{} = nil
+	for _, v := range data {
+		res = append(res, map[string]interface{}{
+			"id": v.ID,
+			"item": map[string]interface{}{
+				"id":        v.Item.ID,
+				"name":      v.Item.Name,
+				"unit":      v.Item.Unit,
+				"min_stock": v.Item.MinStock,
+			},
+			"location": map[string]interface{}{
+				"id":    v.Location.ID,
+				"name":  v.Location.Name,
+				"floor": v.Location.Floor,
+			},
+			"quantity": v.Quantity,
+			"is_low":   isLow(v.Quantity, v.Item.MinStock),
+		})
+	}
+	return map[string]interface{}{
+		"count": count,
+		"meta":  general.OffsetMeta(ctx, false, len(data), count),
+		"data":  res,
+	}, nil
+}
+
+// CreateMovement records stock taken in, taken out or counted at a location. The staff only
+// take stock out, optionally for one of their works.
+func (s *service) CreateMovement(ctx *abstraction.Context, payload *dto.InventoryMovementCreateRequest) (map[string]interface{}, error) {
+	var res map[string]interface{}
+	if err := trxmanager.New(s.DB).WithTrx(ctx, func(ctx *abstraction.Context) error {
+		if ctx.Auth.RoleID != constant.ROLE_ID_ADMIN && payload.Type != constant.INVENTORY_MOVEMENT_OUT {
+			return response.ErrorBuilder(http.StatusBadRequest, errors.New("bad_request"), "this role is not permitted")
+		}
+		if payload.Type != constant.INVENTORY_MOVEMENT_ADJUST && payload.Quantity <= 0 {
+			return response.ErrorBuilder(http.StatusBadRequest, errors.New("bad_request"), "quantity must be more than 0")
+		}
+
+		itemData, err := s.InventoryItemRepository.FindById(ctx, payload.ItemId)
+		if err != nil && err.Error() != "record not found" {
+			return response.ErrorBuilder(http.StatusInternalServerError, err, "server_error")
+		}
+		if itemData == nil {
+			return response.ErrorBuilder(http.StatusBadRequest, errors.New("bad_request"), "inventory item not found")
+		}
+		locationData, err := s.InventoryLocationRepository.FindById(ctx, payload.LocationId)
+		if err != nil && err.Error() != "record not found" {
+			return response.ErrorBuilder(http.StatusInternalServerError, err, "server_error")
+		}
+		if locationData == nil {
+			return response.ErrorBuilder(http.StatusBadRequest, errors.New("bad_request"), "inventory location not found")
+		}
+
+		taskTypeId := payload.TaskTypeId
+		if payload.WorkId != nil {
+			workData, err := s.WorkRepository.FindById(ctx, *payload.WorkId)
+			if err != nil && err.Error() != "record not found" {
+				return response.ErrorBuilder(http.StatusInternalServerError, err, "server_error")
+			}
+			if workData == nil {
+				return response.ErrorBuilder(http.StatusBadRequest, errors.New("bad_request"), "work not found")
+			}
+			if ctx.Auth.RoleID != constant.ROLE_ID_ADMIN && workData.UserId != ctx.Auth.ID {
+				return response.ErrorBuilder(http.StatusBadRequest, errors.New("bad_request"), "this user is not permitted")
+			}
+			// consumption of a work counts for its task type unless told otherwise
+			if taskTypeId == nil {
+				taskTypeId = &workData.TaskTypeId
+			}
+		}
+		if taskTypeId != nil {
+			taskTypeData, err := s.TaskTypeRepository.FindById(ctx, *taskTypeId)
+			if err != nil && err.Error() != "record not found" {
+				return response.ErrorBuilder(http.StatusInternalServerError, err, "server_error")
+			}
+			if taskTypeData == nil {
+				return response.ErrorBuilder(http.StatusBadRequest, errors.New("bad_request"), "task type not found")
+			}
+		}
+
+		stockData, err := s.InventoryStockRepository.FindForUpdate(ctx, itemData.ID, locationData.ID)
+		if err != nil {
+			return response.ErrorBuilder(http.StatusInternalServerError, err, "server_error")
+		}
+
+		var quantity float64
+		switch payload.Type {
+		case constant.INVENTORY_MOVEMENT_IN:
+			quantity = payload.Quantity
+		case constant.INVENTORY_MOVEMENT_OUT:
+			quantity = -payload.Quantity
+			if stockData.Quantity+quantity < 0 {
+				return response.ErrorBuilder(http.StatusBadRequest, errors.New("bad_request"), "stock is not enough")
+			}
+		case constant.INVENTORY_MOVEMENT_ADJUST:
+			quantity = payload.Quantity - stockData.Quantity
+		}
+		quantity = round(quantity)
+		stockAfter := round(stockData.Quantity + quantity)
+
+		if err = s.InventoryStockRepository.UpdateQuantity(ctx, stockData, stockAfter).Error; err != nil {
+			return response.ErrorBuilder(http.StatusInternalServerError, err, "server_error")
+		}
+
+		modelMovement := &model.InventoryMovementEntityModel{
+			Context: ctx,
+			InventoryMovementEntity: model.InventoryMovementEntity{
+				ItemId:     itemData.ID,
+				LocationId: locationData.ID,
+				Type:       payload.Type,
+				Quantity:   quantity,
+				StockAfter: stockAfter,
+				WorkId:     payload.WorkId,
+				TaskTypeId: taskTypeId,
+				Note:       payload.Note,
+			},
+		}
+		if err = s.InventoryMovementRepository.Create(ctx, modelMovement).Error; err != nil {
+			return response.ErrorBuilder(http.StatusInternalServerError, err, "server_error")
+		}
+
+		// notify once when the stock drops below the threshold, not on every movement after
+		if !isLow(stockData.Quantity, itemData.MinStock) && isLow(stockAfter, itemData.MinStock) {
+			if err = s.notifyLowStock(ctx, itemData, locationData, stockAfter); err != nil {
+				return err
+			}
+		}
+
+		modelMovement.Item = *itemData
+		modelMovement.Location = *locationData
+		res = movementToMap(modelMovement)
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+	return map[string]interface{}{
+		"message": "success create!",
+		"data":    res,
+	}, nil
+}
+
+func (s *service) FindMovement(ctx *abstraction.Context) (map[string]interface{}, error) {
+	userId := 0
+	if ctx.Auth.RoleID != constant.ROLE_ID_ADMIN {
+		userId = ctx.Auth.ID
+	}
+
+	data, err := s.InventoryMovementRepository.Find(ctx, userId, false)
+	if err != nil && err.Error() != "record not found" {
+		return nil, response.ErrorBuilder(http.StatusInternalServerError, err, "server_error")
+	}
+	count, err := s.InventoryMovementRepository.Count(ctx, userId)
+	if err != nil && err.Error() != "record not found" {
+		return nil, response.ErrorBuilder(http.StatusInternalServerError, err, "server_error")
+	}
+
+	var res []map[string]interface{} = nil
+	for _, v := range data {
+		row := movementToMap(v)
+		row["user"] = map[string]interface{}{
+			"id":   v.User.ID,
+			"name": v.User.Name,
+		}
+		row["task_type"] = nil
+		if v.TaskType != nil {
+			row["task_type"] = map[string]interface{}{
+				"id":   v.TaskType.ID,
+				"name": v.TaskType.Name,
+			}
+		}
+		res = append(res, row)
+	}
+	return map[string]interface{}{
+		"count": count,
+		"meta":  general.OffsetMeta(ctx, false, len(data), count),
+		"data":  res,
+	}, nil
+}
+
+func (s *service) Report(ctx *abstraction.Context, payload *dto.InventoryReportRequest) (map[string]interface{}, error) {
+	if ctx.Auth.RoleID != constant.ROLE_ID_ADMIN {
+		return nil, response.ErrorBuilder(http.StatusBadRequest, errors.New("bad_request"), "this role is not permitted")
+	}
+
+	month := parseMonth(payload.Month)
+	data, err := s.consumption(ctx, month, payload.LocationId)
+	if err != nil {
+		return nil, err
+	}
+
+	var res []map[string]interface{} = nil
+	for _, v := range data {
+		res = append(res, map[string]interface{}{
+			"item_id":   v.ItemId,
+			"name":      v.Name,
+			"unit":      v.Unit,
+			"stock_in":  v.StockIn,
+			"stock_out": v.StockOut,
+			"adjusted":  v.Adjusted,
+			"stock":     v.Stock,
+		})
+	}
+	return map[string]interface{}{
+		"month": month.Format("2006-01"),
+		"data":  res,
+	}, nil
+}
+
+func (s *service) Export(ctx *abstraction.Context, payload *dto.InventoryReportExportRequest) (string, *bytes.Buffer, string, error) {
+	if ctx.Auth.RoleID != constant.ROLE_ID_ADMIN {
+		return "", nil, "", response.ErrorBuilder(http.StatusBadRequest, errors.New("bad_request"), "this role is not permitted")
+	}
+
+	month := parseMonth(payload.Month)
+	data, err := s.consumption(ctx, month, payload.LocationId)
+	if err != nil {
+		return "", nil, "", err
+	}
+
+	lang := ctx.Lang()
+	title := i18n.T(lang, "export.inventory.title")
+	monthLabel := fmt.Sprintf("%s %d", i18n.T(lang, fmt.Sprintf("month.%d", int(month.Month()))), month.Year())
+	if payload.LocationId != 0 {
+		locationData, err := s.InventoryLocationRepository.FindById(ctx, payload.LocationId)
+		if err != nil && err.Error() != "record not found" {
+			return "", nil, "", response.ErrorBuilder(http.StatusInternalServerError, err, "server_error")
+		}
+		if locationData == nil {
+			return "", nil, "", response.ErrorBuilder(http.StatusBadRequest, errors.New("bad_request"), "inventory location not found")
+		}
+		monthLabel = fmt.Sprintf("%s, %s", locationData.Name, monthLabel)
+	}
+	headers := []string{
+		i18n.T(lang, "export.inventory.no"),
+		i18n.T(lang, "export.inventory.item"),
+		i18n.T(lang, "export.inventory.unit"),
+		i18n.T(lang, "export.inventory.stock_in"),
+		i18n.T(lang, "export.inventory.stock_out"),
+		i18n.T(lang, "export.inventory.adjusted"),
+		i18n.T(lang, "export.inventory.stock"),
+	}
+
+	var rows [][]string
+	for i, v := range data {
+		rows = append(rows, []string{
+			fmt.Sprintf("%d", i+1),
+			v.Name,
+			v.Unit,
+			formatQuantity(v.StockIn),
+			formatQuantity(v.StockOut),
+			formatQuantity(v.Adjusted),
+			formatQuantity(v.Stock),
+		})
+	}
+
+	filename := fmt.Sprintf("(%s) %s", month.Format("200601"), title)
+	if payload.Format == "pdf" {
+		pdf := gofpdf.New("L", "mm", "A4", "")
+		pdf.SetMargins(10, 10, 10)
+		pdf.AddPage()
+		pdf.SetAutoPageBreak(true, 10)
+		pdf.SetFont("Arial", "B", 16)
+		pdf.Cell(0, 10, fmt.Sprintf("%s (%s)", title, monthLabel))
+		pdf.Ln(12)
+		pdf.SetFont("Arial", "B", 10)
+		colWidths := []float64{15, 92, 30, 35, 35, 35, 35}
+		for i, str := range headers {
+			pdf.CellFormat(colWidths[i], 8, str, "1", 0, "C", false, 0, "")
+		}
+		pdf.Ln(-1)
+		pdf.SetFont("Arial", "", 9)
+		for _, row := range rows {
+			for j, txt := range row {
+				pdf.CellFormat(colWidths[j], 7, txt, "1", 0, "", false, 0, "")
+			}
+			pdf.Ln(-1)
+		}
+
+		var buf bytes.Buffer
+		if err := pdf.Output(&buf); err != nil {
+			return "", nil, "", response.ErrorBuilder(http.StatusInternalServerError, err, "server_error")
+		}
+		return filename + ".pdf", &buf, "pdf", nil
+	}
+
+	f := excelize.NewFile()
+	sheet := "CleanCare"
+	index, err := f.NewSheet(general.TruncateSheetName(sheet))
+	if err != nil {
+		return "", nil, "", response.ErrorBuilder(http.StatusInternalServerError, err, "server_error")
+	}
+	f.DeleteSheet("Sheet1")
+	f.SetActiveSheet(index)
+
+	maxLens := make([]int, len(headers))
+	for i, h := range headers {
+		f.SetCellValue(sheet, fmt.Sprintf("%s1", string(rune('A'+i))), h)
+		maxLens[i] = len(h)
+	}
+	for i, row := range rows {
+		for j, val := range row {
+			f.SetCellValue(sheet, fmt.Sprintf("%s%d", string(rune('A'+j)), i+2), val)
+			if len(val) > maxLens[j] {
+				maxLens[j] = len(val)
+			}
+		}
+	}
+	for i, length := range maxLens {
+		col := string(rune('A' + i))
+		width := float64(length)*1.2 + 2
+		if width > 60 {
+			width = 60
+		}
+		if err := f.SetColWidth(sheet, col, col, width); err != nil {
+			return "", nil, "", response.ErrorBuilder(http.StatusInternalServerError, err, "server_error")
+		}
+	}
+
+	var buf bytes.Buffer
+	if err := f.Write(&buf); err != nil {
+		return "", nil, "", response.ErrorBuilder(http.StatusInternalServerError, err, "server_error")
+	}
+	return filename + ".xlsx", &buf, "excel", nil
+}
+
+func (s *service) consumption(ctx *abstraction.Context, month time.Time, location_id int) ([]*model.InventoryConsumption, error) {
+	start, end := monthRange(month)
+	data, err := s.InventoryMovementRepository.Consumption(ctx, start, end, location_id)
+	if err != nil && err.Error() != "record not found" {
+		return nil, response.ErrorBuilder(http.StatusInternalServerError, err, "server_error")
+	}
+	return data, nil
+}
+
+// notifyLowStock queues an email to every admin with a verified email.
+func (s *service) notifyLowStock(ctx *abstraction.Context, item *model.InventoryItemEntityModel, location *model.InventoryLocationEntityModel, stock float64) error {
+	userAdmin, err := s.UserRepository.FindByRoleIdArr(ctx, constant.ROLE_ID_ADMIN, true)
+	if err != nil && err.Error() != "record not found" {
+		return response.ErrorBuilder(http.StatusInternalServerError, err, "server_error")
+	}
+	for _, v := range userAdmin {
+		if v.Email == nil || v.EmailVerifiedAt == nil {
+			continue
+		}
+		lang := i18n.Resolve(v.Language, ctx.Lang())
+		subject, body, err := gomail.TemplateInventoryLowStock.Render(lang, gomail.InventoryLowStockData{
+			NAME:      v.Name,
+			ITEM:      item.Name,
+			LOCATION:  location.Name,
+			STOCK:     formatQuantity(stock),
+			MIN_STOCK: formatQuantity(item.MinStock),
+			UNIT:      item.Unit,
+		})
+		if err != nil {
+			return response.ErrorBuilder(http.StatusInternalServerError, err, "server_error")
+		}
+		if err = s.EmailOutboxRepository.Create(ctx, model.NewEmailOutbox(ctx, *v.Email, gomail.TemplateInventoryLowStock.Name, lang, subject, body)).Error; err != nil {
+			return response.ErrorBuilder(http.StatusInternalServerError, err, "server_error")
+		}
+	}
+	return nil
+}
+
+func movementToMap(v *model.InventoryMovementEntityModel) map[string]interface{} {
+	return map[string]interface{}{
+		"id": v.ID,
+		"item": map[string]interface{}{
+			"id":   v.Item.ID,
+			"name": v.Item.Name,
+			"unit": v.Item.Unit,
+		},
+		"location": map[string]interface{}{
+			"id":   v.Location.ID,
+			"name": v.Location.Name,
+		},
+		"type":         v.Type,
+		"quantity":     v.Quantity,
+		"stock_after":  v.StockAfter,
+		"work_id":      v.WorkId,
+		"task_type_id": v.TaskTypeId,
+		"note":         v.Note,
+		"created_at":   general.FormatWithZWithoutChangingTime(v.CreatedAt),
+	}
+}
+
+func isLow(quantity, min_stock float64) bool {
+	return min_stock > 0 && quantity < min_stock
+}
+
+// round keeps the 2 decimals the columns store.
+func round(v float64) float64 {
+	return math.Round(v*100) / 100
+}
+
+func formatQuantity(v float64) string {
+	return strconv.FormatFloat(v, 'f', -1, 64)
+}
+
+func parseMonth(month string) time.Time {
+	if t, err := time.ParseInLocation("2006-01", month, time.Local); err == nil {
+		return t
+	}
+	now := general.Now()
+	return time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.Local)
+}
+
+func monthRange(month time.Time) (string, string) {
+	start := time.Date(month.Year(), month.Month(), 1, 0, 0, 0, 0, time.Local)
+	end := start.AddDate(0, 1, -1)
+	return start.Format("2006-01-02") + " 00:00:00", end.Format("2006-01-02") + " 23:59:59"
+}
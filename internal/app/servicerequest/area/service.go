@@ -0,0 +1,207 @@
+package area
+
+import (
+	"cleancare/internal/abstraction"
+	"cleancare/internal/dto"
+	"cleancare/internal/factory"
+	"cleancare/internal/model"
+	"cleancare/internal/repository"
+	"cleancare/pkg/constant"
+	"cleancare/pkg/util/general"
+	"cleancare/pkg/util/response"
+	"cleancare/pkg/util/trxmanager"
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type Service interface {
+	Find(ctx *abstraction.Context) (map[string]interface{}, error)
+	FindById(ctx *abstraction.Context, payload *dto.AreaFindByIDRequest) (map[string]interface{}, error)
+	Create(ctx *abstraction.Context, payload *dto.AreaCreateRequest) (map[string]interface{}, error)
+	Update(ctx *abstraction.Context, payload *dto.AreaUpdateRequest) (map[string]interface{}, error)
+	Delete(ctx *abstraction.Context, payload *dto.AreaDeleteByIDRequest) (map[string]interface{}, error)
+}
+
+type service struct {
+	AreaRepository repository.Area
+
+	DB *gorm.DB
+}
+
+func NewService(f *factory.Factory) Service {
+	return &service{
+		AreaRepository: f.AreaRepository,
+
+		DB: f.Db,
+	}
+}
+
+func (s *service) Find(ctx *abstraction.Context) (map[string]interface{}, error) {
+	data, err := s.AreaRepository.Find(ctx, false)
+	if err != nil && err.Error() != "record not found" {
+		return nil, response.ErrorBuilder(http.StatusInternalServerError, err, "server_error")
+	}
+	count, err := s.AreaRepository.Count(ctx)
+	if err != nil && err.Error() != "record not found" {
+		return nil, response.ErrorBuilder(http.StatusInternalServerError, err, "server_error")
+	}
+
+	var res []map[string]interface{} = nil
+	for _, v := range data {
+		res = append(res, toMap(v))
+	}
+	return map[string]interface{}{
+		"count": count,
+		"meta":  general.OffsetMeta(ctx, false, len(data), count),
+		"data":  res,
+	}, nil
+}
+
+func (s *service) FindById(ctx *abstraction.Context, payload *dto.AreaFindByIDRequest) (map[string]interface{}, error) {
+	data, err := s.AreaRepository.FindById(ctx, payload.ID)
+	if err != nil && err.Error() != "record not found" {
+		return nil, response.ErrorBuilder(http.StatusInternalServerError, err, "server_error")
+	}
+	if data == nil {
+		return nil, response.ErrorBuilder(http.StatusBadRequest, errors.New("bad_request"), "area not found")
+	}
+	return map[string]interface{}{
+		"data": toMap(data),
+	}, nil
+}
+
+func (s *service) Create(ctx *abstraction.Context, payload *dto.AreaCreateRequest) (map[string]interface{}, error) {
+	var res map[string]interface{}
+	if err := trxmanager.New(s.DB).WithTrx(ctx, func(ctx *abstraction.Context) error {
+		if ctx.Auth.RoleID != constant.ROLE_ID_ADMIN {
+			return response.ErrorBuilder(http.StatusBadRequest, errors.New("bad_request"), "this role is not permitted")
+		}
+
+		modelArea := &model.AreaEntityModel{
+			Context: ctx,
+			AreaEntity: model.AreaEntity{
+				Name:     payload.Name,
+				Floor:    payload.Floor,
+				Location: payload.Location,
+				Code:     newCode(),
+				IsActive: true,
+				IsDelete: false,
+			},
+		}
+		if err := s.AreaRepository.Create(ctx, modelArea).Error; err != nil {
+			return response.ErrorBuilder(http.StatusInternalServerError, err, "server_error")
+		}
+
+		res = toMap(modelArea)
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+	return map[string]interface{}{
+		"message": "success create!",
+		"data":    res,
+	}, nil
+}
+
+func (s *service) Update(ctx *abstraction.Context, payload *dto.AreaUpdateRequest) (map[string]interface{}, error) {
+	var res map[string]interface{}
+	if err := trxmanager.New(s.DB).WithTrx(ctx, func(ctx *abstraction.Context) error {
+		if ctx.Auth.RoleID != constant.ROLE_ID_ADMIN {
+			return response.ErrorBuilder(http.StatusBadRequest, errors.New("bad_request"), "this role is not permitted")
+		}
+
+		areaData, err := s.AreaRepository.FindById(ctx, payload.ID)
+		if err != nil && err.Error() != "record not found" {
+			return response.ErrorBuilder(http.StatusInternalServerError, err, "server_error")
+		}
+		if areaData == nil {
+			return response.ErrorBuilder(http.StatusBadRequest, errors.New("bad_request"), "area not found")
+		}
+
+		areaData.Context = ctx
+		if payload.Name != nil {
+			areaData.Name = *payload.Name
+		}
+		if payload.Floor != nil {
+			areaData.Floor = *payload.Floor
+		}
+		if payload.Location != nil {
+			areaData.Location = *payload.Location
+		}
+		if payload.IsActive != nil {
+			areaData.IsActive = *payload.IsActive
+		}
+		if payload.RenewCode {
+			areaData.Code = newCode()
+		}
+		if err = s.AreaRepository.Update(ctx, areaData).Error; err != nil {
+			return response.ErrorBuilder(http.StatusInternalServerError, err, "server_error")
+		}
+
+		res = toMap(areaData)
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+	return map[string]interface{}{
+		"message": "success update!",
+		"data":    res,
+	}, nil
+}
+
+// Delete removes the area from the list and its link, the requests sent from it are kept.
+func (s *service) Delete(ctx *abstraction.Context, payload *dto.AreaDeleteByIDRequest) (map[string]interface{}, error) {
+	if err := trxmanager.New(s.DB).WithTrx(ctx, func(ctx *abstraction.Context) error {
+		if ctx.Auth.RoleID != constant.ROLE_ID_ADMIN {
+			return response.ErrorBuilder(http.StatusBadRequest, errors.New("bad_request"), "this role is not permitted")
+		}
+
+		areaData, err := s.AreaRepository.FindById(ctx, payload.ID)
+		if err != nil && err.Error() != "record not found" {
+			return response.ErrorBuilder(http.StatusInternalServerError, err, "server_error")
+		}
+		if areaData == nil {
+			return response.ErrorBuilder(http.StatusBadRequest, errors.New("bad_request"), "area not found")
+		}
+
+		areaData.Context = ctx
+		if err = s.AreaRepository.Delete(ctx, areaData).Error; err != nil {
+			return response.ErrorBuilder(http.StatusInternalServerError, err, "server_error")
+		}
+
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+	return map[string]interface{}{
+		"message": "success delete!",
+	}, nil
+}
+
+// newCode returns a code that cannot be guessed from the ones of the other areas.
+func newCode() string {
+	return strings.ReplaceAll(uuid.NewString(), "-", "")
+}
+
+func toMap(v *model.AreaEntityModel) map[string]interface{} {
+	res := map[string]interface{}{
+		"id":            v.ID,
+		"name":          v.Name,
+		"floor":         v.Floor,
+		"location":      v.Location,
+		"code":          v.Code,
+		"link":          constant.BASE_URL + "/service-request/public/area/" + v.Code,
+		"feedback_link": constant.BASE_URL + "/feedback/public/area/" + v.Code,
+		"is_active":     v.IsActive,
+		"created_at":    general.FormatWithZWithoutChangingTime(v.CreatedAt),
+		"updated_at":    nil,
+	}
+	if v.UpdatedAt != nil {
+		res["updated_at"] = general.FormatWithZWithoutChangingTime(*v.UpdatedAt)
+	}
+	return res
+}
@@ -0,0 +1,20 @@
+package servicerequest
+
+import (
+	"cleancare/internal/middleware"
+
+	"github.com/labstack/echo/v4"
+)
+
+func (h *handler) Route(v *echo.Group) {
+	// the request form is opened from a qr code, without an account
+	v.GET("/public/area/:code", h.PublicArea)
+	v.POST("/public/area/:code", h.PublicCreate, middleware.ServiceRequestIpCheck)
+	v.GET("/public/track/:token", h.Track)
+
+	v.GET("", h.Find, middleware.Authentication)
+	v.GET("/:id", h.FindById, middleware.Authentication)
+	v.PUT("/:id/triage", h.Triage, middleware.Authentication)
+
+	h.AreaHandler.Route(v.Group("/area"))
+}
@@ -0,0 +1,408 @@
+package servicerequest
+
+import (
+	"cleancare/internal/abstraction"
+	"cleancare/internal/app/upload"
+	"cleancare/internal/dto"
+	"cleancare/internal/factory"
+	"cleancare/internal/model"
+	modelToken "cleancare/internal/model/token"
+	"cleancare/internal/repository"
+	"cleancare/pkg/constant"
+	"cleancare/pkg/gdrive"
+	"cleancare/pkg/util/general"
+	"cleancare/pkg/util/response"
+	"cleancare/pkg/util/trxmanager"
+	"cleancare/pkg/ws"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"google.golang.org/api/drive/v3"
+	"gorm.io/gorm"
+)
+
+type Service interface {
+	PublicArea(ctx *abstraction.Context, payload *dto.ServiceRequestPublicAreaRequest) (map[string]interface{}, error)
+	PublicCreate(ctx *abstraction.Context, payload *dto.ServiceRequestPublicCreateRequest) (map[string]interface{}, error)
+	Track(ctx *abstraction.Context, payload *dto.ServiceRequestTrackRequest) (map[string]interface{}, error)
+	Find(ctx *abstraction.Context) (map[string]interface{}, error)
+	FindById(ctx *abstraction.Context, payload *dto.ServiceRequestFindByIDRequest) (map[string]interface{}, error)
+	Triage(ctx *abstraction.Context, payload *dto.ServiceRequestTriageRequest) (map[string]interface{}, error)
+}
+
+type service struct {
+	ServiceRequestRepository repository.ServiceRequest
+	AreaRepository           repository.Area
+	AssignmentRepository     repository.Assignment
+	UserRepository           repository.User
+	TaskRepository           repository.Task
+	TaskTypeRepository       repository.TaskType
+
+	DB     *gorm.DB
+	sDrive *drive.Service
+	fDrive *drive.File
+}
+
+func NewService(f *factory.Factory) Service {
+	return &service{
+		ServiceRequestRepository: f.ServiceRequestRepository,
+		AreaRepository:           f.AreaRepository,
+		AssignmentRepository:     f.AssignmentRepository,
+		UserRepository:           f.UserRepository,
+		TaskRepository:           f.TaskRepository,
+		TaskTypeRepository:       f.TaskTypeRepository,
+
+		DB:     f.Db,
+		sDrive: f.GDrive.Service,
+		fDrive: f.GDrive.FolderCleanCare,
+	}
+}
+
+// PublicArea tells the request form which area the qr code belongs to.
+func (s *service) PublicArea(ctx *abstraction.Context, payload *dto.ServiceRequestPublicAreaRequest) (map[string]interface{}, error) {
+	data, err := s.AreaRepository.FindByCode(ctx, payload.Code)
+	if err != nil && err.Error() != "record not found" {
+		return nil, response.ErrorBuilder(http.StatusInternalServerError, err, "server_error")
+	}
+	if data == nil {
+		return nil, response.ErrorBuilder(http.StatusBadRequest, errors.New("bad_request"), "area not found")
+	}
+	return map[string]interface{}{
+		"data": map[string]interface{}{
+			"name":     data.Name,
+			"floor":    data.Floor,
+			"location": data.Location,
+		},
+	}, nil
+}
+
+// PublicCreate takes a request from an occupant without an account, the returned link is the
+// only way for them to follow it.
+func (s *service) PublicCreate(ctx *abstraction.Context, payload *dto.ServiceRequestPublicCreateRequest) (map[string]interface{}, error) {
+	var (
+		allFileUploaded []string = nil
+		res             map[string]interface{}
+		adminIds        []int
+		requestId       int
+	)
+	if err := trxmanager.New(s.DB).WithTrx(ctx, func(ctx *abstraction.Context) error {
+		if len(payload.Photo) > 1 {
+			return response.ErrorBuilder(http.StatusBadRequest, errors.New("bad_request"), "only one photo can be sent")
+		}
+
+		areaData, err := s.AreaRepository.FindByCode(ctx, payload.Code)
+		if err != nil && err.Error() != "record not found" {
+			return response.ErrorBuilder(http.StatusInternalServerError, err, "server_error")
+		}
+		if areaData == nil {
+			return response.ErrorBuilder(http.StatusBadRequest, errors.New("bad_request"), "area not found")
+		}
+
+		modelRequest := &model.ServiceRequestEntityModel{
+			Context: ctx,
+			ServiceRequestEntity: model.ServiceRequestEntity{
+				AreaId:           areaData.ID,
+				Description:      payload.Description,
+				RequesterName:    payload.RequesterName,
+				RequesterContact: payload.RequesterContact,
+				Ip:               ctx.RealIP(),
+				Status:           constant.SERVICE_REQUEST_STATUS_PENDING,
+			},
+		}
+		for _, v := range payload.Photo {
+			newFile, _, err := upload.SaveImage(s.sDrive, s.fDrive, v)
+			if err != nil {
+				return err
+			}
+			allFileUploaded = append(allFileUploaded, newFile.Id)
+			modelRequest.PhotoStorageKey = &newFile.Id
+			modelRequest.PhotoFileName = &newFile.Name
+		}
+		if err = s.ServiceRequestRepository.Create(ctx, modelRequest).Error; err != nil {
+			return response.ErrorBuilder(http.StatusInternalServerError, err, "server_error")
+		}
+
+		trackToken := &modelToken.ServiceRequestToken{ServiceRequestId: modelRequest.ID}
+		token, err := trackToken.GenerateToken()
+		if err != nil {
+			return response.ErrorBuilder(http.StatusInternalServerError, err, "server_error")
+		}
+
+		userAdmin, err := s.UserRepository.FindAllByRoleId(ctx, constant.ROLE_ID_ADMIN)
+		if err != nil && err.Error() != "record not found" {
+			return response.ErrorBuilder(http.StatusInternalServerError, err, "server_error")
+		}
+		for _, v := range userAdmin {
+			adminIds = append(adminIds, v.ID)
+		}
+
+		requestId = modelRequest.ID
+		res = map[string]interface{}{
+			"id":         modelRequest.ID,
+			"status":     modelRequest.Status,
+			"token":      *token,
+			"track_link": constant.BASE_URL + "/service-request/public/track/" + *token,
+		}
+		return nil
+	}); err != nil {
+		for _, v := range allFileUploaded {
+			errDel := gdrive.DeleteFile(s.sDrive, v)
+			if errDel != nil {
+				logrus.Error("error delete file for error trxmanager:", errDel.Error())
+			}
+		}
+		return nil, err
+	}
+
+	for _, id := range adminIds {
+		if err := ws.Publish(id, map[string]interface{}{
+			"type":               "service_request",
+			"service_request_id": requestId,
+		}); err != nil {
+			logrus.Errorf("error publish service request to user %d: %s", id, err.Error())
+		}
+	}
+
+	return map[string]interface{}{
+		"message": "success create!",
+		"data":    res,
+	}, nil
+}
+
+// Track shows the requester how far the request is, without the details kept for the admins.
+func (s *service) Track(ctx *abstraction.Context, payload *dto.ServiceRequestTrackRequest) (map[string]interface{}, error) {
+	tokenData, err := modelToken.ValidateServiceRequestToken(payload.Token)
+	if err != nil {
+		return nil, response.ErrorBuilder(http.StatusBadRequest, errors.New("bad_request"), "your token is invalid")
+	}
+
+	data, err := s.ServiceRequestRepository.FindById(ctx, tokenData.ServiceRequestId)
+	if err != nil && err.Error() != "record not found" {
+		return nil, response.ErrorBuilder(http.StatusInternalServerError, err, "server_error")
+	}
+	if data == nil {
+		return nil, response.ErrorBuilder(http.StatusBadRequest, errors.New("bad_request"), "service request not found")
+	}
+	status, completedAt := progress(data)
+
+	res := map[string]interface{}{
+		"id": data.ID,
+		"area": map[string]interface{}{
+			"name":     data.Area.Name,
+			"floor":    data.Area.Floor,
+			"location": data.Area.Location,
+		},
+		"description":  data.Description,
+		"status":       status,
+		"note":         data.TriageNote,
+		"created_at":   general.FormatWithZWithoutChangingTime(data.CreatedAt),
+		"triaged_at":   nil,
+		"completed_at": nil,
+	}
+	if data.TriagedAt != nil {
+		res["triaged_at"] = general.FormatWithZWithoutChangingTime(*data.TriagedAt)
+	}
+	if completedAt != nil {
+		res["completed_at"] = general.FormatWithZWithoutChangingTime(*completedAt)
+	}
+	return map[string]interface{}{
+		"data": res,
+	}, nil
+}
+
+func (s *service) Find(ctx *abstraction.Context) (map[string]interface{}, error) {
+	if ctx.Auth.RoleID != constant.ROLE_ID_ADMIN {
+		return nil, response.ErrorBuilder(http.StatusBadRequest, errors.New("bad_request"), "this role is not permitted")
+	}
+
+	data, err := s.ServiceRequestRepository.Find(ctx, false)
+	if err != nil && err.Error() != "record not found" {
+		return nil, response.ErrorBuilder(http.StatusInternalServerError, err, "server_error")
+	}
+	count, err := s.ServiceRequestRepository.Count(ctx)
+	if err != nil && err.Error() != "record not found" {
+		return nil, response.ErrorBuilder(http.StatusInternalServerError, err, "server_error")
+	}
+
+	var res []map[string]interface{} = nil
+	for _, v := range data {
+		res = append(res, toMap(v))
+	}
+	return map[string]interface{}{
+		"count": count,
+		"meta":  general.OffsetMeta(ctx, false, len(data), count),
+		"data":  res,
+	}, nil
+}
+
+func (s *service) FindById(ctx *abstraction.Context, payload *dto.ServiceRequestFindByIDRequest) (map[string]interface{}, error) {
+	if ctx.Auth.RoleID != constant.ROLE_ID_ADMIN {
+		return nil, response.ErrorBuilder(http.StatusBadRequest, errors.New("bad_request"), "this role is not permitted")
+	}
+
+	data, err := s.ServiceRequestRepository.FindById(ctx, payload.ID)
+	if err != nil && err.Error() != "record not found" {
+		return nil, response.ErrorBuilder(http.StatusInternalServerError, err, "server_error")
+	}
+	if data == nil {
+		return nil, response.ErrorBuilder(http.StatusBadRequest, errors.New("bad_request"), "service request not found")
+	}
+	return map[string]interface{}{
+		"data": toMap(data),
+	}, nil
+}
+
+// Triage turns a pending request into an assignment for a staff, or rejects it. A request is
+// only triaged once, the assignment is changed through its own endpoints afterwards.
+func (s *service) Triage(ctx *abstraction.Context, payload *dto.ServiceRequestTriageRequest) (map[string]interface{}, error) {
+	if err := trxmanager.New(s.DB).WithTrx(ctx, func(ctx *abstraction.Context) error {
+		if ctx.Auth.RoleID != constant.ROLE_ID_ADMIN {
+			return response.ErrorBuilder(http.StatusBadRequest, errors.New("bad_request"), "this role is not permitted")
+		}
+
+		requestData, err := s.ServiceRequestRepository.FindByIdForUpdate(ctx, payload.ID)
+		if err != nil && err.Error() != "record not found" {
+			return response.ErrorBuilder(http.StatusInternalServerError, err, "server_error")
+		}
+		if requestData == nil {
+			return response.ErrorBuilder(http.StatusBadRequest, errors.New("bad_request"), "service request not found")
+		}
+		if requestData.Status != constant.SERVICE_REQUEST_STATUS_PENDING {
+			return response.ErrorBuilder(http.StatusBadRequest, errors.New("bad_request"), "service request is already triaged")
+		}
+
+		requestData.Context = ctx
+		requestData.Status = constant.SERVICE_REQUEST_STATUS_REJECTED
+		requestData.TriageNote = payload.Note
+		requestData.TriagedBy = &ctx.Auth.ID
+		requestData.TriagedAt = general.Now()
+		if payload.Action == "assign" {
+			if err = s.validateStaff(ctx, payload.UserId); err != nil {
+				return err
+			}
+			if err = s.validateTask(ctx, payload.TaskId, payload.TaskTypeId); err != nil {
+				return err
+			}
+
+			info := requestData.Description
+			if requestData.Area.Location != "" {
+				info = requestData.Area.Location + " - " + info
+			}
+			dueAt, _ := time.ParseInLocation("2006-01-02 15:04:05", payload.DueAt, time.Local)
+			modelAssignment := &model.AssignmentEntityModel{
+				Context: ctx,
+				AssignmentEntity: model.AssignmentEntity{
+					UserId:     payload.UserId,
+					TaskId:     payload.TaskId,
+					TaskTypeId: payload.TaskTypeId,
+					AreaId:     &requestData.AreaId,
+					Floor:      requestData.Area.Floor,
+					Info:       info,
+					DueAt:      dueAt,
+					IsDelete:   false,
+				},
+			}
+			if err = s.AssignmentRepository.Create(ctx, modelAssignment).Error; err != nil {
+				return response.ErrorBuilder(http.StatusInternalServerError, err, "server_error")
+			}
+			requestData.Status = constant.SERVICE_REQUEST_STATUS_ASSIGNED
+			requestData.AssignmentId = &modelAssignment.ID
+		}
+
+		if err = s.ServiceRequestRepository.Triage(ctx, requestData).Error; err != nil {
+			return response.ErrorBuilder(http.StatusInternalServerError, err, "server_error")
+		}
+
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+	return map[string]interface{}{
+		"message": "success update!",
+	}, nil
+}
+
+// progress follows an assigned request to the work made for its assignment, the request is
+// done once that work is completed.
+func progress(data *model.ServiceRequestEntityModel) (string, *time.Time) {
+	if data.Status != constant.SERVICE_REQUEST_STATUS_ASSIGNED || data.Assignment == nil || data.Assignment.Work == nil {
+		return data.Status, nil
+	}
+	if data.Assignment.Work.CompletedAt != nil {
+		return constant.SERVICE_REQUEST_STATUS_DONE, data.Assignment.Work.CompletedAt
+	}
+	return constant.SERVICE_REQUEST_STATUS_IN_PROGRESS, nil
+}
+
+func (s *service) validateStaff(ctx *abstraction.Context, userId int) error {
+	userData, err := s.UserRepository.FindById(ctx, userId)
+	if err != nil && err.Error() != "record not found" {
+		return response.ErrorBuilder(http.StatusInternalServerError, err, "server_error")
+	}
+	if userData == nil {
+		return response.ErrorBuilder(http.StatusBadRequest, errors.New("bad_request"), "user not found")
+	}
+	if userData.RoleId != constant.ROLE_ID_STAFF {
+		return response.ErrorBuilder(http.StatusBadRequest, errors.New("bad_request"), "this user is not permitted")
+	}
+	return nil
+}
+
+func (s *service) validateTask(ctx *abstraction.Context, taskId, taskTypeId int) error {
+	taskData, err := s.TaskRepository.FindById(ctx, taskId)
+	if err != nil && err.Error() != "record not found" {
+		return response.ErrorBuilder(http.StatusInternalServerError, err, "server_error")
+	}
+	if taskData == nil {
+		return response.ErrorBuilder(http.StatusBadRequest, errors.New("bad_request"), "task not found")
+	}
+	taskTypeData, err := s.TaskTypeRepository.FindById(ctx, taskTypeId)
+	if err != nil && err.Error() != "record not found" {
+		return response.ErrorBuilder(http.StatusInternalServerError, err, "server_error")
+	}
+	if taskTypeData == nil || taskTypeData.TaskId != taskId {
+		return response.ErrorBuilder(http.StatusBadRequest, errors.New("bad_request"), "task type not found")
+	}
+	return nil
+}
+
+func toMap(v *model.ServiceRequestEntityModel) map[string]interface{} {
+	status, completedAt := progress(v)
+	res := map[string]interface{}{
+		"id": v.ID,
+		"area": map[string]interface{}{
+			"id":       v.Area.ID,
+			"name":     v.Area.Name,
+			"floor":    v.Area.Floor,
+			"location": v.Area.Location,
+		},
+		"description":       v.Description,
+		"requester_name":    v.RequesterName,
+		"requester_contact": v.RequesterContact,
+		"photo":             nil,
+		"status":            v.Status,
+		"progress":          status,
+		"assignment_id":     v.AssignmentId,
+		"triage_note":       v.TriageNote,
+		"triaged_by":        v.TriagedBy,
+		"triaged_at":        nil,
+		"completed_at":      nil,
+		"created_at":        general.FormatWithZWithoutChangingTime(v.CreatedAt),
+	}
+	if v.PhotoStorageKey != nil {
+		res["photo"] = map[string]interface{}{
+			"view": "https://lh3.googleusercontent.com/d/" + *v.PhotoStorageKey,
+			"name": v.PhotoFileName,
+			"id":   *v.PhotoStorageKey,
+		}
+	}
+	if v.TriagedAt != nil {
+		res["triaged_at"] = general.FormatWithZWithoutChangingTime(*v.TriagedAt)
+	}
+	if completedAt != nil {
+		res["completed_at"] = general.FormatWithZWithoutChangingTime(*completedAt)
+	}
+	return res
+}
@@ -0,0 +1,15 @@
+package sla
+
+import (
+	"cleancare/internal/middleware"
+
+	"github.com/labstack/echo/v4"
+)
+
+func (h *handler) Route(v *echo.Group) {
+	v.GET("/policy", h.Find, middleware.Authentication)
+	v.POST("/policy", h.Create, middleware.Authentication)
+	v.GET("/policy/:id", h.FindById, middleware.Authentication)
+	v.PUT("/policy/:id", h.Update, middleware.Authentication)
+	v.DELETE("/policy/:id", h.Delete, middleware.Authentication)
+}
@@ -0,0 +1,267 @@
+package sla
+
+import (
+	"cleancare/internal/abstraction"
+	"cleancare/internal/dto"
+	"cleancare/internal/factory"
+	"cleancare/internal/model"
+	"cleancare/internal/repository"
+	"cleancare/pkg/constant"
+	"cleancare/pkg/util/general"
+	"cleancare/pkg/util/response"
+	"cleancare/pkg/util/trxmanager"
+	"errors"
+	"net/http"
+
+	"gorm.io/gorm"
+)
+
+type Service interface {
+	Find(ctx *abstraction.Context) (map[string]interface{}, error)
+	FindById(ctx *abstraction.Context, payload *dto.SlaPolicyFindByIDRequest) (map[string]interface{}, error)
+	Create(ctx *abstraction.Context, payload *dto.SlaPolicyCreateRequest) (map[string]interface{}, error)
+	Update(ctx *abstraction.Context, payload *dto.SlaPolicyUpdateRequest) (map[string]interface{}, error)
+	Delete(ctx *abstraction.Context, payload *dto.SlaPolicyDeleteByIDRequest) (map[string]interface{}, error)
+}
+
+type service struct {
+	SlaPolicyRepository   repository.SlaPolicy
+	TaskRepository        repository.Task
+	TaskTypeRepository    repository.TaskType
+	WorkRepository        repository.Work
+	UserRepository        repository.User
+	EmailOutboxRepository repository.EmailOutbox
+
+	DB *gorm.DB
+}
+
+func NewService(f *factory.Factory) Service {
+	return newService(f)
+}
+
+func newService(f *factory.Factory) *service {
+	return &service{
+		SlaPolicyRepository:   f.SlaPolicyRepository,
+		TaskRepository:        f.TaskRepository,
+		TaskTypeRepository:    f.TaskTypeRepository,
+		WorkRepository:        f.WorkRepository,
+		UserRepository:        f.UserRepository,
+		EmailOutboxRepository: f.EmailOutboxRepository,
+
+		DB: f.Db,
+	}
+}
+
+func (s *service) Find(ctx *abstraction.Context) (map[string]interface{}, error) {
+	data, err := s.SlaPolicyRepository.Find(ctx, false)
+	if err != nil && err.Error() != "record not found" {
+		return nil, response.ErrorBuilder(http.StatusInternalServerError, err, "server_error")
+	}
+	count, err := s.SlaPolicyRepository.Count(ctx)
+	if err != nil && err.Error() != "record not found" {
+		return nil, response.ErrorBuilder(http.StatusInternalServerError, err, "server_error")
+	}
+	var res []map[string]interface{} = nil
+	for _, v := range data {
+		res = append(res, toMap(v))
+	}
+	return map[string]interface{}{
+		"count": count,
+		"meta":  general.OffsetMeta(ctx, false, len(data), count),
+		"data":  res,
+	}, nil
+}
+
+func (s *service) FindById(ctx *abstraction.Context, payload *dto.SlaPolicyFindByIDRequest) (map[string]interface{}, error) {
+	data, err := s.SlaPolicyRepository.FindById(ctx, payload.ID)
+	if err != nil && err.Error() != "record not found" {
+		return nil, response.ErrorBuilder(http.StatusInternalServerError, err, "server_error")
+	}
+	if data == nil {
+		return nil, response.ErrorBuilder(http.StatusBadRequest, errors.New("bad_request"), "sla policy not found")
+	}
+	return map[string]interface{}{
+		"data": toMap(data),
+	}, nil
+}
+
+// Create adds the policy of a task, or of one task type of it. Works created before keep the
+// due times they were given.
+func (s *service) Create(ctx *abstraction.Context, payload *dto.SlaPolicyCreateRequest) (map[string]interface{}, error) {
+	var res map[string]interface{}
+	if err := trxmanager.New(s.DB).WithTrx(ctx, func(ctx *abstraction.Context) error {
+		if ctx.Auth.RoleID != constant.ROLE_ID_ADMIN {
+			return response.ErrorBuilder(http.StatusBadRequest, errors.New("bad_request"), "this role is not permitted")
+		}
+
+		taskData, err := s.TaskRepository.FindById(ctx, payload.TaskId)
+		if err != nil && err.Error() != "record not found" {
+			return response.ErrorBuilder(http.StatusInternalServerError, err, "server_error")
+		}
+		if taskData == nil {
+			return response.ErrorBuilder(http.StatusBadRequest, errors.New("bad_request"), "task not found")
+		}
+		if payload.TaskTypeId != nil {
+			taskTypeData, err := s.TaskTypeRepository.FindById(ctx, *payload.TaskTypeId)
+			if err != nil && err.Error() != "record not found" {
+				return response.ErrorBuilder(http.StatusInternalServerError, err, "server_error")
+			}
+			if taskTypeData == nil || taskTypeData.TaskId != taskData.ID {
+				return response.ErrorBuilder(http.StatusBadRequest, errors.New("bad_request"), "task type not found")
+			}
+		}
+
+		policyData, err := s.SlaPolicyRepository.FindByTaskAndType(ctx, payload.TaskId, payload.TaskTypeId)
+		if err != nil && err.Error() != "record not found" {
+			return response.ErrorBuilder(http.StatusInternalServerError, err, "server_error")
+		}
+		if policyData != nil {
+			return response.ErrorBuilder(http.StatusBadRequest, errors.New("bad_request"), "sla policy already exists")
+		}
+
+		atRiskPercent := constant.SLA_AT_RISK_PERCENT_DEFAULT
+		if payload.AtRiskPercent != nil {
+			atRiskPercent = *payload.AtRiskPercent
+		}
+		modelPolicy := &model.SlaPolicyEntityModel{
+			Context: ctx,
+			SlaPolicyEntity: model.SlaPolicyEntity{
+				TaskId:          payload.TaskId,
+				TaskTypeId:      payload.TaskTypeId,
+				StartMinutes:    payload.StartMinutes,
+				CompleteMinutes: payload.CompleteMinutes,
+				AtRiskPercent:   atRiskPercent,
+				IsDelete:        false,
+			},
+		}
+		if err = validateLimits(&modelPolicy.SlaPolicyEntity); err != nil {
+			return err
+		}
+		if err = s.SlaPolicyRepository.Create(ctx, modelPolicy).Error; err != nil {
+			return response.ErrorBuilder(http.StatusInternalServerError, err, "server_error")
+		}
+
+		modelPolicy.Task = *taskData
+		res = toMap(modelPolicy)
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+	return map[string]interface{}{
+		"message": "success create!",
+		"data":    res,
+	}, nil
+}
+
+func (s *service) Update(ctx *abstraction.Context, payload *dto.SlaPolicyUpdateRequest) (map[string]interface{}, error) {
+	var res map[string]interface{}
+	if err := trxmanager.New(s.DB).WithTrx(ctx, func(ctx *abstraction.Context) error {
+		if ctx.Auth.RoleID != constant.ROLE_ID_ADMIN {
+			return response.ErrorBuilder(http.StatusBadRequest, errors.New("bad_request"), "this role is not permitted")
+		}
+
+		policyData, err := s.SlaPolicyRepository.FindById(ctx, payload.ID)
+		if err != nil && err.Error() != "record not found" {
+			return response.ErrorBuilder(http.StatusInternalServerError, err, "server_error")
+		}
+		if policyData == nil {
+			return response.ErrorBuilder(http.StatusBadRequest, errors.New("bad_request"), "sla policy not found")
+		}
+
+		policyData.Context = ctx
+		if payload.StartMinutes != nil {
+			policyData.StartMinutes = payload.StartMinutes
+			if *payload.StartMinutes == 0 {
+				policyData.StartMinutes = nil
+			}
+		}
+		if payload.CompleteMinutes != nil {
+			policyData.CompleteMinutes = payload.CompleteMinutes
+			if *payload.CompleteMinutes == 0 {
+				policyData.CompleteMinutes = nil
+			}
+		}
+		if payload.AtRiskPercent != nil {
+			policyData.AtRiskPercent = *payload.AtRiskPercent
+		}
+		if err = validateLimits(&policyData.SlaPolicyEntity); err != nil {
+			return err
+		}
+		if err = s.SlaPolicyRepository.Update(ctx, policyData).Error; err != nil {
+			return response.ErrorBuilder(http.StatusInternalServerError, err, "server_error")
+		}
+
+		res = toMap(policyData)
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+	return map[string]interface{}{
+		"message": "success update!",
+		"data":    res,
+	}, nil
+}
+
+func (s *service) Delete(ctx *abstraction.Context, payload *dto.SlaPolicyDeleteByIDRequest) (map[string]interface{}, error) {
+	if err := trxmanager.New(s.DB).WithTrx(ctx, func(ctx *abstraction.Context) error {
+		if ctx.Auth.RoleID != constant.ROLE_ID_ADMIN {
+			return response.ErrorBuilder(http.StatusBadRequest, errors.New("bad_request"), "this role is not permitted")
+		}
+
+		policyData, err := s.SlaPolicyRepository.FindById(ctx, payload.ID)
+		if err != nil && err.Error() != "record not found" {
+			return response.ErrorBuilder(http.StatusInternalServerError, err, "server_error")
+		}
+		if policyData == nil {
+			return response.ErrorBuilder(http.StatusBadRequest, errors.New("bad_request"), "sla policy not found")
+		}
+
+		policyData.Context = ctx
+		if err = s.SlaPolicyRepository.Delete(ctx, policyData).Error; err != nil {
+			return response.ErrorBuilder(http.StatusInternalServerError, err, "server_error")
+		}
+
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+	return map[string]interface{}{
+		"message": "success delete!",
+	}, nil
+}
+
+// validateLimits needs a policy to limit something, and the start to come before the completion.
+func validateLimits(data *model.SlaPolicyEntity) error {
+	if data.StartMinutes == nil && data.CompleteMinutes == nil {
+		return response.ErrorBuilder(http.StatusBadRequest, errors.New("bad_request"), "sla policy needs a start or complete time")
+	}
+	if data.StartMinutes != nil && data.CompleteMinutes != nil && *data.StartMinutes > *data.CompleteMinutes {
+		return response.ErrorBuilder(http.StatusBadRequest, errors.New("bad_request"), "start time of the sla policy is after its complete time")
+	}
+	return nil
+}
+
+func toMap(data *model.SlaPolicyEntityModel) map[string]interface{} {
+	res := map[string]interface{}{
+		"id": data.ID,
+		"task": map[string]interface{}{
+			"id":   data.Task.ID,
+			"name": data.Task.Name,
+		},
+		"task_type":        nil,
+		"start_minutes":    data.StartMinutes,
+		"complete_minutes": data.CompleteMinutes,
+		"at_risk_percent":  data.AtRiskPercent,
+	}
+	if data.TaskType != nil {
+		res["task_type"] = map[string]interface{}{
+			"id":   data.TaskType.ID,
+			"name": data.TaskType.Name,
+		}
+	} else if data.TaskTypeId != nil {
+		res["task_type"] = map[string]interface{}{
+			"id": *data.TaskTypeId,
+		}
+	}
+	return res
+}
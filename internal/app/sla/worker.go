@@ -0,0 +1,158 @@
+package sla
+
+import (
+	"cleancare/internal/abstraction"
+	"cleancare/internal/config"
+	"cleancare/internal/factory"
+	"cleancare/internal/model"
+	"cleancare/pkg/constant"
+	"cleancare/pkg/gomail"
+	"cleancare/pkg/i18n"
+	"cleancare/pkg/scheduler"
+	"cleancare/pkg/util/trxmanager"
+	"cleancare/pkg/ws"
+	"context"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+const checkBatch = 100
+
+type worker struct {
+	service *service
+}
+
+// StartChecker moves the open works to at risk, breached or met every SLA_CHECK_INTERVAL
+// minutes until ctx is done, the admins are told when a work becomes at risk or breached.
+func StartChecker(ctx context.Context, f *factory.Factory) {
+	w := &worker{
+		service: newService(f),
+	}
+	scheduler.Every(ctx, "sla checker", time.Duration(config.Get().App.SlaCheckInterval)*time.Minute, w.run)
+}
+
+func (w *worker) run(ctx context.Context) {
+	now := time.Now()
+	atRiskPercents := make(map[int]int)
+
+	var afterId, changed int
+	for ctx.Err() == nil {
+		data, err := w.service.WorkRepository.FindSlaOpen(newJobContext(), afterId, checkBatch)
+		if err != nil {
+			logrus.Error("error find open sla:", err.Error())
+			return
+		}
+
+		for _, v := range data {
+			afterId = v.ID
+			status := v.Evaluate(now, w.atRiskPercent(atRiskPercents, v.SlaPolicyId))
+			if status == v.SlaStatus {
+				continue
+			}
+			if err := w.update(newJobContext(), v, status); err != nil {
+				logrus.Errorf("error update sla of work %d: %s", v.ID, err.Error())
+				continue
+			}
+			changed++
+		}
+		if len(data) < checkBatch {
+			break
+		}
+	}
+
+	if changed > 0 {
+		logrus.Infof("sla checker changed the sla status of %d work(s)", changed)
+	}
+}
+
+// atRiskPercent returns the at risk percent of the policy, the default one for a work that
+// only has the sla hours of its task or whose policy was removed since.
+func (w *worker) atRiskPercent(cache map[int]int, policy_id *int) int {
+	if policy_id == nil {
+		return constant.SLA_AT_RISK_PERCENT_DEFAULT
+	}
+	if v, ok := cache[*policy_id]; ok {
+		return v
+	}
+	percent := constant.SLA_AT_RISK_PERCENT_DEFAULT
+	policyData, err := w.service.SlaPolicyRepository.FindById(newJobContext(), *policy_id)
+	if err != nil && err.Error() != "record not found" {
+		logrus.Errorf("error find sla policy %d: %s", *policy_id, err.Error())
+	}
+	if policyData != nil {
+		percent = policyData.AtRiskPercent
+	}
+	cache[*policy_id] = percent
+	return percent
+}
+
+func (w *worker) update(ctx *abstraction.Context, data *model.WorkSlaCheck, status string) error {
+	escalate := status == constant.SLA_STATUS_AT_RISK || status == constant.SLA_STATUS_BREACHED
+
+	var adminIds []int
+	if err := trxmanager.New(w.service.DB).WithTrx(ctx, func(ctx *abstraction.Context) error {
+		if err := w.service.WorkRepository.UpdateSlaStatus(ctx, data.ID, status).Error; err != nil {
+			return err
+		}
+		if !escalate {
+			return nil
+		}
+
+		userAdmin, err := w.service.UserRepository.FindAllByRoleId(ctx, constant.ROLE_ID_ADMIN)
+		if err != nil && err.Error() != "record not found" {
+			return err
+		}
+		due := data.SlaCompleteDueAt
+		if data.SlaStartDueAt != nil && (due == nil || (data.StartedAt == nil && data.CompletedAt == nil)) {
+			due = data.SlaStartDueAt
+		}
+		for _, v := range userAdmin {
+			adminIds = append(adminIds, v.ID)
+			if v.Email == nil || v.EmailVerifiedAt == nil {
+				continue
+			}
+			lang := i18n.Resolve(v.Language, ctx.Lang())
+			dueAt := ""
+			if due != nil {
+				dueAt = i18n.FormatDateTime(lang, *due)
+			}
+			subject, body, err := gomail.TemplateSlaEscalation.Render(lang, gomail.SlaEscalationData{
+				NAME:      v.Name,
+				STATUS:    status,
+				STAFF:     data.UserName,
+				TASK:      data.TaskName,
+				TASK_TYPE: data.TaskTypeName,
+				FLOOR:     data.Floor,
+				DUE:       dueAt,
+			})
+			if err != nil {
+				return err
+			}
+			if err = w.service.EmailOutboxRepository.Create(ctx, model.NewEmailOutbox(ctx, *v.Email, gomail.TemplateSlaEscalation.Name, lang, subject, body)).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	}); err != nil {
+		return err
+	}
+
+	// the channel of the user is only told once the status is stored
+	for _, id := range adminIds {
+		if err := ws.Publish(id, map[string]interface{}{
+			"type":       "sla_escalation",
+			"work_id":    data.ID,
+			"sla_status": status,
+		}); err != nil {
+			logrus.Errorf("error publish sla escalation to user %d: %s", id, err.Error())
+		}
+	}
+	return nil
+}
+
+func newJobContext() *abstraction.Context {
+	return &abstraction.Context{
+		Auth: &abstraction.AuthContext{},
+	}
+}
@@ -0,0 +1,11 @@
+package storage
+
+import (
+	"cleancare/internal/middleware"
+
+	"github.com/labstack/echo/v4"
+)
+
+func (h *handler) Route(v *echo.Group) {
+	v.POST("/gc", h.Gc, middleware.Authentication)
+}
@@ -0,0 +1,255 @@
+package storage
+
+import (
+	"cleancare/internal/abstraction"
+	"cleancare/internal/config"
+	"cleancare/internal/dto"
+	"cleancare/internal/factory"
+	"cleancare/internal/repository"
+	"cleancare/pkg/constant"
+	"cleancare/pkg/gdrive"
+	"cleancare/pkg/util/general"
+	"cleancare/pkg/util/response"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"google.golang.org/api/drive/v3"
+)
+
+type Service interface {
+	Gc(ctx *abstraction.Context, payload *dto.StorageGcRequest) (map[string]interface{}, error)
+}
+
+type service struct {
+	UserRepository          repository.User
+	WorkRepository          repository.Work
+	UploadSessionRepository repository.UploadSession
+	WorkPhotoRepository     repository.WorkPhoto
+	IncidentRepository      repository.Incident
+
+	ServiceRequestRepository repository.ServiceRequest
+
+	sDrive *drive.Service
+	fDrive *drive.File
+}
+
+func NewService(f *factory.Factory) Service {
+	return newService(f)
+}
+
+func newService(f *factory.Factory) *service {
+	return &service{
+		UserRepository:          f.UserRepository,
+		WorkRepository:          f.WorkRepository,
+		UploadSessionRepository: f.UploadSessionRepository,
+		WorkPhotoRepository:     f.WorkPhotoRepository,
+		IncidentRepository:      f.IncidentRepository,
+
+		ServiceRequestRepository: f.ServiceRequestRepository,
+
+		sDrive: f.GDrive.Service,
+		fDrive: f.GDrive.FolderCleanCare,
+	}
+}
+
+type fileReference struct {
+	Entity   string `json:"entity"`
+	EntityId int    `json:"entity_id"`
+	Column   string `json:"column"`
+	FileId   string `json:"file_id"`
+}
+
+type orphanFile struct {
+	FileId    string `json:"file_id"`
+	Name      string `json:"name"`
+	Size      int64  `json:"size"`
+	CreatedAt string `json:"created_at"`
+	Expired   bool   `json:"expired"`
+	Deleted   bool   `json:"deleted"`
+}
+
+type gcResult struct {
+	DryRun     bool
+	Files      int
+	Referenced int
+	Orphans    []*orphanFile
+	Dangling   []*fileReference
+	Deleted    int
+}
+
+func (s *service) Gc(ctx *abstraction.Context, payload *dto.StorageGcRequest) (map[string]interface{}, error) {
+	if ctx.Auth.RoleID != constant.ROLE_ID_ADMIN {
+		return nil, response.ErrorBuilder(http.StatusBadRequest, errors.New("bad_request"), "this role is not permitted")
+	}
+
+	// nothing is deleted unless the caller asks for it with dry_run=false
+	dryRun := payload.DryRun == nil || *payload.DryRun
+	result, err := s.gc(ctx, dryRun)
+	if err != nil {
+		return nil, err
+	}
+
+	return map[string]interface{}{
+		"dry_run":     result.DryRun,
+		"grace_hours": config.Get().App.StorageGcGraceHours,
+		"files":       result.Files,
+		"referenced":  result.Referenced,
+		"orphans":     result.Orphans,
+		"dangling":    result.Dangling,
+		"deleted":     result.Deleted,
+	}, nil
+}
+
+// gc lists the drive folder and compares it with every file the database points to. Files
+// nobody points to are orphans, and are deleted once they are older than the grace period so
+// an upload whose row is not committed yet is never touched. References to files missing
+// from the folder are only reported.
+func (s *service) gc(ctx *abstraction.Context, dryRun bool) (*gcResult, error) {
+	if s.fDrive == nil {
+		return nil, response.ErrorBuilder(http.StatusInternalServerError, errors.New("drive folder is not ready"), "server_error")
+	}
+
+	// the folder is listed before the references are read, a file uploaded in between is
+	// either referenced already or still inside the grace period
+	files, err := gdrive.ListFiles(s.sDrive, s.fDrive.Id)
+	if err != nil {
+		return nil, response.ErrorBuilder(http.StatusInternalServerError, err, "server_error")
+	}
+
+	references, err := s.findReferences(ctx)
+	if err != nil {
+		return nil, response.ErrorBuilder(http.StatusInternalServerError, err, "server_error")
+	}
+
+	// finished uploads not used by a work yet are kept, the upload cleanup removes them once
+	// they expire
+	uploads, err := s.UploadSessionRepository.FindFileReferences(ctx)
+	if err != nil {
+		return nil, response.ErrorBuilder(http.StatusInternalServerError, err, "server_error")
+	}
+
+	referenced := make(map[string]bool)
+	for _, v := range references {
+		referenced[v.FileId] = true
+	}
+	for _, v := range uploads {
+		referenced[v.FileId] = true
+	}
+
+	graceUntil := time.Now().Add(-time.Duration(config.Get().App.StorageGcGraceHours) * time.Hour)
+	orphans, dangling := classifyFiles(files, references, referenced, graceUntil)
+
+	result := &gcResult{
+		DryRun:     dryRun,
+		Files:      len(files),
+		Referenced: len(referenced),
+		Orphans:    orphans,
+		Dangling:   dangling,
+	}
+
+	for _, v := range orphans {
+		if !v.Expired || dryRun {
+			continue
+		}
+		if err := gdrive.DeleteFile(s.sDrive, v.FileId); err != nil {
+			logrus.Error("error delete orphan file:", err.Error())
+		} else {
+			v.Deleted = true
+			result.Deleted++
+		}
+	}
+
+	return result, nil
+}
+
+// classifyFiles returns the files of the folder nobody points to, expired when they were
+// created before graceUntil, and the references whose file is missing from the folder. A file
+// whose creation time cannot be read is never expired.
+func classifyFiles(files []*drive.File, references []*fileReference, referenced map[string]bool, graceUntil time.Time) ([]*orphanFile, []*fileReference) {
+	orphans := []*orphanFile{}
+	dangling := []*fileReference{}
+
+	stored := make(map[string]bool)
+	for _, v := range files {
+		stored[v.Id] = true
+		if referenced[v.Id] {
+			continue
+		}
+
+		orphan := &orphanFile{
+			FileId:    v.Id,
+			Name:      v.Name,
+			Size:      v.Size,
+			CreatedAt: v.CreatedTime,
+		}
+		if createdAt, err := time.Parse(time.RFC3339, v.CreatedTime); err == nil {
+			orphan.Expired = createdAt.Before(graceUntil)
+		}
+		orphans = append(orphans, orphan)
+	}
+
+	for _, v := range references {
+		if !stored[v.FileId] {
+			dangling = append(dangling, v)
+		}
+	}
+
+	return orphans, dangling
+}
+
+// findReferences collects every drive file id stored in the database, soft deleted rows
+// included since their files are only removed when the row is purged.
+func (s *service) findReferences(ctx *abstraction.Context) ([]*fileReference, error) {
+	var references []*fileReference
+
+	works, err := s.WorkRepository.FindFileReferences(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, v := range works {
+		if v.ImageBefore != nil && *v.ImageBefore != "" {
+			fileId, _ := general.SplitFileAndNameWithDelimiter(*v.ImageBefore)
+			references = append(references, &fileReference{Entity: "work", EntityId: v.ID, Column: "image_before", FileId: fileId})
+		}
+		if v.ImageAfter != nil && *v.ImageAfter != "" {
+			fileId, _ := general.SplitFileAndNameWithDelimiter(*v.ImageAfter)
+			references = append(references, &fileReference{Entity: "work", EntityId: v.ID, Column: "image_after", FileId: fileId})
+		}
+	}
+
+	photos, err := s.WorkPhotoRepository.FindFileReferences(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, v := range photos {
+		references = append(references, &fileReference{Entity: "work_photo", EntityId: v.ID, Column: "storage_key", FileId: v.StorageKey})
+	}
+
+	incidentPhotos, err := s.IncidentRepository.FindPhotoFileReferences(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, v := range incidentPhotos {
+		references = append(references, &fileReference{Entity: "incident_photo", EntityId: v.ID, Column: "storage_key", FileId: v.StorageKey})
+	}
+
+	serviceRequests, err := s.ServiceRequestRepository.FindFileReferences(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, v := range serviceRequests {
+		references = append(references, &fileReference{Entity: "service_request", EntityId: v.ID, Column: "photo_storage_key", FileId: v.PhotoStorageKey})
+	}
+
+	users, err := s.UserRepository.FindFileReferences(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, v := range users {
+		references = append(references, &fileReference{Entity: "user", EntityId: v.ID, Column: "profile", FileId: *v.Profile})
+	}
+
+	return references, nil
+}
@@ -0,0 +1,60 @@
+package storage
+
+import (
+	"testing"
+	"time"
+
+	"google.golang.org/api/drive/v3"
+)
+
+func TestClassifyFiles(t *testing.T) {
+	graceUntil := time.Date(2024, 5, 10, 12, 0, 0, 0, time.UTC)
+
+	files := []*drive.File{
+		{Id: "used", CreatedTime: "2024-01-01T00:00:00Z"},
+		{Id: "upload", CreatedTime: "2024-01-01T00:00:00Z"},
+		{Id: "old", CreatedTime: "2024-05-01T00:00:00Z"},
+		{Id: "fresh", CreatedTime: "2024-05-10T13:00:00Z"},
+		{Id: "edge", CreatedTime: "2024-05-10T12:00:00Z"},
+		{Id: "unknown", CreatedTime: ""},
+	}
+	references := []*fileReference{
+		{Entity: "work", EntityId: 1, Column: "image_before", FileId: "used"},
+		{Entity: "work_photo", EntityId: 2, Column: "storage_key", FileId: "missing"},
+	}
+	// the pending upload is referenced without being a database reference, it is never dangling
+	referenced := map[string]bool{"used": true, "missing": true, "upload": true}
+
+	orphans, dangling := classifyFiles(files, references, referenced, graceUntil)
+
+	want := map[string]bool{"old": true, "fresh": false, "edge": false, "unknown": false}
+	if len(orphans) != len(want) {
+		t.Fatalf("orphans = %d, want %d", len(orphans), len(want))
+	}
+	for _, v := range orphans {
+		expired, ok := want[v.FileId]
+		if !ok {
+			t.Fatalf("%q should not be an orphan", v.FileId)
+		}
+		if v.Expired != expired {
+			t.Fatalf("%q expired = %v, want %v", v.FileId, v.Expired, expired)
+		}
+		if v.Deleted {
+			t.Fatalf("%q should not be deleted by the classification", v.FileId)
+		}
+	}
+
+	if len(dangling) != 1 || dangling[0].FileId != "missing" || dangling[0].EntityId != 2 {
+		t.Fatalf("dangling = %v, want the missing work photo", dangling)
+	}
+}
+
+func TestClassifyFilesEmpty(t *testing.T) {
+	orphans, dangling := classifyFiles(nil, nil, map[string]bool{}, time.Now())
+	if orphans == nil || dangling == nil {
+		t.Fatal("empty results must be empty lists so the response shows [] instead of null")
+	}
+	if len(orphans) != 0 || len(dangling) != 0 {
+		t.Fatalf("orphans = %v, dangling = %v, want none", orphans, dangling)
+	}
+}
@@ -0,0 +1,39 @@
+package storage
+
+import (
+	"cleancare/internal/abstraction"
+	"cleancare/internal/config"
+	"cleancare/internal/factory"
+	"cleancare/pkg/scheduler"
+	"context"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+type worker struct {
+	service *service
+}
+
+// StartGc reconciles the drive folder with the database every STORAGE_GC_INTERVAL minutes
+// until ctx is done. With STORAGE_GC_DRY_RUN set the orphans are only logged.
+func StartGc(ctx context.Context, f *factory.Factory) {
+	w := &worker{
+		service: newService(f),
+	}
+	scheduler.Every(ctx, "storage gc", time.Duration(config.Get().App.StorageGcInterval)*time.Minute, w.run)
+}
+
+func (w *worker) run(ctx context.Context) {
+	result, err := w.service.gc(&abstraction.Context{Auth: &abstraction.AuthContext{}}, config.Get().App.StorageGcDryRun)
+	if err != nil {
+		logrus.Errorf("error storage gc: %s", err.Error())
+		return
+	}
+
+	for _, v := range result.Dangling {
+		logrus.Warnf("storage gc: %s %d %s points to missing file %s", v.Entity, v.EntityId, v.Column, v.FileId)
+	}
+	logrus.Infof("storage gc checked %d file(s): %d orphan(s), %d deleted, %d dangling reference(s), dry run %t",
+		result.Files, len(result.Orphans), result.Deleted, len(result.Dangling), result.DryRun)
+}
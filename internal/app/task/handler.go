@@ -3,8 +3,10 @@ package task
 import (
 	"cleancare/internal/abstraction"
 	"cleancare/internal/app/task/tipe"
+	"cleancare/internal/dto"
 	"cleancare/internal/factory"
 	"cleancare/pkg/util/response"
+	"net/http"
 
 	"github.com/labstack/echo/v4"
 )
@@ -30,3 +32,63 @@ func (h handler) Find(c echo.Context) (err error) {
 	}
 	return response.SuccessResponse(data).SendSuccess(c)
 }
+
+func (h handler) FindById(c echo.Context) (err error) {
+	payload := new(dto.TaskFindByIDRequest)
+	if err = c.Bind(payload); err != nil {
+		return response.ErrorBuilder(http.StatusBadRequest, err, "error bind payload").SendError(c)
+	}
+	if err = c.Validate(payload); err != nil {
+		return response.ErrorBuilder(http.StatusBadRequest, err, "error validate payload").SendError(c)
+	}
+	data, err := h.service.FindById(c.(*abstraction.Context), payload)
+	if err != nil {
+		return response.ErrorResponse(err).SendError(c)
+	}
+	return response.SuccessResponse(data).SendSuccess(c)
+}
+
+func (h handler) Create(c echo.Context) (err error) {
+	payload := new(dto.TaskCreateRequest)
+	if err = c.Bind(payload); err != nil {
+		return response.ErrorBuilder(http.StatusBadRequest, err, "error bind payload").SendError(c)
+	}
+	if err = c.Validate(payload); err != nil {
+		return response.ErrorBuilder(http.StatusBadRequest, err, "error validate payload").SendError(c)
+	}
+	data, err := h.service.Create(c.(*abstraction.Context), payload)
+	if err != nil {
+		return response.ErrorResponse(err).SendError(c)
+	}
+	return response.SuccessResponse(data).SendSuccess(c)
+}
+
+func (h handler) Update(c echo.Context) (err error) {
+	payload := new(dto.TaskUpdateRequest)
+	if err = c.Bind(payload); err != nil {
+		return response.ErrorBuilder(http.StatusBadRequest, err, "error bind payload").SendError(c)
+	}
+	if err = c.Validate(payload); err != nil {
+		return response.ErrorBuilder(http.StatusBadRequest, err, "error validate payload").SendError(c)
+	}
+	data, err := h.service.Update(c.(*abstraction.Context), payload)
+	if err != nil {
+		return response.ErrorResponse(err).SendError(c)
+	}
+	return response.SuccessResponse(data).SendSuccess(c)
+}
+
+func (h handler) Delete(c echo.Context) (err error) {
+	payload := new(dto.TaskDeleteByIDRequest)
+	if err = c.Bind(payload); err != nil {
+		return response.ErrorBuilder(http.StatusBadRequest, err, "error bind payload").SendError(c)
+	}
+	if err = c.Validate(payload); err != nil {
+		return response.ErrorBuilder(http.StatusBadRequest, err, "error validate payload").SendError(c)
+	}
+	data, err := h.service.Delete(c.(*abstraction.Context), payload)
+	if err != nil {
+		return response.ErrorResponse(err).SendError(c)
+	}
+	return response.SuccessResponse(data).SendSuccess(c)
+}
@@ -8,6 +8,11 @@ import (
 
 func (h *handler) Route(v *echo.Group) {
 	v.GET("", h.Find, middleware.Authentication)
+	v.POST("", h.Create, middleware.Authentication)
 
 	h.TaskTypeHandler.Route(v.Group("/type"))
+
+	v.GET("/:id", h.FindById, middleware.Authentication)
+	v.PUT("/:id", h.Update, middleware.Authentication)
+	v.DELETE("/:id", h.Delete, middleware.Authentication)
 }
@@ -2,9 +2,14 @@ package task
 
 import (
 	"cleancare/internal/abstraction"
+	"cleancare/internal/dto"
 	"cleancare/internal/factory"
+	"cleancare/internal/model"
 	"cleancare/internal/repository"
+	"cleancare/pkg/constant"
 	"cleancare/pkg/util/response"
+	"cleancare/pkg/util/trxmanager"
+	"errors"
 	"net/http"
 
 	"gorm.io/gorm"
@@ -12,17 +17,23 @@ import (
 
 type Service interface {
 	Find(ctx *abstraction.Context) (map[string]interface{}, error)
+	FindById(ctx *abstraction.Context, payload *dto.TaskFindByIDRequest) (map[string]interface{}, error)
+	Create(ctx *abstraction.Context, payload *dto.TaskCreateRequest) (map[string]interface{}, error)
+	Update(ctx *abstraction.Context, payload *dto.TaskUpdateRequest) (map[string]interface{}, error)
+	Delete(ctx *abstraction.Context, payload *dto.TaskDeleteByIDRequest) (map[string]interface{}, error)
 }
 
 type service struct {
-	TaskRepository repository.Task
+	TaskRepository     repository.Task
+	TaskTypeRepository repository.TaskType
 
 	DB *gorm.DB
 }
 
 func NewService(f *factory.Factory) Service {
 	return &service{
-		TaskRepository: f.TaskRepository,
+		TaskRepository:     f.TaskRepository,
+		TaskTypeRepository: f.TaskTypeRepository,
 
 		DB: f.Db,
 	}
@@ -39,13 +50,153 @@ func (s *service) Find(ctx *abstraction.Context) (map[string]interface{}, error)
 	}
 	var res []map[string]interface{} = nil
 	for _, v := range data {
-		res = append(res, map[string]interface{}{
-			"id":   v.ID,
-			"name": v.Name,
-		})
+		res = append(res, toMap(v))
 	}
 	return map[string]interface{}{
 		"count": count,
 		"data":  res,
 	}, nil
 }
+
+func (s *service) FindById(ctx *abstraction.Context, payload *dto.TaskFindByIDRequest) (map[string]interface{}, error) {
+	data, err := s.TaskRepository.FindById(ctx, payload.ID)
+	if err != nil && err.Error() != "record not found" {
+		return nil, response.ErrorBuilder(http.StatusInternalServerError, err, "server_error")
+	}
+	if data == nil {
+		return nil, response.ErrorBuilder(http.StatusBadRequest, errors.New("bad_request"), "task not found")
+	}
+	return map[string]interface{}{
+		"data": toMap(data),
+	}, nil
+}
+
+func (s *service) Create(ctx *abstraction.Context, payload *dto.TaskCreateRequest) (map[string]interface{}, error) {
+	var res map[string]interface{}
+	if err := trxmanager.New(s.DB).WithTrx(ctx, func(ctx *abstraction.Context) error {
+		if ctx.Auth.RoleID != constant.ROLE_ID_ADMIN {
+			return response.ErrorBuilder(http.StatusBadRequest, errors.New("bad_request"), "this role is not permitted")
+		}
+
+		modelTask := &model.TaskEntityModel{
+			Context: ctx,
+			TaskEntity: model.TaskEntity{
+				Name:                payload.Name,
+				DashboardGroup:      payload.DashboardGroup,
+				RequiresImageBefore: payload.RequiresImageBefore,
+				RequiresImageAfter:  payload.RequiresImageAfter,
+				SlaHours:            payload.SlaHours,
+				IsDelete:            false,
+			},
+		}
+		if err := s.TaskRepository.Create(ctx, modelTask).Error; err != nil {
+			return response.ErrorBuilder(http.StatusInternalServerError, err, "server_error")
+		}
+
+		res = toMap(modelTask)
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+	return map[string]interface{}{
+		"message": "success create!",
+		"data":    res,
+	}, nil
+}
+
+func (s *service) Update(ctx *abstraction.Context, payload *dto.TaskUpdateRequest) (map[string]interface{}, error) {
+	var res map[string]interface{}
+	if err := trxmanager.New(s.DB).WithTrx(ctx, func(ctx *abstraction.Context) error {
+		if ctx.Auth.RoleID != constant.ROLE_ID_ADMIN {
+			return response.ErrorBuilder(http.StatusBadRequest, errors.New("bad_request"), "this role is not permitted")
+		}
+
+		taskData, err := s.TaskRepository.FindById(ctx, payload.ID)
+		if err != nil && err.Error() != "record not found" {
+			return response.ErrorBuilder(http.StatusInternalServerError, err, "server_error")
+		}
+		if taskData == nil {
+			return response.ErrorBuilder(http.StatusBadRequest, errors.New("bad_request"), "task not found")
+		}
+
+		taskData.Context = ctx
+		if payload.Name != nil {
+			taskData.Name = *payload.Name
+		}
+		if payload.DashboardGroup != nil {
+			taskData.DashboardGroup = *payload.DashboardGroup
+		}
+		if payload.RequiresImageBefore != nil {
+			taskData.RequiresImageBefore = *payload.RequiresImageBefore
+		}
+		if payload.RequiresImageAfter != nil {
+			taskData.RequiresImageAfter = *payload.RequiresImageAfter
+		}
+		if payload.SlaHours != nil {
+			taskData.SlaHours = payload.SlaHours
+			if *payload.SlaHours == 0 {
+				taskData.SlaHours = nil
+			}
+		}
+		if err = s.TaskRepository.Update(ctx, taskData).Error; err != nil {
+			return response.ErrorBuilder(http.StatusInternalServerError, err, "server_error")
+		}
+
+		res = toMap(taskData)
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+	return map[string]interface{}{
+		"message": "success update!",
+		"data":    res,
+	}, nil
+}
+
+func (s *service) Delete(ctx *abstraction.Context, payload *dto.TaskDeleteByIDRequest) (map[string]interface{}, error) {
+	if err := trxmanager.New(s.DB).WithTrx(ctx, func(ctx *abstraction.Context) error {
+		if ctx.Auth.RoleID != constant.ROLE_ID_ADMIN {
+			return response.ErrorBuilder(http.StatusBadRequest, errors.New("bad_request"), "this role is not permitted")
+		}
+
+		taskData, err := s.TaskRepository.FindById(ctx, payload.ID)
+		if err != nil && err.Error() != "record not found" {
+			return response.ErrorBuilder(http.StatusInternalServerError, err, "server_error")
+		}
+		if taskData == nil {
+			return response.ErrorBuilder(http.StatusBadRequest, errors.New("bad_request"), "task not found")
+		}
+
+		// the works of the task keep it, only its task types have to go first
+		count, err := s.TaskTypeRepository.CountByTaskId(ctx, taskData.ID)
+		if err != nil {
+			return response.ErrorBuilder(http.StatusInternalServerError, err, "server_error")
+		}
+		if *count > 0 {
+			return response.ErrorBuilder(http.StatusBadRequest, errors.New("bad_request"), "task is still used")
+		}
+
+		taskData.Context = ctx
+		if err = s.TaskRepository.Delete(ctx, taskData).Error; err != nil {
+			return response.ErrorBuilder(http.StatusInternalServerError, err, "server_error")
+		}
+
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+	return map[string]interface{}{
+		"message": "success delete!",
+	}, nil
+}
+
+func toMap(data *model.TaskEntityModel) map[string]interface{} {
+	return map[string]interface{}{
+		"id":                    data.ID,
+		"name":                  data.Name,
+		"dashboard_group":       data.DashboardGroup,
+		"requires_image_before": data.RequiresImageBefore,
+		"requires_image_after":  data.RequiresImageAfter,
+		"sla_hours":             data.SlaHours,
+	}
+}
@@ -11,4 +11,6 @@ func (h *Handler) Route(v *echo.Group) {
 	v.POST("", h.Create, middleware.Authentication)
 	v.DELETE("/:id", h.Delete, middleware.Authentication)
 	v.PUT("/:id", h.Update, middleware.Authentication)
+	v.GET("/:id/checklist", h.FindChecklist, middleware.Authentication)
+	v.PUT("/:id/checklist", h.UpdateChecklist, middleware.Authentication)
 }
@@ -6,6 +6,8 @@ import (
 	"cleancare/internal/factory"
 	"cleancare/internal/model"
 	"cleancare/internal/repository"
+	"cleancare/pkg/constant"
+	"cleancare/pkg/util/general"
 	"cleancare/pkg/util/response"
 	"cleancare/pkg/util/trxmanager"
 	"errors"
@@ -19,32 +21,66 @@ type Service interface {
 	Create(ctx *abstraction.Context, payload *dto.TaskTypeCreateRequest) (map[string]interface{}, error)
 	Delete(ctx *abstraction.Context, payload *dto.TaskTypeDeleteByIDRequest) (map[string]interface{}, error)
 	Update(ctx *abstraction.Context, payload *dto.TaskTypeUpdateRequest) (map[string]interface{}, error)
+	FindChecklist(ctx *abstraction.Context, payload *dto.TaskTypeChecklistFindRequest) (map[string]interface{}, error)
+	UpdateChecklist(ctx *abstraction.Context, payload *dto.TaskTypeChecklistUpdateRequest) (map[string]interface{}, error)
 }
 
 type service struct {
-	TaskTypeRepository repository.TaskType
-	TaskRepository     repository.Task
+	TaskTypeRepository              repository.TaskType
+	TaskRepository                  repository.Task
+	TaskTypeChecklistItemRepository repository.TaskTypeChecklistItem
 
 	DB *gorm.DB
 }
 
 func NewService(f *factory.Factory) Service {
 	return &service{
-		TaskTypeRepository: f.TaskTypeRepository,
-		TaskRepository:     f.TaskRepository,
+		TaskTypeRepository:              f.TaskTypeRepository,
+		TaskRepository:                  f.TaskRepository,
+		TaskTypeChecklistItemRepository: f.TaskTypeChecklistItemRepository,
 
 		DB: f.Db,
 	}
 }
 
 func (s *service) Find(ctx *abstraction.Context) (map[string]interface{}, error) {
-	data, err := s.TaskTypeRepository.Find(ctx, false)
-	if err != nil && err.Error() != "record not found" {
-		return nil, response.ErrorBuilder(http.StatusInternalServerError, err, "server_error")
+	var (
+		data  []*model.TaskTypeEntityModel
+		count *int
+		meta  map[string]interface{}
+	)
+	paging, err := general.ProcessCursor(ctx, false)
+	if err != nil {
+		return nil, response.ErrorBuilder(http.StatusBadRequest, errors.New("bad_request"), err.Error())
 	}
-	count, err := s.TaskTypeRepository.Count(ctx)
-	if err != nil && err.Error() != "record not found" {
-		return nil, response.ErrorBuilder(http.StatusInternalServerError, err, "server_error")
+	if paging != nil {
+		var hasMore bool
+		data, hasMore, err = s.TaskTypeRepository.FindCursor(ctx, paging)
+		if err != nil && err.Error() != "record not found" {
+			return nil, response.ErrorBuilder(http.StatusInternalServerError, err, "server_error")
+		}
+		if general.IncludeTotal(ctx) {
+			count, err = s.TaskTypeRepository.Count(ctx)
+			if err != nil && err.Error() != "record not found" {
+				return nil, response.ErrorBuilder(http.StatusInternalServerError, err, "server_error")
+			}
+		}
+		var first, last *general.Cursor
+		if len(data) > 0 {
+			first = general.NewCursor(nil, data[0].ID)
+			last = general.NewCursor(nil, data[len(data)-1].ID)
+		}
+		meta = paging.Meta(hasMore, first, last, count)
+	} else {
+		data, err = s.TaskTypeRepository.Find(ctx, false)
+		if err != nil && err.Error() != "record not found" {
+			return nil, response.ErrorBuilder(http.StatusInternalServerError, err, "server_error")
+		}
+		count, err = s.TaskTypeRepository.Count(ctx)
+		if err != nil && err.Error() != "record not found" {
+			return nil, response.ErrorBuilder(http.StatusInternalServerError, err, "server_error")
+		}
+		meta = general.OffsetMeta(ctx, false, len(data), count)
 	}
 	var res []map[string]interface{} = nil
 	for _, v := range data {
@@ -57,6 +93,7 @@ func (s *service) Find(ctx *abstraction.Context) (map[string]interface{}, error)
 	}
 	return map[string]interface{}{
 		"count": count,
+		"meta":  meta,
 		"data":  res,
 	}, nil
 }
@@ -106,6 +143,7 @@ func (s *service) Delete(ctx *abstraction.Context, payload *dto.TaskTypeDeleteBy
 		newTaskTypeData.Context = ctx
 		newTaskTypeData.ID = taskTypeData.ID
 		newTaskTypeData.IsDelete = true
+		newTaskTypeData.DeletedAt = general.Now()
 
 		if err = s.TaskTypeRepository.Update(ctx, newTaskTypeData).Error; err != nil {
 			return response.ErrorBuilder(http.StatusInternalServerError, err, "server_error")
@@ -159,3 +197,118 @@ func (s *service) Update(ctx *abstraction.Context, payload *dto.TaskTypeUpdateRe
 		"message": "success update!",
 	}, nil
 }
+
+func (s *service) FindChecklist(ctx *abstraction.Context, payload *dto.TaskTypeChecklistFindRequest) (map[string]interface{}, error) {
+	taskTypeData, err := s.TaskTypeRepository.FindById(ctx, payload.ID)
+	if err != nil && err.Error() != "record not found" {
+		return nil, response.ErrorBuilder(http.StatusInternalServerError, err, "server_error")
+	}
+	if taskTypeData == nil {
+		return nil, response.ErrorBuilder(http.StatusBadRequest, errors.New("bad_request"), "task type not found")
+	}
+
+	data, err := s.TaskTypeChecklistItemRepository.FindByTaskTypeId(ctx, taskTypeData.ID)
+	if err != nil && err.Error() != "record not found" {
+		return nil, response.ErrorBuilder(http.StatusInternalServerError, err, "server_error")
+	}
+	res := []map[string]interface{}{}
+	for _, v := range data {
+		res = append(res, checklistItemToMap(v))
+	}
+	return map[string]interface{}{
+		"data": res,
+	}, nil
+}
+
+func (s *service) UpdateChecklist(ctx *abstraction.Context, payload *dto.TaskTypeChecklistUpdateRequest) (map[string]interface{}, error) {
+	var res []map[string]interface{}
+	if err := trxmanager.New(s.DB).WithTrx(ctx, func(ctx *abstraction.Context) error {
+		if ctx.Auth.RoleID != constant.ROLE_ID_ADMIN {
+			return response.ErrorBuilder(http.StatusBadRequest, errors.New("bad_request"), "this role is not permitted")
+		}
+
+		taskTypeData, err := s.TaskTypeRepository.FindById(ctx, payload.ID)
+		if err != nil && err.Error() != "record not found" {
+			return response.ErrorBuilder(http.StatusInternalServerError, err, "server_error")
+		}
+		if taskTypeData == nil {
+			return response.ErrorBuilder(http.StatusBadRequest, errors.New("bad_request"), "task type not found")
+		}
+
+		oldData, err := s.TaskTypeChecklistItemRepository.FindByTaskTypeId(ctx, taskTypeData.ID)
+		if err != nil && err.Error() != "record not found" {
+			return response.ErrorBuilder(http.StatusInternalServerError, err, "server_error")
+		}
+		oldItems := make(map[int]*model.TaskTypeChecklistItemEntityModel)
+		for _, v := range oldData {
+			oldItems[v.ID] = v
+		}
+
+		// the works already created keep their copy of the checklist, see work_checklist
+		kept := make(map[int]bool)
+		for i, v := range payload.Items {
+			if v.ID != 0 {
+				item, ok := oldItems[v.ID]
+				if !ok || kept[v.ID] {
+					return response.ErrorBuilder(http.StatusBadRequest, errors.New("bad_request"), "checklist item not found")
+				}
+				kept[v.ID] = true
+
+				item.Context = ctx
+				item.Name = v.Name
+				item.IsRequired = v.IsRequired
+				item.SortOrder = i
+				if err = s.TaskTypeChecklistItemRepository.Update(ctx, item).Error; err != nil {
+					return response.ErrorBuilder(http.StatusInternalServerError, err, "server_error")
+				}
+				res = append(res, checklistItemToMap(item))
+				continue
+			}
+
+			modelItem := &model.TaskTypeChecklistItemEntityModel{
+				Context: ctx,
+				TaskTypeChecklistItemEntity: model.TaskTypeChecklistItemEntity{
+					TaskTypeId: taskTypeData.ID,
+					Name:       v.Name,
+					IsRequired: v.IsRequired,
+					SortOrder:  i,
+					IsDelete:   false,
+				},
+			}
+			if err = s.TaskTypeChecklistItemRepository.Create(ctx, modelItem).Error; err != nil {
+				return response.ErrorBuilder(http.StatusInternalServerError, err, "server_error")
+			}
+			res = append(res, checklistItemToMap(modelItem))
+		}
+
+		for _, v := range oldData {
+			if kept[v.ID] {
+				continue
+			}
+			v.Context = ctx
+			if err = s.TaskTypeChecklistItemRepository.Delete(ctx, v).Error; err != nil {
+				return response.ErrorBuilder(http.StatusInternalServerError, err, "server_error")
+			}
+		}
+
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+	if res == nil {
+		res = []map[string]interface{}{}
+	}
+	return map[string]interface{}{
+		"message": "success update!",
+		"data":    res,
+	}, nil
+}
+
+func checklistItemToMap(data *model.TaskTypeChecklistItemEntityModel) map[string]interface{} {
+	return map[string]interface{}{
+		"id":          data.ID,
+		"name":        data.Name,
+		"is_required": data.IsRequired,
+		"sort_order":  data.SortOrder,
+	}
+}
@@ -0,0 +1,14 @@
+package trash
+
+import (
+	"cleancare/internal/middleware"
+
+	"github.com/labstack/echo/v4"
+)
+
+func (h *handler) Route(v *echo.Group) {
+	v.GET("/:entity", h.Find, middleware.Authentication)
+	v.GET("/:entity/:id", h.FindById, middleware.Authentication)
+	v.POST("/:entity/:id/restore", h.Restore, middleware.Authentication)
+	v.DELETE("/:entity/:id", h.Purge, middleware.Authentication)
+}
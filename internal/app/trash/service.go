@@ -0,0 +1,587 @@
+package trash
+
+import (
+	"cleancare/internal/abstraction"
+	"cleancare/internal/dto"
+	"cleancare/internal/factory"
+	"cleancare/internal/model"
+	"cleancare/internal/repository"
+	"cleancare/pkg/constant"
+	"cleancare/pkg/gdrive"
+	"cleancare/pkg/util/general"
+	"cleancare/pkg/util/response"
+	"cleancare/pkg/util/trxmanager"
+	"errors"
+	"net/http"
+	"slices"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"google.golang.org/api/drive/v3"
+	"gorm.io/gorm"
+)
+
+type Service interface {
+	Find(ctx *abstraction.Context, payload *dto.TrashFindRequest) (map[string]interface{}, error)
+	FindById(ctx *abstraction.Context, payload *dto.TrashByIDRequest) (map[string]interface{}, error)
+	Restore(ctx *abstraction.Context, payload *dto.TrashByIDRequest) (map[string]interface{}, error)
+	Purge(ctx *abstraction.Context, payload *dto.TrashByIDRequest) (map[string]interface{}, error)
+}
+
+type service struct {
+	UserRepository            repository.User
+	WorkRepository            repository.Work
+	TaskTypeRepository        repository.TaskType
+	CommentRepository         repository.Comment
+	AssignmentRepository      repository.Assignment
+	PasswordHistoryRepository repository.PasswordHistory
+	WorkPhotoRepository       repository.WorkPhoto
+	PhotoFlagRepository       repository.PhotoFlag
+	IncidentRepository        repository.Incident
+
+	TaskTypeChecklistItemRepository repository.TaskTypeChecklistItem
+	WorkChecklistRepository         repository.WorkChecklist
+	SlaPolicyRepository             repository.SlaPolicy
+	FeedbackRepository              repository.Feedback
+	WorkVerificationRepository      repository.WorkVerification
+	InventoryMovementRepository     repository.InventoryMovement
+
+	DB     *gorm.DB
+	sDrive *drive.Service
+}
+
+func NewService(f *factory.Factory) Service {
+	return newService(f)
+}
+
+func newService(f *factory.Factory) *service {
+	return &service{
+		UserRepository:            f.UserRepository,
+		WorkRepository:            f.WorkRepository,
+		TaskTypeRepository:        f.TaskTypeRepository,
+		CommentRepository:         f.CommentRepository,
+		AssignmentRepository:      f.AssignmentRepository,
+		PasswordHistoryRepository: f.PasswordHistoryRepository,
+		WorkPhotoRepository:       f.WorkPhotoRepository,
+		PhotoFlagRepository:       f.PhotoFlagRepository,
+		IncidentRepository:        f.IncidentRepository,
+
+		TaskTypeChecklistItemRepository: f.TaskTypeChecklistItemRepository,
+		WorkChecklistRepository:         f.WorkChecklistRepository,
+		SlaPolicyRepository:             f.SlaPolicyRepository,
+		FeedbackRepository:              f.FeedbackRepository,
+		WorkVerificationRepository:      f.WorkVerificationRepository,
+		InventoryMovementRepository:     f.InventoryMovementRepository,
+
+		DB:     f.Db,
+		sDrive: f.GDrive.Service,
+	}
+}
+
+func (s *service) Find(ctx *abstraction.Context, payload *dto.TrashFindRequest) (map[string]interface{}, error) {
+	if ctx.Auth.RoleID != constant.ROLE_ID_ADMIN {
+		return nil, response.ErrorBuilder(http.StatusBadRequest, errors.New("bad_request"), "this role is not permitted")
+	}
+
+	var (
+		res   []map[string]interface{} = nil
+		count *int
+		err   error
+	)
+	switch payload.Entity {
+	case "user":
+		var data []*model.UserEntityModel
+		if data, err = s.UserRepository.FindDeleted(ctx, false); err == nil {
+			for _, v := range data {
+				res = append(res, userToMap(v))
+			}
+			count, err = s.UserRepository.CountDeleted(ctx)
+		}
+	case "work":
+		var data []*model.WorkEntityModel
+		if data, err = s.WorkRepository.FindDeleted(ctx, false); err == nil {
+			for _, v := range data {
+				res = append(res, workToMap(v))
+			}
+			count, err = s.WorkRepository.CountDeleted(ctx)
+		}
+	case "task_type":
+		var data []*model.TaskTypeEntityModel
+		if data, err = s.TaskTypeRepository.FindDeleted(ctx, false); err == nil {
+			for _, v := range data {
+				res = append(res, taskTypeToMap(v))
+			}
+			count, err = s.TaskTypeRepository.CountDeleted(ctx)
+		}
+	case "comment":
+		var data []*model.CommentEntityModel
+		if data, err = s.CommentRepository.FindDeleted(ctx, false); err == nil {
+			for _, v := range data {
+				res = append(res, commentToMap(v))
+			}
+			count, err = s.CommentRepository.CountDeleted(ctx)
+		}
+	}
+	if err != nil && err.Error() != "record not found" {
+		return nil, response.ErrorBuilder(http.StatusInternalServerError, err, "server_error")
+	}
+
+	return map[string]interface{}{
+		"count": count,
+		"meta":  general.OffsetMeta(ctx, false, len(res), count),
+		"data":  res,
+	}, nil
+}
+
+func (s *service) FindById(ctx *abstraction.Context, payload *dto.TrashByIDRequest) (map[string]interface{}, error) {
+	if ctx.Auth.RoleID != constant.ROLE_ID_ADMIN {
+		return nil, response.ErrorBuilder(http.StatusBadRequest, errors.New("bad_request"), "this role is not permitted")
+	}
+
+	var (
+		res map[string]interface{}
+		err error
+	)
+	switch payload.Entity {
+	case "user":
+		var data *model.UserEntityModel
+		if data, err = s.UserRepository.FindDeletedById(ctx, payload.ID); data != nil {
+			res = userToMap(data)
+		}
+	case "work":
+		var data *model.WorkEntityModel
+		if data, err = s.WorkRepository.FindDeletedById(ctx, payload.ID); data != nil {
+			res = workToMap(data)
+		}
+	case "task_type":
+		var data *model.TaskTypeEntityModel
+		if data, err = s.TaskTypeRepository.FindDeletedById(ctx, payload.ID); data != nil {
+			res = taskTypeToMap(data)
+		}
+	case "comment":
+		var data *model.CommentEntityModel
+		if data, err = s.CommentRepository.FindDeletedById(ctx, payload.ID); data != nil {
+			res = commentToMap(data)
+		}
+	}
+	if err != nil && err.Error() != "record not found" {
+		return nil, response.ErrorBuilder(http.StatusInternalServerError, err, "server_error")
+	}
+	if res == nil {
+		return nil, response.ErrorBuilder(http.StatusBadRequest, errors.New("bad_request"), "deleted record not found")
+	}
+
+	return map[string]interface{}{
+		"data": res,
+	}, nil
+}
+
+func (s *service) Restore(ctx *abstraction.Context, payload *dto.TrashByIDRequest) (map[string]interface{}, error) {
+	if err := trxmanager.New(s.DB).WithTrx(ctx, func(ctx *abstraction.Context) error {
+		if ctx.Auth.RoleID != constant.ROLE_ID_ADMIN {
+			return response.ErrorBuilder(http.StatusBadRequest, errors.New("bad_request"), "this role is not permitted")
+		}
+
+		switch payload.Entity {
+		case "user":
+			return s.restoreUser(ctx, payload.ID)
+		case "work":
+			return s.restoreWork(ctx, payload.ID)
+		case "task_type":
+			return s.restoreTaskType(ctx, payload.ID)
+		case "comment":
+			return s.restoreComment(ctx, payload.ID)
+		}
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+	return map[string]interface{}{
+		"message": "success restore!",
+	}, nil
+}
+
+func (s *service) Purge(ctx *abstraction.Context, payload *dto.TrashByIDRequest) (map[string]interface{}, error) {
+	if ctx.Auth.RoleID != constant.ROLE_ID_ADMIN {
+		return nil, response.ErrorBuilder(http.StatusBadRequest, errors.New("bad_request"), "this role is not permitted")
+	}
+
+	if err := s.purge(ctx, payload.Entity, payload.ID); err != nil {
+		return nil, err
+	}
+	return map[string]interface{}{
+		"message": "success delete!",
+	}, nil
+}
+
+// purge hard deletes a soft deleted record in its own transaction, the drive files it
+// owned are removed once the transaction is committed.
+func (s *service) purge(ctx *abstraction.Context, entity string, id int) error {
+	var files []string
+	if err := trxmanager.New(s.DB).WithTrx(ctx, func(ctx *abstraction.Context) (err error) {
+		switch entity {
+		case "user":
+			files, err = s.purgeUser(ctx, id)
+		case "work":
+			files, err = s.purgeWork(ctx, id)
+		case "task_type":
+			err = s.purgeTaskType(ctx, id)
+		case "comment":
+			err = s.purgeComment(ctx, id)
+		}
+		return err
+	}); err != nil {
+		return err
+	}
+
+	for _, v := range files {
+		if errDel := gdrive.DeleteFile(s.sDrive, v); errDel != nil {
+			logrus.Error("error delete file for purge:", errDel.Error())
+		}
+	}
+	return nil
+}
+
+func (s *service) restoreUser(ctx *abstraction.Context, id int) error {
+	data, err := s.UserRepository.FindDeletedById(ctx, id)
+	if err != nil && err.Error() != "record not found" {
+		return response.ErrorBuilder(http.StatusInternalServerError, err, "server_error")
+	}
+	if data == nil {
+		return response.ErrorBuilder(http.StatusBadRequest, errors.New("bad_request"), "user not found")
+	}
+
+	userNumber, err := s.UserRepository.FindByNumberId(ctx, data.NumberId)
+	if err != nil && err.Error() != "record not found" {
+		return response.ErrorBuilder(http.StatusInternalServerError, err, "server_error")
+	}
+	if userNumber != nil {
+		return response.ErrorBuilder(http.StatusBadRequest, errors.New("bad_request"), "number id already exist")
+	}
+	if data.Email != nil {
+		userEmail, err := s.UserRepository.FindByEmail(ctx, *data.Email)
+		if err != nil && err.Error() != "record not found" {
+			return response.ErrorBuilder(http.StatusInternalServerError, err, "server_error")
+		}
+		if userEmail != nil {
+			return response.ErrorBuilder(http.StatusBadRequest, errors.New("bad_request"), "email already exist")
+		}
+	}
+
+	newUserData := new(model.UserEntityModel)
+	newUserData.Context = ctx
+	newUserData.ID = data.ID
+	if err = s.UserRepository.Restore(ctx, newUserData).Error; err != nil {
+		return response.ErrorBuilder(http.StatusInternalServerError, err, "server_error")
+	}
+	return nil
+}
+
+func (s *service) restoreWork(ctx *abstraction.Context, id int) error {
+	data, err := s.WorkRepository.FindDeletedById(ctx, id)
+	if err != nil && err.Error() != "record not found" {
+		return response.ErrorBuilder(http.StatusInternalServerError, err, "server_error")
+	}
+	if data == nil {
+		return response.ErrorBuilder(http.StatusBadRequest, errors.New("bad_request"), "work not found")
+	}
+
+	userData, err := s.UserRepository.FindById(ctx, data.UserId)
+	if err != nil && err.Error() != "record not found" {
+		return response.ErrorBuilder(http.StatusInternalServerError, err, "server_error")
+	}
+	if userData == nil {
+		return response.ErrorBuilder(http.StatusBadRequest, errors.New("bad_request"), "user not found")
+	}
+
+	newWorkData := new(model.WorkEntityModel)
+	newWorkData.Context = ctx
+	newWorkData.ID = data.ID
+	if err = s.WorkRepository.Restore(ctx, newWorkData).Error; err != nil {
+		return response.ErrorBuilder(http.StatusInternalServerError, err, "server_error")
+	}
+	return nil
+}
+
+func (s *service) restoreTaskType(ctx *abstraction.Context, id int) error {
+	data, err := s.TaskTypeRepository.FindDeletedById(ctx, id)
+	if err != nil && err.Error() != "record not found" {
+		return response.ErrorBuilder(http.StatusInternalServerError, err, "server_error")
+	}
+	if data == nil {
+		return response.ErrorBuilder(http.StatusBadRequest, errors.New("bad_request"), "task type not found")
+	}
+
+	newTaskTypeData := new(model.TaskTypeEntityModel)
+	newTaskTypeData.Context = ctx
+	newTaskTypeData.ID = data.ID
+	if err = s.TaskTypeRepository.Restore(ctx, newTaskTypeData).Error; err != nil {
+		return response.ErrorBuilder(http.StatusInternalServerError, err, "server_error")
+	}
+	return nil
+}
+
+func (s *service) restoreComment(ctx *abstraction.Context, id int) error {
+	data, err := s.CommentRepository.FindDeletedById(ctx, id)
+	if err != nil && err.Error() != "record not found" {
+		return response.ErrorBuilder(http.StatusInternalServerError, err, "server_error")
+	}
+	if data == nil {
+		return response.ErrorBuilder(http.StatusBadRequest, errors.New("bad_request"), "comment not found")
+	}
+
+	workData, err := s.WorkRepository.FindById(ctx, data.WorkId)
+	if err != nil && err.Error() != "record not found" {
+		return response.ErrorBuilder(http.StatusInternalServerError, err, "server_error")
+	}
+	if workData == nil {
+		return response.ErrorBuilder(http.StatusBadRequest, errors.New("bad_request"), "work not found")
+	}
+
+	newCommentData := new(model.CommentEntityModel)
+	newCommentData.Context = ctx
+	newCommentData.ID = data.ID
+	if err = s.CommentRepository.Restore(ctx, newCommentData).Error; err != nil {
+		return response.ErrorBuilder(http.StatusInternalServerError, err, "server_error")
+	}
+	return nil
+}
+
+func (s *service) purgeUser(ctx *abstraction.Context, id int) ([]string, error) {
+	data, err := s.UserRepository.FindDeletedById(ctx, id)
+	if err != nil && err.Error() != "record not found" {
+		return nil, response.ErrorBuilder(http.StatusInternalServerError, err, "server_error")
+	}
+	if data == nil {
+		return nil, response.ErrorBuilder(http.StatusBadRequest, errors.New("bad_request"), "user not found")
+	}
+
+	// work, assignments, comments, incidents and stock movements keep the user id, the user
+	// stays until they are purged
+	for _, count := range []func(*abstraction.Context, int) (*int, error){
+		s.WorkRepository.CountByUserId,
+		s.AssignmentRepository.CountByUserId,
+		s.CommentRepository.CountByCreatedBy,
+		s.IncidentRepository.CountByUserId,
+		s.InventoryMovementRepository.CountByCreatedBy,
+	} {
+		total, err := count(ctx, data.ID)
+		if err != nil {
+			return nil, response.ErrorBuilder(http.StatusInternalServerError, err, "server_error")
+		}
+		if *total > 0 {
+			return nil, response.ErrorBuilder(http.StatusBadRequest, errors.New("bad_request"), "user still has related data")
+		}
+	}
+
+	if err = s.PasswordHistoryRepository.DeleteByUserId(ctx, data.ID).Error; err != nil {
+		return nil, response.ErrorBuilder(http.StatusInternalServerError, err, "server_error")
+	}
+	data.Context = ctx
+	if err = s.UserRepository.Purge(ctx, data).Error; err != nil {
+		return nil, response.ErrorBuilder(http.StatusInternalServerError, err, "server_error")
+	}
+
+	var files []string
+	if data.Profile != nil {
+		files = append(files, *data.Profile)
+	}
+	return files, nil
+}
+
+func (s *service) purgeWork(ctx *abstraction.Context, id int) ([]string, error) {
+	data, err := s.WorkRepository.FindDeletedById(ctx, id)
+	if err != nil && err.Error() != "record not found" {
+		return nil, response.ErrorBuilder(http.StatusInternalServerError, err, "server_error")
+	}
+	if data == nil {
+		return nil, response.ErrorBuilder(http.StatusBadRequest, errors.New("bad_request"), "work not found")
+	}
+
+	comments, err := s.CommentRepository.FindAllByWorkId(ctx, data.ID)
+	if err != nil && err.Error() != "record not found" {
+		return nil, response.ErrorBuilder(http.StatusInternalServerError, err, "server_error")
+	}
+	for _, v := range comments {
+		v.Context = ctx
+		if err = s.CommentRepository.Purge(ctx, v).Error; err != nil {
+			return nil, response.ErrorBuilder(http.StatusInternalServerError, err, "server_error")
+		}
+	}
+
+	assignmentData, err := s.AssignmentRepository.FindByWorkId(ctx, data.ID)
+	if err != nil && err.Error() != "record not found" {
+		return nil, response.ErrorBuilder(http.StatusInternalServerError, err, "server_error")
+	}
+	if assignmentData != nil {
+		assignmentData.Context = ctx
+		if err = s.AssignmentRepository.UpdateToNull(ctx, assignmentData, "work_id").Error; err != nil {
+			return nil, response.ErrorBuilder(http.StatusInternalServerError, err, "server_error")
+		}
+	}
+
+	// image_before and image_after are the covers of the photos, the photos hold every file
+	photos, err := s.WorkPhotoRepository.FindByWorkId(ctx, data.ID)
+	if err != nil && err.Error() != "record not found" {
+		return nil, response.ErrorBuilder(http.StatusInternalServerError, err, "server_error")
+	}
+	if err = s.WorkPhotoRepository.DeleteByWorkId(ctx, data.ID).Error; err != nil {
+		return nil, response.ErrorBuilder(http.StatusInternalServerError, err, "server_error")
+	}
+	if err = s.PhotoFlagRepository.DeleteByWorkId(ctx, data.ID).Error; err != nil {
+		return nil, response.ErrorBuilder(http.StatusInternalServerError, err, "server_error")
+	}
+	if err = s.PhotoFlagRepository.UnlinkMatchWork(ctx, data.ID).Error; err != nil {
+		return nil, response.ErrorBuilder(http.StatusInternalServerError, err, "server_error")
+	}
+	if err = s.WorkChecklistRepository.DeleteByWorkId(ctx, data.ID).Error; err != nil {
+		return nil, response.ErrorBuilder(http.StatusInternalServerError, err, "server_error")
+	}
+	if err = s.FeedbackRepository.DeleteByWorkId(ctx, data.ID).Error; err != nil {
+		return nil, response.ErrorBuilder(http.StatusInternalServerError, err, "server_error")
+	}
+	if err = s.WorkVerificationRepository.DeleteByWorkId(ctx, data.ID).Error; err != nil {
+		return nil, response.ErrorBuilder(http.StatusInternalServerError, err, "server_error")
+	}
+
+	// incidents and stock movements outlive the work they were found or used during
+	if err = s.IncidentRepository.UnlinkWork(ctx, data.ID).Error; err != nil {
+		return nil, response.ErrorBuilder(http.StatusInternalServerError, err, "server_error")
+	}
+	if err = s.InventoryMovementRepository.UnlinkWork(ctx, data.ID).Error; err != nil {
+		return nil, response.ErrorBuilder(http.StatusInternalServerError, err, "server_error")
+	}
+
+	data.Context = ctx
+	if err = s.WorkRepository.Purge(ctx, data).Error; err != nil {
+		return nil, response.ErrorBuilder(http.StatusInternalServerError, err, "server_error")
+	}
+
+	var files []string
+	for _, v := range photos {
+		files = append(files, v.StorageKey)
+	}
+	for _, v := range []*string{data.ImageBefore, data.ImageAfter} {
+		if v != nil {
+			file, _ := general.SplitFileAndNameWithDelimiter(*v)
+			if !slices.Contains(files, file) {
+				files = append(files, file)
+			}
+		}
+	}
+	return files, nil
+}
+
+func (s *service) purgeTaskType(ctx *abstraction.Context, id int) error {
+	data, err := s.TaskTypeRepository.FindDeletedById(ctx, id)
+	if err != nil && err.Error() != "record not found" {
+		return response.ErrorBuilder(http.StatusInternalServerError, err, "server_error")
+	}
+	if data == nil {
+		return response.ErrorBuilder(http.StatusBadRequest, errors.New("bad_request"), "task type not found")
+	}
+
+	for _, count := range []func(*abstraction.Context, int) (*int, error){
+		s.WorkRepository.CountByTaskTypeId,
+		s.AssignmentRepository.CountByTaskTypeId,
+	} {
+		total, err := count(ctx, data.ID)
+		if err != nil {
+			return response.ErrorBuilder(http.StatusInternalServerError, err, "server_error")
+		}
+		if *total > 0 {
+			return response.ErrorBuilder(http.StatusBadRequest, errors.New("bad_request"), "task type is still used")
+		}
+	}
+
+	if err = s.TaskTypeChecklistItemRepository.DeleteByTaskTypeId(ctx, data.ID).Error; err != nil {
+		return response.ErrorBuilder(http.StatusInternalServerError, err, "server_error")
+	}
+	if err = s.SlaPolicyRepository.DeleteByTaskTypeId(ctx, data.ID).Error; err != nil {
+		return response.ErrorBuilder(http.StatusInternalServerError, err, "server_error")
+	}
+	if err = s.InventoryMovementRepository.UnlinkTaskType(ctx, data.ID).Error; err != nil {
+		return response.ErrorBuilder(http.StatusInternalServerError, err, "server_error")
+	}
+
+	data.Context = ctx
+	if err = s.TaskTypeRepository.Purge(ctx, data).Error; err != nil {
+		return response.ErrorBuilder(http.StatusInternalServerError, err, "server_error")
+	}
+	return nil
+}
+
+func (s *service) purgeComment(ctx *abstraction.Context, id int) error {
+	data, err := s.CommentRepository.FindDeletedById(ctx, id)
+	if err != nil && err.Error() != "record not found" {
+		return response.ErrorBuilder(http.StatusInternalServerError, err, "server_error")
+	}
+	if data == nil {
+		return response.ErrorBuilder(http.StatusBadRequest, errors.New("bad_request"), "comment not found")
+	}
+
+	data.Context = ctx
+	if err = s.CommentRepository.Purge(ctx, data).Error; err != nil {
+		return response.ErrorBuilder(http.StatusInternalServerError, err, "server_error")
+	}
+	return nil
+}
+
+func deletedAt(t *time.Time) interface{} {
+	if t == nil {
+		return nil
+	}
+	return general.FormatWithZWithoutChangingTime(*t)
+}
+
+func userToMap(v *model.UserEntityModel) map[string]interface{} {
+	return map[string]interface{}{
+		"id":         v.ID,
+		"number_id":  v.NumberId,
+		"name":       v.Name,
+		"email":      v.Email,
+		"role_id":    v.RoleId,
+		"role_name":  v.Role.Name,
+		"floor":      v.Floor,
+		"deleted_at": deletedAt(v.DeletedAt),
+		"created_at": general.FormatWithZWithoutChangingTime(v.CreatedAt),
+	}
+}
+
+func workToMap(v *model.WorkEntityModel) map[string]interface{} {
+	return map[string]interface{}{
+		"id":             v.ID,
+		"user_id":        v.UserId,
+		"user_name":      v.User.Name,
+		"task_id":        v.TaskId,
+		"task_name":      v.Task.Name,
+		"task_type_id":   v.TaskTypeId,
+		"task_type_name": v.TaskType.Name,
+		"floor":          v.Floor,
+		"info":           v.Info,
+		"deleted_at":     deletedAt(v.DeletedAt),
+		"created_at":     general.FormatWithZWithoutChangingTime(v.CreatedAt),
+	}
+}
+
+func taskTypeToMap(v *model.TaskTypeEntityModel) map[string]interface{} {
+	return map[string]interface{}{
+		"id":         v.ID,
+		"name":       v.Name,
+		"task_id":    v.TaskId,
+		"task_name":  v.Task.Name,
+		"deleted_at": deletedAt(v.DeletedAt),
+	}
+}
+
+func commentToMap(v *model.CommentEntityModel) map[string]interface{} {
+	return map[string]interface{}{
+		"id":              v.ID,
+		"work_id":         v.WorkId,
+		"comment":         v.Comment,
+		"created_by":      v.CreatedBy,
+		"created_by_name": v.CreateBy.Name,
+		"deleted_at":      deletedAt(v.DeletedAt),
+		"created_at":      general.FormatWithZWithoutChangingTime(v.CreatedAt),
+	}
+}
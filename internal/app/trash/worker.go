@@ -0,0 +1,124 @@
+package trash
+
+import (
+	"cleancare/internal/abstraction"
+	"cleancare/internal/config"
+	"cleancare/internal/factory"
+	"cleancare/internal/model"
+	"cleancare/pkg/scheduler"
+	"cleancare/pkg/util/response"
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+const retentionBatch = 50
+
+type worker struct {
+	service *service
+}
+
+// StartRetention purges records deleted more than TRASH_RETENTION_DAYS ago, every
+// TRASH_PURGE_INTERVAL minutes until ctx is done.
+func StartRetention(ctx context.Context, f *factory.Factory) {
+	w := &worker{
+		service: newService(f),
+	}
+	scheduler.Every(ctx, "trash retention", time.Duration(config.Get().App.TrashPurgeInterval)*time.Minute, w.run)
+}
+
+func (w *worker) run(ctx context.Context) {
+	before := time.Now().AddDate(0, 0, -config.Get().App.TrashRetentionDays)
+
+	// work goes first, a purged work can free its user and task type in the same run
+	w.purgeExpired(ctx, "work", before, func(c *abstraction.Context, afterId int) ([]int, error) {
+		data, err := w.service.WorkRepository.FindDeletedBefore(c, before, afterId, retentionBatch)
+		return workIds(data), err
+	})
+	w.purgeExpired(ctx, "comment", before, func(c *abstraction.Context, afterId int) ([]int, error) {
+		data, err := w.service.CommentRepository.FindDeletedBefore(c, before, afterId, retentionBatch)
+		return commentIds(data), err
+	})
+	w.purgeExpired(ctx, "task_type", before, func(c *abstraction.Context, afterId int) ([]int, error) {
+		data, err := w.service.TaskTypeRepository.FindDeletedBefore(c, before, afterId, retentionBatch)
+		return taskTypeIds(data), err
+	})
+	w.purgeExpired(ctx, "user", before, func(c *abstraction.Context, afterId int) ([]int, error) {
+		data, err := w.service.UserRepository.FindDeletedBefore(c, before, afterId, retentionBatch)
+		return userIds(data), err
+	})
+}
+
+func (w *worker) purgeExpired(ctx context.Context, entity string, before time.Time, find func(c *abstraction.Context, afterId int) ([]int, error)) {
+	var afterId, purged int
+	for ctx.Err() == nil {
+		ids, err := find(newJobContext(), afterId)
+		if err != nil {
+			logrus.Errorf("error find expired %s: %s", entity, err.Error())
+			return
+		}
+
+		for _, id := range ids {
+			afterId = id
+			if err := w.service.purge(newJobContext(), entity, id); err != nil {
+				// records still referenced by live data are kept until a later run
+				if re, ok := err.(*response.MetaError); ok && re.Code == http.StatusBadRequest {
+					logrus.Debugf("skip purge %s %d: %v", entity, id, re.Data)
+					continue
+				}
+				logrus.Errorf("error purge %s %d: %s", entity, id, err.Error())
+				continue
+			}
+			purged++
+		}
+		if len(ids) < retentionBatch {
+			break
+		}
+	}
+
+	if purged > 0 {
+		logrus.Infof("trash retention purged %d %s deleted before %s", purged, entity, before.Format(time.DateOnly))
+	}
+}
+
+// newJobContext gives every purge its own context, WithTrx keeps the committed
+// transaction on the context it was given.
+func newJobContext() *abstraction.Context {
+	return &abstraction.Context{
+		Auth: &abstraction.AuthContext{},
+	}
+}
+
+func workIds(data []*model.WorkEntityModel) []int {
+	var ids []int
+	for _, v := range data {
+		ids = append(ids, v.ID)
+	}
+	return ids
+}
+
+func commentIds(data []*model.CommentEntityModel) []int {
+	var ids []int
+	for _, v := range data {
+		ids = append(ids, v.ID)
+	}
+	return ids
+}
+
+func taskTypeIds(data []*model.TaskTypeEntityModel) []int {
+	var ids []int
+	for _, v := range data {
+		ids = append(ids, v.ID)
+	}
+	return ids
+}
+
+func userIds(data []*model.UserEntityModel) []int {
+	var ids []int
+	for _, v := range data {
+		ids = append(ids, v.ID)
+	}
+	return ids
+}
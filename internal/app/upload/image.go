@@ -0,0 +1,80 @@
+package upload
+
+import (
+	"bytes"
+	"cleancare/internal/abstraction"
+	"cleancare/internal/repository"
+	"cleancare/pkg/constant"
+	"cleancare/pkg/gdrive"
+	"cleancare/pkg/util/general"
+	"cleancare/pkg/util/imagecheck"
+	"cleancare/pkg/util/response"
+	"errors"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"time"
+
+	"google.golang.org/api/drive/v3"
+)
+
+// SaveImage puts an image sent with a form in folder on the drive, the file is inspected on
+// the way for the photo checks.
+func SaveImage(sDrive *drive.Service, folder *drive.File, file *multipart.FileHeader) (*drive.File, *imagecheck.Result, error) {
+	isImageFile, fullFileName := general.ValidateImage(file.Filename)
+	if !isImageFile {
+		return nil, nil, response.ErrorBuilder(http.StatusBadRequest, errors.New("bad_request"), fmt.Sprintf("file format for %s is not approved", file.Filename))
+	}
+
+	f, err := file.Open()
+	if err != nil {
+		return nil, nil, response.ErrorBuilder(http.StatusInternalServerError, err, "server_error")
+	}
+	defer f.Close()
+
+	data, err := io.ReadAll(f)
+	if err != nil {
+		return nil, nil, response.ErrorBuilder(http.StatusInternalServerError, err, "server_error")
+	}
+
+	newFile, err := gdrive.CreateFile(sDrive, fullFileName, "application/octet-stream", bytes.NewReader(data), folder.Id)
+	if err != nil {
+		return nil, nil, response.ErrorBuilder(http.StatusInternalServerError, err, "server_error")
+	}
+	return newFile, imagecheck.Inspect(data), nil
+}
+
+// Claim takes a completed resumable upload of the user for a work or incident image and returns
+// the file as stored in the image columns, with what was read from it when it completed. The
+// claim is undone with the transaction when the row using it is not saved.
+func Claim(ctx *abstraction.Context, sessions repository.UploadSession, id string) (*string, *imagecheck.Result, error) {
+	uploadData, err := sessions.FindById(ctx, id)
+	if err != nil && err.Error() != "record not found" {
+		return nil, nil, response.ErrorBuilder(http.StatusInternalServerError, err, "server_error")
+	}
+	if uploadData == nil || uploadData.ExpiresAt.Before(time.Now()) {
+		return nil, nil, response.ErrorBuilder(http.StatusBadRequest, errors.New("bad_request"), "upload not found")
+	}
+	if uploadData.UserId != ctx.Auth.ID {
+		return nil, nil, response.ErrorBuilder(http.StatusBadRequest, errors.New("bad_request"), "this user is not permitted")
+	}
+	if uploadData.Status != constant.UPLOAD_STATUS_COMPLETED || uploadData.FileId == nil {
+		return nil, nil, response.ErrorBuilder(http.StatusBadRequest, errors.New("bad_request"), "upload is not complete yet")
+	}
+
+	consumed, err := sessions.Consume(ctx, uploadData)
+	if err != nil {
+		return nil, nil, response.ErrorBuilder(http.StatusInternalServerError, err, "server_error")
+	}
+	if !consumed {
+		return nil, nil, response.ErrorBuilder(http.StatusBadRequest, errors.New("bad_request"), "upload is already used")
+	}
+
+	imgFileDelimiter := general.JoinFileAndNameWithDelimiter(*uploadData.FileId, *uploadData.DriveName)
+	check := &imagecheck.Result{
+		CapturedAt: uploadData.CapturedAt,
+		Hash:       uploadData.Phash,
+	}
+	return &imgFileDelimiter, check, nil
+}
@@ -0,0 +1,15 @@
+package upload
+
+import (
+	"cleancare/internal/middleware"
+
+	"github.com/labstack/echo/v4"
+)
+
+func (h *handler) Route(v *echo.Group) {
+	v.POST("", h.Create, middleware.Authentication)
+	v.GET("/:id", h.FindById, middleware.Authentication)
+	v.PATCH("/:id", h.WriteChunk, middleware.Authentication)
+	v.POST("/:id/complete", h.Complete, middleware.Authentication)
+	v.DELETE("/:id", h.Delete, middleware.Authentication)
+}
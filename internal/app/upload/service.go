@@ -0,0 +1,343 @@
+package upload
+
+import (
+	"bytes"
+	"cleancare/internal/abstraction"
+	"cleancare/internal/config"
+	"cleancare/internal/dto"
+	"cleancare/internal/factory"
+	"cleancare/internal/model"
+	"cleancare/internal/repository"
+	"cleancare/pkg/constant"
+	"cleancare/pkg/gdrive"
+	"cleancare/pkg/util/general"
+	"cleancare/pkg/util/imagecheck"
+	"cleancare/pkg/util/response"
+	"cleancare/pkg/util/trxmanager"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+	"google.golang.org/api/drive/v3"
+	"gorm.io/gorm"
+)
+
+type Service interface {
+	Create(ctx *abstraction.Context, payload *dto.UploadCreateRequest) (map[string]interface{}, error)
+	FindById(ctx *abstraction.Context, payload *dto.UploadByIDRequest) (map[string]interface{}, error)
+	WriteChunk(ctx *abstraction.Context, payload *dto.UploadChunkRequest) (map[string]interface{}, error)
+	Complete(ctx *abstraction.Context, payload *dto.UploadByIDRequest) (map[string]interface{}, error)
+	Delete(ctx *abstraction.Context, payload *dto.UploadByIDRequest) (map[string]interface{}, error)
+}
+
+type service struct {
+	UploadSessionRepository repository.UploadSession
+
+	DB     *gorm.DB
+	sDrive *drive.Service
+	fDrive *drive.File
+}
+
+func NewService(f *factory.Factory) Service {
+	return newService(f)
+}
+
+func newService(f *factory.Factory) *service {
+	return &service{
+		UploadSessionRepository: f.UploadSessionRepository,
+
+		DB:     f.Db,
+		sDrive: f.GDrive.Service,
+		fDrive: f.GDrive.FolderCleanCare,
+	}
+}
+
+func (s *service) Create(ctx *abstraction.Context, payload *dto.UploadCreateRequest) (map[string]interface{}, error) {
+	if payload.Size > int64(config.Get().Drive.UploadMaxSize)<<20 {
+		return nil, response.ErrorBuilder(http.StatusBadRequest, errors.New("bad_request"), "file is too large")
+	}
+	if isImageFile, _ := general.ValidateImage(payload.FileName); !isImageFile {
+		return nil, response.ErrorBuilder(http.StatusBadRequest, errors.New("bad_request"), fmt.Sprintf("file format for %s is not approved", payload.FileName))
+	}
+
+	uploadData := &model.UploadSessionEntityModel{
+		ID:      uuid.NewString(),
+		Context: ctx,
+		UploadSessionEntity: model.UploadSessionEntity{
+			UserId:    ctx.Auth.ID,
+			FileName:  payload.FileName,
+			Size:      payload.Size,
+			Checksum:  strings.ToLower(payload.Checksum),
+			Status:    constant.UPLOAD_STATUS_PENDING,
+			ExpiresAt: time.Now().Add(time.Duration(config.Get().Drive.UploadSessionTTL) * time.Minute),
+		},
+	}
+
+	if err := os.MkdirAll(config.Get().Drive.UploadDir, 0o755); err != nil {
+		return nil, response.ErrorBuilder(http.StatusInternalServerError, err, "server_error")
+	}
+	f, err := os.Create(partPath(uploadData.ID))
+	if err != nil {
+		return nil, response.ErrorBuilder(http.StatusInternalServerError, err, "server_error")
+	}
+	f.Close()
+
+	if err := s.UploadSessionRepository.Create(ctx, uploadData).Error; err != nil {
+		os.Remove(partPath(uploadData.ID))
+		return nil, response.ErrorBuilder(http.StatusInternalServerError, err, "server_error")
+	}
+
+	return map[string]interface{}{
+		"message": "success create!",
+		"data":    toMap(uploadData),
+	}, nil
+}
+
+func (s *service) FindById(ctx *abstraction.Context, payload *dto.UploadByIDRequest) (map[string]interface{}, error) {
+	uploadData, err := s.UploadSessionRepository.FindById(ctx, payload.ID)
+	if err := checkSession(ctx, uploadData, err); err != nil {
+		return nil, err
+	}
+
+	return map[string]interface{}{
+		"data": toMap(uploadData),
+	}, nil
+}
+
+// WriteChunk stores chunk at offset, which must be the number of bytes received so far. A
+// client that lost track after a dropped connection reads the offset back with FindById.
+func (s *service) WriteChunk(ctx *abstraction.Context, payload *dto.UploadChunkRequest) (map[string]interface{}, error) {
+	var uploadData *model.UploadSessionEntityModel
+	if err := trxmanager.New(s.DB).WithTrx(ctx, func(ctx *abstraction.Context) error {
+		var err error
+		uploadData, err = s.UploadSessionRepository.FindByIdForUpdate(ctx, payload.ID)
+		if err := checkSession(ctx, uploadData, err); err != nil {
+			return err
+		}
+		if uploadData.Status != constant.UPLOAD_STATUS_PENDING {
+			return response.ErrorBuilder(http.StatusBadRequest, errors.New("bad_request"), "upload is already completed")
+		}
+		if payload.Offset != uploadData.Received {
+			res := response.ErrorBuilder(http.StatusConflict, errors.New("conflict"), "upload offset does not match")
+			res.Data.(map[string]interface{})["offset"] = uploadData.Received
+			return res
+		}
+		if len(payload.Chunk) == 0 {
+			return response.ErrorBuilder(http.StatusBadRequest, errors.New("bad_request"), "chunk is empty")
+		}
+		if payload.Offset+int64(len(payload.Chunk)) > uploadData.Size {
+			return response.ErrorBuilder(http.StatusBadRequest, errors.New("bad_request"), "chunk exceeds the upload size")
+		}
+
+		f, err := os.OpenFile(partPath(uploadData.ID), os.O_WRONLY, 0o644)
+		if err != nil {
+			return response.ErrorBuilder(http.StatusInternalServerError, err, "server_error")
+		}
+		defer f.Close()
+		// drop what an earlier chunk wrote before its request failed
+		if err = f.Truncate(payload.Offset); err != nil {
+			return response.ErrorBuilder(http.StatusInternalServerError, err, "server_error")
+		}
+		if _, err = f.WriteAt(payload.Chunk, payload.Offset); err != nil {
+			return response.ErrorBuilder(http.StatusInternalServerError, err, "server_error")
+		}
+		if err = f.Sync(); err != nil {
+			return response.ErrorBuilder(http.StatusInternalServerError, err, "server_error")
+		}
+
+		uploadData.Received = payload.Offset + int64(len(payload.Chunk))
+		newUploadData := new(model.UploadSessionEntityModel)
+		newUploadData.Context = ctx
+		newUploadData.ID = uploadData.ID
+		newUploadData.Received = uploadData.Received
+		if err = s.UploadSessionRepository.Update(ctx, newUploadData).Error; err != nil {
+			return response.ErrorBuilder(http.StatusInternalServerError, err, "server_error")
+		}
+
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+
+	return map[string]interface{}{
+		"data": toMap(uploadData),
+	}, nil
+}
+
+// Complete checks the received file against the checksum given when the upload was created
+// and moves it to the drive. A mismatch starts the upload over from offset 0.
+func (s *service) Complete(ctx *abstraction.Context, payload *dto.UploadByIDRequest) (map[string]interface{}, error) {
+	var (
+		uploadData       *model.UploadSessionEntityModel
+		fileUploaded     string
+		checksumMismatch bool
+	)
+	if err := trxmanager.New(s.DB).WithTrx(ctx, func(ctx *abstraction.Context) error {
+		var err error
+		uploadData, err = s.UploadSessionRepository.FindByIdForUpdate(ctx, payload.ID)
+		if err := checkSession(ctx, uploadData, err); err != nil {
+			return err
+		}
+		if uploadData.Status == constant.UPLOAD_STATUS_COMPLETED {
+			return nil
+		}
+		if uploadData.Received != uploadData.Size {
+			return response.ErrorBuilder(http.StatusBadRequest, errors.New("bad_request"), "upload is not complete yet")
+		}
+
+		f, err := os.Open(partPath(uploadData.ID))
+		if err != nil {
+			return response.ErrorBuilder(http.StatusInternalServerError, err, "server_error")
+		}
+		defer f.Close()
+
+		hash := sha256.New()
+		if _, err = io.Copy(hash, f); err != nil {
+			return response.ErrorBuilder(http.StatusInternalServerError, err, "server_error")
+		}
+		if hex.EncodeToString(hash.Sum(nil)) != uploadData.Checksum {
+			checksumMismatch = true
+			uploadData.Received = 0
+			if err = s.UploadSessionRepository.Rewind(ctx, uploadData).Error; err != nil {
+				return response.ErrorBuilder(http.StatusInternalServerError, err, "server_error")
+			}
+			return nil
+		}
+		if _, err = f.Seek(0, io.SeekStart); err != nil {
+			return response.ErrorBuilder(http.StatusInternalServerError, err, "server_error")
+		}
+		data, err := io.ReadAll(f)
+		if err != nil {
+			return response.ErrorBuilder(http.StatusInternalServerError, err, "server_error")
+		}
+		// the photo checks run when the upload is used, the file is only read here
+		check := imagecheck.Inspect(data)
+
+		_, fullFileName := general.ValidateImage(uploadData.FileName)
+		newFile, err := gdrive.CreateFile(s.sDrive, fullFileName, "application/octet-stream", bytes.NewReader(data), s.fDrive.Id)
+		if err != nil {
+			return response.ErrorBuilder(http.StatusInternalServerError, err, "server_error")
+		}
+		fileUploaded = newFile.Id
+
+		uploadData.Status = constant.UPLOAD_STATUS_COMPLETED
+		uploadData.FileId = &newFile.Id
+		uploadData.DriveName = &newFile.Name
+		// the file can be referenced for a whole session ttl once it is on the drive
+		uploadData.ExpiresAt = time.Now().Add(time.Duration(config.Get().Drive.UploadSessionTTL) * time.Minute)
+		newUploadData := new(model.UploadSessionEntityModel)
+		newUploadData.Context = ctx
+		newUploadData.ID = uploadData.ID
+		newUploadData.Status = uploadData.Status
+		newUploadData.FileId = uploadData.FileId
+		newUploadData.DriveName = uploadData.DriveName
+		newUploadData.CapturedAt = check.CapturedAt
+		newUploadData.Phash = check.Hash
+		newUploadData.ExpiresAt = uploadData.ExpiresAt
+		if err = s.UploadSessionRepository.Update(ctx, newUploadData).Error; err != nil {
+			return response.ErrorBuilder(http.StatusInternalServerError, err, "server_error")
+		}
+
+		return nil
+	}); err != nil {
+		if fileUploaded != "" {
+			if errDel := gdrive.DeleteFile(s.sDrive, fileUploaded); errDel != nil {
+				logrus.Error("error delete file for error trxmanager:", errDel.Error())
+			}
+		}
+		return nil, err
+	}
+
+	if checksumMismatch {
+		if err := os.Truncate(partPath(uploadData.ID), 0); err != nil {
+			logrus.Error("error truncate upload part:", err.Error())
+		}
+		return nil, response.ErrorBuilder(http.StatusBadRequest, errors.New("bad_request"), "upload checksum does not match")
+	}
+	if fileUploaded != "" {
+		removePart(uploadData.ID)
+	}
+
+	return map[string]interface{}{
+		"data": toMap(uploadData),
+	}, nil
+}
+
+func (s *service) Delete(ctx *abstraction.Context, payload *dto.UploadByIDRequest) (map[string]interface{}, error) {
+	var uploadData *model.UploadSessionEntityModel
+	if err := trxmanager.New(s.DB).WithTrx(ctx, func(ctx *abstraction.Context) error {
+		var err error
+		uploadData, err = s.UploadSessionRepository.FindByIdForUpdate(ctx, payload.ID)
+		if err := checkSession(ctx, uploadData, err); err != nil {
+			return err
+		}
+		if err = s.UploadSessionRepository.Delete(ctx, uploadData).Error; err != nil {
+			return response.ErrorBuilder(http.StatusInternalServerError, err, "server_error")
+		}
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+
+	s.removeFiles(uploadData)
+
+	return map[string]interface{}{
+		"message": "success delete!",
+	}, nil
+}
+
+// removeFiles deletes what an upload left behind once its row is gone, the drive file is kept
+// when a work uses it.
+func (s *service) removeFiles(data *model.UploadSessionEntityModel) {
+	removePart(data.ID)
+	if data.FileId != nil && data.ConsumedAt == nil {
+		if errDel := gdrive.DeleteFile(s.sDrive, *data.FileId); errDel != nil {
+			logrus.Error("error delete upload file:", errDel.Error())
+		}
+	}
+}
+
+func checkSession(ctx *abstraction.Context, data *model.UploadSessionEntityModel, err error) error {
+	if err != nil && err.Error() != "record not found" {
+		return response.ErrorBuilder(http.StatusInternalServerError, err, "server_error")
+	}
+	if data == nil || data.ExpiresAt.Before(time.Now()) {
+		return response.ErrorBuilder(http.StatusBadRequest, errors.New("bad_request"), "upload not found")
+	}
+	if data.UserId != ctx.Auth.ID {
+		return response.ErrorBuilder(http.StatusBadRequest, errors.New("bad_request"), "this user is not permitted")
+	}
+	return nil
+}
+
+func partPath(id string) string {
+	return filepath.Join(config.Get().Drive.UploadDir, id+".part")
+}
+
+func removePart(id string) {
+	if err := os.Remove(partPath(id)); err != nil && !os.IsNotExist(err) {
+		logrus.Error("error remove upload part:", err.Error())
+	}
+}
+
+func toMap(data *model.UploadSessionEntityModel) map[string]interface{} {
+	return map[string]interface{}{
+		"id":             data.ID,
+		"file_name":      data.FileName,
+		"size":           data.Size,
+		"offset":         data.Received,
+		"status":         data.Status,
+		"max_chunk_size": int64(config.Get().Drive.UploadMaxChunkSize) << 20,
+		"expires_at":     data.ExpiresAt,
+		"created_at":     data.CreatedAt,
+	}
+}
@@ -0,0 +1,59 @@
+package upload
+
+import (
+	"cleancare/internal/abstraction"
+	"cleancare/internal/factory"
+	"cleancare/pkg/scheduler"
+	"context"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	cleanupInterval = 30 * time.Minute
+	cleanupBatch    = 50
+)
+
+type worker struct {
+	service *service
+}
+
+// StartCleanup removes the expired upload sessions with their chunks, and their drive file
+// when no work used it, every 30 minutes until ctx is done.
+func StartCleanup(ctx context.Context, f *factory.Factory) {
+	w := &worker{
+		service: newService(f),
+	}
+	scheduler.Every(ctx, "upload cleanup", cleanupInterval, w.run)
+}
+
+func (w *worker) run(ctx context.Context) {
+	jobCtx := &abstraction.Context{
+		Auth: &abstraction.AuthContext{},
+	}
+
+	var removed int
+	for ctx.Err() == nil {
+		data, err := w.service.UploadSessionRepository.FindExpired(jobCtx, time.Now(), cleanupBatch)
+		if err != nil {
+			logrus.Error("error find expired upload:", err.Error())
+			return
+		}
+
+		for _, v := range data {
+			if err := w.service.UploadSessionRepository.Delete(jobCtx, v).Error; err != nil {
+				logrus.Errorf("error delete upload %s: %s", v.ID, err.Error())
+				return
+			}
+			w.service.removeFiles(v)
+			removed++
+		}
+		if len(data) < cleanupBatch {
+			break
+		}
+	}
+	if removed > 0 {
+		logrus.Infof("upload cleanup removed %d expired upload(s)", removed)
+	}
+}
@@ -128,3 +128,27 @@ func (h handler) Export(c echo.Context) (err error) {
 	}
 	return response.SendBlobData(c, filename, *data, format)
 }
+
+func (h handler) Import(c echo.Context) (err error) {
+	payload := new(dto.UserImportRequest)
+	if err = c.Bind(payload); err != nil {
+		return response.ErrorBuilder(http.StatusBadRequest, err, "error bind payload").SendError(c)
+	}
+	if err = c.Validate(payload); err != nil {
+		return response.ErrorBuilder(http.StatusBadRequest, err, "error validate payload").SendError(c)
+	}
+
+	contentType := c.Request().Header.Get("Content-Type")
+	if strings.HasPrefix(contentType, "multipart/form-data") {
+		if err := c.Request().ParseMultipartForm(64 << 20); err != nil {
+			return response.ErrorBuilder(http.StatusBadRequest, err, "error bind multipart/form-data").SendError(c)
+		}
+		payload.File = c.Request().MultipartForm.File["file"]
+	}
+
+	data, err := h.service.Import(c.(*abstraction.Context), payload)
+	if err != nil {
+		return response.ErrorResponse(err).SendError(c)
+	}
+	return response.SuccessResponse(data).SendSuccess(c)
+}
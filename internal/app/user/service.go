@@ -3,17 +3,26 @@ package user
 import (
 	"bytes"
 	"cleancare/internal/abstraction"
+	"cleancare/internal/app/email"
 	"cleancare/internal/dto"
 	"cleancare/internal/factory"
 	"cleancare/internal/model"
 	"cleancare/internal/repository"
 	"cleancare/pkg/constant"
 	"cleancare/pkg/gdrive"
+	"cleancare/pkg/i18n"
+	"cleancare/pkg/password"
+	"cleancare/pkg/util/export"
 	"cleancare/pkg/util/general"
 	"cleancare/pkg/util/response"
 	"cleancare/pkg/util/trxmanager"
+	"encoding/csv"
 	"fmt"
+	"mime/multipart"
 	"net/http"
+	"path/filepath"
+	"strconv"
+	"strings"
 
 	"github.com/go-redis/redis/v8"
 	"github.com/jung-kurt/gofpdf"
@@ -33,11 +42,14 @@ type Service interface {
 	Delete(ctx *abstraction.Context, payload *dto.UserDeleteByIDRequest) (map[string]interface{}, error)
 	ChangePassword(ctx *abstraction.Context, payload *dto.UserChangePasswordRequest) (map[string]interface{}, error)
 	Export(ctx *abstraction.Context, payload *dto.UserExportRequest) (string, *bytes.Buffer, string, error)
+	Import(ctx *abstraction.Context, payload *dto.UserImportRequest) (map[string]interface{}, error)
 }
 
 type service struct {
-	UserRepository repository.User
-	RoleRepository repository.Role
+	UserRepository            repository.User
+	RoleRepository            repository.Role
+	PasswordHistoryRepository repository.PasswordHistory
+	EmailOutboxRepository     repository.EmailOutbox
 
 	DB      *gorm.DB
 	DbRedis *redis.Client
@@ -47,8 +59,10 @@ type service struct {
 
 func NewService(f *factory.Factory) Service {
 	return &service{
-		UserRepository: f.UserRepository,
-		RoleRepository: f.RoleRepository,
+		UserRepository:            f.UserRepository,
+		RoleRepository:            f.RoleRepository,
+		PasswordHistoryRepository: f.PasswordHistoryRepository,
+		EmailOutboxRepository:     f.EmailOutboxRepository,
 
 		DB:      f.Db,
 		DbRedis: f.DbRedis,
@@ -104,20 +118,48 @@ func (s *service) Create(ctx *abstraction.Context, payload *dto.UserCreateReques
 
 func (s *service) Find(ctx *abstraction.Context) (map[string]interface{}, error) {
 	var res []map[string]interface{} = nil
-	data, err := s.UserRepository.Find(ctx, false)
-	if err != nil && err.Error() != "record not found" {
-		return nil, response.ErrorBuilder(http.StatusInternalServerError, err, "server_error")
+	var (
+		data  []*model.UserEntityModel
+		count *int
+		meta  map[string]interface{}
+	)
+	paging, err := general.ProcessCursor(ctx, true)
+	if err != nil {
+		return nil, response.ErrorBuilder(http.StatusBadRequest, errors.New("bad_request"), err.Error())
 	}
-	count, err := s.UserRepository.Count(ctx)
-	if err != nil && err.Error() != "record not found" {
-		return nil, response.ErrorBuilder(http.StatusInternalServerError, err, "server_error")
+	if paging != nil {
+		var hasMore bool
+		data, hasMore, err = s.UserRepository.FindCursor(ctx, paging)
+		if err != nil && err.Error() != "record not found" {
+			return nil, response.ErrorBuilder(http.StatusInternalServerError, err, "server_error")
+		}
+		if general.IncludeTotal(ctx) {
+			count, err = s.UserRepository.Count(ctx)
+			if err != nil && err.Error() != "record not found" {
+				return nil, response.ErrorBuilder(http.StatusInternalServerError, err, "server_error")
+			}
+		}
+		var first, last *general.Cursor
+		if len(data) > 0 {
+			first = general.NewCursor(&data[0].CreatedAt, data[0].ID)
+			last = general.NewCursor(&data[len(data)-1].CreatedAt, data[len(data)-1].ID)
+		}
+		meta = paging.Meta(hasMore, first, last, count)
+	} else {
+		data, err = s.UserRepository.Find(ctx, false)
+		if err != nil && err.Error() != "record not found" {
+			return nil, response.ErrorBuilder(http.StatusInternalServerError, err, "server_error")
+		}
+		count, err = s.UserRepository.Count(ctx)
+		if err != nil && err.Error() != "record not found" {
+			return nil, response.ErrorBuilder(http.StatusInternalServerError, err, "server_error")
+		}
+		meta = general.OffsetMeta(ctx, false, len(data), count)
 	}
 	for _, v := range data {
 		email := "-"
-		verified := false
 		if v.Email != nil {
 			email = *v.Email
-			verified = true
 		}
 
 		resUser := map[string]interface{}{
@@ -125,7 +167,7 @@ func (s *service) Find(ctx *abstraction.Context) (map[string]interface{}, error)
 			"number_id":    v.NumberId,
 			"name":         v.Name,
 			"email":        email,
-			"verified":     verified,
+			"verified":     v.EmailVerifiedAt != nil,
 			"is_delete":    v.IsDelete,
 			"created_at":   general.FormatWithZWithoutChangingTime(v.CreatedAt),
 			"updated_at":   general.FormatWithZWithoutChangingTime(*v.UpdatedAt),
@@ -156,6 +198,7 @@ func (s *service) Find(ctx *abstraction.Context) (map[string]interface{}, error)
 	}
 	return map[string]interface{}{
 		"count": count,
+		"meta":  meta,
 		"data":  res,
 	}, nil
 }
@@ -168,16 +211,19 @@ func (s *service) FindById(ctx *abstraction.Context, payload *dto.UserFindByIDRe
 	}
 	if data != nil {
 		res = map[string]interface{}{
-			"id":           data.ID,
-			"number_id":    data.NumberId,
-			"name":         data.Name,
-			"email":        data.Email,
-			"is_delete":    data.IsDelete,
-			"created_at":   general.FormatWithZWithoutChangingTime(data.CreatedAt),
-			"updated_at":   general.FormatWithZWithoutChangingTime(*data.UpdatedAt),
-			"profile":      data.Profile,
-			"profile_name": data.ProfileName,
-			"floor":        data.Floor,
+			"id":             data.ID,
+			"number_id":      data.NumberId,
+			"name":           data.Name,
+			"email":          data.Email,
+			"email_verified": data.EmailVerifiedAt != nil,
+			"pending_email":  data.PendingEmail,
+			"is_delete":      data.IsDelete,
+			"created_at":     general.FormatWithZWithoutChangingTime(data.CreatedAt),
+			"updated_at":     general.FormatWithZWithoutChangingTime(*data.UpdatedAt),
+			"profile":        data.Profile,
+			"profile_name":   data.ProfileName,
+			"floor":          data.Floor,
+			"language":       data.Language,
 			"role": map[string]interface{}{
 				"id":   data.Role.ID,
 				"name": data.Role.Name,
@@ -208,6 +254,7 @@ func (s *service) Update(ctx *abstraction.Context, payload *dto.UserUpdateReques
 	var (
 		allFileUploaded []string
 		allFileOld      []string
+		pendingEmail    *string
 	)
 	if err := trxmanager.New(s.DB).WithTrx(ctx, func(ctx *abstraction.Context) error {
 		userData, err := s.UserRepository.FindById(ctx, payload.ID)
@@ -227,8 +274,20 @@ func (s *service) Update(ctx *abstraction.Context, payload *dto.UserUpdateReques
 		if payload.Name != nil {
 			newUserData.Name = *payload.Name
 		}
-		if payload.Email != nil {
-			newUserData.Email = payload.Email
+		// a new email is kept as pending until the link sent to it is opened
+		if payload.Email != nil && (userData.Email == nil || !strings.EqualFold(*userData.Email, *payload.Email)) {
+			if !general.IsValidEmail(*payload.Email) {
+				return response.ErrorBuilder(http.StatusBadRequest, errors.New("bad_request"), "email is not valid")
+			}
+			userEmail, err := s.UserRepository.FindByEmail(ctx, *payload.Email)
+			if err != nil && err.Error() != "record not found" {
+				return response.ErrorBuilder(http.StatusInternalServerError, err, "server_error")
+			}
+			if userEmail != nil {
+				return response.ErrorBuilder(http.StatusBadRequest, errors.New("bad_request"), "email already exist")
+			}
+			newUserData.PendingEmail = payload.Email
+			pendingEmail = payload.Email
 		}
 		if payload.RoleId != nil {
 			roleData, err := s.RoleRepository.FindById(ctx, *payload.RoleId)
@@ -283,10 +342,26 @@ func (s *service) Update(ctx *abstraction.Context, payload *dto.UserUpdateReques
 		if payload.Floor != nil {
 			newUserData.Floor = *payload.Floor
 		}
+		if payload.Language != nil {
+			newUserData.Language = payload.Language
+		}
 		if err = s.UserRepository.Update(ctx, newUserData).Error; err != nil {
 			return response.ErrorBuilder(http.StatusInternalServerError, err, "server_error")
 		}
 
+		if payload.Language != nil {
+			general.SetUserLanguageToRedis(s.DbRedis, payload.ID, payload.Language)
+		}
+
+		if pendingEmail != nil {
+			if payload.Language != nil {
+				userData.Language = payload.Language
+			}
+			if err = email.SendVerification(ctx, s.EmailOutboxRepository, userData, *pendingEmail); err != nil {
+				return err
+			}
+		}
+
 		return nil
 	}); err != nil {
 		for _, v := range allFileUploaded {
@@ -305,9 +380,13 @@ func (s *service) Update(ctx *abstraction.Context, payload *dto.UserUpdateReques
 		}
 	}
 
-	return map[string]interface{}{
+	res := map[string]interface{}{
 		"message": "success update!",
-	}, nil
+	}
+	if pendingEmail != nil {
+		res["pending_email"] = *pendingEmail
+	}
+	return res, nil
 }
 
 func (s *service) Delete(ctx *abstraction.Context, payload *dto.UserDeleteByIDRequest) (map[string]interface{}, error) {
@@ -328,6 +407,7 @@ func (s *service) Delete(ctx *abstraction.Context, payload *dto.UserDeleteByIDRe
 		newUserData.Context = ctx
 		newUserData.ID = userData.ID
 		newUserData.IsDelete = true
+		newUserData.DeletedAt = general.Now()
 
 		if err = s.UserRepository.Update(ctx, newUserData).Error; err != nil {
 			return response.ErrorBuilder(http.StatusInternalServerError, err, "server_error")
@@ -369,6 +449,15 @@ func (s *service) ChangePassword(ctx *abstraction.Context, payload *dto.UserChan
 			return response.ErrorBuilder(http.StatusBadRequest, errors.New("bad_request"), "the new password cannot be the same as the old password")
 		}
 
+		policy := password.CurrentPolicy()
+		usedPasswords, err := s.PasswordHistoryRepository.FindLastPasswords(ctx, userData.ID, policy.History)
+		if err != nil {
+			return response.ErrorBuilder(http.StatusInternalServerError, err, "server_error")
+		}
+		if fields := policy.Validate("new_password", payload.NewPassword, usedPasswords); fields != nil {
+			return response.FieldErrorBuilder("password does not meet the policy", fields)
+		}
+
 		hashedPassword, err := bcrypt.GenerateFromPassword([]byte(payload.NewPassword), bcrypt.DefaultCost)
 		if err != nil {
 			return response.ErrorBuilder(http.StatusInternalServerError, err, "server_error")
@@ -383,6 +472,9 @@ func (s *service) ChangePassword(ctx *abstraction.Context, payload *dto.UserChan
 		if err = s.UserRepository.Update(ctx, newUserData).Error; err != nil {
 			return response.ErrorBuilder(http.StatusInternalServerError, err, "server_error")
 		}
+		if err = s.PasswordHistoryRepository.Add(ctx, userData.ID, hashPasswordStr).Error; err != nil {
+			return response.ErrorBuilder(http.StatusInternalServerError, err, "server_error")
+		}
 
 		userLoginFrom := general.GetRedisUUIDArray(s.DbRedis, general.GenerateRedisKeyUserLogin(userData.ID))
 		for _, v := range userLoginFrom {
@@ -405,21 +497,34 @@ func (s *service) Export(ctx *abstraction.Context, payload *dto.UserExportReques
 		return "", nil, "", response.ErrorBuilder(http.StatusInternalServerError, err, "server_error")
 	}
 
+	lang := ctx.Lang()
+	title := i18n.T(lang, "export.user.title")
+	headers := []string{
+		i18n.T(lang, "export.user.no"),
+		i18n.T(lang, "export.user.number_id"),
+		i18n.T(lang, "export.user.name"),
+		i18n.T(lang, "export.user.email"),
+		i18n.T(lang, "export.user.role"),
+		i18n.T(lang, "export.user.registered_at"),
+		i18n.T(lang, "export.user.verified"),
+		i18n.T(lang, "export.user.floor"),
+	}
+
+	if export.IsDataFormat(payload.Format) {
+		return s.exportData(payload, data, title)
+	}
+
 	if payload.Format == "pdf" {
 		pdf := gofpdf.New("L", "mm", "A4", "")
 		pdf.SetMargins(10, 10, 10)
 		pdf.AddPage()
 		pdf.SetAutoPageBreak(true, 10)
 		pdf.SetFont("Arial", "B", 16)
-		pdf.Cell(0, 10, "CleanCare - Laporan Data Pengguna")
+		pdf.Cell(0, 10, title)
 		pdf.Ln(12)
 		pdf.SetFont("Arial", "B", 10)
-		header := []string{
-			"No", "Nomor ID", "Nama", "Email",
-			"Jabatan", "Tanggal Terdaftar", "Status Verifikasi", "Penempatan",
-		}
 		colWidths := []float64{8, 30, 38, 48, 35, 55, 30, 33}
-		for i, str := range header {
+		for i, str := range headers {
 			pdf.CellFormat(colWidths[i], 8, str, "1", 0, "C", false, 0, "")
 		}
 		pdf.Ln(-1)
@@ -429,18 +534,20 @@ func (s *service) Export(ctx *abstraction.Context, payload *dto.UserExportReques
 			no := fmt.Sprintf("%d", i+1)
 			email := "-"
 			role := ""
-			verified := "Belum"
+			verified := i18n.T(lang, "verified.no")
 			floor := "-"
 
 			if v.Email != nil {
 				email = *v.Email
-				verified = "Sudah"
+			}
+			if v.EmailVerifiedAt != nil {
+				verified = i18n.T(lang, "verified.yes")
 			}
 
 			if v.RoleId == constant.ROLE_ID_STAFF {
-				role = "Petugas Kebersihan"
+				role = i18n.T(lang, "role.staff")
 			} else {
-				role = "Supervisor"
+				role = i18n.T(lang, "role.admin")
 			}
 
 			if v.Floor != "" {
@@ -453,7 +560,7 @@ func (s *service) Export(ctx *abstraction.Context, payload *dto.UserExportReques
 				v.Name,
 				email,
 				role,
-				general.ConvertDateTimeToIndonesian(v.CreatedAt.Format("2006-01-02 15:04:05")),
+				i18n.FormatDateTime(lang, v.CreatedAt),
 				verified,
 				floor,
 			}
@@ -471,7 +578,7 @@ func (s *service) Export(ctx *abstraction.Context, payload *dto.UserExportReques
 			if pdf.GetY()+maxHeight > 190 {
 				pdf.AddPage()
 				pdf.SetFont("Arial", "B", 10)
-				for i, str := range header {
+				for i, str := range headers {
 					pdf.CellFormat(colWidths[i], 8, str, "1", 0, "C", false, 0, "")
 				}
 				pdf.Ln(-1)
@@ -494,7 +601,7 @@ func (s *service) Export(ctx *abstraction.Context, payload *dto.UserExportReques
 			return "", nil, "", response.ErrorBuilder(http.StatusInternalServerError, err, "server_error")
 		}
 
-		filename := "CleanCare - Laporan Data Pengguna.pdf"
+		filename := title + ".pdf"
 		return filename, &buf, "pdf", nil
 
 	} else {
@@ -507,7 +614,6 @@ func (s *service) Export(ctx *abstraction.Context, payload *dto.UserExportReques
 		f.DeleteSheet("Sheet1")
 		f.SetActiveSheet(index)
 
-		headers := []string{"No", "Nomor ID", "Nama", "Email", "Jabatan", "Tanggal Terdaftar", "Status Verifikasi", "Penempatan"}
 		for i, h := range headers {
 			col := string(rune('A' + i))
 			cell := fmt.Sprintf("%s1", col)
@@ -529,15 +635,18 @@ func (s *service) Export(ctx *abstraction.Context, payload *dto.UserExportReques
 
 			if v.Email == nil {
 				values[3] = "-"
-				values[6] = "Belum"
 			} else {
 				values[3] = *v.Email
-				values[6] = "Sudah"
+			}
+			if v.EmailVerifiedAt == nil {
+				values[6] = i18n.T(lang, "verified.no")
+			} else {
+				values[6] = i18n.T(lang, "verified.yes")
 			}
 			if v.RoleId == constant.ROLE_ID_STAFF {
-				values[4] = "Petugas Kebersihan"
+				values[4] = i18n.T(lang, "role.staff")
 			} else {
-				values[4] = "Supervisor"
+				values[4] = i18n.T(lang, "role.admin")
 			}
 
 			if v.Floor == "" {
@@ -546,7 +655,7 @@ func (s *service) Export(ctx *abstraction.Context, payload *dto.UserExportReques
 				values[7] = v.Floor
 			}
 
-			values[5] = general.ConvertDateTimeToIndonesian(v.CreatedAt.Format("2006-01-02 15:04:05"))
+			values[5] = i18n.FormatDateTime(lang, v.CreatedAt)
 
 			for j, val := range values {
 				col := string(rune('A' + j))
@@ -580,7 +689,351 @@ func (s *service) Export(ctx *abstraction.Context, payload *dto.UserExportReques
 		if err := f.Write(&buf); err != nil {
 			return "", nil, "", response.ErrorBuilder(http.StatusInternalServerError, err, "server_error")
 		}
-		filename := "CleanCare - Laporan Data Pengguna.xlsx"
+		filename := title + ".xlsx"
 		return filename, &buf, "excel", nil
 	}
 }
+
+var userExportColumns = []string{
+	"id", "number_id", "name", "email", "role_id", "role_name", "floor", "verified", "created_at", "updated_at",
+}
+
+func (s *service) exportData(payload *dto.UserExportRequest, data []*model.UserEntityModel, title string) (string, *bytes.Buffer, string, error) {
+	columns, err := export.SelectColumns(userExportColumns, payload.Columns)
+	if err != nil {
+		return "", nil, "", response.ErrorBuilder(http.StatusBadRequest, errors.New("bad_request"), err.Error())
+	}
+
+	var rows []map[string]interface{}
+	for _, v := range data {
+		var (
+			email     string
+			updatedAt string
+		)
+		if v.Email != nil {
+			email = *v.Email
+		}
+		if v.UpdatedAt != nil {
+			updatedAt = general.FormatWithZWithoutChangingTime(*v.UpdatedAt)
+		}
+		rows = append(rows, map[string]interface{}{
+			"id":         v.ID,
+			"number_id":  v.NumberId,
+			"name":       v.Name,
+			"email":      email,
+			"role_id":    v.RoleId,
+			"role_name":  v.Role.Name,
+			"floor":      v.Floor,
+			"verified":   v.EmailVerifiedAt != nil,
+			"created_at": general.FormatWithZWithoutChangingTime(v.CreatedAt),
+			"updated_at": updatedAt,
+		})
+	}
+
+	buf, err := export.Write(payload.Format, columns, rows)
+	if err != nil {
+		return "", nil, "", response.ErrorBuilder(http.StatusInternalServerError, err, "server_error")
+	}
+
+	filename := fmt.Sprintf("%s.%s", title, payload.Format)
+	return filename, buf, payload.Format, nil
+}
+
+func (s *service) Import(ctx *abstraction.Context, payload *dto.UserImportRequest) (map[string]interface{}, error) {
+	if ctx.Auth.RoleID != constant.ROLE_ID_ADMIN {
+		return nil, response.ErrorBuilder(http.StatusBadRequest, errors.New("bad_request"), "this role is not permitted")
+	}
+	if len(payload.File) == 0 {
+		return nil, response.ErrorBuilder(http.StatusBadRequest, errors.New("bad_request"), "file not found")
+	}
+
+	records, err := readImportFile(payload.File[0])
+	if err != nil {
+		return nil, err
+	}
+	rows, err := parseImportRows(records)
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		report []map[string]interface{}
+		fields []response.FieldError
+	)
+	if err = trxmanager.New(s.DB).WithTrx(ctx, func(ctx *abstraction.Context) error {
+		report, fields, err = s.validateImportRows(ctx, rows)
+		if err != nil {
+			return err
+		}
+		if payload.DryRun {
+			return nil
+		}
+		if len(fields) > 0 {
+			return response.FieldErrorBuilder("import file has invalid rows", fields)
+		}
+
+		for _, v := range rows {
+			modelUser := &model.UserEntityModel{
+				Context: ctx,
+				UserEntity: model.UserEntity{
+					NumberId: v.NumberId,
+					Name:     v.Name,
+					RoleId:   v.RoleId,
+					IsDelete: false,
+					Floor:    v.Floor,
+				},
+			}
+			if err = s.UserRepository.Create(ctx, modelUser).Error; err != nil {
+				return response.ErrorBuilder(http.StatusInternalServerError, err, "server_error")
+			}
+		}
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+
+	if payload.DryRun {
+		return map[string]interface{}{
+			"dry_run": true,
+			"total":   len(rows),
+			"valid":   len(rows) - countInvalidRows(report),
+			"invalid": countInvalidRows(report),
+			"data":    report,
+			"fields":  fields,
+		}, nil
+	}
+	return map[string]interface{}{
+		"message": "success import!",
+		"count":   len(rows),
+	}, nil
+}
+
+type userImportRow struct {
+	Row      int
+	NumberId string
+	Name     string
+	Role     string
+	RoleId   int
+	Floor    string
+}
+
+const (
+	importColumnNumberId = "number_id"
+	importColumnName     = "name"
+	importColumnRole     = "role"
+	importColumnFloor    = "floor"
+)
+
+// importColumnAliases maps a lowercased header to its import column, accepting the
+// keys of the data export as well as the localized headers of the excel export.
+func importColumnAliases() map[string]string {
+	aliases := map[string]string{
+		"number_id": importColumnNumberId,
+		"name":      importColumnName,
+		"role":      importColumnRole,
+		"role_id":   importColumnRole,
+		"role_name": importColumnRole,
+		"floor":     importColumnFloor,
+	}
+	for _, lang := range []string{i18n.LANG_ID, i18n.LANG_EN} {
+		aliases[strings.ToLower(i18n.T(lang, "export.user.number_id"))] = importColumnNumberId
+		aliases[strings.ToLower(i18n.T(lang, "export.user.name"))] = importColumnName
+		aliases[strings.ToLower(i18n.T(lang, "export.user.role"))] = importColumnRole
+		aliases[strings.ToLower(i18n.T(lang, "export.user.floor"))] = importColumnFloor
+	}
+	return aliases
+}
+
+func readImportFile(file *multipart.FileHeader) ([][]string, error) {
+	src, err := file.Open()
+	if err != nil {
+		return nil, response.ErrorBuilder(http.StatusInternalServerError, err, "server_error")
+	}
+	defer src.Close()
+
+	switch strings.ToLower(filepath.Ext(file.Filename)) {
+	case ".xlsx":
+		f, err := excelize.OpenReader(src)
+		if err != nil {
+			return nil, response.ErrorBuilder(http.StatusBadRequest, err, "import file is not valid")
+		}
+		defer f.Close()
+		sheets := f.GetSheetList()
+		if len(sheets) == 0 {
+			return nil, response.ErrorBuilder(http.StatusBadRequest, errors.New("bad_request"), "import file is empty")
+		}
+		records, err := f.GetRows(sheets[0])
+		if err != nil {
+			return nil, response.ErrorBuilder(http.StatusBadRequest, err, "import file is not valid")
+		}
+		return records, nil
+	case ".csv":
+		r := csv.NewReader(src)
+		r.FieldsPerRecord = -1
+		r.TrimLeadingSpace = true
+		records, err := r.ReadAll()
+		if err != nil {
+			return nil, response.ErrorBuilder(http.StatusBadRequest, err, "import file is not valid")
+		}
+		if len(records) > 0 && len(records[0]) > 0 {
+			records[0][0] = strings.TrimPrefix(records[0][0], "\ufeff")
+		}
+		return records, nil
+	}
+	return nil, response.ErrorBuilder(http.StatusBadRequest, errors.New("bad_request"), "import file must be xlsx or csv")
+}
+
+func parseImportRows(records [][]string) ([]*userImportRow, error) {
+	if len(records) < 2 {
+		return nil, response.ErrorBuilder(http.StatusBadRequest, errors.New("bad_request"), "import file is empty")
+	}
+
+	aliases := importColumnAliases()
+	index := map[string]int{}
+	for i, v := range records[0] {
+		if col, ok := aliases[strings.ToLower(strings.TrimSpace(v))]; ok {
+			if _, exist := index[col]; !exist {
+				index[col] = i
+			}
+		}
+	}
+	for _, col := range []string{importColumnNumberId, importColumnName, importColumnRole, importColumnFloor} {
+		if _, ok := index[col]; !ok {
+			return nil, response.ErrorBuilder(http.StatusBadRequest, errors.New("bad_request"), "import file has missing columns")
+		}
+	}
+
+	cell := func(record []string, col string) string {
+		i := index[col]
+		if i >= len(record) {
+			return ""
+		}
+		return strings.TrimSpace(record[i])
+	}
+
+	var rows []*userImportRow
+	for i, record := range records[1:] {
+		row := &userImportRow{
+			Row:      i + 2,
+			NumberId: cell(record, importColumnNumberId),
+			Name:     cell(record, importColumnName),
+			Role:     cell(record, importColumnRole),
+			Floor:    cell(record, importColumnFloor),
+		}
+		if row.NumberId == "" && row.Name == "" && row.Role == "" && row.Floor == "" {
+			continue
+		}
+		rows = append(rows, row)
+	}
+	if len(rows) == 0 {
+		return nil, response.ErrorBuilder(http.StatusBadRequest, errors.New("bad_request"), "import file is empty")
+	}
+	if len(rows) > constant.USER_IMPORT_MAX_ROWS {
+		return nil, response.ErrorBuilder(http.StatusBadRequest, errors.New("bad_request"), "import file has too many rows")
+	}
+	return rows, nil
+}
+
+// validateImportRows checks every row and resolves its role, the returned field errors
+// carry the spreadsheet row number in their params.
+func (s *service) validateImportRows(ctx *abstraction.Context, rows []*userImportRow) ([]map[string]interface{}, []response.FieldError, error) {
+	roles := map[string]int{}
+	for _, id := range []int{constant.ROLE_ID_ADMIN, constant.ROLE_ID_STAFF} {
+		roleData, err := s.RoleRepository.FindById(ctx, id)
+		if err != nil && err.Error() != "record not found" {
+			return nil, nil, response.ErrorBuilder(http.StatusInternalServerError, err, "server_error")
+		}
+		if roleData != nil {
+			roles[strings.ToLower(roleData.Name)] = roleData.ID
+		}
+	}
+	for _, lang := range []string{i18n.LANG_ID, i18n.LANG_EN} {
+		roles[strings.ToLower(i18n.T(lang, "role.staff"))] = constant.ROLE_ID_STAFF
+		roles[strings.ToLower(i18n.T(lang, "role.admin"))] = constant.ROLE_ID_ADMIN
+	}
+
+	var (
+		report []map[string]interface{}
+		fields []response.FieldError
+		seen   = map[string]int{}
+	)
+	for _, v := range rows {
+		var rowFields []response.FieldError
+		add := func(field, code, message string, params map[string]interface{}) {
+			if params == nil {
+				params = map[string]interface{}{}
+			}
+			params["row"] = v.Row
+			rowFields = append(rowFields, response.FieldError{
+				Field:   field,
+				Code:    code,
+				Message: message,
+				Params:  params,
+			})
+		}
+
+		if v.NumberId == "" {
+			add("number_id", "required", "number id is required", nil)
+		} else if first, ok := seen[strings.ToLower(v.NumberId)]; ok {
+			add("number_id", "duplicate", "number id is duplicated in the file", map[string]interface{}{"first_row": first})
+		} else {
+			seen[strings.ToLower(v.NumberId)] = v.Row
+			userNumber, err := s.UserRepository.FindByNumberId(ctx, v.NumberId)
+			if err != nil && err.Error() != "record not found" {
+				return nil, nil, response.ErrorBuilder(http.StatusInternalServerError, err, "server_error")
+			}
+			if userNumber != nil {
+				add("number_id", "exist", "number id already exist", nil)
+			}
+		}
+
+		if v.Name == "" {
+			add("name", "required", "name is required", nil)
+		}
+
+		if v.Role == "" {
+			add("role", "required", "role is required", nil)
+		} else if roleId, err := strconv.Atoi(v.Role); err == nil {
+			roleData, err := s.RoleRepository.FindById(ctx, roleId)
+			if err != nil && err.Error() != "record not found" {
+				return nil, nil, response.ErrorBuilder(http.StatusInternalServerError, err, "server_error")
+			}
+			if roleData == nil {
+				add("role", "unknown", "role not found", nil)
+			} else {
+				v.RoleId = roleData.ID
+			}
+		} else if roleId, ok := roles[strings.ToLower(v.Role)]; ok {
+			v.RoleId = roleId
+		} else {
+			add("role", "unknown", "role not found", nil)
+		}
+
+		if v.Floor == "" || v.Floor == "-" {
+			add("floor", "required", "floor is required", nil)
+		} else if len([]rune(v.Floor)) > constant.USER_IMPORT_MAX_FLOOR_LENGTH {
+			add("floor", "max_length", "floor is too long", nil)
+		}
+
+		report = append(report, map[string]interface{}{
+			"row":       v.Row,
+			"number_id": v.NumberId,
+			"name":      v.Name,
+			"role_id":   v.RoleId,
+			"floor":     v.Floor,
+			"valid":     len(rowFields) == 0,
+		})
+		fields = append(fields, rowFields...)
+	}
+	return report, fields, nil
+}
+
+func countInvalidRows(report []map[string]interface{}) int {
+	count := 0
+	for _, v := range report {
+		if valid, _ := v["valid"].(bool); !valid {
+			count++
+		}
+	}
+	return count
+}
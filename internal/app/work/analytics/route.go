@@ -0,0 +1,15 @@
+package analytics
+
+import (
+	"cleancare/internal/middleware"
+
+	"github.com/labstack/echo/v4"
+)
+
+func (h *Handler) Route(v *echo.Group) {
+	v.GET("/trend", h.Trend, middleware.Authentication)
+	v.GET("/completion", h.Completion, middleware.Authentication)
+	v.GET("/staff", h.Staff, middleware.Authentication)
+	v.GET("/task-type", h.TaskType, middleware.Authentication)
+	v.GET("/comparison", h.Comparison, middleware.Authentication)
+}
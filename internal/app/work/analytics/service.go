@@ -0,0 +1,301 @@
+package analytics
+
+import (
+	"cleancare/internal/abstraction"
+	"cleancare/internal/dto"
+	"cleancare/internal/factory"
+	"cleancare/internal/model"
+	"cleancare/internal/repository"
+	"cleancare/pkg/constant"
+	"cleancare/pkg/util/general"
+	"cleancare/pkg/util/response"
+	"errors"
+	"fmt"
+	"math"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+	"gorm.io/gorm"
+)
+
+type Service interface {
+	Trend(ctx *abstraction.Context, payload *dto.WorkAnalyticsRequest) (map[string]interface{}, error)
+	Completion(ctx *abstraction.Context, payload *dto.WorkAnalyticsRequest) (map[string]interface{}, error)
+	Staff(ctx *abstraction.Context, payload *dto.WorkAnalyticsRequest) (map[string]interface{}, error)
+	TaskType(ctx *abstraction.Context, payload *dto.WorkAnalyticsRequest) (map[string]interface{}, error)
+	Comparison(ctx *abstraction.Context, payload *dto.WorkAnalyticsRequest) (map[string]interface{}, error)
+}
+
+type service struct {
+	WorkRepository repository.Work
+
+	DB      *gorm.DB
+	DbRedis *redis.Client
+}
+
+func NewService(f *factory.Factory) Service {
+	return &service{
+		WorkRepository: f.WorkRepository,
+
+		DB:      f.Db,
+		DbRedis: f.DbRedis,
+	}
+}
+
+func (s *service) Trend(ctx *abstraction.Context, payload *dto.WorkAnalyticsRequest) (map[string]interface{}, error) {
+	if err := s.validate(ctx, payload); err != nil {
+		return nil, err
+	}
+	if payload.Interval == "" {
+		payload.Interval = "day"
+	}
+
+	data, err := s.cached("trend", payload, func() (interface{}, error) {
+		trend, err := s.WorkRepository.AnalyticsTrend(ctx, payload.TaskId, payload.CreatedAt, payload.Interval)
+		if err != nil {
+			return nil, err
+		}
+		var res []map[string]interface{}
+		for _, v := range trend {
+			res = append(res, map[string]interface{}{
+				"period":          v.Period,
+				"total":           v.Total,
+				"completed":       v.Completed,
+				"completion_rate": percentage(v.Completed, v.Total),
+			})
+		}
+		return res, nil
+	})
+	if err != nil {
+		return nil, response.ErrorBuilder(http.StatusInternalServerError, err, "server_error")
+	}
+
+	return map[string]interface{}{
+		"interval": payload.Interval,
+		"data":     data,
+	}, nil
+}
+
+func (s *service) Completion(ctx *abstraction.Context, payload *dto.WorkAnalyticsRequest) (map[string]interface{}, error) {
+	if err := s.validate(ctx, payload); err != nil {
+		return nil, err
+	}
+
+	data, err := s.cached("completion", payload, func() (interface{}, error) {
+		summary, err := s.WorkRepository.AnalyticsCompletion(ctx, payload.TaskId, payload.CreatedAt)
+		if err != nil {
+			return nil, err
+		}
+		return completionMap(summary), nil
+	})
+	if err != nil {
+		return nil, response.ErrorBuilder(http.StatusInternalServerError, err, "server_error")
+	}
+
+	return map[string]interface{}{
+		"data": data,
+	}, nil
+}
+
+func (s *service) Staff(ctx *abstraction.Context, payload *dto.WorkAnalyticsRequest) (map[string]interface{}, error) {
+	if err := s.validate(ctx, payload); err != nil {
+		return nil, err
+	}
+	if payload.Top == 0 {
+		payload.Top = 5
+	}
+
+	data, err := s.cached("staff", payload, func() (interface{}, error) {
+		staff, err := s.WorkRepository.AnalyticsStaff(ctx, payload.TaskId, payload.CreatedAt)
+		if err != nil {
+			return nil, err
+		}
+		var res []map[string]interface{}
+		for _, v := range staff {
+			res = append(res, map[string]interface{}{
+				"user_id":                v.UserId,
+				"name":                   v.Name,
+				"total":                  v.Total,
+				"completed":              v.Completed,
+				"completion_rate":        percentage(v.Completed, v.Total),
+				"avg_completion_seconds": math.Round(v.AvgCompletionSeconds),
+			})
+		}
+
+		top := res[:min(payload.Top, len(res))]
+		bottom := []map[string]interface{}{}
+		for i := len(res) - 1; i >= 0 && len(bottom) < payload.Top; i-- {
+			bottom = append(bottom, res[i])
+		}
+		return map[string]interface{}{
+			"top":    top,
+			"bottom": bottom,
+		}, nil
+	})
+	if err != nil {
+		return nil, response.ErrorBuilder(http.StatusInternalServerError, err, "server_error")
+	}
+
+	return map[string]interface{}{
+		"data": data,
+	}, nil
+}
+
+func (s *service) TaskType(ctx *abstraction.Context, payload *dto.WorkAnalyticsRequest) (map[string]interface{}, error) {
+	if err := s.validate(ctx, payload); err != nil {
+		return nil, err
+	}
+
+	data, err := s.cached("task-type", payload, func() (interface{}, error) {
+		taskType, err := s.WorkRepository.AnalyticsTaskType(ctx, payload.TaskId, payload.CreatedAt)
+		if err != nil {
+			return nil, err
+		}
+		total := 0
+		for _, v := range taskType {
+			total += v.Count
+		}
+		var res []map[string]interface{}
+		for _, v := range taskType {
+			res = append(res, map[string]interface{}{
+				"task_type_id": v.TaskTypeId,
+				"name":         v.Name,
+				"count":        v.Count,
+				"percentage":   percentage(v.Count, total),
+			})
+		}
+		return res, nil
+	})
+	if err != nil {
+		return nil, response.ErrorBuilder(http.StatusInternalServerError, err, "server_error")
+	}
+
+	return map[string]interface{}{
+		"data": data,
+	}, nil
+}
+
+func (s *service) Comparison(ctx *abstraction.Context, payload *dto.WorkAnalyticsRequest) (map[string]interface{}, error) {
+	if err := s.validate(ctx, payload); err != nil {
+		return nil, err
+	}
+
+	data, err := s.cached("comparison", payload, func() (interface{}, error) {
+		previousCreatedAt := previousPeriod(payload.CreatedAt)
+
+		current, err := s.WorkRepository.AnalyticsCompletion(ctx, payload.TaskId, payload.CreatedAt)
+		if err != nil {
+			return nil, err
+		}
+		previous, err := s.WorkRepository.AnalyticsCompletion(ctx, payload.TaskId, previousCreatedAt)
+		if err != nil {
+			return nil, err
+		}
+
+		currentRate := percentage(current.Completed, current.Total)
+		previousRate := percentage(previous.Completed, previous.Total)
+		return map[string]interface{}{
+			"current": map[string]interface{}{
+				"created_at": payload.CreatedAt,
+				"summary":    completionMap(current),
+			},
+			"previous": map[string]interface{}{
+				"created_at": previousCreatedAt,
+				"summary":    completionMap(previous),
+			},
+			"change": map[string]interface{}{
+				"total":                  change(float64(current.Total), float64(previous.Total)),
+				"completed":              change(float64(current.Completed), float64(previous.Completed)),
+				"completion_rate":        change(currentRate, previousRate),
+				"avg_completion_seconds": change(math.Round(current.AvgCompletionSeconds), math.Round(previous.AvgCompletionSeconds)),
+			},
+		}, nil
+	})
+	if err != nil {
+		return nil, response.ErrorBuilder(http.StatusInternalServerError, err, "server_error")
+	}
+
+	return map[string]interface{}{
+		"data": data,
+	}, nil
+}
+
+func (s *service) validate(ctx *abstraction.Context, payload *dto.WorkAnalyticsRequest) error {
+	if ctx.Auth.RoleID != constant.ROLE_ID_ADMIN {
+		return response.ErrorBuilder(http.StatusBadRequest, errors.New("bad_request"), "this role is not permitted")
+	}
+	start, end, ok := parseCreatedAt(payload.CreatedAt)
+	if !ok || end.Before(start) {
+		return response.ErrorBuilder(http.StatusBadRequest, errors.New("bad_request"), "created_at must be formatted as YYYY-MM-DD_YYYY-MM-DD")
+	}
+	return nil
+}
+
+// cached returns the result of fn from redis when it is still there, analytics
+// are allowed to be a few minutes behind the work table.
+func (s *service) cached(name string, payload *dto.WorkAnalyticsRequest, fn func() (interface{}, error)) (interface{}, error) {
+	key := fmt.Sprintf(constant.REDIS_KEY_WORK_ANALYTICS, name, fmt.Sprintf("%d_%s_%s_%d", payload.TaskId, payload.CreatedAt, payload.Interval, payload.Top))
+
+	var data interface{}
+	if general.GetCacheRedis(s.DbRedis, key, &data) {
+		return data, nil
+	}
+
+	data, err := fn()
+	if err != nil {
+		return nil, err
+	}
+	general.SetCacheRedis(s.DbRedis, key, data, constant.REDIS_WORK_ANALYTICS_EXPIRE*time.Minute)
+	return data, nil
+}
+
+func completionMap(v *model.WorkCompletionSummary) map[string]interface{} {
+	return map[string]interface{}{
+		"total":                  v.Total,
+		"completed":              v.Completed,
+		"completion_rate":        percentage(v.Completed, v.Total),
+		"avg_completion_seconds": math.Round(v.AvgCompletionSeconds),
+	}
+}
+
+func parseCreatedAt(createdAt string) (time.Time, time.Time, bool) {
+	valDate := strings.Split(createdAt, "_")
+	if len(valDate) != 2 {
+		return time.Time{}, time.Time{}, false
+	}
+	start, errStart := time.Parse("2006-01-02", valDate[0])
+	end, errEnd := time.Parse("2006-01-02", valDate[1])
+	if errStart != nil || errEnd != nil {
+		return time.Time{}, time.Time{}, false
+	}
+	return start, end, true
+}
+
+// previousPeriod returns the range of the same length right before createdAt.
+func previousPeriod(createdAt string) string {
+	start, end, _ := parseCreatedAt(createdAt)
+	days := int(end.Sub(start).Hours()/24) + 1
+	previousEnd := start.AddDate(0, 0, -1)
+	previousStart := previousEnd.AddDate(0, 0, -(days - 1))
+	return previousStart.Format("2006-01-02") + "_" + previousEnd.Format("2006-01-02")
+}
+
+func percentage(part, total int) float64 {
+	if total == 0 {
+		return 0
+	}
+	return math.Round(float64(part)/float64(total)*10000) / 100
+}
+
+func change(current, previous float64) map[string]interface{} {
+	res := map[string]interface{}{
+		"difference": math.Round((current-previous)*100) / 100,
+		"percentage": nil,
+	}
+	if previous != 0 {
+		res["percentage"] = math.Round((current-previous)/previous*10000) / 100
+	}
+	return res
+}
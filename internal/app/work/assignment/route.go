@@ -0,0 +1,15 @@
+package assignment
+
+import (
+	"cleancare/internal/middleware"
+
+	"github.com/labstack/echo/v4"
+)
+
+func (h *Handler) Route(v *echo.Group) {
+	v.POST("", h.Create, middleware.Authentication)
+	v.GET("", h.Find, middleware.Authentication)
+	v.GET("/:id", h.FindById, middleware.Authentication)
+	v.PUT("/:id", h.Update, middleware.Authentication)
+	v.DELETE("/:id", h.Delete, middleware.Authentication)
+}
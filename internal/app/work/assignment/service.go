@@ -0,0 +1,310 @@
+package assignment
+
+import (
+	"cleancare/internal/abstraction"
+	"cleancare/internal/dto"
+	"cleancare/internal/factory"
+	"cleancare/internal/model"
+	"cleancare/internal/repository"
+	"cleancare/pkg/constant"
+	"cleancare/pkg/util/general"
+	"cleancare/pkg/util/response"
+	"cleancare/pkg/util/trxmanager"
+	"errors"
+	"net/http"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type Service interface {
+	Create(ctx *abstraction.Context, payload *dto.AssignmentCreateRequest) (map[string]interface{}, error)
+	Find(ctx *abstraction.Context) (map[string]interface{}, error)
+	FindById(ctx *abstraction.Context, payload *dto.AssignmentFindByIDRequest) (map[string]interface{}, error)
+	Update(ctx *abstraction.Context, payload *dto.AssignmentUpdateRequest) (map[string]interface{}, error)
+	Delete(ctx *abstraction.Context, payload *dto.AssignmentDeleteByIDRequest) (map[string]interface{}, error)
+}
+
+type service struct {
+	AssignmentRepository repository.Assignment
+	UserRepository       repository.User
+	TaskRepository       repository.Task
+	TaskTypeRepository   repository.TaskType
+	AreaRepository       repository.Area
+
+	DB *gorm.DB
+}
+
+func NewService(f *factory.Factory) Service {
+	return &service{
+		AssignmentRepository: f.AssignmentRepository,
+		UserRepository:       f.UserRepository,
+		TaskRepository:       f.TaskRepository,
+		TaskTypeRepository:   f.TaskTypeRepository,
+		AreaRepository:       f.AreaRepository,
+
+		DB: f.Db,
+	}
+}
+
+func (s *service) Create(ctx *abstraction.Context, payload *dto.AssignmentCreateRequest) (map[string]interface{}, error) {
+	if err := trxmanager.New(s.DB).WithTrx(ctx, func(ctx *abstraction.Context) error {
+		if ctx.Auth.RoleID != constant.ROLE_ID_ADMIN {
+			return response.ErrorBuilder(http.StatusBadRequest, errors.New("bad_request"), "this role is not permitted")
+		}
+
+		if err := s.validateStaff(ctx, payload.UserId); err != nil {
+			return err
+		}
+		if err := s.validateTask(ctx, payload.TaskId, payload.TaskTypeId); err != nil {
+			return err
+		}
+		if payload.AreaId != nil {
+			if err := s.validateArea(ctx, *payload.AreaId); err != nil {
+				return err
+			}
+		}
+
+		dueAt, _ := time.ParseInLocation("2006-01-02 15:04:05", payload.DueAt, time.Local)
+		modelAssignment := &model.AssignmentEntityModel{
+			Context: ctx,
+			AssignmentEntity: model.AssignmentEntity{
+				UserId:     payload.UserId,
+				TaskId:     payload.TaskId,
+				TaskTypeId: payload.TaskTypeId,
+				AreaId:     payload.AreaId,
+				Floor:      payload.Floor,
+				Info:       payload.Info,
+				DueAt:      dueAt,
+				IsDelete:   false,
+			},
+		}
+		if err := s.AssignmentRepository.Create(ctx, modelAssignment).Error; err != nil {
+			return response.ErrorBuilder(http.StatusInternalServerError, err, "server_error")
+		}
+
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+	return map[string]interface{}{
+		"message": "success create!",
+	}, nil
+}
+
+func (s *service) Find(ctx *abstraction.Context) (map[string]interface{}, error) {
+	userId := 0
+	if ctx.Auth.RoleID == constant.ROLE_ID_STAFF {
+		userId = ctx.Auth.ID
+	}
+
+	data, err := s.AssignmentRepository.Find(ctx, userId, false)
+	if err != nil && err.Error() != "record not found" {
+		return nil, response.ErrorBuilder(http.StatusInternalServerError, err, "server_error")
+	}
+	count, err := s.AssignmentRepository.Count(ctx, userId)
+	if err != nil && err.Error() != "record not found" {
+		return nil, response.ErrorBuilder(http.StatusInternalServerError, err, "server_error")
+	}
+
+	var res []map[string]interface{} = nil
+	for _, v := range data {
+		res = append(res, s.toMap(v))
+	}
+	return map[string]interface{}{
+		"count": count,
+		"data":  res,
+	}, nil
+}
+
+func (s *service) FindById(ctx *abstraction.Context, payload *dto.AssignmentFindByIDRequest) (map[string]interface{}, error) {
+	data, err := s.AssignmentRepository.FindById(ctx, payload.ID)
+	if err != nil && err.Error() != "record not found" {
+		return nil, response.ErrorBuilder(http.StatusInternalServerError, err, "server_error")
+	}
+	if data == nil {
+		return nil, response.ErrorBuilder(http.StatusBadRequest, errors.New("bad_request"), "assignment not found")
+	}
+	if ctx.Auth.RoleID == constant.ROLE_ID_STAFF && data.UserId != ctx.Auth.ID {
+		return nil, response.ErrorBuilder(http.StatusBadRequest, errors.New("bad_request"), "this user is not permitted")
+	}
+
+	return map[string]interface{}{
+		"data": s.toMap(data),
+	}, nil
+}
+
+func (s *service) Update(ctx *abstraction.Context, payload *dto.AssignmentUpdateRequest) (map[string]interface{}, error) {
+	if err := trxmanager.New(s.DB).WithTrx(ctx, func(ctx *abstraction.Context) error {
+		if ctx.Auth.RoleID != constant.ROLE_ID_ADMIN {
+			return response.ErrorBuilder(http.StatusBadRequest, errors.New("bad_request"), "this role is not permitted")
+		}
+
+		assignmentData, err := s.AssignmentRepository.FindById(ctx, payload.ID)
+		if err != nil && err.Error() != "record not found" {
+			return response.ErrorBuilder(http.StatusInternalServerError, err, "server_error")
+		}
+		if assignmentData == nil {
+			return response.ErrorBuilder(http.StatusBadRequest, errors.New("bad_request"), "assignment not found")
+		}
+		if assignmentData.WorkId != nil {
+			return response.ErrorBuilder(http.StatusBadRequest, errors.New("bad_request"), "assignment already done")
+		}
+
+		newAssignmentData := new(model.AssignmentEntityModel)
+		newAssignmentData.Context = ctx
+		newAssignmentData.ID = payload.ID
+		if payload.UserId != nil {
+			if err := s.validateStaff(ctx, *payload.UserId); err != nil {
+				return err
+			}
+			newAssignmentData.UserId = *payload.UserId
+		}
+		if payload.TaskId != nil || payload.TaskTypeId != nil {
+			taskId, taskTypeId := assignmentData.TaskId, assignmentData.TaskTypeId
+			if payload.TaskId != nil {
+				taskId = *payload.TaskId
+			}
+			if payload.TaskTypeId != nil {
+				taskTypeId = *payload.TaskTypeId
+			}
+			if err := s.validateTask(ctx, taskId, taskTypeId); err != nil {
+				return err
+			}
+			newAssignmentData.TaskId = taskId
+			newAssignmentData.TaskTypeId = taskTypeId
+		}
+		if payload.AreaId != nil {
+			if err := s.validateArea(ctx, *payload.AreaId); err != nil {
+				return err
+			}
+			newAssignmentData.AreaId = payload.AreaId
+		}
+		if payload.Floor != nil {
+			newAssignmentData.Floor = *payload.Floor
+		}
+		if payload.Info != nil {
+			newAssignmentData.Info = *payload.Info
+		}
+		if payload.DueAt != nil {
+			dueAt, _ := time.ParseInLocation("2006-01-02 15:04:05", *payload.DueAt, time.Local)
+			newAssignmentData.DueAt = dueAt
+		}
+
+		if err = s.AssignmentRepository.Update(ctx, newAssignmentData).Error; err != nil {
+			return response.ErrorBuilder(http.StatusInternalServerError, err, "server_error")
+		}
+
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+	return map[string]interface{}{
+		"message": "success update!",
+	}, nil
+}
+
+func (s *service) Delete(ctx *abstraction.Context, payload *dto.AssignmentDeleteByIDRequest) (map[string]interface{}, error) {
+	if err := trxmanager.New(s.DB).WithTrx(ctx, func(ctx *abstraction.Context) error {
+		if ctx.Auth.RoleID != constant.ROLE_ID_ADMIN {
+			return response.ErrorBuilder(http.StatusBadRequest, errors.New("bad_request"), "this role is not permitted")
+		}
+
+		assignmentData, err := s.AssignmentRepository.FindById(ctx, payload.ID)
+		if err != nil && err.Error() != "record not found" {
+			return response.ErrorBuilder(http.StatusInternalServerError, err, "server_error")
+		}
+		if assignmentData == nil {
+			return response.ErrorBuilder(http.StatusBadRequest, errors.New("bad_request"), "assignment not found")
+		}
+
+		newAssignmentData := new(model.AssignmentEntityModel)
+		newAssignmentData.Context = ctx
+		newAssignmentData.ID = assignmentData.ID
+		newAssignmentData.IsDelete = true
+
+		if err = s.AssignmentRepository.Update(ctx, newAssignmentData).Error; err != nil {
+			return response.ErrorBuilder(http.StatusInternalServerError, err, "server_error")
+		}
+
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+	return map[string]interface{}{
+		"message": "success delete!",
+	}, nil
+}
+
+func (s *service) validateArea(ctx *abstraction.Context, areaId int) error {
+	areaData, err := s.AreaRepository.FindById(ctx, areaId)
+	if err != nil && err.Error() != "record not found" {
+		return response.ErrorBuilder(http.StatusInternalServerError, err, "server_error")
+	}
+	if areaData == nil {
+		return response.ErrorBuilder(http.StatusBadRequest, errors.New("bad_request"), "area not found")
+	}
+	return nil
+}
+
+func (s *service) validateStaff(ctx *abstraction.Context, userId int) error {
+	userData, err := s.UserRepository.FindById(ctx, userId)
+	if err != nil && err.Error() != "record not found" {
+		return response.ErrorBuilder(http.StatusInternalServerError, err, "server_error")
+	}
+	if userData == nil {
+		return response.ErrorBuilder(http.StatusBadRequest, errors.New("bad_request"), "user not found")
+	}
+	if userData.RoleId != constant.ROLE_ID_STAFF {
+		return response.ErrorBuilder(http.StatusBadRequest, errors.New("bad_request"), "this user is not permitted")
+	}
+	return nil
+}
+
+func (s *service) validateTask(ctx *abstraction.Context, taskId, taskTypeId int) error {
+	taskData, err := s.TaskRepository.FindById(ctx, taskId)
+	if err != nil && err.Error() != "record not found" {
+		return response.ErrorBuilder(http.StatusInternalServerError, err, "server_error")
+	}
+	if taskData == nil {
+		return response.ErrorBuilder(http.StatusBadRequest, errors.New("bad_request"), "task not found")
+	}
+	taskTypeData, err := s.TaskTypeRepository.FindById(ctx, taskTypeId)
+	if err != nil && err.Error() != "record not found" {
+		return response.ErrorBuilder(http.StatusInternalServerError, err, "server_error")
+	}
+	if taskTypeData == nil || taskTypeData.TaskId != taskId {
+		return response.ErrorBuilder(http.StatusBadRequest, errors.New("bad_request"), "task type not found")
+	}
+	return nil
+}
+
+func (s *service) toMap(v *model.AssignmentEntityModel) map[string]interface{} {
+	res := map[string]interface{}{
+		"id": v.ID,
+		"user": map[string]interface{}{
+			"id":   v.User.ID,
+			"name": v.User.Name,
+		},
+		"task": map[string]interface{}{
+			"id":   v.Task.ID,
+			"name": v.Task.Name,
+		},
+		"task_type": map[string]interface{}{
+			"id":   v.TaskType.ID,
+			"name": v.TaskType.Name,
+		},
+		"area_id":    v.AreaId,
+		"floor":      v.Floor,
+		"info":       v.Info,
+		"due_at":     general.FormatWithZWithoutChangingTime(v.DueAt),
+		"work_id":    v.WorkId,
+		"is_done":    v.WorkId != nil,
+		"created_at": general.FormatWithZWithoutChangingTime(v.CreatedAt),
+		"updated_at": nil,
+	}
+	if v.UpdatedAt != nil {
+		res["updated_at"] = general.FormatWithZWithoutChangingTime(*v.UpdatedAt)
+	}
+	return res
+}
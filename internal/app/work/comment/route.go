@@ -8,7 +8,7 @@ import (
 
 func (h *Handler) Route(v *echo.Group) {
 	v.GET("/:work_id", h.FindByWorkId, middleware.Authentication)
-	v.POST("", h.Create, middleware.Authentication)
+	v.POST("", h.Create, middleware.Authentication, middleware.Idempotency)
 	v.DELETE("/:id", h.Delete, middleware.Authentication)
 	v.PUT("/:id", h.Update, middleware.Authentication)
 }
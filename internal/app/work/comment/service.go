@@ -55,13 +55,43 @@ func (s *service) FindByWorkId(ctx *abstraction.Context, payload *dto.CommentFin
 		return nil, response.ErrorBuilder(http.StatusBadRequest, errors.New("bad_request"), "work not found")
 	}
 
-	data, err := s.CommentRepository.FindByWorkId(ctx, payload.WorkId, false)
-	if err != nil && err.Error() != "record not found" {
-		return nil, response.ErrorBuilder(http.StatusInternalServerError, err, "server_error")
+	var (
+		data  []*model.CommentEntityModel
+		count *int
+		meta  map[string]interface{}
+	)
+	paging, err := general.ProcessCursor(ctx, true)
+	if err != nil {
+		return nil, response.ErrorBuilder(http.StatusBadRequest, errors.New("bad_request"), err.Error())
 	}
-	count, err := s.CommentRepository.CountByWorkId(ctx, payload.WorkId)
-	if err != nil && err.Error() != "record not found" {
-		return nil, response.ErrorBuilder(http.StatusInternalServerError, err, "server_error")
+	if paging != nil {
+		var hasMore bool
+		data, hasMore, err = s.CommentRepository.FindByWorkIdCursor(ctx, payload.WorkId, paging)
+		if err != nil && err.Error() != "record not found" {
+			return nil, response.ErrorBuilder(http.StatusInternalServerError, err, "server_error")
+		}
+		if general.IncludeTotal(ctx) {
+			count, err = s.CommentRepository.CountByWorkId(ctx, payload.WorkId)
+			if err != nil && err.Error() != "record not found" {
+				return nil, response.ErrorBuilder(http.StatusInternalServerError, err, "server_error")
+			}
+		}
+		var first, last *general.Cursor
+		if len(data) > 0 {
+			first = general.NewCursor(&data[0].CreatedAt, data[0].ID)
+			last = general.NewCursor(&data[len(data)-1].CreatedAt, data[len(data)-1].ID)
+		}
+		meta = paging.Meta(hasMore, first, last, count)
+	} else {
+		data, err = s.CommentRepository.FindByWorkId(ctx, payload.WorkId, false)
+		if err != nil && err.Error() != "record not found" {
+			return nil, response.ErrorBuilder(http.StatusInternalServerError, err, "server_error")
+		}
+		count, err = s.CommentRepository.CountByWorkId(ctx, payload.WorkId)
+		if err != nil && err.Error() != "record not found" {
+			return nil, response.ErrorBuilder(http.StatusInternalServerError, err, "server_error")
+		}
+		meta = general.OffsetMeta(ctx, false, len(data), count)
 	}
 
 	var res []map[string]interface{} = nil
@@ -90,6 +120,7 @@ func (s *service) FindByWorkId(ctx *abstraction.Context, payload *dto.CommentFin
 	}
 	return map[string]interface{}{
 		"count": count,
+		"meta":  meta,
 		"data":  res,
 	}, nil
 }
@@ -156,6 +187,7 @@ func (s *service) Delete(ctx *abstraction.Context, payload *dto.CommentDeleteByI
 		newCommentData.Context = ctx
 		newCommentData.ID = commentData.ID
 		newCommentData.IsDelete = true
+		newCommentData.DeletedAt = general.Now()
 		if err = s.CommentRepository.Update(ctx, newCommentData).Error; err != nil {
 			return response.ErrorBuilder(http.StatusInternalServerError, err, "server_error")
 		}
@@ -0,0 +1,13 @@
+package flag
+
+import (
+	"cleancare/internal/middleware"
+
+	"github.com/labstack/echo/v4"
+)
+
+func (h *Handler) Route(v *echo.Group) {
+	v.GET("", h.Find, middleware.Authentication)
+	v.GET("/:id", h.FindById, middleware.Authentication)
+	v.PUT("/:id", h.Review, middleware.Authentication)
+}
@@ -0,0 +1,172 @@
+package flag
+
+import (
+	"cleancare/internal/abstraction"
+	"cleancare/internal/dto"
+	"cleancare/internal/factory"
+	"cleancare/internal/model"
+	"cleancare/internal/repository"
+	"cleancare/pkg/constant"
+	"cleancare/pkg/util/general"
+	"cleancare/pkg/util/response"
+	"cleancare/pkg/util/trxmanager"
+	"errors"
+	"net/http"
+
+	"gorm.io/gorm"
+)
+
+type Service interface {
+	Find(ctx *abstraction.Context) (map[string]interface{}, error)
+	FindById(ctx *abstraction.Context, payload *dto.PhotoFlagFindByIDRequest) (map[string]interface{}, error)
+	Review(ctx *abstraction.Context, payload *dto.PhotoFlagReviewRequest) (map[string]interface{}, error)
+}
+
+type service struct {
+	PhotoFlagRepository repository.PhotoFlag
+
+	DB *gorm.DB
+}
+
+func NewService(f *factory.Factory) Service {
+	return &service{
+		PhotoFlagRepository: f.PhotoFlagRepository,
+
+		DB: f.Db,
+	}
+}
+
+// Find is the review list of the admin, filtered by status, type and work_id.
+func (s *service) Find(ctx *abstraction.Context) (map[string]interface{}, error) {
+	if ctx.Auth.RoleID != constant.ROLE_ID_ADMIN {
+		return nil, response.ErrorBuilder(http.StatusBadRequest, errors.New("bad_request"), "this role is not permitted")
+	}
+
+	data, err := s.PhotoFlagRepository.Find(ctx, false)
+	if err != nil && err.Error() != "record not found" {
+		return nil, response.ErrorBuilder(http.StatusInternalServerError, err, "server_error")
+	}
+	count, err := s.PhotoFlagRepository.Count(ctx)
+	if err != nil && err.Error() != "record not found" {
+		return nil, response.ErrorBuilder(http.StatusInternalServerError, err, "server_error")
+	}
+
+	var res []map[string]interface{} = nil
+	for _, v := range data {
+		res = append(res, s.toMap(v))
+	}
+	return map[string]interface{}{
+		"count": count,
+		"meta":  general.OffsetMeta(ctx, false, len(data), count),
+		"data":  res,
+	}, nil
+}
+
+func (s *service) FindById(ctx *abstraction.Context, payload *dto.PhotoFlagFindByIDRequest) (map[string]interface{}, error) {
+	if ctx.Auth.RoleID != constant.ROLE_ID_ADMIN {
+		return nil, response.ErrorBuilder(http.StatusBadRequest, errors.New("bad_request"), "this role is not permitted")
+	}
+
+	data, err := s.PhotoFlagRepository.FindById(ctx, payload.ID)
+	if err != nil && err.Error() != "record not found" {
+		return nil, response.ErrorBuilder(http.StatusInternalServerError, err, "server_error")
+	}
+	if data == nil {
+		return nil, response.ErrorBuilder(http.StatusBadRequest, errors.New("bad_request"), "photo flag not found")
+	}
+
+	return map[string]interface{}{
+		"data": s.toMap(data),
+	}, nil
+}
+
+func (s *service) Review(ctx *abstraction.Context, payload *dto.PhotoFlagReviewRequest) (map[string]interface{}, error) {
+	if err := trxmanager.New(s.DB).WithTrx(ctx, func(ctx *abstraction.Context) error {
+		if ctx.Auth.RoleID != constant.ROLE_ID_ADMIN {
+			return response.ErrorBuilder(http.StatusBadRequest, errors.New("bad_request"), "this role is not permitted")
+		}
+
+		flagData, err := s.PhotoFlagRepository.FindById(ctx, payload.ID)
+		if err != nil && err.Error() != "record not found" {
+			return response.ErrorBuilder(http.StatusInternalServerError, err, "server_error")
+		}
+		if flagData == nil {
+			return response.ErrorBuilder(http.StatusBadRequest, errors.New("bad_request"), "photo flag not found")
+		}
+
+		newFlagData := new(model.PhotoFlagEntityModel)
+		newFlagData.Context = ctx
+		newFlagData.ID = flagData.ID
+		newFlagData.Status = payload.Status
+		newFlagData.ReviewNote = payload.Note
+		newFlagData.ReviewedBy = &ctx.Auth.ID
+		newFlagData.ReviewedAt = general.Now()
+		if err = s.PhotoFlagRepository.Update(ctx, newFlagData).Error; err != nil {
+			return response.ErrorBuilder(http.StatusInternalServerError, err, "server_error")
+		}
+
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+	return map[string]interface{}{
+		"message": "success update!",
+	}, nil
+}
+
+func (s *service) toMap(v *model.PhotoFlagEntityModel) map[string]interface{} {
+	res := map[string]interface{}{
+		"id": v.ID,
+		"work": map[string]interface{}{
+			"id":    v.Work.ID,
+			"floor": v.Work.Floor,
+			"info":  v.Work.Info,
+			"user": map[string]interface{}{
+				"id":   v.Work.User.ID,
+				"name": v.Work.User.Name,
+			},
+			"verification_status": v.Work.VerificationStatus,
+		},
+		"photo":        photoToMap(&v.WorkPhoto),
+		"type":         v.Type,
+		"captured_at":  nil,
+		"submitted_at": general.FormatWithZWithoutChangingTime(v.SubmittedAt),
+		"match":        nil,
+		"status":       v.Status,
+		"review_note":  v.ReviewNote,
+		"reviewed_by":  v.ReviewedBy,
+		"reviewed_at":  nil,
+		"created_at":   general.FormatWithZWithoutChangingTime(v.CreatedAt),
+	}
+	if v.CapturedAt != nil {
+		res["captured_at"] = general.FormatWithZWithoutChangingTime(*v.CapturedAt)
+	}
+	if v.MatchWorkId != nil {
+		match := map[string]interface{}{
+			"work_id":  v.MatchWorkId,
+			"distance": v.Distance,
+			"photo":    nil,
+		}
+		// the matched photo may have been removed since
+		if v.MatchWorkPhoto != nil {
+			match["photo"] = photoToMap(v.MatchWorkPhoto)
+		}
+		res["match"] = match
+	}
+	if v.ReviewedAt != nil {
+		res["reviewed_at"] = general.FormatWithZWithoutChangingTime(*v.ReviewedAt)
+	}
+	return res
+}
+
+func photoToMap(v *model.WorkPhotoEntityModel) map[string]interface{} {
+	if v.ID == 0 {
+		return nil
+	}
+	return map[string]interface{}{
+		"id":    v.ID,
+		"phase": v.Phase,
+		"view":  "https://lh3.googleusercontent.com/d/" + v.StorageKey,
+		"name":  v.FileName,
+	}
+}
@@ -2,7 +2,12 @@ package work
 
 import (
 	"cleancare/internal/abstraction"
+	"cleancare/internal/app/work/analytics"
+	"cleancare/internal/app/work/assignment"
 	"cleancare/internal/app/work/comment"
+	"cleancare/internal/app/work/flag"
+	"cleancare/internal/app/work/scorecard"
+	"cleancare/internal/app/work/worksync"
 	"cleancare/internal/dto"
 	"cleancare/internal/factory"
 	"cleancare/pkg/util/response"
@@ -15,14 +20,26 @@ import (
 type handler struct {
 	service Service
 
-	CommentHandler comment.Handler
+	CommentHandler   comment.Handler
+	AnalyticsHandler analytics.Handler
+
+	AssignmentHandler assignment.Handler
+	ScorecardHandler  scorecard.Handler
+	SyncHandler       worksync.Handler
+	FlagHandler       flag.Handler
 }
 
 func NewHandler(f *factory.Factory) *handler {
 	return &handler{
 		service: NewService(f),
 
-		CommentHandler: *comment.NewHandler(f),
+		CommentHandler:   *comment.NewHandler(f),
+		AnalyticsHandler: *analytics.NewHandler(f),
+
+		AssignmentHandler: *assignment.NewHandler(f),
+		ScorecardHandler:  *scorecard.NewHandler(f),
+		SyncHandler:       *worksync.NewHandler(f),
+		FlagHandler:       *flag.NewHandler(f),
 	}
 }
 
@@ -160,3 +177,73 @@ func (h handler) DashboardStaf(c echo.Context) (err error) {
 	}
 	return response.SuccessResponse(data).SendSuccess(c)
 }
+
+func (h handler) Verify(c echo.Context) (err error) {
+	payload := new(dto.WorkVerifyRequest)
+	if err := c.Bind(payload); err != nil {
+		return response.ErrorBuilder(http.StatusBadRequest, err, "error bind payload").SendError(c)
+	}
+	if err = c.Validate(payload); err != nil {
+		return response.ErrorBuilder(http.StatusBadRequest, err, "error validate payload").SendError(c)
+	}
+	data, err := h.service.Verify(c.(*abstraction.Context), payload)
+	if err != nil {
+		return response.ErrorResponse(err).SendError(c)
+	}
+	return response.SuccessResponse(data).SendSuccess(c)
+}
+
+func (h handler) CreatePhoto(c echo.Context) (err error) {
+	payload := new(dto.WorkPhotoCreateRequest)
+
+	if err = c.Bind(payload); err != nil {
+		return response.ErrorBuilder(http.StatusBadRequest, err, "error bind payload").SendError(c)
+	}
+	if err = c.Validate(payload); err != nil {
+		return response.ErrorBuilder(http.StatusBadRequest, err, "error validate payload").SendError(c)
+	}
+
+	contentType := c.Request().Header.Get("Content-Type")
+	if strings.HasPrefix(contentType, "multipart/form-data") {
+		if err := c.Request().ParseMultipartForm(64 << 20); err != nil {
+			return response.ErrorBuilder(http.StatusBadRequest, err, "error bind multipart/form-data").SendError(c)
+		}
+		payload.File = c.Request().MultipartForm.File["file"]
+	}
+
+	data, err := h.service.CreatePhoto(c.(*abstraction.Context), payload)
+	if err != nil {
+		return response.ErrorResponse(err).SendError(c)
+	}
+	return response.SuccessResponse(data).SendSuccess(c)
+}
+
+func (h handler) OrderPhoto(c echo.Context) (err error) {
+	payload := new(dto.WorkPhotoOrderRequest)
+	if err := c.Bind(payload); err != nil {
+		return response.ErrorBuilder(http.StatusBadRequest, err, "error bind payload").SendError(c)
+	}
+	if err = c.Validate(payload); err != nil {
+		return response.ErrorBuilder(http.StatusBadRequest, err, "error validate payload").SendError(c)
+	}
+	data, err := h.service.OrderPhoto(c.(*abstraction.Context), payload)
+	if err != nil {
+		return response.ErrorResponse(err).SendError(c)
+	}
+	return response.SuccessResponse(data).SendSuccess(c)
+}
+
+func (h handler) DeletePhoto(c echo.Context) (err error) {
+	payload := new(dto.WorkPhotoDeleteRequest)
+	if err := c.Bind(payload); err != nil {
+		return response.ErrorBuilder(http.StatusBadRequest, err, "error bind payload").SendError(c)
+	}
+	if err = c.Validate(payload); err != nil {
+		return response.ErrorBuilder(http.StatusBadRequest, err, "error validate payload").SendError(c)
+	}
+	data, err := h.service.DeletePhoto(c.(*abstraction.Context), payload)
+	if err != nil {
+		return response.ErrorResponse(err).SendError(c)
+	}
+	return response.SuccessResponse(data).SendSuccess(c)
+}
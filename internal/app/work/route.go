@@ -7,14 +7,23 @@ import (
 )
 
 func (h *handler) Route(v *echo.Group) {
-	v.POST("", h.Create, middleware.Authentication)
+	v.POST("", h.Create, middleware.Authentication, middleware.Idempotency)
 	v.DELETE("/:id", h.Delete, middleware.Authentication)
 	v.GET("", h.Find, middleware.Authentication)
 	v.GET("/:id", h.FindById, middleware.Authentication)
-	v.PUT("/:id", h.Update, middleware.Authentication)
+	v.PUT("/:id", h.Update, middleware.Authentication, middleware.Idempotency)
 	v.GET("/export", h.Export, middleware.Authentication)
 	v.GET("/dashboard-admin", h.DashboardAdmin, middleware.Authentication)
 	v.GET("/dashboard-staf", h.DashboardStaf, middleware.Authentication)
+	v.PUT("/:id/verification", h.Verify, middleware.Authentication)
+	v.POST("/:id/photo", h.CreatePhoto, middleware.Authentication, middleware.Idempotency)
+	v.PUT("/:id/photo/order", h.OrderPhoto, middleware.Authentication)
+	v.DELETE("/:id/photo/:photo_id", h.DeletePhoto, middleware.Authentication)
 
 	h.CommentHandler.Route(v.Group("/comment"))
+	h.AnalyticsHandler.Route(v.Group("/analytics"))
+	h.AssignmentHandler.Route(v.Group("/assignment"))
+	h.ScorecardHandler.Route(v.Group("/scorecard"))
+	h.SyncHandler.Route(v.Group("/sync"))
+	h.FlagHandler.Route(v.Group("/photo-flag"))
 }
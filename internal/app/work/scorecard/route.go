@@ -0,0 +1,14 @@
+package scorecard
+
+import (
+	"cleancare/internal/middleware"
+
+	"github.com/labstack/echo/v4"
+)
+
+func (h *Handler) Route(v *echo.Group) {
+	v.GET("/leaderboard", h.Leaderboard, middleware.Authentication)
+	v.GET("/leaderboard/export", h.Export, middleware.Authentication)
+	v.GET("/me", h.History, middleware.Authentication)
+	v.GET("/user/:user_id", h.History, middleware.Authentication)
+}
@@ -0,0 +1,298 @@
+package scorecard
+
+import (
+	"bytes"
+	"cleancare/internal/abstraction"
+	"cleancare/internal/dto"
+	"cleancare/internal/factory"
+	"cleancare/internal/model"
+	"cleancare/internal/repository"
+	"cleancare/pkg/constant"
+	"cleancare/pkg/i18n"
+	"cleancare/pkg/util/general"
+	"cleancare/pkg/util/response"
+	"errors"
+	"fmt"
+	"math"
+	"net/http"
+	"sort"
+	"time"
+
+	"github.com/jung-kurt/gofpdf"
+	"github.com/xuri/excelize/v2"
+	"gorm.io/gorm"
+)
+
+type Service interface {
+	Leaderboard(ctx *abstraction.Context, payload *dto.WorkScorecardLeaderboardRequest) (map[string]interface{}, error)
+	Export(ctx *abstraction.Context, payload *dto.WorkScorecardExportRequest) (string, *bytes.Buffer, string, error)
+	History(ctx *abstraction.Context, payload *dto.WorkScorecardHistoryRequest) (map[string]interface{}, error)
+}
+
+type service struct {
+	WorkRepository repository.Work
+	UserRepository repository.User
+
+	DB *gorm.DB
+}
+
+func NewService(f *factory.Factory) Service {
+	return &service{
+		WorkRepository: f.WorkRepository,
+		UserRepository: f.UserRepository,
+
+		DB: f.Db,
+	}
+}
+
+func (s *service) Leaderboard(ctx *abstraction.Context, payload *dto.WorkScorecardLeaderboardRequest) (map[string]interface{}, error) {
+	if ctx.Auth.RoleID != constant.ROLE_ID_ADMIN {
+		return nil, response.ErrorBuilder(http.StatusBadRequest, errors.New("bad_request"), "this role is not permitted")
+	}
+
+	month := parseMonth(payload.Month)
+	data, err := s.leaderboard(ctx, month)
+	if err != nil {
+		return nil, err
+	}
+
+	var res []map[string]interface{} = nil
+	for i, v := range data {
+		row := scorecardMap(v)
+		row["rank"] = i + 1
+		res = append(res, row)
+	}
+	return map[string]interface{}{
+		"month": month.Format("2006-01"),
+		"data":  res,
+	}, nil
+}
+
+func (s *service) Export(ctx *abstraction.Context, payload *dto.WorkScorecardExportRequest) (string, *bytes.Buffer, string, error) {
+	if ctx.Auth.RoleID != constant.ROLE_ID_ADMIN {
+		return "", nil, "", response.ErrorBuilder(http.StatusBadRequest, errors.New("bad_request"), "this role is not permitted")
+	}
+
+	month := parseMonth(payload.Month)
+	data, err := s.leaderboard(ctx, month)
+	if err != nil {
+		return "", nil, "", err
+	}
+
+	lang := ctx.Lang()
+	title := i18n.T(lang, "export.scorecard.title")
+	monthLabel := fmt.Sprintf("%s %d", i18n.T(lang, fmt.Sprintf("month.%d", int(month.Month()))), month.Year())
+	headers := []string{
+		i18n.T(lang, "export.scorecard.rank"),
+		i18n.T(lang, "export.scorecard.staff"),
+		i18n.T(lang, "export.scorecard.completed"),
+		i18n.T(lang, "export.scorecard.assigned"),
+		i18n.T(lang, "export.scorecard.on_time_rate"),
+		i18n.T(lang, "export.scorecard.rejected"),
+		i18n.T(lang, "export.scorecard.comment_turnaround"),
+	}
+
+	var rows [][]string
+	for i, v := range data {
+		turnaround := "-"
+		if v.AvgCommentTurnaroundSeconds != nil {
+			turnaround = fmt.Sprintf("%.0f", *v.AvgCommentTurnaroundSeconds/60)
+		}
+		rows = append(rows, []string{
+			fmt.Sprintf("%d", i+1),
+			v.Name,
+			fmt.Sprintf("%d", v.Completed),
+			fmt.Sprintf("%d", v.Assigned),
+			fmt.Sprintf("%.2f", percentage(v.OnTime, v.Assigned)),
+			fmt.Sprintf("%d", v.Rejected),
+			turnaround,
+		})
+	}
+
+	filename := fmt.Sprintf("(%s) %s", month.Format("200601"), title)
+	if payload.Format == "pdf" {
+		pdf := gofpdf.New("L", "mm", "A4", "")
+		pdf.SetMargins(10, 10, 10)
+		pdf.AddPage()
+		pdf.SetAutoPageBreak(true, 10)
+		pdf.SetFont("Arial", "B", 16)
+		pdf.Cell(0, 10, fmt.Sprintf("%s (%s)", title, monthLabel))
+		pdf.Ln(12)
+		pdf.SetFont("Arial", "B", 10)
+		colWidths := []float64{20, 70, 30, 30, 35, 30, 62}
+		for i, str := range headers {
+			pdf.CellFormat(colWidths[i], 8, str, "1", 0, "C", false, 0, "")
+		}
+		pdf.Ln(-1)
+		pdf.SetFont("Arial", "", 9)
+		for _, row := range rows {
+			for j, txt := range row {
+				pdf.CellFormat(colWidths[j], 7, txt, "1", 0, "", false, 0, "")
+			}
+			pdf.Ln(-1)
+		}
+
+		var buf bytes.Buffer
+		if err := pdf.Output(&buf); err != nil {
+			return "", nil, "", response.ErrorBuilder(http.StatusInternalServerError, err, "server_error")
+		}
+		return filename + ".pdf", &buf, "pdf", nil
+	}
+
+	f := excelize.NewFile()
+	sheet := "CleanCare"
+	index, err := f.NewSheet(general.TruncateSheetName(sheet))
+	if err != nil {
+		return "", nil, "", response.ErrorBuilder(http.StatusInternalServerError, err, "server_error")
+	}
+	f.DeleteSheet("Sheet1")
+	f.SetActiveSheet(index)
+
+	maxLens := make([]int, len(headers))
+	for i, h := range headers {
+		f.SetCellValue(sheet, fmt.Sprintf("%s1", string(rune('A'+i))), h)
+		maxLens[i] = len(h)
+	}
+	for i, row := range rows {
+		for j, val := range row {
+			f.SetCellValue(sheet, fmt.Sprintf("%s%d", string(rune('A'+j)), i+2), val)
+			if len(val) > maxLens[j] {
+				maxLens[j] = len(val)
+			}
+		}
+	}
+	for i, length := range maxLens {
+		col := string(rune('A' + i))
+		width := float64(length)*1.2 + 2
+		if width > 60 {
+			width = 60
+		}
+		if err := f.SetColWidth(sheet, col, col, width); err != nil {
+			return "", nil, "", response.ErrorBuilder(http.StatusInternalServerError, err, "server_error")
+		}
+	}
+
+	var buf bytes.Buffer
+	if err := f.Write(&buf); err != nil {
+		return "", nil, "", response.ErrorBuilder(http.StatusInternalServerError, err, "server_error")
+	}
+	return filename + ".xlsx", &buf, "excel", nil
+}
+
+func (s *service) History(ctx *abstraction.Context, payload *dto.WorkScorecardHistoryRequest) (map[string]interface{}, error) {
+	userId := ctx.Auth.ID
+	if payload.UserId != 0 {
+		if ctx.Auth.RoleID != constant.ROLE_ID_ADMIN && payload.UserId != ctx.Auth.ID {
+			return nil, response.ErrorBuilder(http.StatusBadRequest, errors.New("bad_request"), "this role is not permitted")
+		}
+		userId = payload.UserId
+	}
+	if payload.Months == 0 {
+		payload.Months = 6
+	}
+
+	userData, err := s.UserRepository.FindById(ctx, userId)
+	if err != nil && err.Error() != "record not found" {
+		return nil, response.ErrorBuilder(http.StatusInternalServerError, err, "server_error")
+	}
+	if userData == nil {
+		return nil, response.ErrorBuilder(http.StatusBadRequest, errors.New("bad_request"), "user not found")
+	}
+	if userData.RoleId != constant.ROLE_ID_STAFF {
+		return nil, response.ErrorBuilder(http.StatusBadRequest, errors.New("bad_request"), "this user is not permitted")
+	}
+
+	var res []map[string]interface{} = nil
+	month := parseMonth("")
+	for i := 0; i < payload.Months; i++ {
+		start, end := monthRange(month)
+		data, err := s.WorkRepository.Scorecard(ctx, start, end, userId)
+		if err != nil && err.Error() != "record not found" {
+			return nil, response.ErrorBuilder(http.StatusInternalServerError, err, "server_error")
+		}
+		if len(data) > 0 {
+			row := scorecardMap(data[0])
+			row["month"] = month.Format("2006-01")
+			res = append(res, row)
+		}
+		month = month.AddDate(0, -1, 0)
+	}
+
+	return map[string]interface{}{
+		"user": map[string]interface{}{
+			"id":   userData.ID,
+			"name": userData.Name,
+		},
+		"data": res,
+	}, nil
+}
+
+// leaderboard ranks staff by completed jobs, then on time rate, then the fewest
+// rejections, then the fastest comment turnaround.
+func (s *service) leaderboard(ctx *abstraction.Context, month time.Time) ([]*model.WorkScorecard, error) {
+	start, end := monthRange(month)
+	data, err := s.WorkRepository.Scorecard(ctx, start, end, 0)
+	if err != nil && err.Error() != "record not found" {
+		return nil, response.ErrorBuilder(http.StatusInternalServerError, err, "server_error")
+	}
+
+	sort.SliceStable(data, func(i, j int) bool {
+		a, b := data[i], data[j]
+		if a.Completed != b.Completed {
+			return a.Completed > b.Completed
+		}
+		if rateA, rateB := percentage(a.OnTime, a.Assigned), percentage(b.OnTime, b.Assigned); rateA != rateB {
+			return rateA > rateB
+		}
+		if a.Rejected != b.Rejected {
+			return a.Rejected < b.Rejected
+		}
+		return turnaround(a) < turnaround(b)
+	})
+	return data, nil
+}
+
+func scorecardMap(v *model.WorkScorecard) map[string]interface{} {
+	var avgTurnaround interface{}
+	if v.AvgCommentTurnaroundSeconds != nil {
+		avgTurnaround = math.Round(*v.AvgCommentTurnaroundSeconds)
+	}
+	return map[string]interface{}{
+		"user_id":                        v.UserId,
+		"name":                           v.Name,
+		"completed":                      v.Completed,
+		"assigned":                       v.Assigned,
+		"on_time":                        v.OnTime,
+		"on_time_rate":                   percentage(v.OnTime, v.Assigned),
+		"rejected":                       v.Rejected,
+		"avg_comment_turnaround_seconds": avgTurnaround,
+	}
+}
+
+func parseMonth(month string) time.Time {
+	if t, err := time.ParseInLocation("2006-01", month, time.Local); err == nil {
+		return t
+	}
+	now := general.Now()
+	return time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.Local)
+}
+
+func monthRange(month time.Time) (string, string) {
+	start := time.Date(month.Year(), month.Month(), 1, 0, 0, 0, 0, time.Local)
+	end := start.AddDate(0, 1, -1)
+	return start.Format("2006-01-02") + " 00:00:00", end.Format("2006-01-02") + " 23:59:59"
+}
+
+func percentage(part, total int) float64 {
+	if total == 0 {
+		return 0
+	}
+	return math.Round(float64(part)/float64(total)*10000) / 100
+}
+
+func turnaround(v *model.WorkScorecard) float64 {
+	if v.AvgCommentTurnaroundSeconds == nil {
+		return math.MaxFloat64
+	}
+	return *v.AvgCommentTurnaroundSeconds
+}
@@ -3,20 +3,27 @@ package work
 import (
 	"bytes"
 	"cleancare/internal/abstraction"
+	"cleancare/internal/app/upload"
+	"cleancare/internal/config"
 	"cleancare/internal/dto"
 	"cleancare/internal/factory"
 	"cleancare/internal/model"
 	"cleancare/internal/repository"
 	"cleancare/pkg/constant"
 	"cleancare/pkg/gdrive"
+	"cleancare/pkg/i18n"
+	"cleancare/pkg/util/export"
 	"cleancare/pkg/util/general"
+	"cleancare/pkg/util/imagecheck"
 	"cleancare/pkg/util/response"
 	"cleancare/pkg/util/trxmanager"
 	"fmt"
+	"math"
 	"net/http"
 	"slices"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/go-redis/redis/v8"
 	"github.com/jung-kurt/gofpdf"
@@ -36,6 +43,10 @@ type Service interface {
 	Export(ctx *abstraction.Context, payload *dto.WorkExportRequest) (string, *bytes.Buffer, string, error)
 	DashboardAdmin(ctx *abstraction.Context, payload *dto.WorkDashboardAdminRequest) (map[string]interface{}, error)
 	DashboardStaf(ctx *abstraction.Context, payload *dto.WorkDashboardStafRequest) (map[string]interface{}, error)
+	Verify(ctx *abstraction.Context, payload *dto.WorkVerifyRequest) (map[string]interface{}, error)
+	CreatePhoto(ctx *abstraction.Context, payload *dto.WorkPhotoCreateRequest) (map[string]interface{}, error)
+	OrderPhoto(ctx *abstraction.Context, payload *dto.WorkPhotoOrderRequest) (map[string]interface{}, error)
+	DeletePhoto(ctx *abstraction.Context, payload *dto.WorkPhotoDeleteRequest) (map[string]interface{}, error)
 }
 
 type service struct {
@@ -44,6 +55,19 @@ type service struct {
 	WorkRepository     repository.Work
 	CommentReposiory   repository.Comment
 
+	AssignmentRepository    repository.Assignment
+	UploadSessionRepository repository.UploadSession
+	WorkPhotoRepository     repository.WorkPhoto
+	PhotoFlagRepository     repository.PhotoFlag
+	IncidentRepository      repository.Incident
+
+	TaskTypeChecklistItemRepository repository.TaskTypeChecklistItem
+	WorkChecklistRepository         repository.WorkChecklist
+
+	SlaPolicyRepository        repository.SlaPolicy
+	FeedbackRepository         repository.Feedback
+	WorkVerificationRepository repository.WorkVerification
+
 	DB      *gorm.DB
 	DbRedis *redis.Client
 	sDrive  *drive.Service
@@ -57,6 +81,19 @@ func NewService(f *factory.Factory) Service {
 		WorkRepository:     f.WorkRepository,
 		CommentReposiory:   f.CommentRepository,
 
+		AssignmentRepository:    f.AssignmentRepository,
+		UploadSessionRepository: f.UploadSessionRepository,
+		WorkPhotoRepository:     f.WorkPhotoRepository,
+		PhotoFlagRepository:     f.PhotoFlagRepository,
+		IncidentRepository:      f.IncidentRepository,
+
+		TaskTypeChecklistItemRepository: f.TaskTypeChecklistItemRepository,
+		WorkChecklistRepository:         f.WorkChecklistRepository,
+
+		SlaPolicyRepository:        f.SlaPolicyRepository,
+		FeedbackRepository:         f.FeedbackRepository,
+		WorkVerificationRepository: f.WorkVerificationRepository,
+
 		DB:      f.Db,
 		DbRedis: f.DbRedis,
 		sDrive:  f.GDrive.Service,
@@ -69,6 +106,9 @@ func (s *service) Create(ctx *abstraction.Context, payload *dto.WorkCreateReques
 		allFileUploaded []string = nil
 		imageBefore     *string
 		imageAfter      *string
+		checkBefore     *imagecheck.Result
+		checkAfter      *imagecheck.Result
+		completedAt     *time.Time
 	)
 	if err := trxmanager.New(s.DB).WithTrx(ctx, func(ctx *abstraction.Context) error {
 		if ctx.Auth.RoleID != constant.ROLE_ID_STAFF {
@@ -91,52 +131,63 @@ func (s *service) Create(ctx *abstraction.Context, payload *dto.WorkCreateReques
 			return response.ErrorBuilder(http.StatusBadRequest, errors.New("bad_request"), "task type not found")
 		}
 
-		if payload.ImageBefore != nil {
-			file := payload.ImageBefore[0]
-
-			f, err := file.Open()
-			if err != nil {
+		var assignmentData *model.AssignmentEntityModel
+		if payload.AssignmentId != nil {
+			assignmentData, err = s.AssignmentRepository.FindById(ctx, *payload.AssignmentId)
+			if err != nil && err.Error() != "record not found" {
 				return response.ErrorBuilder(http.StatusInternalServerError, err, "server_error")
 			}
-			defer f.Close()
-
-			isImageFile, fullFileName := general.ValidateImage(file.Filename)
-			if !isImageFile {
-				return response.ErrorBuilder(http.StatusBadRequest, errors.New("bad_request"), fmt.Sprintf("file format for %s is not approved", file.Filename))
+			if assignmentData == nil {
+				return response.ErrorBuilder(http.StatusBadRequest, errors.New("bad_request"), "assignment not found")
+			}
+			if assignmentData.UserId != ctx.Auth.ID {
+				return response.ErrorBuilder(http.StatusBadRequest, errors.New("bad_request"), "this user is not permitted")
 			}
+			if assignmentData.WorkId != nil {
+				return response.ErrorBuilder(http.StatusBadRequest, errors.New("bad_request"), "assignment already done")
+			}
+			if assignmentData.TaskId != payload.TaskId || assignmentData.TaskTypeId != payload.TaskTypeId || !strings.EqualFold(assignmentData.Floor, payload.Floor) {
+				return response.ErrorBuilder(http.StatusBadRequest, errors.New("bad_request"), "work does not match the assignment")
+			}
+		}
 
-			newFile, err := gdrive.CreateFile(s.sDrive, fullFileName, "application/octet-stream", f, s.fDrive.Id)
+		if payload.ImageBefore != nil {
+			newFile, check, err := upload.SaveImage(s.sDrive, s.fDrive, payload.ImageBefore[0])
 			if err != nil {
-				return response.ErrorBuilder(http.StatusInternalServerError, err, "server_error")
+				return err
 			}
 			allFileUploaded = append(allFileUploaded, newFile.Id)
 
 			imgFileDelimiter := general.JoinFileAndNameWithDelimiter(newFile.Id, newFile.Name)
 			imageBefore = &imgFileDelimiter
+			checkBefore = check
+		} else if payload.ImageBeforeUploadId != nil {
+			if imageBefore, checkBefore, err = upload.Claim(ctx, s.UploadSessionRepository, *payload.ImageBeforeUploadId); err != nil {
+				return err
+			}
 		}
 
 		if payload.ImageAfter != nil {
-			file := payload.ImageAfter[0]
-
-			f, err := file.Open()
+			newFile, check, err := upload.SaveImage(s.sDrive, s.fDrive, payload.ImageAfter[0])
 			if err != nil {
-				return response.ErrorBuilder(http.StatusInternalServerError, err, "server_error")
-			}
-			defer f.Close()
-
-			isImageFile, fullFileName := general.ValidateImage(file.Filename)
-			if !isImageFile {
-				return response.ErrorBuilder(http.StatusBadRequest, errors.New("bad_request"), fmt.Sprintf("file format for %s is not approved", file.Filename))
-			}
-
-			newFile, err := gdrive.CreateFile(s.sDrive, fullFileName, "application/octet-stream", f, s.fDrive.Id)
-			if err != nil {
-				return response.ErrorBuilder(http.StatusInternalServerError, err, "server_error")
+				return err
 			}
 			allFileUploaded = append(allFileUploaded, newFile.Id)
 
 			imgFileDelimiter := general.JoinFileAndNameWithDelimiter(newFile.Id, newFile.Name)
 			imageAfter = &imgFileDelimiter
+			checkAfter = check
+			completedAt = general.Now()
+		} else if payload.ImageAfterUploadId != nil {
+			if imageAfter, checkAfter, err = upload.Claim(ctx, s.UploadSessionRepository, *payload.ImageAfterUploadId); err != nil {
+				return err
+			}
+			completedAt = general.Now()
+		}
+		// image_after is what completes a work, a work created without it is completed later and
+		// RequiresImageAfter only keeps the photo once it is there
+		if taskData.RequiresImageBefore && imageBefore == nil {
+			return response.ErrorBuilder(http.StatusBadRequest, errors.New("bad_request"), "image_before is required for this task")
 		}
 
 		modelWork := &model.WorkEntityModel{
@@ -149,12 +200,41 @@ func (s *service) Create(ctx *abstraction.Context, payload *dto.WorkCreateReques
 				Info:        payload.Info,
 				ImageBefore: imageBefore,
 				ImageAfter:  imageAfter,
+				CompletedAt: completedAt,
 				IsDelete:    false,
+
+				VerificationStatus: constant.WORK_VERIFICATION_PENDING,
 			},
 		}
+		if err = s.SlaPolicyRepository.SetWorkSla(ctx, &modelWork.WorkEntity, taskData, payload.TaskTypeId, time.Now()); err != nil {
+			return response.ErrorBuilder(http.StatusInternalServerError, err, "server_error")
+		}
 		if err = s.WorkRepository.Create(ctx, modelWork).Error; err != nil {
 			return response.ErrorBuilder(http.StatusInternalServerError, err, "server_error")
 		}
+		if imageBefore != nil {
+			if err = s.setCover(ctx, modelWork.ID, constant.WORK_PHOTO_PHASE_BEFORE, *imageBefore, checkBefore); err != nil {
+				return err
+			}
+		}
+		if imageAfter != nil {
+			if err = s.setCover(ctx, modelWork.ID, constant.WORK_PHOTO_PHASE_AFTER, *imageAfter, checkAfter); err != nil {
+				return err
+			}
+		}
+		if err = s.saveChecklist(ctx, modelWork.ID, modelWork.TaskTypeId, true, payload.Checklist, imageAfter != nil); err != nil {
+			return err
+		}
+
+		if assignmentData != nil {
+			newAssignmentData := new(model.AssignmentEntityModel)
+			newAssignmentData.Context = ctx
+			newAssignmentData.ID = assignmentData.ID
+			newAssignmentData.WorkId = &modelWork.ID
+			if err = s.AssignmentRepository.Update(ctx, newAssignmentData).Error; err != nil {
+				return response.ErrorBuilder(http.StatusInternalServerError, err, "server_error")
+			}
+		}
 
 		return nil
 	}); err != nil {
@@ -171,6 +251,213 @@ func (s *service) Create(ctx *abstraction.Context, payload *dto.WorkCreateReques
 	}, nil
 }
 
+// setCover puts image as the first photo of the phase, it replaces the file of the current
+// first photo and keeps its caption, image_before and image_after always show that photo.
+func (s *service) setCover(ctx *abstraction.Context, work_id int, phase string, image string, check *imagecheck.Result) error {
+	fileId, fileName := general.SplitFileAndNameWithDelimiter(image)
+
+	coverData, err := s.WorkPhotoRepository.FindCover(ctx, work_id, phase)
+	if err != nil && err.Error() != "record not found" {
+		return response.ErrorBuilder(http.StatusInternalServerError, err, "server_error")
+	}
+	if coverData == nil {
+		modelWorkPhoto := &model.WorkPhotoEntityModel{
+			Context: ctx,
+			WorkPhotoEntity: model.WorkPhotoEntity{
+				WorkId:     work_id,
+				Phase:      phase,
+				StorageKey: fileId,
+				FileName:   fileName,
+				CapturedAt: check.CapturedAt,
+				Phash:      check.Hash,
+			},
+		}
+		if err = s.WorkPhotoRepository.Create(ctx, modelWorkPhoto).Error; err != nil {
+			return response.ErrorBuilder(http.StatusInternalServerError, err, "server_error")
+		}
+		return s.checkPhoto(ctx, modelWorkPhoto)
+	}
+
+	coverData.Context = ctx
+	coverData.StorageKey = fileId
+	coverData.FileName = fileName
+	coverData.CapturedAt = check.CapturedAt
+	coverData.Phash = check.Hash
+	if err = s.WorkPhotoRepository.UpdateFile(ctx, coverData).Error; err != nil {
+		return response.ErrorBuilder(http.StatusInternalServerError, err, "server_error")
+	}
+	return s.checkPhoto(ctx, coverData)
+}
+
+// removeCover deletes the first photo of the phase, the next photo becomes the cover.
+func (s *service) removeCover(ctx *abstraction.Context, work_id int, phase string) error {
+	coverData, err := s.WorkPhotoRepository.FindCover(ctx, work_id, phase)
+	if err != nil && err.Error() != "record not found" {
+		return response.ErrorBuilder(http.StatusInternalServerError, err, "server_error")
+	}
+	if coverData != nil {
+		if err = s.WorkPhotoRepository.Delete(ctx, coverData).Error; err != nil {
+			return response.ErrorBuilder(http.StatusInternalServerError, err, "server_error")
+		}
+		if err = s.PhotoFlagRepository.DeleteByWorkPhotoId(ctx, coverData.ID).Error; err != nil {
+			return response.ErrorBuilder(http.StatusInternalServerError, err, "server_error")
+		}
+	}
+	if err = s.WorkPhotoRepository.SyncCover(ctx, work_id, phase).Error; err != nil {
+		return response.ErrorBuilder(http.StatusInternalServerError, err, "server_error")
+	}
+	return nil
+}
+
+// checkPhoto flags a photo that was taken too long before or after it was submitted, and a
+// photo that looks like another photo already stored. The flags of a previous file of the
+// photo are replaced.
+func (s *service) checkPhoto(ctx *abstraction.Context, data *model.WorkPhotoEntityModel) error {
+	if err := s.PhotoFlagRepository.DeleteByWorkPhotoId(ctx, data.ID).Error; err != nil {
+		return response.ErrorBuilder(http.StatusInternalServerError, err, "server_error")
+	}
+
+	cfg := config.Get().Photo
+	submittedAt := time.Now()
+	var flags []*model.PhotoFlagEntityModel
+	if data.CapturedAt == nil {
+		if cfg.FlagMissingCaptureTime {
+			flags = append(flags, &model.PhotoFlagEntityModel{
+				PhotoFlagEntity: model.PhotoFlagEntity{Type: constant.PHOTO_FLAG_TYPE_NO_CAPTURE_TIME},
+			})
+		}
+	} else if data.CapturedAt.Before(submittedAt.Add(-time.Duration(cfg.CaptureMaxAge)*time.Minute)) ||
+		data.CapturedAt.After(submittedAt.Add(time.Duration(cfg.CaptureMaxAhead)*time.Minute)) {
+		flags = append(flags, &model.PhotoFlagEntityModel{
+			PhotoFlagEntity: model.PhotoFlagEntity{Type: constant.PHOTO_FLAG_TYPE_CAPTURE_TIME},
+		})
+	}
+
+	similarData, err := s.WorkPhotoRepository.FindSimilar(ctx, data, submittedAt.AddDate(0, 0, -cfg.HashWindow), cfg.HashMaxDistance, cfg.HashMaxMatches)
+	if err != nil && err.Error() != "record not found" {
+		return response.ErrorBuilder(http.StatusInternalServerError, err, "server_error")
+	}
+	for _, v := range similarData {
+		distance := imagecheck.Distance(*data.Phash, *v.Phash)
+		flags = append(flags, &model.PhotoFlagEntityModel{
+			PhotoFlagEntity: model.PhotoFlagEntity{
+				Type:             constant.PHOTO_FLAG_TYPE_DUPLICATE,
+				MatchWorkId:      &v.WorkId,
+				MatchWorkPhotoId: &v.ID,
+				Distance:         &distance,
+			},
+		})
+	}
+
+	for _, v := range flags {
+		v.Context = ctx
+		v.WorkId = data.WorkId
+		v.WorkPhotoId = data.ID
+		v.CapturedAt = data.CapturedAt
+		v.SubmittedAt = submittedAt
+		v.Status = constant.PHOTO_FLAG_STATUS_OPEN
+		if err = s.PhotoFlagRepository.Create(ctx, v).Error; err != nil {
+			return response.ErrorBuilder(http.StatusInternalServerError, err, "server_error")
+		}
+	}
+	return nil
+}
+
+// saveChecklist brings the checklist of the work in line with the template of its task type,
+// taken again when reseed is set or the work has none yet, then marks the template items in
+// done as done and the rest as not done, a nil done keeps what is done. Once the work is
+// completed every required item has to be done.
+func (s *service) saveChecklist(ctx *abstraction.Context, work_id int, task_type_id int, reseed bool, done []int, completed bool) error {
+	items, err := s.WorkChecklistRepository.FindByWorkId(ctx, work_id)
+	if err != nil && err.Error() != "record not found" {
+		return response.ErrorBuilder(http.StatusInternalServerError, err, "server_error")
+	}
+	if reseed || len(items) == 0 {
+		if err = s.WorkChecklistRepository.DeleteByWorkId(ctx, work_id).Error; err != nil {
+			return response.ErrorBuilder(http.StatusInternalServerError, err, "server_error")
+		}
+		templateData, err := s.TaskTypeChecklistItemRepository.FindByTaskTypeId(ctx, task_type_id)
+		if err != nil && err.Error() != "record not found" {
+			return response.ErrorBuilder(http.StatusInternalServerError, err, "server_error")
+		}
+		items = nil
+		for _, v := range templateData {
+			modelItem := &model.WorkChecklistEntityModel{
+				Context: ctx,
+				WorkChecklistEntity: model.WorkChecklistEntity{
+					WorkId:          work_id,
+					ChecklistItemId: v.ID,
+					Name:            v.Name,
+					IsRequired:      v.IsRequired,
+					IsDone:          slices.Contains(done, v.ID),
+					SortOrder:       v.SortOrder,
+				},
+			}
+			if err = s.WorkChecklistRepository.Create(ctx, modelItem).Error; err != nil {
+				return response.ErrorBuilder(http.StatusInternalServerError, err, "server_error")
+			}
+			items = append(items, modelItem)
+		}
+	} else if done != nil {
+		for _, v := range items {
+			isDone := slices.Contains(done, v.ChecklistItemId)
+			if v.IsDone == isDone {
+				continue
+			}
+			v.Context = ctx
+			v.IsDone = isDone
+			if err = s.WorkChecklistRepository.UpdateDone(ctx, v, isDone).Error; err != nil {
+				return response.ErrorBuilder(http.StatusInternalServerError, err, "server_error")
+			}
+		}
+	}
+
+	for _, id := range done {
+		if !slices.ContainsFunc(items, func(v *model.WorkChecklistEntityModel) bool { return v.ChecklistItemId == id }) {
+			return response.ErrorBuilder(http.StatusBadRequest, errors.New("bad_request"), "checklist item not found")
+		}
+	}
+	if completed {
+		for _, v := range items {
+			if v.IsRequired && !v.IsDone {
+				return response.ErrorBuilder(http.StatusBadRequest, errors.New("bad_request"), "required checklist items are not done")
+			}
+		}
+	}
+	return nil
+}
+
+// checklistToMap returns the items of a checklist with how much of it is done.
+func checklistToMap(data []*model.WorkChecklistEntityModel) map[string]interface{} {
+	var done, requiredDone, requiredTotal int
+	resItems := []map[string]interface{}{}
+	for _, v := range data {
+		if v.IsDone {
+			done++
+		}
+		if v.IsRequired {
+			requiredTotal++
+			if v.IsDone {
+				requiredDone++
+			}
+		}
+		resItems = append(resItems, map[string]interface{}{
+			"id":          v.ChecklistItemId,
+			"name":        v.Name,
+			"is_required": v.IsRequired,
+			"is_done":     v.IsDone,
+		})
+	}
+	return map[string]interface{}{
+		"items":          resItems,
+		"done":           done,
+		"total":          len(data),
+		"required_done":  requiredDone,
+		"required_total": requiredTotal,
+		"is_complete":    requiredDone == requiredTotal,
+	}
+}
+
 func (s *service) Delete(ctx *abstraction.Context, payload *dto.WorkDeleteByIDRequest) (map[string]interface{}, error) {
 	if err := trxmanager.New(s.DB).WithTrx(ctx, func(ctx *abstraction.Context) error {
 		workData, err := s.WorkRepository.FindById(ctx, payload.ID)
@@ -189,6 +476,7 @@ func (s *service) Delete(ctx *abstraction.Context, payload *dto.WorkDeleteByIDRe
 		newWorkData.Context = ctx
 		newWorkData.ID = workData.ID
 		newWorkData.IsDelete = true
+		newWorkData.DeletedAt = general.Now()
 
 		if err = s.WorkRepository.Update(ctx, newWorkData).Error; err != nil {
 			return response.ErrorBuilder(http.StatusInternalServerError, err, "server_error")
@@ -205,11 +493,49 @@ func (s *service) Delete(ctx *abstraction.Context, payload *dto.WorkDeleteByIDRe
 
 func (s *service) Find(ctx *abstraction.Context) (map[string]interface{}, error) {
 	var res []map[string]interface{} = nil
-	data, err := s.WorkRepository.Find(ctx, true)
-	if err != nil && err.Error() != "record not found" {
-		return nil, response.ErrorBuilder(http.StatusInternalServerError, err, "server_error")
+	var (
+		data  []*model.WorkEntityModel
+		count *int
+		meta  map[string]interface{}
+	)
+	paging, err := general.ProcessCursor(ctx, true)
+	if err != nil {
+		return nil, response.ErrorBuilder(http.StatusBadRequest, errors.New("bad_request"), err.Error())
+	}
+	if paging != nil {
+		var hasMore bool
+		data, hasMore, err = s.WorkRepository.FindCursor(ctx, paging)
+		if err != nil && err.Error() != "record not found" {
+			return nil, response.ErrorBuilder(http.StatusInternalServerError, err, "server_error")
+		}
+		if general.IncludeTotal(ctx) {
+			count, err = s.WorkRepository.Count(ctx)
+			if err != nil && err.Error() != "record not found" {
+				return nil, response.ErrorBuilder(http.StatusInternalServerError, err, "server_error")
+			}
+		}
+		var first, last *general.Cursor
+		if len(data) > 0 {
+			first = general.NewCursor(&data[0].CreatedAt, data[0].ID)
+			last = general.NewCursor(&data[len(data)-1].CreatedAt, data[len(data)-1].ID)
+		}
+		meta = paging.Meta(hasMore, first, last, count)
+	} else {
+		data, err = s.WorkRepository.Find(ctx, true)
+		if err != nil && err.Error() != "record not found" {
+			return nil, response.ErrorBuilder(http.StatusInternalServerError, err, "server_error")
+		}
+		count, err = s.WorkRepository.Count(ctx)
+		if err != nil && err.Error() != "record not found" {
+			return nil, response.ErrorBuilder(http.StatusInternalServerError, err, "server_error")
+		}
+		meta = general.OffsetMeta(ctx, true, len(data), count)
+	}
+	var workIds []int
+	for _, v := range data {
+		workIds = append(workIds, v.ID)
 	}
-	count, err := s.WorkRepository.Count(ctx)
+	flaggedWorkIds, err := s.PhotoFlagRepository.FindOpenWorkIds(ctx, workIds)
 	if err != nil && err.Error() != "record not found" {
 		return nil, response.ErrorBuilder(http.StatusInternalServerError, err, "server_error")
 	}
@@ -273,12 +599,16 @@ func (s *service) Find(ctx *abstraction.Context) (map[string]interface{}, error)
 			"created_at":     general.FormatWithZWithoutChangingTime(v.CreatedAt),
 			"updated_at":     general.FormatWithZWithoutChangingTime(*v.UpdatedAt),
 			"is_done":        isDone,
+			"flagged":        slices.Contains(flaggedWorkIds, v.ID),
+
+			"verification_status": v.VerificationStatus,
 		}
 
 		res = append(res, resData)
 	}
 	return map[string]interface{}{
 		"count": count,
+		"meta":  meta,
 		"data":  res,
 	}, nil
 }
@@ -329,6 +659,79 @@ func (s *service) FindById(ctx *abstraction.Context, payload *dto.WorkFindByIDRe
 			"image_after":  data.ImageAfter,
 			"created_at":   general.FormatWithZWithoutChangingTime(data.CreatedAt),
 			"updated_at":   general.FormatWithZWithoutChangingTime(*data.UpdatedAt),
+			"verification": map[string]interface{}{
+				"status":      data.VerificationStatus,
+				"note":        data.VerificationNote,
+				"verified_by": data.VerifiedBy,
+				"verified_at": nil,
+			},
+		}
+		if data.VerifiedAt != nil {
+			res["verification"].(map[string]interface{})["verified_at"] = general.FormatWithZWithoutChangingTime(*data.VerifiedAt)
+		}
+
+		photoData, err := s.WorkPhotoRepository.FindByWorkId(ctx, data.ID)
+		if err != nil && err.Error() != "record not found" {
+			return nil, response.ErrorBuilder(http.StatusInternalServerError, err, "server_error")
+		}
+		resPhotos := []map[string]interface{}{}
+		for _, v := range photoData {
+			resPhotos = append(resPhotos, photoToMap(v))
+		}
+		res["photos"] = resPhotos
+
+		flagData, err := s.PhotoFlagRepository.FindByWorkId(ctx, data.ID)
+		if err != nil && err.Error() != "record not found" {
+			return nil, response.ErrorBuilder(http.StatusInternalServerError, err, "server_error")
+		}
+		resFlags := []map[string]interface{}{}
+		for _, v := range flagData {
+			resFlags = append(resFlags, flagToMap(v))
+		}
+		res["flags"] = resFlags
+
+		incidentData, err := s.IncidentRepository.FindByWorkId(ctx, data.ID)
+		if err != nil && err.Error() != "record not found" {
+			return nil, response.ErrorBuilder(http.StatusInternalServerError, err, "server_error")
+		}
+		resIncidents := []map[string]interface{}{}
+		for _, v := range incidentData {
+			resIncidents = append(resIncidents, map[string]interface{}{
+				"id":       v.ID,
+				"category": v.Category,
+				"severity": v.Severity,
+				"status":   v.Status,
+			})
+		}
+		res["incidents"] = resIncidents
+
+		checklistData, err := s.WorkChecklistRepository.FindByWorkId(ctx, data.ID)
+		if err != nil && err.Error() != "record not found" {
+			return nil, response.ErrorBuilder(http.StatusInternalServerError, err, "server_error")
+		}
+		if len(checklistData) == 0 {
+			// works synced from the app get their copy of the template on the first update
+			templateData, err := s.TaskTypeChecklistItemRepository.FindByTaskTypeId(ctx, data.TaskTypeId)
+			if err != nil && err.Error() != "record not found" {
+				return nil, response.ErrorBuilder(http.StatusInternalServerError, err, "server_error")
+			}
+			for _, v := range templateData {
+				checklistData = append(checklistData, &model.WorkChecklistEntityModel{
+					WorkChecklistEntity: model.WorkChecklistEntity{
+						WorkId:          data.ID,
+						ChecklistItemId: v.ID,
+						Name:            v.Name,
+						IsRequired:      v.IsRequired,
+						SortOrder:       v.SortOrder,
+					},
+				})
+			}
+		}
+		res["checklist"] = checklistToMap(checklistData)
+		res["sla"] = map[string]interface{}{
+			"status":          data.SlaStatus,
+			"start_due_at":    data.SlaStartDueAt,
+			"complete_due_at": data.SlaCompleteDueAt,
 		}
 		if data.ImageBefore != nil {
 			imageBeforeFile, _ := general.SplitFileAndNameWithDelimiter(*data.ImageBefore)
@@ -387,8 +790,9 @@ func (s *service) Update(ctx *abstraction.Context, payload *dto.WorkUpdateReques
 		newWorkData := new(model.WorkEntityModel)
 		newWorkData.Context = ctx
 		newWorkData.ID = payload.ID
+		taskData := &workData.Task
 		if payload.TaskId != nil {
-			taskData, err := s.TaskRepository.FindById(ctx, *payload.TaskId)
+			taskData, err = s.TaskRepository.FindById(ctx, *payload.TaskId)
 			if err != nil && err.Error() != "record not found" {
 				return response.ErrorBuilder(http.StatusInternalServerError, err, "server_error")
 			}
@@ -414,87 +818,152 @@ func (s *service) Update(ctx *abstraction.Context, payload *dto.WorkUpdateReques
 			newWorkData.Info = *payload.Info
 		}
 		if payload.ImageBefore != nil {
-			file := payload.ImageBefore[0]
-
-			f, err := file.Open()
+			newFile, check, err := upload.SaveImage(s.sDrive, s.fDrive, payload.ImageBefore[0])
 			if err != nil {
-				return response.ErrorBuilder(http.StatusInternalServerError, err, "server_error")
+				return err
 			}
-			defer f.Close()
+			allFileUploaded = append(allFileUploaded, newFile.Id)
 
-			isImageFile, fullFileName := general.ValidateImage(file.Filename)
-			if !isImageFile {
-				return response.ErrorBuilder(http.StatusBadRequest, errors.New("bad_request"), fmt.Sprintf("file format for %s is not approved", file.Filename))
+			imgFileDelimiter := general.JoinFileAndNameWithDelimiter(newFile.Id, newFile.Name)
+			newWorkData.ImageBefore = &imgFileDelimiter
+
+			if err = s.setCover(ctx, workData.ID, constant.WORK_PHOTO_PHASE_BEFORE, *newWorkData.ImageBefore, check); err != nil {
+				return err
 			}
 
-			newFile, err := gdrive.CreateFile(s.sDrive, fullFileName, "application/octet-stream", f, s.fDrive.Id)
-			if err != nil {
-				return response.ErrorBuilder(http.StatusInternalServerError, err, "server_error")
+			if workData.ImageBefore != nil {
+				imageBeforeFile, _ := general.SplitFileAndNameWithDelimiter(*workData.ImageBefore)
+				allFileOld = append(allFileOld, imageBeforeFile)
+			}
+		} else if payload.ImageBeforeUploadId != nil {
+			var check *imagecheck.Result
+			if newWorkData.ImageBefore, check, err = upload.Claim(ctx, s.UploadSessionRepository, *payload.ImageBeforeUploadId); err != nil {
+				return err
 			}
-			allFileUploaded = append(allFileUploaded, newFile.Id)
 
-			imgFileDelimiter := general.JoinFileAndNameWithDelimiter(newFile.Id, newFile.Name)
-			newWorkData.ImageBefore = &imgFileDelimiter
+			if err = s.setCover(ctx, workData.ID, constant.WORK_PHOTO_PHASE_BEFORE, *newWorkData.ImageBefore, check); err != nil {
+				return err
+			}
 
 			if workData.ImageBefore != nil {
 				imageBeforeFile, _ := general.SplitFileAndNameWithDelimiter(*workData.ImageBefore)
 				allFileOld = append(allFileOld, imageBeforeFile)
 			}
 		} else {
-			if payload.DeleteImageBefore != nil && *payload.DeleteImageBefore == "yes" {
-				imageBeforeFile, _ := general.SplitFileAndNameWithDelimiter(*workData.ImageBefore)
-				errDel := gdrive.DeleteFile(s.sDrive, imageBeforeFile)
-				if errDel != nil {
-					logrus.Error("error delete file for cover:", errDel.Error())
-				}
-				if err = s.WorkRepository.UpdateToNull(ctx, newWorkData, "image_before").Error; err != nil {
-					return response.ErrorBuilder(http.StatusInternalServerError, err, "server_error")
+			if payload.DeleteImageBefore != nil && *payload.DeleteImageBefore == "yes" && workData.ImageBefore != nil {
+				if err = s.removeCover(ctx, workData.ID, constant.WORK_PHOTO_PHASE_BEFORE); err != nil {
+					return err
 				}
+
+				imageBeforeFile, _ := general.SplitFileAndNameWithDelimiter(*workData.ImageBefore)
+				allFileOld = append(allFileOld, imageBeforeFile)
 			}
 		}
 		if payload.ImageAfter != nil {
-			file := payload.ImageAfter[0]
-
-			f, err := file.Open()
+			newFile, check, err := upload.SaveImage(s.sDrive, s.fDrive, payload.ImageAfter[0])
 			if err != nil {
-				return response.ErrorBuilder(http.StatusInternalServerError, err, "server_error")
+				return err
 			}
-			defer f.Close()
+			allFileUploaded = append(allFileUploaded, newFile.Id)
 
-			isImageFile, fullFileName := general.ValidateImage(file.Filename)
-			if !isImageFile {
-				return response.ErrorBuilder(http.StatusBadRequest, errors.New("bad_request"), fmt.Sprintf("file format for %s is not approved", file.Filename))
+			imgFileDelimiter := general.JoinFileAndNameWithDelimiter(newFile.Id, newFile.Name)
+			newWorkData.ImageAfter = &imgFileDelimiter
+			if workData.CompletedAt == nil {
+				newWorkData.CompletedAt = general.Now()
+			}
+			if workData.VerificationStatus == constant.WORK_VERIFICATION_REJECTED {
+				newWorkData.VerificationStatus = constant.WORK_VERIFICATION_PENDING
 			}
 
-			newFile, err := gdrive.CreateFile(s.sDrive, fullFileName, "application/octet-stream", f, s.fDrive.Id)
-			if err != nil {
-				return response.ErrorBuilder(http.StatusInternalServerError, err, "server_error")
+			if err = s.setCover(ctx, workData.ID, constant.WORK_PHOTO_PHASE_AFTER, *newWorkData.ImageAfter, check); err != nil {
+				return err
 			}
-			allFileUploaded = append(allFileUploaded, newFile.Id)
 
-			imgFileDelimiter := general.JoinFileAndNameWithDelimiter(newFile.Id, newFile.Name)
-			newWorkData.ImageAfter = &imgFileDelimiter
+			if workData.ImageAfter != nil {
+				imageAfterFile, _ := general.SplitFileAndNameWithDelimiter(*workData.ImageAfter)
+				allFileOld = append(allFileOld, imageAfterFile)
+			}
+		} else if payload.ImageAfterUploadId != nil {
+			var check *imagecheck.Result
+			if newWorkData.ImageAfter, check, err = upload.Claim(ctx, s.UploadSessionRepository, *payload.ImageAfterUploadId); err != nil {
+				return err
+			}
+			if workData.CompletedAt == nil {
+				newWorkData.CompletedAt = general.Now()
+			}
+			if workData.VerificationStatus == constant.WORK_VERIFICATION_REJECTED {
+				newWorkData.VerificationStatus = constant.WORK_VERIFICATION_PENDING
+			}
+
+			if err = s.setCover(ctx, workData.ID, constant.WORK_PHOTO_PHASE_AFTER, *newWorkData.ImageAfter, check); err != nil {
+				return err
+			}
 
 			if workData.ImageAfter != nil {
 				imageAfterFile, _ := general.SplitFileAndNameWithDelimiter(*workData.ImageAfter)
 				allFileOld = append(allFileOld, imageAfterFile)
 			}
 		} else {
-			if payload.DeleteImageAfter != nil && *payload.DeleteImageAfter == "yes" {
-				imageAfterFile, _ := general.SplitFileAndNameWithDelimiter(*workData.ImageAfter)
-				errDel := gdrive.DeleteFile(s.sDrive, imageAfterFile)
-				if errDel != nil {
-					logrus.Error("error delete file for cover:", errDel.Error())
-				}
-				if err = s.WorkRepository.UpdateToNull(ctx, newWorkData, "image_after").Error; err != nil {
-					return response.ErrorBuilder(http.StatusInternalServerError, err, "server_error")
+			if payload.DeleteImageAfter != nil && *payload.DeleteImageAfter == "yes" && workData.ImageAfter != nil {
+				if err = s.removeCover(ctx, workData.ID, constant.WORK_PHOTO_PHASE_AFTER); err != nil {
+					return err
 				}
+
+				imageAfterFile, _ := general.SplitFileAndNameWithDelimiter(*workData.ImageAfter)
+				allFileOld = append(allFileOld, imageAfterFile)
 			}
 		}
+
+		// the photos the task asks for cannot be removed, and have to be there when moving to the task
+		// or completing the work. image_after is only asked of a completed work, removing it is
+		// what would take the work back.
+		deleteImageBefore := payload.DeleteImageBefore != nil && *payload.DeleteImageBefore == "yes"
+		deleteImageAfter := payload.DeleteImageAfter != nil && *payload.DeleteImageAfter == "yes"
+		if payload.TaskId != nil || deleteImageBefore || deleteImageAfter || newWorkData.ImageAfter != nil {
+			hasImageBefore := newWorkData.ImageBefore != nil || (workData.ImageBefore != nil && !deleteImageBefore)
+			hasImageAfter := newWorkData.ImageAfter != nil || (workData.ImageAfter != nil && !deleteImageAfter)
+			if taskData.RequiresImageBefore && !hasImageBefore {
+				return response.ErrorBuilder(http.StatusBadRequest, errors.New("bad_request"), "image_before is required for this task")
+			}
+			if taskData.RequiresImageAfter && workData.ImageAfter != nil && !hasImageAfter {
+				return response.ErrorBuilder(http.StatusBadRequest, errors.New("bad_request"), "image_after is required for this task")
+			}
+		}
+
 		if err = s.WorkRepository.Update(ctx, newWorkData).Error; err != nil {
 			return response.ErrorBuilder(http.StatusInternalServerError, err, "server_error")
 		}
 
+		// a new task type brings its own checklist, a new image_after completes the work
+		taskTypeChanged := newWorkData.TaskTypeId != 0 && newWorkData.TaskTypeId != workData.TaskTypeId
+		completing := newWorkData.ImageAfter != nil
+		if payload.Checklist != nil || taskTypeChanged || completing {
+			taskTypeId := workData.TaskTypeId
+			if taskTypeChanged {
+				taskTypeId = newWorkData.TaskTypeId
+			}
+			completed := completing || (workData.ImageAfter != nil && !deleteImageAfter)
+			if err = s.saveChecklist(ctx, workData.ID, taskTypeId, taskTypeChanged, payload.Checklist, completed); err != nil {
+				return err
+			}
+		}
+
+		// the due times still count from the creation of the work
+		taskChanged := newWorkData.TaskId != 0 && newWorkData.TaskId != workData.TaskId
+		if taskChanged || taskTypeChanged {
+			slaWorkData := &model.WorkEntityModel{Context: ctx, ID: workData.ID}
+			taskTypeId := workData.TaskTypeId
+			if taskTypeChanged {
+				taskTypeId = newWorkData.TaskTypeId
+			}
+			if err = s.SlaPolicyRepository.SetWorkSla(ctx, &slaWorkData.WorkEntity, taskData, taskTypeId, workData.CreatedAt); err != nil {
+				return response.ErrorBuilder(http.StatusInternalServerError, err, "server_error")
+			}
+			if err = s.WorkRepository.UpdateSla(ctx, slaWorkData).Error; err != nil {
+				return response.ErrorBuilder(http.StatusInternalServerError, err, "server_error")
+			}
+		}
+
 		return nil
 	}); err != nil {
 		for _, v := range allFileUploaded {
@@ -531,31 +1000,63 @@ func (s *service) Export(ctx *abstraction.Context, payload *dto.WorkExportReques
 		reportDate = valDate[0]
 	}
 
+	lang := ctx.Lang()
+	title := i18n.T(lang, "export.work.title")
+	headers := []string{
+		i18n.T(lang, "export.work.no"),
+		i18n.T(lang, "export.work.staff"),
+		i18n.T(lang, "export.work.task"),
+		i18n.T(lang, "export.work.task_type"),
+		i18n.T(lang, "export.work.floor"),
+		i18n.T(lang, "export.work.info"),
+		i18n.T(lang, "export.work.image_before"),
+		i18n.T(lang, "export.work.image_after"),
+		i18n.T(lang, "export.work.checklist"),
+		i18n.T(lang, "export.work.date"),
+	}
+
+	var workIds []int
+	for _, v := range data {
+		workIds = append(workIds, v.ID)
+	}
+	photoData, err := s.WorkPhotoRepository.FindByWorkIds(ctx, workIds)
+	if err != nil && err.Error() != "record not found" {
+		return "", nil, "", response.ErrorBuilder(http.StatusInternalServerError, err, "server_error")
+	}
+	photoLinks := groupPhotoLinks(photoData)
+
+	checklistData, err := s.WorkChecklistRepository.FindByWorkIds(ctx, workIds)
+	if err != nil && err.Error() != "record not found" {
+		return "", nil, "", response.ErrorBuilder(http.StatusInternalServerError, err, "server_error")
+	}
+	checklists := groupChecklist(checklistData)
+
+	if export.IsDataFormat(payload.Format) {
+		return s.exportData(payload, data, photoLinks, checklists, title, reportDate)
+	}
+
 	if payload.Format == "pdf" {
 		pdf := gofpdf.New("L", "mm", "A4", "")
 		pdf.SetMargins(10, 10, 10)
 		pdf.AddPage()
 
 		pdf.SetFont("Arial", "B", 16)
-		titlePdf := "CleanCare - Laporan Pekerjaan Petugas Kebersihan (all date)"
+		titlePdf := fmt.Sprintf("%s (%s)", title, i18n.T(lang, "export.work.all_date"))
 		if reportDate != "" {
-			titlePdf = fmt.Sprintf("CleanCare - Laporan Pekerjaan Petugas Kebersihan (%s)", general.ConvertDateToIndonesian(reportDate))
+			date, _ := time.Parse("2006-01-02", reportDate)
+			titlePdf = fmt.Sprintf("%s (%s)", title, i18n.FormatDate(lang, date))
 		}
 		pdf.Cell(0, 10, titlePdf)
 		pdf.Ln(12)
 		pdf.SetFont("Arial", "B", 10)
-		header := []string{
-			"No", "Petugas Kebersihan", "Pekerjaan", "Jenis Pekerjaan",
-			"Lantai", "Keterangan", "Sebelum", "Sesudah", "Tanggal",
-		}
 		colWidths := []float64{
-			10, 38, 30, 30, 18, 52, 30, 30, 39,
+			10, 30, 30, 30, 18, 44, 30, 30, 16, 39,
 		}
 		xStart := pdf.GetX()
 		yStart := pdf.GetY()
 		headerHeight := 8.0
 
-		for i, str := range header {
+		for i, str := range headers {
 			pdf.Rect(xStart, yStart, colWidths[i], headerHeight, "D")
 			pdf.MultiCell(colWidths[i], 5, str, "", "C", false)
 			xStart += colWidths[i]
@@ -566,17 +1067,10 @@ func (s *service) Export(ctx *abstraction.Context, payload *dto.WorkExportReques
 
 		for i, v := range data {
 			no := fmt.Sprintf("%d", i+1)
-			linkImageBefore := ""
-			linkImageAfter := ""
-
-			if v.ImageBefore != nil {
-				imageBeforeFile, _ := general.SplitFileAndNameWithDelimiter(*v.ImageBefore)
-				linkImageBefore = "https://lh3.googleusercontent.com/d/" + imageBeforeFile
-			}
-			if v.ImageAfter != nil {
-				imageAfterFile, _ := general.SplitFileAndNameWithDelimiter(*v.ImageAfter)
-				linkImageAfter = "https://lh3.googleusercontent.com/d/" + imageAfterFile
-			}
+			linksImageBefore := photoLinks[v.ID][constant.WORK_PHOTO_PHASE_BEFORE]
+			linksImageAfter := photoLinks[v.ID][constant.WORK_PHOTO_PHASE_AFTER]
+			linkImageBefore := strings.Join(linksImageBefore, "\n")
+			linkImageAfter := strings.Join(linksImageAfter, "\n")
 
 			row := []string{
 				no,
@@ -587,9 +1081,8 @@ func (s *service) Export(ctx *abstraction.Context, payload *dto.WorkExportReques
 				v.Info,
 				linkImageBefore,
 				linkImageAfter,
-				general.ConvertDateTimeToIndonesian(
-					v.CreatedAt.Format("2006-01-02 15:04:05"),
-				),
+				checklists[v.ID].String(),
+				i18n.FormatDateTime(lang, v.CreatedAt),
 			}
 
 			startX := pdf.GetX()
@@ -608,14 +1101,19 @@ func (s *service) Export(ctx *abstraction.Context, payload *dto.WorkExportReques
 				pdf.Rect(x, y, colWidths[j], maxHeight, "D")
 
 				if (j == 6 || j == 7) && txt != "" {
+					links := linksImageBefore
+					if j == 7 {
+						links = linksImageAfter
+					}
 					pdf.SetTextColor(0, 0, 255)
-					lines := pdf.SplitLines([]byte(txt), colWidths[j])
 					lineHeight := 5.0
 					textY := y
-					for _, line := range lines {
-						pdf.SetXY(x, textY)
-						pdf.CellFormat(colWidths[j], lineHeight, string(line), "", 0, "", false, 0, txt)
-						textY += lineHeight
+					for _, link := range links {
+						for _, line := range pdf.SplitLines([]byte(link), colWidths[j]) {
+							pdf.SetXY(x, textY)
+							pdf.CellFormat(colWidths[j], lineHeight, string(line), "", 0, "", false, 0, link)
+							textY += lineHeight
+						}
 					}
 					pdf.SetTextColor(0, 0, 0)
 				} else {
@@ -633,9 +1131,9 @@ func (s *service) Export(ctx *abstraction.Context, payload *dto.WorkExportReques
 			return "", nil, "", response.ErrorBuilder(http.StatusInternalServerError, err, "server_error")
 		}
 
-		filename := "CleanCare - Laporan Pekerjaan Petugas Kebersihan.pdf"
+		filename := title + ".pdf"
 		if reportDate != "" {
-			filename = fmt.Sprintf("(%s) CleanCare - Laporan Pekerjaan Petugas Kebersihan.pdf", strings.ReplaceAll(reportDate, "-", ""))
+			filename = fmt.Sprintf("(%s) %s.pdf", strings.ReplaceAll(reportDate, "-", ""), title)
 		}
 		return filename, &buf, "pdf", nil
 
@@ -649,10 +1147,6 @@ func (s *service) Export(ctx *abstraction.Context, payload *dto.WorkExportReques
 		f.DeleteSheet("Sheet1")
 		f.SetActiveSheet(index)
 
-		headers := []string{
-			"No", "Petugas Kebersihan", "Pekerjaan", "Jenis Pekerjaan",
-			"Lantai", "Keterangan", "Sebelum", "Sesudah", "Tanggal",
-		}
 		for i, h := range headers {
 			col := string(rune('A' + i))
 			cell := fmt.Sprintf("%s1", col)
@@ -676,19 +1170,12 @@ func (s *service) Export(ctx *abstraction.Context, payload *dto.WorkExportReques
 			colG := fmt.Sprintf("G%d", rowNum)
 			colH := fmt.Sprintf("H%d", rowNum)
 			colI := fmt.Sprintf("I%d", rowNum)
+			colJ := fmt.Sprintf("J%d", rowNum)
 
-			var (
-				linkImageBefore string
-				linkImageAfter  string
-			)
-			if v.ImageBefore != nil {
-				imageBeforeFile, _ := general.SplitFileAndNameWithDelimiter(*v.ImageBefore)
-				linkImageBefore = "https://lh3.googleusercontent.com/d/" + imageBeforeFile
-			}
-			if v.ImageAfter != nil {
-				imageAfterFile, _ := general.SplitFileAndNameWithDelimiter(*v.ImageAfter)
-				linkImageAfter = "https://lh3.googleusercontent.com/d/" + imageAfterFile
-			}
+			linksImageBefore := photoLinks[v.ID][constant.WORK_PHOTO_PHASE_BEFORE]
+			linksImageAfter := photoLinks[v.ID][constant.WORK_PHOTO_PHASE_AFTER]
+			linkImageBefore := strings.Join(linksImageBefore, "\n")
+			linkImageAfter := strings.Join(linksImageAfter, "\n")
 
 			f.SetCellValue(sheet, colA, no)
 			maxLens[0] = len(fmt.Sprintf("%d", no))
@@ -700,15 +1187,18 @@ func (s *service) Export(ctx *abstraction.Context, payload *dto.WorkExportReques
 				v.Info,
 				linkImageBefore,
 				linkImageAfter,
-				general.ConvertDateTimeToIndonesian(v.CreatedAt.Format("2006-01-02 15:04:05")),
+				checklists[v.ID].String(),
+				i18n.FormatDateTime(lang, v.CreatedAt),
 			}
-			cols := []string{colB, colC, colD, colE, colF, colG, colH, colI}
+			cols := []string{colB, colC, colD, colE, colF, colG, colH, colI, colJ}
 
 			for j, val := range values {
 				col := cols[j]
 				if (j == 5 || j == 6) && val != "" {
+					// a cell holds one hyperlink, it opens the first photo and lists the rest
+					link, _, _ := strings.Cut(val, "\n")
 					f.SetCellValue(sheet, col, val)
-					f.SetCellHyperLink(sheet, col, val, "External")
+					f.SetCellHyperLink(sheet, col, link, "External")
 				} else {
 					f.SetCellValue(sheet, col, val)
 				}
@@ -742,15 +1232,128 @@ func (s *service) Export(ctx *abstraction.Context, payload *dto.WorkExportReques
 			return "", nil, "", response.ErrorBuilder(http.StatusInternalServerError, err, "server_error")
 		}
 
-		filename := "CleanCare - Laporan Pekerjaan Petugas Kebersihan.xlsx"
+		filename := title + ".xlsx"
 		if reportDate != "" {
-			filename = fmt.Sprintf("(%s) CleanCare - Laporan Pekerjaan Petugas Kebersihan.xlsx", strings.ReplaceAll(reportDate, "-", ""))
+			filename = fmt.Sprintf("(%s) %s.xlsx", strings.ReplaceAll(reportDate, "-", ""), title)
 		}
 		return filename, &buf, "excel", nil
 	}
 }
 
+var workExportColumns = []string{
+	"id", "user_id", "user_name", "task_id", "task_name", "task_type_id", "task_type_name",
+	"floor", "info", "image_before", "image_after", "image_issue", "is_done",
+	"checklist_done", "checklist_total", "checklist_complete", "created_at", "updated_at",
+}
+
+// checklistCount is how much of the checklist of a work is done.
+type checklistCount struct {
+	Done          int
+	Total         int
+	RequiredDone  int
+	RequiredTotal int
+}
+
+func (c *checklistCount) String() string {
+	if c == nil || c.Total == 0 {
+		return ""
+	}
+	return fmt.Sprintf("%d/%d", c.Done, c.Total)
+}
+
+// groupChecklist counts the checklist items of each work by work id.
+func groupChecklist(data []*model.WorkChecklistEntityModel) map[int]*checklistCount {
+	res := make(map[int]*checklistCount)
+	for _, v := range data {
+		if res[v.WorkId] == nil {
+			res[v.WorkId] = new(checklistCount)
+		}
+		c := res[v.WorkId]
+		c.Total++
+		if v.IsDone {
+			c.Done++
+		}
+		if v.IsRequired {
+			c.RequiredTotal++
+			if v.IsDone {
+				c.RequiredDone++
+			}
+		}
+	}
+	return res
+}
+
+// groupPhotoLinks returns the view links of the photos by work id and phase, in photo order.
+func groupPhotoLinks(data []*model.WorkPhotoEntityModel) map[int]map[string][]string {
+	res := make(map[int]map[string][]string)
+	for _, v := range data {
+		if res[v.WorkId] == nil {
+			res[v.WorkId] = make(map[string][]string)
+		}
+		res[v.WorkId][v.Phase] = append(res[v.WorkId][v.Phase], "https://lh3.googleusercontent.com/d/"+v.StorageKey)
+	}
+	return res
+}
+
+func (s *service) exportData(payload *dto.WorkExportRequest, data []*model.WorkEntityModel, photoLinks map[int]map[string][]string, checklists map[int]*checklistCount, title, reportDate string) (string, *bytes.Buffer, string, error) {
+	columns, err := export.SelectColumns(workExportColumns, payload.Columns)
+	if err != nil {
+		return "", nil, "", response.ErrorBuilder(http.StatusBadRequest, errors.New("bad_request"), err.Error())
+	}
+
+	var rows []map[string]interface{}
+	for _, v := range data {
+		var updatedAt string
+		if v.UpdatedAt != nil {
+			updatedAt = general.FormatWithZWithoutChangingTime(*v.UpdatedAt)
+		}
+		checklist := checklists[v.ID]
+		if checklist == nil {
+			checklist = new(checklistCount)
+		}
+		rows = append(rows, map[string]interface{}{
+			"id":                 v.ID,
+			"user_id":            v.UserId,
+			"user_name":          v.User.Name,
+			"task_id":            v.TaskId,
+			"task_name":          v.Task.Name,
+			"task_type_id":       v.TaskTypeId,
+			"task_type_name":     v.TaskType.Name,
+			"floor":              v.Floor,
+			"info":               v.Info,
+			"image_before":       strings.Join(photoLinks[v.ID][constant.WORK_PHOTO_PHASE_BEFORE], "\n"),
+			"image_after":        strings.Join(photoLinks[v.ID][constant.WORK_PHOTO_PHASE_AFTER], "\n"),
+			"image_issue":        strings.Join(photoLinks[v.ID][constant.WORK_PHOTO_PHASE_ISSUE], "\n"),
+			"is_done":            v.ImageAfter != nil,
+			"checklist_done":     checklist.Done,
+			"checklist_total":    checklist.Total,
+			"checklist_complete": checklist.RequiredDone == checklist.RequiredTotal,
+			"created_at":         general.FormatWithZWithoutChangingTime(v.CreatedAt),
+			"updated_at":         updatedAt,
+		})
+	}
+
+	buf, err := export.Write(payload.Format, columns, rows)
+	if err != nil {
+		return "", nil, "", response.ErrorBuilder(http.StatusInternalServerError, err, "server_error")
+	}
+
+	filename := fmt.Sprintf("%s.%s", title, payload.Format)
+	if reportDate != "" {
+		filename = fmt.Sprintf("(%s) %s.%s", strings.ReplaceAll(reportDate, "-", ""), title, payload.Format)
+	}
+	return filename, buf, payload.Format, nil
+}
+
 func (s *service) DashboardAdmin(ctx *abstraction.Context, payload *dto.WorkDashboardAdminRequest) (map[string]interface{}, error) {
+	taskData, err := s.TaskRepository.FindById(ctx, payload.TaskId)
+	if err != nil && err.Error() != "record not found" {
+		return nil, response.ErrorBuilder(http.StatusInternalServerError, err, "server_error")
+	}
+	if taskData == nil {
+		return nil, response.ErrorBuilder(http.StatusBadRequest, errors.New("bad_request"), "task not found")
+	}
+
 	floorSummary, userSummary, errFloor, errUser := s.WorkRepository.FindByTaskIdArrAdmin(ctx, payload.TaskId, payload.CreatedAt, true)
 	if errFloor != nil && errFloor.Error() != "record not found" {
 		return nil, response.ErrorBuilder(http.StatusInternalServerError, errFloor, "server_error")
@@ -758,13 +1361,47 @@ func (s *service) DashboardAdmin(ctx *abstraction.Context, payload *dto.WorkDash
 	if errUser != nil && errUser.Error() != "record not found" {
 		return nil, response.ErrorBuilder(http.StatusInternalServerError, errUser, "server_error")
 	}
-	if payload.TaskId == constant.TASK_ID_DAILY {
+	slaSummary, err := s.WorkRepository.SlaSummary(ctx, payload.TaskId, payload.CreatedAt)
+	if err != nil && err.Error() != "record not found" {
+		return nil, response.ErrorBuilder(http.StatusInternalServerError, err, "server_error")
+	}
+	// compliance only counts the works whose sla is decided
+	var compliance *float64
+	if decided := slaSummary.Met + slaSummary.Breached; decided > 0 {
+		v := math.Round(float64(slaSummary.Met)/float64(decided)*10000) / 100
+		compliance = &v
+	}
+	resSla := map[string]interface{}{
+		"on_track":   slaSummary.OnTrack,
+		"at_risk":    slaSummary.AtRisk,
+		"breached":   slaSummary.Breached,
+		"met":        slaSummary.Met,
+		"compliance": compliance,
+	}
+	floorSatisfaction, err := s.FeedbackRepository.SatisfactionByFloor(ctx, payload.TaskId, payload.CreatedAt)
+	if err != nil && err.Error() != "record not found" {
+		return nil, response.ErrorBuilder(http.StatusInternalServerError, err, "server_error")
+	}
+	userSatisfaction, err := s.FeedbackRepository.SatisfactionByUser(ctx, payload.TaskId, payload.CreatedAt)
+	if err != nil && err.Error() != "record not found" {
+		return nil, response.ErrorBuilder(http.StatusInternalServerError, err, "server_error")
+	}
+	resSatisfaction := map[string]interface{}{
+		"floor": floorSatisfaction,
+		"user":  userSatisfaction,
+	}
+
+	if taskData.DashboardGroup == constant.TASK_DASHBOARD_GROUP_FLOOR {
 		return map[string]interface{}{
-			"data": floorSummary,
+			"data":         floorSummary,
+			"sla":          resSla,
+			"satisfaction": resSatisfaction,
 		}, nil
 	} else {
 		return map[string]interface{}{
-			"data": userSummary,
+			"data":         userSummary,
+			"sla":          resSla,
+			"satisfaction": resSatisfaction,
 		}, nil
 	}
 }
@@ -778,3 +1415,340 @@ func (s *service) DashboardStaf(ctx *abstraction.Context, payload *dto.WorkDashb
 		"data": taskTypeSummary,
 	}, nil
 }
+
+func (s *service) Verify(ctx *abstraction.Context, payload *dto.WorkVerifyRequest) (map[string]interface{}, error) {
+	if err := trxmanager.New(s.DB).WithTrx(ctx, func(ctx *abstraction.Context) error {
+		if ctx.Auth.RoleID != constant.ROLE_ID_ADMIN {
+			return response.ErrorBuilder(http.StatusBadRequest, errors.New("bad_request"), "this role is not permitted")
+		}
+
+		workData, err := s.WorkRepository.FindById(ctx, payload.ID)
+		if err != nil && err.Error() != "record not found" {
+			return response.ErrorBuilder(http.StatusInternalServerError, err, "server_error")
+		}
+		if workData == nil {
+			return response.ErrorBuilder(http.StatusBadRequest, errors.New("bad_request"), "work not found")
+		}
+		if workData.CompletedAt == nil {
+			return response.ErrorBuilder(http.StatusBadRequest, errors.New("bad_request"), "work is not done yet")
+		}
+
+		newWorkData := new(model.WorkEntityModel)
+		newWorkData.Context = ctx
+		newWorkData.ID = workData.ID
+		newWorkData.VerificationStatus = payload.Status
+		newWorkData.VerifiedBy = &ctx.Auth.ID
+		newWorkData.VerifiedAt = general.Now()
+		newWorkData.VerificationNote = payload.Note
+		if err = s.WorkRepository.Update(ctx, newWorkData).Error; err != nil {
+			return response.ErrorBuilder(http.StatusInternalServerError, err, "server_error")
+		}
+		if payload.Note == nil {
+			if err = s.WorkRepository.UpdateToNull(ctx, newWorkData, "verification_note").Error; err != nil {
+				return response.ErrorBuilder(http.StatusInternalServerError, err, "server_error")
+			}
+		}
+		modelVerification := &model.WorkVerificationEntityModel{
+			Context: ctx,
+			WorkVerificationEntity: model.WorkVerificationEntity{
+				WorkId:     workData.ID,
+				Status:     payload.Status,
+				Note:       payload.Note,
+				VerifiedBy: ctx.Auth.ID,
+			},
+		}
+		if err = s.WorkVerificationRepository.Create(ctx, modelVerification).Error; err != nil {
+			return response.ErrorBuilder(http.StatusInternalServerError, err, "server_error")
+		}
+
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+	return map[string]interface{}{
+		"message": "success update!",
+	}, nil
+}
+
+func (s *service) CreatePhoto(ctx *abstraction.Context, payload *dto.WorkPhotoCreateRequest) (map[string]interface{}, error) {
+	var (
+		allFileUploaded []string = nil
+		res             map[string]interface{}
+	)
+	if err := trxmanager.New(s.DB).WithTrx(ctx, func(ctx *abstraction.Context) error {
+		workData, err := s.WorkRepository.FindById(ctx, payload.ID)
+		if err != nil && err.Error() != "record not found" {
+			return response.ErrorBuilder(http.StatusInternalServerError, err, "server_error")
+		}
+		if workData == nil {
+			return response.ErrorBuilder(http.StatusBadRequest, errors.New("bad_request"), "work not found")
+		}
+
+		if ctx.Auth.ID != workData.UserId {
+			return response.ErrorBuilder(http.StatusBadRequest, errors.New("bad_request"), "this role is not permitted")
+		}
+
+		photoData, err := s.WorkPhotoRepository.FindByWorkIdPhase(ctx, workData.ID, payload.Phase)
+		if err != nil && err.Error() != "record not found" {
+			return response.ErrorBuilder(http.StatusInternalServerError, err, "server_error")
+		}
+		if len(photoData) >= constant.WORK_PHOTO_MAX_PER_PHASE {
+			return response.ErrorBuilder(http.StatusBadRequest, errors.New("bad_request"), "photo limit of the phase is reached")
+		}
+		// an after photo completes the work, the before photo the task asks for has to be there by then
+		if payload.Phase == constant.WORK_PHOTO_PHASE_AFTER && workData.Task.RequiresImageBefore && workData.ImageBefore == nil {
+			return response.ErrorBuilder(http.StatusBadRequest, errors.New("bad_request"), "image_before is required for this task")
+		}
+		sortOrder := 0
+		if len(photoData) > 0 {
+			sortOrder = photoData[len(photoData)-1].SortOrder + 1
+		}
+
+		var takenAt *time.Time
+		if payload.TakenAt != nil {
+			t, err := time.Parse(time.RFC3339, *payload.TakenAt)
+			if err != nil {
+				return response.ErrorBuilder(http.StatusBadRequest, errors.New("bad_request"), "invalid taken_at")
+			}
+			takenAt = &t
+		}
+
+		var (
+			image *string
+			check *imagecheck.Result
+		)
+		if payload.File != nil {
+			var newFile *drive.File
+			if newFile, check, err = upload.SaveImage(s.sDrive, s.fDrive, payload.File[0]); err != nil {
+				return err
+			}
+			allFileUploaded = append(allFileUploaded, newFile.Id)
+
+			imgFileDelimiter := general.JoinFileAndNameWithDelimiter(newFile.Id, newFile.Name)
+			image = &imgFileDelimiter
+		} else if payload.UploadId != nil {
+			if image, check, err = upload.Claim(ctx, s.UploadSessionRepository, *payload.UploadId); err != nil {
+				return err
+			}
+		} else {
+			return response.ErrorBuilder(http.StatusBadRequest, errors.New("bad_request"), "photo is required")
+		}
+
+		fileId, fileName := general.SplitFileAndNameWithDelimiter(*image)
+		modelWorkPhoto := &model.WorkPhotoEntityModel{
+			Context: ctx,
+			WorkPhotoEntity: model.WorkPhotoEntity{
+				WorkId:     workData.ID,
+				Phase:      payload.Phase,
+				StorageKey: fileId,
+				FileName:   fileName,
+				Caption:    payload.Caption,
+				SortOrder:  sortOrder,
+				TakenAt:    takenAt,
+				CapturedAt: check.CapturedAt,
+				Phash:      check.Hash,
+			},
+		}
+		if err = s.WorkPhotoRepository.Create(ctx, modelWorkPhoto).Error; err != nil {
+			return response.ErrorBuilder(http.StatusInternalServerError, err, "server_error")
+		}
+		if err = s.checkPhoto(ctx, modelWorkPhoto); err != nil {
+			return err
+		}
+		if err = s.WorkPhotoRepository.SyncCover(ctx, workData.ID, payload.Phase).Error; err != nil {
+			return response.ErrorBuilder(http.StatusInternalServerError, err, "server_error")
+		}
+
+		// the first after photo completes the work, same as image_after on update
+		if payload.Phase == constant.WORK_PHOTO_PHASE_AFTER && workData.ImageAfter == nil {
+			if err = s.saveChecklist(ctx, workData.ID, workData.TaskTypeId, false, nil, true); err != nil {
+				return err
+			}
+		}
+
+		// new proof of the work goes back to the admin, same as a new image_after on update
+		if payload.Phase == constant.WORK_PHOTO_PHASE_AFTER && workData.VerificationStatus == constant.WORK_VERIFICATION_REJECTED {
+			newWorkData := new(model.WorkEntityModel)
+			newWorkData.Context = ctx
+			newWorkData.ID = workData.ID
+			newWorkData.VerificationStatus = constant.WORK_VERIFICATION_PENDING
+			if err = s.WorkRepository.Update(ctx, newWorkData).Error; err != nil {
+				return response.ErrorBuilder(http.StatusInternalServerError, err, "server_error")
+			}
+		}
+
+		res = photoToMap(modelWorkPhoto)
+		return nil
+	}); err != nil {
+		for _, v := range allFileUploaded {
+			errDel := gdrive.DeleteFile(s.sDrive, v)
+			if errDel != nil {
+				logrus.Error("error delete file for error trxmanager:", errDel.Error())
+			}
+		}
+		return nil, err
+	}
+	return map[string]interface{}{
+		"message": "success create!",
+		"data":    res,
+	}, nil
+}
+
+func (s *service) OrderPhoto(ctx *abstraction.Context, payload *dto.WorkPhotoOrderRequest) (map[string]interface{}, error) {
+	var res []map[string]interface{}
+	if err := trxmanager.New(s.DB).WithTrx(ctx, func(ctx *abstraction.Context) error {
+		workData, err := s.WorkRepository.FindById(ctx, payload.ID)
+		if err != nil && err.Error() != "record not found" {
+			return response.ErrorBuilder(http.StatusInternalServerError, err, "server_error")
+		}
+		if workData == nil {
+			return response.ErrorBuilder(http.StatusBadRequest, errors.New("bad_request"), "work not found")
+		}
+
+		if ctx.Auth.ID != workData.UserId {
+			return response.ErrorBuilder(http.StatusBadRequest, errors.New("bad_request"), "this role is not permitted")
+		}
+
+		photoData, err := s.WorkPhotoRepository.FindByWorkIdPhase(ctx, workData.ID, payload.Phase)
+		if err != nil && err.Error() != "record not found" {
+			return response.ErrorBuilder(http.StatusInternalServerError, err, "server_error")
+		}
+
+		// ids must list every photo of the phase exactly once
+		photoById := make(map[int]*model.WorkPhotoEntityModel)
+		for _, v := range photoData {
+			photoById[v.ID] = v
+		}
+		if len(payload.Ids) != len(photoData) {
+			return response.ErrorBuilder(http.StatusBadRequest, errors.New("bad_request"), "photo ids do not match the photos of the phase")
+		}
+		for i, id := range payload.Ids {
+			photo, ok := photoById[id]
+			if !ok {
+				return response.ErrorBuilder(http.StatusBadRequest, errors.New("bad_request"), "photo ids do not match the photos of the phase")
+			}
+			delete(photoById, id)
+
+			photo.Context = ctx
+			if err = s.WorkPhotoRepository.UpdateSortOrder(ctx, photo, i).Error; err != nil {
+				return response.ErrorBuilder(http.StatusInternalServerError, err, "server_error")
+			}
+			photo.SortOrder = i
+			res = append(res, photoToMap(photo))
+		}
+
+		if err = s.WorkPhotoRepository.SyncCover(ctx, workData.ID, payload.Phase).Error; err != nil {
+			return response.ErrorBuilder(http.StatusInternalServerError, err, "server_error")
+		}
+
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+	return map[string]interface{}{
+		"message": "success update!",
+		"data":    res,
+	}, nil
+}
+
+func (s *service) DeletePhoto(ctx *abstraction.Context, payload *dto.WorkPhotoDeleteRequest) (map[string]interface{}, error) {
+	var fileOld string
+	if err := trxmanager.New(s.DB).WithTrx(ctx, func(ctx *abstraction.Context) error {
+		workData, err := s.WorkRepository.FindById(ctx, payload.ID)
+		if err != nil && err.Error() != "record not found" {
+			return response.ErrorBuilder(http.StatusInternalServerError, err, "server_error")
+		}
+		if workData == nil {
+			return response.ErrorBuilder(http.StatusBadRequest, errors.New("bad_request"), "work not found")
+		}
+
+		if ctx.Auth.ID != workData.UserId {
+			return response.ErrorBuilder(http.StatusBadRequest, errors.New("bad_request"), "this role is not permitted")
+		}
+
+		photoData, err := s.WorkPhotoRepository.FindById(ctx, payload.PhotoId)
+		if err != nil && err.Error() != "record not found" {
+			return response.ErrorBuilder(http.StatusInternalServerError, err, "server_error")
+		}
+		if photoData == nil || photoData.WorkId != workData.ID {
+			return response.ErrorBuilder(http.StatusBadRequest, errors.New("bad_request"), "photo not found")
+		}
+
+		// the last photo of a phase the task asks for has to stay
+		if (photoData.Phase == constant.WORK_PHOTO_PHASE_BEFORE && workData.Task.RequiresImageBefore) ||
+			(photoData.Phase == constant.WORK_PHOTO_PHASE_AFTER && workData.Task.RequiresImageAfter) {
+			count, err := s.WorkPhotoRepository.CountByWorkIdPhase(ctx, workData.ID, photoData.Phase)
+			if err != nil {
+				return response.ErrorBuilder(http.StatusInternalServerError, err, "server_error")
+			}
+			if *count <= 1 {
+				return response.ErrorBuilder(http.StatusBadRequest, errors.New("bad_request"), fmt.Sprintf("image_%s is required for this task", photoData.Phase))
+			}
+		}
+
+		if err = s.WorkPhotoRepository.Delete(ctx, photoData).Error; err != nil {
+			return response.ErrorBuilder(http.StatusInternalServerError, err, "server_error")
+		}
+		if err = s.PhotoFlagRepository.DeleteByWorkPhotoId(ctx, photoData.ID).Error; err != nil {
+			return response.ErrorBuilder(http.StatusInternalServerError, err, "server_error")
+		}
+		if err = s.WorkPhotoRepository.SyncCover(ctx, workData.ID, photoData.Phase).Error; err != nil {
+			return response.ErrorBuilder(http.StatusInternalServerError, err, "server_error")
+		}
+
+		fileOld = photoData.StorageKey
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+
+	if errDel := gdrive.DeleteFile(s.sDrive, fileOld); errDel != nil {
+		logrus.Error("error delete file old after trxmanager:", errDel.Error())
+	}
+
+	return map[string]interface{}{
+		"message": "success delete!",
+	}, nil
+}
+
+func photoToMap(data *model.WorkPhotoEntityModel) map[string]interface{} {
+	res := map[string]interface{}{
+		"id":          data.ID,
+		"phase":       data.Phase,
+		"caption":     data.Caption,
+		"sort_order":  data.SortOrder,
+		"taken_at":    nil,
+		"captured_at": nil,
+		"image": map[string]interface{}{
+			"view": "https://lh3.googleusercontent.com/d/" + data.StorageKey,
+			"name": data.FileName,
+			"id":   data.StorageKey,
+		},
+	}
+	if data.TakenAt != nil {
+		res["taken_at"] = general.FormatWithZWithoutChangingTime(*data.TakenAt)
+	}
+	if data.CapturedAt != nil {
+		res["captured_at"] = general.FormatWithZWithoutChangingTime(*data.CapturedAt)
+	}
+	return res
+}
+
+func flagToMap(data *model.PhotoFlagEntityModel) map[string]interface{} {
+	res := map[string]interface{}{
+		"id":                  data.ID,
+		"work_photo_id":       data.WorkPhotoId,
+		"type":                data.Type,
+		"captured_at":         nil,
+		"submitted_at":        general.FormatWithZWithoutChangingTime(data.SubmittedAt),
+		"match_work_id":       data.MatchWorkId,
+		"match_work_photo_id": data.MatchWorkPhotoId,
+		"distance":            data.Distance,
+		"status":              data.Status,
+		"review_note":         data.ReviewNote,
+	}
+	if data.CapturedAt != nil {
+		res["captured_at"] = general.FormatWithZWithoutChangingTime(*data.CapturedAt)
+	}
+	return res
+}
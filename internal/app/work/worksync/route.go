@@ -0,0 +1,11 @@
+package worksync
+
+import (
+	"cleancare/internal/middleware"
+
+	"github.com/labstack/echo/v4"
+)
+
+func (h *Handler) Route(v *echo.Group) {
+	v.POST("", h.Sync, middleware.Authentication)
+}
@@ -0,0 +1,394 @@
+package worksync
+
+import (
+	"cleancare/internal/abstraction"
+	"cleancare/internal/dto"
+	"cleancare/internal/factory"
+	"cleancare/internal/model"
+	"cleancare/internal/repository"
+	"cleancare/pkg/constant"
+	"cleancare/pkg/i18n"
+	"cleancare/pkg/util/general"
+	"cleancare/pkg/util/response"
+	"cleancare/pkg/util/trxmanager"
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+const (
+	statusCreated   = "created"
+	statusUpdated   = "updated"
+	statusUnchanged = "unchanged"
+	statusConflict  = "conflict"
+	statusFailed    = "failed"
+
+	// cursorOverlap is sent again on the next sync, a transaction still open when the cursor
+	// is taken commits rows stamped before it
+	cursorOverlap = time.Minute
+)
+
+type Service interface {
+	Sync(ctx *abstraction.Context, payload *dto.WorkSyncRequest) (map[string]interface{}, error)
+}
+
+type service struct {
+	TaskRepository       repository.Task
+	TaskTypeRepository   repository.TaskType
+	WorkRepository       repository.Work
+	CommentRepository    repository.Comment
+	AssignmentRepository repository.Assignment
+
+	WorkChecklistRepository repository.WorkChecklist
+	SlaPolicyRepository     repository.SlaPolicy
+
+	DB *gorm.DB
+}
+
+func NewService(f *factory.Factory) Service {
+	return &service{
+		TaskRepository:       f.TaskRepository,
+		TaskTypeRepository:   f.TaskTypeRepository,
+		WorkRepository:       f.WorkRepository,
+		CommentRepository:    f.CommentRepository,
+		AssignmentRepository: f.AssignmentRepository,
+
+		WorkChecklistRepository: f.WorkChecklistRepository,
+		SlaPolicyRepository:     f.SlaPolicyRepository,
+
+		DB: f.Db,
+	}
+}
+
+// Sync applies the work entries the app changed offline, then returns everything that changed
+// on the server since the cursor of the previous sync, the entries just applied included.
+func (s *service) Sync(ctx *abstraction.Context, payload *dto.WorkSyncRequest) (map[string]interface{}, error) {
+	if ctx.Auth.RoleID != constant.ROLE_ID_STAFF {
+		return nil, response.ErrorBuilder(http.StatusBadRequest, errors.New("bad_request"), "this role is not permitted")
+	}
+
+	var since *time.Time
+	if payload.Cursor != "" {
+		cursor, err := time.Parse(time.RFC3339, payload.Cursor)
+		if err != nil {
+			return nil, response.ErrorBuilder(http.StatusBadRequest, errors.New("bad_request"), "invalid sync cursor")
+		}
+		since = &cursor
+	}
+	nextCursor := general.Now().Truncate(time.Second).Add(-cursorOverlap)
+
+	results := []map[string]interface{}{}
+	for _, item := range payload.Items {
+		results = append(results, s.apply(ctx, item))
+	}
+
+	works, err := s.WorkRepository.FindChangedSince(ctx, ctx.Auth.ID, since)
+	if err != nil {
+		return nil, response.ErrorBuilder(http.StatusInternalServerError, err, "server_error")
+	}
+	comments, err := s.CommentRepository.FindChangedSince(ctx, ctx.Auth.ID, since)
+	if err != nil {
+		return nil, response.ErrorBuilder(http.StatusInternalServerError, err, "server_error")
+	}
+	taskTypes, err := s.TaskTypeRepository.FindChangedSince(ctx, since)
+	if err != nil {
+		return nil, response.ErrorBuilder(http.StatusInternalServerError, err, "server_error")
+	}
+	assignments, err := s.AssignmentRepository.FindChangedSince(ctx, ctx.Auth.ID, since)
+	if err != nil {
+		return nil, response.ErrorBuilder(http.StatusInternalServerError, err, "server_error")
+	}
+
+	resWork := []map[string]interface{}{}
+	for _, v := range works {
+		resWork = append(resWork, workToMap(v))
+	}
+	resComment := []map[string]interface{}{}
+	for _, v := range comments {
+		resComment = append(resComment, map[string]interface{}{
+			"id":         v.ID,
+			"work_id":    v.WorkId,
+			"comment":    v.Comment,
+			"is_delete":  v.IsDelete,
+			"created_by": map[string]interface{}{"id": v.CreateBy.ID, "name": v.CreateBy.Name},
+			"created_at": v.CreatedAt,
+			"updated_at": v.UpdatedAt,
+		})
+	}
+	resTaskType := []map[string]interface{}{}
+	for _, v := range taskTypes {
+		resTaskType = append(resTaskType, map[string]interface{}{
+			"id":        v.ID,
+			"task_id":   v.TaskId,
+			"name":      v.Name,
+			"is_delete": v.IsDelete,
+		})
+	}
+	resAssignment := []map[string]interface{}{}
+	for _, v := range assignments {
+		resAssignment = append(resAssignment, map[string]interface{}{
+			"id":           v.ID,
+			"task_id":      v.TaskId,
+			"task_type_id": v.TaskTypeId,
+			"floor":        v.Floor,
+			"info":         v.Info,
+			"due_at":       v.DueAt,
+			"work_id":      v.WorkId,
+			"is_delete":    v.IsDelete,
+			"created_at":   v.CreatedAt,
+			"updated_at":   v.UpdatedAt,
+		})
+	}
+
+	return map[string]interface{}{
+		"cursor":  nextCursor.Format(time.RFC3339),
+		"results": results,
+		"changes": map[string]interface{}{
+			"work":       resWork,
+			"comment":    resComment,
+			"task_type":  resTaskType,
+			"assignment": resAssignment,
+		},
+	}, nil
+}
+
+// apply writes one entry in its own transaction so a rejected entry does not undo the others.
+// Entries are matched on client_id, a retried batch finds them applied and reports unchanged.
+func (s *service) apply(ctx *abstraction.Context, item *dto.WorkSyncItem) map[string]interface{} {
+	var (
+		workId int
+		status string
+		reason string
+	)
+	err := trxmanager.New(s.DB).WithTrx(ctx, func(ctx *abstraction.Context) error {
+		workData, err := s.WorkRepository.FindByClientId(ctx, ctx.Auth.ID, item.ClientId)
+		if err != nil && err.Error() != "record not found" {
+			return response.ErrorBuilder(http.StatusInternalServerError, err, "server_error")
+		}
+		if workData == nil {
+			workId, err = s.create(ctx, item)
+			status = statusCreated
+			return err
+		}
+
+		workId = workData.ID
+		if workData.IsDelete {
+			status, reason = statusConflict, "work was deleted on the server"
+			return nil
+		}
+		if workData.ClientUpdatedAt != nil && !item.ClientUpdatedAt.After(*workData.ClientUpdatedAt) {
+			status = statusUnchanged
+			return nil
+		}
+		serverUpdatedAt := workData.CreatedAt
+		if workData.UpdatedAt != nil {
+			serverUpdatedAt = *workData.UpdatedAt
+		}
+		if item.BaseUpdatedAt == nil || serverUpdatedAt.After(*item.BaseUpdatedAt) {
+			status, reason = statusConflict, "work was changed on the server"
+			return nil
+		}
+
+		status = statusUpdated
+		return s.update(ctx, workData, item)
+	})
+	// every item gets its own transaction, the committed one must not be reused
+	ctx.Trx = nil
+
+	if err != nil && status == statusCreated {
+		// a concurrent request with the same batch may have created it first
+		if workData, _ := s.WorkRepository.FindByClientId(ctx, ctx.Auth.ID, item.ClientId); workData != nil {
+			workId, status, err = workData.ID, statusUnchanged, nil
+		}
+	}
+
+	result := map[string]interface{}{
+		"client_id": item.ClientId,
+		"status":    status,
+	}
+	if err != nil {
+		result["status"] = statusFailed
+		result["message"] = i18n.Message(ctx.MessageLang(), errorMessage(err))
+		return result
+	}
+	if reason != "" {
+		result["message"] = i18n.Message(ctx.MessageLang(), reason)
+	}
+
+	workData, err := s.WorkRepository.FindByClientId(ctx, ctx.Auth.ID, item.ClientId)
+	if err == nil && workData != nil {
+		result["data"] = workToMap(workData)
+	} else {
+		result["data"] = map[string]interface{}{"id": workId}
+	}
+	return result
+}
+
+func (s *service) create(ctx *abstraction.Context, item *dto.WorkSyncItem) (int, error) {
+	taskData, err := s.validateTask(ctx, item)
+	if err != nil {
+		return 0, err
+	}
+
+	var assignmentData *model.AssignmentEntityModel
+	if item.AssignmentId != nil {
+		assignmentData, err = s.AssignmentRepository.FindById(ctx, *item.AssignmentId)
+		if err != nil && err.Error() != "record not found" {
+			return 0, response.ErrorBuilder(http.StatusInternalServerError, err, "server_error")
+		}
+		if assignmentData == nil {
+			return 0, response.ErrorBuilder(http.StatusBadRequest, errors.New("bad_request"), "assignment not found")
+		}
+		if assignmentData.UserId != ctx.Auth.ID {
+			return 0, response.ErrorBuilder(http.StatusBadRequest, errors.New("bad_request"), "this user is not permitted")
+		}
+		if assignmentData.WorkId != nil {
+			return 0, response.ErrorBuilder(http.StatusBadRequest, errors.New("bad_request"), "assignment already done")
+		}
+		if assignmentData.TaskId != item.TaskId || assignmentData.TaskTypeId != item.TaskTypeId || !strings.EqualFold(assignmentData.Floor, item.Floor) {
+			return 0, response.ErrorBuilder(http.StatusBadRequest, errors.New("bad_request"), "work does not match the assignment")
+		}
+	}
+
+	// the photos a task asks for follow through the photo endpoints, the work cannot be completed
+	// without them
+	clientId := item.ClientId
+	clientUpdatedAt := item.ClientUpdatedAt
+	modelWork := &model.WorkEntityModel{
+		Context: ctx,
+		WorkEntity: model.WorkEntity{
+			UserId:          ctx.Auth.ID,
+			ClientId:        &clientId,
+			ClientUpdatedAt: &clientUpdatedAt,
+			TaskId:          item.TaskId,
+			TaskTypeId:      item.TaskTypeId,
+			Floor:           item.Floor,
+			Info:            item.Info,
+			IsDelete:        false,
+
+			VerificationStatus: constant.WORK_VERIFICATION_PENDING,
+		},
+	}
+	// the sla counts from when the work reaches the server, like its created_at
+	if err := s.SlaPolicyRepository.SetWorkSla(ctx, &modelWork.WorkEntity, taskData, item.TaskTypeId, time.Now()); err != nil {
+		return 0, response.ErrorBuilder(http.StatusInternalServerError, err, "server_error")
+	}
+	if err := s.WorkRepository.Create(ctx, modelWork).Error; err != nil {
+		return 0, response.ErrorBuilder(http.StatusInternalServerError, err, "server_error")
+	}
+
+	if assignmentData != nil {
+		newAssignmentData := new(model.AssignmentEntityModel)
+		newAssignmentData.Context = ctx
+		newAssignmentData.ID = assignmentData.ID
+		newAssignmentData.WorkId = &modelWork.ID
+		if err := s.AssignmentRepository.Update(ctx, newAssignmentData).Error; err != nil {
+			return 0, response.ErrorBuilder(http.StatusInternalServerError, err, "server_error")
+		}
+	}
+
+	return modelWork.ID, nil
+}
+
+func (s *service) update(ctx *abstraction.Context, workData *model.WorkEntityModel, item *dto.WorkSyncItem) error {
+	taskData, err := s.validateTask(ctx, item)
+	if err != nil {
+		return err
+	}
+
+	clientUpdatedAt := item.ClientUpdatedAt
+	newWorkData := new(model.WorkEntityModel)
+	newWorkData.Context = ctx
+	newWorkData.ID = workData.ID
+	newWorkData.TaskId = item.TaskId
+	newWorkData.TaskTypeId = item.TaskTypeId
+	newWorkData.Floor = item.Floor
+	newWorkData.Info = item.Info
+	newWorkData.ClientUpdatedAt = &clientUpdatedAt
+	if err := s.WorkRepository.Update(ctx, newWorkData).Error; err != nil {
+		return response.ErrorBuilder(http.StatusInternalServerError, err, "server_error")
+	}
+
+	// the checklist of the old task type no longer applies, the work takes the new template on its next update
+	if item.TaskTypeId != workData.TaskTypeId {
+		if err := s.WorkChecklistRepository.DeleteByWorkId(ctx, workData.ID).Error; err != nil {
+			return response.ErrorBuilder(http.StatusInternalServerError, err, "server_error")
+		}
+	}
+
+	if item.TaskId != workData.TaskId || item.TaskTypeId != workData.TaskTypeId {
+		slaWorkData := &model.WorkEntityModel{Context: ctx, ID: workData.ID}
+		if err := s.SlaPolicyRepository.SetWorkSla(ctx, &slaWorkData.WorkEntity, taskData, item.TaskTypeId, workData.CreatedAt); err != nil {
+			return response.ErrorBuilder(http.StatusInternalServerError, err, "server_error")
+		}
+		if err := s.WorkRepository.UpdateSla(ctx, slaWorkData).Error; err != nil {
+			return response.ErrorBuilder(http.StatusInternalServerError, err, "server_error")
+		}
+	}
+	return nil
+}
+
+func (s *service) validateTask(ctx *abstraction.Context, item *dto.WorkSyncItem) (*model.TaskEntityModel, error) {
+	taskData, err := s.TaskRepository.FindById(ctx, item.TaskId)
+	if err != nil && err.Error() != "record not found" {
+		return nil, response.ErrorBuilder(http.StatusInternalServerError, err, "server_error")
+	}
+	if taskData == nil {
+		return nil, response.ErrorBuilder(http.StatusBadRequest, errors.New("bad_request"), "task not found")
+	}
+
+	taskTypeData, err := s.TaskTypeRepository.FindById(ctx, item.TaskTypeId)
+	if err != nil && err.Error() != "record not found" {
+		return nil, response.ErrorBuilder(http.StatusInternalServerError, err, "server_error")
+	}
+	if taskTypeData == nil {
+		return nil, response.ErrorBuilder(http.StatusBadRequest, errors.New("bad_request"), "task type not found")
+	}
+	return taskData, nil
+}
+
+func errorMessage(err error) string {
+	if re, ok := err.(*response.MetaError); ok {
+		if m, ok := re.Data.(map[string]interface{}); ok {
+			if msg, ok := m["message"].(string); ok {
+				return msg
+			}
+		}
+	}
+	return "server_error"
+}
+
+func workToMap(v *model.WorkEntityModel) map[string]interface{} {
+	return map[string]interface{}{
+		"id":                  v.ID,
+		"client_id":           v.ClientId,
+		"client_updated_at":   v.ClientUpdatedAt,
+		"task_id":             v.TaskId,
+		"task_type_id":        v.TaskTypeId,
+		"floor":               v.Floor,
+		"info":                v.Info,
+		"image_before":        imageToMap(v.ImageBefore),
+		"image_after":         imageToMap(v.ImageAfter),
+		"completed_at":        v.CompletedAt,
+		"verification_status": v.VerificationStatus,
+		"verification_note":   v.VerificationNote,
+		"is_delete":           v.IsDelete,
+		"created_at":          v.CreatedAt,
+		"updated_at":          v.UpdatedAt,
+	}
+}
+
+func imageToMap(image *string) map[string]interface{} {
+	if image == nil || *image == "" {
+		return nil
+	}
+	fileId, name := general.SplitFileAndNameWithDelimiter(*image)
+	return map[string]interface{}{
+		"view": "https://lh3.googleusercontent.com/d/" + fileId,
+		"name": name,
+		"id":   fileId,
+	}
+}
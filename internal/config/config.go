@@ -2,27 +2,57 @@ package config
 
 import (
 	"cleancare/pkg/constant"
+	"cleancare/pkg/i18n"
 	"fmt"
 	"os"
+	"strconv"
 	"sync"
 
 	"github.com/joho/godotenv"
 )
 
 type Configuration struct {
-	App     App
-	DB      DB
-	Redis   Redis
-	Logging Logging
-	JWT     JWT
-	Gomail  Gomail
-	Drive   Drive
+	App      App
+	DB       DB
+	Redis    Redis
+	Logging  Logging
+	JWT      JWT
+	Gomail   Gomail
+	Drive    Drive
+	Password Password
+	Photo    Photo
 }
 
 type App struct {
-	App     string
-	Port    string
-	Version string
+	App             string
+	Port            string
+	Version         string
+	DefaultLanguage string
+	// DefaultMessageLanguage is the language of the API messages without a preference, English
+	// when unset.
+	DefaultMessageLanguage string
+	// ResetPasswordTokenTTL is how long a reset password link stays valid, in minutes.
+	ResetPasswordTokenTTL int
+	// EmailVerificationTokenTTL is how long an email verification link stays valid, in minutes.
+	EmailVerificationTokenTTL int
+	// TrashRetentionDays is how long soft deleted records are kept before they are purged.
+	TrashRetentionDays int
+	// TrashPurgeInterval is how often the retention job runs, in minutes.
+	TrashPurgeInterval int
+	// StorageGcInterval is how often the drive folder is reconciled against the database, in minutes.
+	StorageGcInterval int
+	// StorageGcGraceHours is how old an orphaned drive file must be before it is deleted.
+	StorageGcGraceHours int
+	// StorageGcDryRun makes the scheduled reconciliation only report, without deleting anything.
+	StorageGcDryRun bool
+	// SlaCheckInterval is how often the sla of the open works is checked, in minutes.
+	SlaCheckInterval int
+	// FeedbackWorkMaxAge is how long after its completion a work can still be rated, in hours.
+	FeedbackWorkMaxAge int
+	// IdempotencyKeyTTL is how long a response is replayed for a repeated idempotency key, in minutes.
+	IdempotencyKeyTTL int
+	// IdempotencyLockTTL is how long a request holds its idempotency key before it is considered lost, in minutes.
+	IdempotencyLockTTL int
 }
 
 type DB struct {
@@ -58,11 +88,52 @@ type Gomail struct {
 	SenderName   string
 	AuthEmail    string
 	AuthPassword string
+	// Driver is smtp, or file to write every email into FileDir instead of sending it.
+	Driver  string
+	FileDir string
+	// MaxAttempts is how many times the outbox tries an email before it is dead.
+	MaxAttempts int
+	// WorkerInterval is how often the outbox is polled, in seconds.
+	WorkerInterval int
+}
+
+type Password struct {
+	MinLength     int
+	RequireUpper  bool
+	RequireLower  bool
+	RequireNumber bool
+	RequireSymbol bool
+	// History is how many previous passwords of a user cannot be used again.
+	History int
 }
 
 type Drive struct {
 	CredentialsDrive  string
 	RefreshTokenDrive string
+	// UploadDir keeps the chunks of resumable uploads until they are completed.
+	UploadDir string
+	// UploadMaxSize is the largest file a resumable upload accepts, in megabytes.
+	UploadMaxSize int
+	// UploadMaxChunkSize is the largest chunk a single request may carry, in megabytes.
+	UploadMaxChunkSize int
+	// UploadSessionTTL is how long an upload can be resumed and then referenced, in minutes.
+	UploadSessionTTL int
+}
+
+type Photo struct {
+	// CaptureMaxAge is how long before the submission a photo may have been taken, in minutes.
+	CaptureMaxAge int
+	// CaptureMaxAhead is how far a capture time may be after the submission, for clocks that
+	// are slightly off, in minutes.
+	CaptureMaxAhead int
+	// FlagMissingCaptureTime flags photos without a capture time, most apps strip it.
+	FlagMissingCaptureTime bool
+	// HashMaxDistance is how many of the 64 hash bits two photos may differ in to be duplicates.
+	HashMaxDistance int
+	// HashMaxMatches is how many duplicates are flagged for one photo.
+	HashMaxMatches int
+	// HashWindow is how far back photos are compared for duplicates, in days.
+	HashWindow int
 }
 
 var lock = &sync.Mutex{}
@@ -95,6 +166,19 @@ func Init() *Configuration {
 	defaultConfig.App.App = os.Getenv("APP")
 	defaultConfig.App.Port = os.Getenv("PORT")
 	defaultConfig.App.Version = os.Getenv("VERSION")
+	defaultConfig.App.DefaultLanguage = os.Getenv("DEFAULT_LANGUAGE")
+	defaultConfig.App.DefaultMessageLanguage = os.Getenv("DEFAULT_MESSAGE_LANGUAGE")
+	defaultConfig.App.ResetPasswordTokenTTL = getEnvInt("RESET_PASSWORD_TOKEN_TTL", 30)
+	defaultConfig.App.EmailVerificationTokenTTL = getEnvInt("EMAIL_VERIFICATION_TOKEN_TTL", 1440)
+	defaultConfig.App.TrashRetentionDays = getEnvInt("TRASH_RETENTION_DAYS", 30)
+	defaultConfig.App.TrashPurgeInterval = getEnvInt("TRASH_PURGE_INTERVAL", 60)
+	defaultConfig.App.StorageGcInterval = getEnvInt("STORAGE_GC_INTERVAL", 1440)
+	defaultConfig.App.StorageGcGraceHours = getEnvInt("STORAGE_GC_GRACE_HOURS", 24)
+	defaultConfig.App.StorageGcDryRun = getEnvBool("STORAGE_GC_DRY_RUN", false)
+	defaultConfig.App.SlaCheckInterval = getEnvInt("SLA_CHECK_INTERVAL", 5)
+	defaultConfig.App.FeedbackWorkMaxAge = getEnvInt("FEEDBACK_WORK_MAX_AGE", 72)
+	defaultConfig.App.IdempotencyKeyTTL = getEnvInt("IDEMPOTENCY_KEY_TTL", 1440)
+	defaultConfig.App.IdempotencyLockTTL = getEnvInt("IDEMPOTENCY_LOCK_TTL", 5)
 	defaultConfig.DB.DbHost = os.Getenv("DB_HOST")
 	defaultConfig.DB.DbUser = os.Getenv("DB_USER")
 	defaultConfig.DB.DbPass = os.Getenv("DB_PASS")
@@ -115,8 +199,68 @@ func Init() *Configuration {
 	defaultConfig.Gomail.SenderName = os.Getenv("SENDER_NAME")
 	defaultConfig.Gomail.AuthEmail = os.Getenv("AUTH_EMAIL")
 	defaultConfig.Gomail.AuthPassword = os.Getenv("AUTH_PASSWORD")
+	defaultConfig.Gomail.Driver = getEnvString("MAIL_DRIVER", "smtp")
+	defaultConfig.Gomail.FileDir = getEnvString("MAIL_FILE_DIR", "./storage/mail")
+	defaultConfig.Gomail.MaxAttempts = getEnvInt("MAIL_MAX_ATTEMPTS", 5)
+	defaultConfig.Gomail.WorkerInterval = getEnvInt("MAIL_WORKER_INTERVAL", 10)
 	defaultConfig.Drive.CredentialsDrive = os.Getenv("CREDENTIALS_DRIVE")
 	defaultConfig.Drive.RefreshTokenDrive = os.Getenv("REFRESH_DRIVE")
+	defaultConfig.Drive.UploadDir = getEnvString("UPLOAD_DIR", "./storage/upload")
+	defaultConfig.Drive.UploadMaxSize = getEnvInt("UPLOAD_MAX_SIZE", 20)
+	defaultConfig.Drive.UploadMaxChunkSize = getEnvInt("UPLOAD_MAX_CHUNK_SIZE", 8)
+	defaultConfig.Drive.UploadSessionTTL = getEnvInt("UPLOAD_SESSION_TTL", 1440)
+	defaultConfig.Password.MinLength = getEnvInt("PASSWORD_MIN_LENGTH", 8)
+	defaultConfig.Password.RequireUpper = getEnvBool("PASSWORD_REQUIRE_UPPER", true)
+	defaultConfig.Password.RequireLower = getEnvBool("PASSWORD_REQUIRE_LOWER", true)
+	defaultConfig.Password.RequireNumber = getEnvBool("PASSWORD_REQUIRE_NUMBER", true)
+	defaultConfig.Password.RequireSymbol = getEnvBool("PASSWORD_REQUIRE_SYMBOL", false)
+	defaultConfig.Password.History = getEnvIntOrZero("PASSWORD_HISTORY", 5)
+	defaultConfig.Photo.CaptureMaxAge = getEnvInt("PHOTO_CAPTURE_MAX_AGE", 720)
+	defaultConfig.Photo.CaptureMaxAhead = getEnvInt("PHOTO_CAPTURE_MAX_AHEAD", 10)
+	defaultConfig.Photo.FlagMissingCaptureTime = getEnvBool("PHOTO_FLAG_MISSING_CAPTURE_TIME", false)
+	defaultConfig.Photo.HashMaxDistance = getEnvInt("PHOTO_HASH_MAX_DISTANCE", 6)
+	defaultConfig.Photo.HashMaxMatches = getEnvInt("PHOTO_HASH_MAX_MATCHES", 5)
+	defaultConfig.Photo.HashWindow = getEnvInt("PHOTO_HASH_WINDOW", 90)
+
+	if lang := i18n.Normalize(defaultConfig.App.DefaultLanguage); lang != "" {
+		i18n.Default = lang
+	}
+	if lang := i18n.Normalize(defaultConfig.App.DefaultMessageLanguage); lang != "" {
+		i18n.MessageDefault = lang
+	}
 
 	return &defaultConfig
 }
+
+func getEnvString(key string, def string) string {
+	if val := os.Getenv(key); val != "" {
+		return val
+	}
+	return def
+}
+
+// getEnvInt returns the env value of key, or def when it is empty or not a positive number.
+func getEnvInt(key string, def int) int {
+	val, err := strconv.Atoi(os.Getenv(key))
+	if err != nil || val <= 0 {
+		return def
+	}
+	return val
+}
+
+// getEnvIntOrZero is getEnvInt for the settings that 0 turns off.
+func getEnvIntOrZero(key string, def int) int {
+	val, err := strconv.Atoi(os.Getenv(key))
+	if err != nil || val < 0 {
+		return def
+	}
+	return val
+}
+
+func getEnvBool(key string, def bool) bool {
+	val, err := strconv.ParseBool(os.Getenv(key))
+	if err != nil {
+		return def
+	}
+	return val
+}
@@ -0,0 +1,30 @@
+package dto
+
+type AssignmentCreateRequest struct {
+	UserId     int    `json:"user_id" form:"user_id" validate:"required"`
+	TaskId     int    `json:"task_id" form:"task_id" validate:"required"`
+	TaskTypeId int    `json:"task_type_id" form:"task_type_id" validate:"required"`
+	AreaId     *int   `json:"area_id" form:"area_id"`
+	Floor      string `json:"floor" form:"floor" validate:"required"`
+	Info       string `json:"info" form:"info"`
+	DueAt      string `json:"due_at" form:"due_at" validate:"required,datetime=2006-01-02 15:04:05"`
+}
+
+type AssignmentFindByIDRequest struct {
+	ID int `param:"id" validate:"required"`
+}
+
+type AssignmentUpdateRequest struct {
+	ID         int     `param:"id" validate:"required"`
+	UserId     *int    `json:"user_id" form:"user_id"`
+	TaskId     *int    `json:"task_id" form:"task_id"`
+	TaskTypeId *int    `json:"task_type_id" form:"task_type_id"`
+	AreaId     *int    `json:"area_id" form:"area_id"`
+	Floor      *string `json:"floor" form:"floor"`
+	Info       *string `json:"info" form:"info"`
+	DueAt      *string `json:"due_at" form:"due_at" validate:"omitempty,datetime=2006-01-02 15:04:05"`
+}
+
+type AssignmentDeleteByIDRequest struct {
+	ID int `param:"id" validate:"required"`
+}
@@ -0,0 +1,10 @@
+package dto
+
+type AuditLogFindByIDRequest struct {
+	ID int `param:"id" validate:"required"`
+}
+
+type AuditLogExportRequest struct {
+	Format  string `query:"format" validate:"required,oneof=csv json ndjson"`
+	Columns string `query:"columns"`
+}
@@ -52,10 +52,16 @@ type AuthSendEmailForgotPasswordRequest struct {
 	Email string `json:"email" form:"email" validate:"required"`
 }
 
-type AuthValidationResetPasswordRequest struct {
+type AuthResetPasswordFormRequest struct {
 	Token string `param:"token" validate:"required"`
 }
 
+type AuthResetPasswordRequest struct {
+	Token           string `param:"token" validate:"required"`
+	NewPassword     string `json:"new_password" form:"new_password"`
+	ConfirmPassword string `json:"confirm_password" form:"confirm_password"`
+}
+
 type AuthVerifyNumberRequest struct {
 	NumberId string `json:"number_id" form:"number_id" validate:"required"`
 }
@@ -66,3 +72,11 @@ type AuthRegisterRequest struct {
 	Password *string `json:"password" form:"password"`
 	Profile  []*multipart.FileHeader
 }
+
+type AuthSendEmailVerificationRequest struct {
+	Email string `json:"email" form:"email" validate:"required"`
+}
+
+type AuthVerifyEmailRequest struct {
+	Token string `param:"token" validate:"required"`
+}
@@ -0,0 +1,9 @@
+package dto
+
+type EmailOutboxFindByIDRequest struct {
+	ID int `param:"id" validate:"required"`
+}
+
+type EmailOutboxRetryRequest struct {
+	ID int `param:"id" validate:"required"`
+}
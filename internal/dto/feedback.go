@@ -0,0 +1,11 @@
+package dto
+
+type FeedbackPublicAreaRequest struct {
+	Code string `param:"code" validate:"required,max=32"`
+}
+
+type FeedbackPublicCreateRequest struct {
+	Code    string  `param:"code" validate:"required,max=32"`
+	Rating  int     `json:"rating" form:"rating" validate:"required,min=1,max=5"`
+	Comment *string `json:"comment" form:"comment" validate:"omitempty,max=1000"`
+}
@@ -0,0 +1,58 @@
+package dto
+
+import "mime/multipart"
+
+type IncidentCreateRequest struct {
+	Category    string `json:"category" form:"category" validate:"required,oneof=fixture leak supply safety other"`
+	Severity    string `json:"severity" form:"severity" validate:"required,oneof=low medium high critical"`
+	Floor       string `json:"floor" form:"floor" validate:"required,max=100"`
+	Location    string `json:"location" form:"location" validate:"max=255"`
+	Description string `json:"description" form:"description" validate:"required"`
+	WorkId      *int   `json:"work_id" form:"work_id" validate:"omitempty,min=1"`
+	Photo       []*multipart.FileHeader
+
+	// PhotoUploadIds use completed resumable uploads instead of files in the form.
+	PhotoUploadIds []string `json:"photo_upload_ids" form:"photo_upload_ids" validate:"omitempty,dive,uuid"`
+}
+
+type IncidentFindByIDRequest struct {
+	ID int `param:"id" validate:"required"`
+}
+
+type IncidentUpdateRequest struct {
+	ID          int     `param:"id" validate:"required"`
+	Category    *string `json:"category" form:"category" validate:"omitempty,oneof=fixture leak supply safety other"`
+	Severity    *string `json:"severity" form:"severity" validate:"omitempty,oneof=low medium high critical"`
+	Floor       *string `json:"floor" form:"floor" validate:"omitempty,max=100"`
+	Location    *string `json:"location" form:"location" validate:"omitempty,max=255"`
+	Description *string `json:"description" form:"description"`
+	WorkId      *int    `json:"work_id" form:"work_id" validate:"omitempty,min=0"`
+}
+
+type IncidentStatusRequest struct {
+	ID     int     `param:"id" validate:"required"`
+	Status string  `json:"status" validate:"required,oneof=open acknowledged resolved"`
+	Note   *string `json:"note" validate:"omitempty,max=255"`
+}
+
+type IncidentAssignRequest struct {
+	ID         int  `param:"id" validate:"required"`
+	AssigneeId *int `json:"assignee_id" validate:"omitempty,min=1"`
+}
+
+type IncidentDeleteByIDRequest struct {
+	ID int `param:"id" validate:"required"`
+}
+
+type IncidentPhotoCreateRequest struct {
+	ID   int `param:"id" validate:"required"`
+	File []*multipart.FileHeader
+
+	// UploadId uses a completed resumable upload instead of a file in the form.
+	UploadId *string `json:"upload_id" form:"upload_id" validate:"omitempty,uuid"`
+}
+
+type IncidentPhotoDeleteRequest struct {
+	ID      int `param:"id" validate:"required"`
+	PhotoId int `param:"photo_id" validate:"required"`
+}
@@ -0,0 +1,57 @@
+package dto
+
+type InventoryItemCreateRequest struct {
+	Name     string  `json:"name" form:"name" validate:"required,max=100"`
+	Unit     string  `json:"unit" form:"unit" validate:"required,max=20"`
+	MinStock float64 `json:"min_stock" form:"min_stock" validate:"min=0"`
+}
+
+type InventoryItemUpdateRequest struct {
+	ID       int      `param:"id" validate:"required"`
+	Name     *string  `json:"name" form:"name" validate:"omitempty,max=100"`
+	Unit     *string  `json:"unit" form:"unit" validate:"omitempty,max=20"`
+	MinStock *float64 `json:"min_stock" form:"min_stock" validate:"omitempty,min=0"`
+}
+
+type InventoryItemDeleteByIDRequest struct {
+	ID int `param:"id" validate:"required"`
+}
+
+type InventoryLocationCreateRequest struct {
+	Name  string `json:"name" form:"name" validate:"required,max=100"`
+	Floor string `json:"floor" form:"floor" validate:"max=100"`
+}
+
+type InventoryLocationUpdateRequest struct {
+	ID    int     `param:"id" validate:"required"`
+	Name  *string `json:"name" form:"name" validate:"omitempty,max=100"`
+	Floor *string `json:"floor" form:"floor" validate:"omitempty,max=100"`
+}
+
+type InventoryLocationDeleteByIDRequest struct {
+	ID int `param:"id" validate:"required"`
+}
+
+type InventoryMovementCreateRequest struct {
+	ItemId     int    `json:"item_id" validate:"required"`
+	LocationId int    `json:"location_id" validate:"required"`
+	Type       string `json:"type" validate:"required,oneof=in out adjust"`
+
+	// Quantity is the amount taken in or out, for adjust it is the counted stock.
+	Quantity float64 `json:"quantity" validate:"min=0"`
+
+	WorkId     *int    `json:"work_id" validate:"omitempty,min=1"`
+	TaskTypeId *int    `json:"task_type_id" validate:"omitempty,min=1"`
+	Note       *string `json:"note" validate:"omitempty,max=255"`
+}
+
+type InventoryReportRequest struct {
+	Month      string `query:"month" validate:"omitempty,datetime=2006-01"`
+	LocationId int    `query:"location_id"`
+}
+
+type InventoryReportExportRequest struct {
+	Month      string `query:"month" validate:"omitempty,datetime=2006-01"`
+	LocationId int    `query:"location_id"`
+	Format     string `query:"format" validate:"required,oneof=pdf excel"`
+}
@@ -0,0 +1,11 @@
+package dto
+
+type PhotoFlagFindByIDRequest struct {
+	ID int `param:"id" validate:"required"`
+}
+
+type PhotoFlagReviewRequest struct {
+	ID     int     `param:"id" validate:"required"`
+	Status string  `json:"status" form:"status" validate:"required,oneof=open confirmed dismissed"`
+	Note   *string `json:"note" form:"note" validate:"omitempty,max=255"`
+}
@@ -0,0 +1,60 @@
+package dto
+
+import "mime/multipart"
+
+type AreaFindByIDRequest struct {
+	ID int `param:"id" validate:"required"`
+}
+
+type AreaCreateRequest struct {
+	Name     string `json:"name" form:"name" validate:"required,max=100"`
+	Floor    string `json:"floor" form:"floor" validate:"required,max=100"`
+	Location string `json:"location" form:"location" validate:"max=255"`
+}
+
+type AreaUpdateRequest struct {
+	ID       int     `param:"id" validate:"required"`
+	Name     *string `json:"name" form:"name" validate:"omitempty,max=100"`
+	Floor    *string `json:"floor" form:"floor" validate:"omitempty,max=100"`
+	Location *string `json:"location" form:"location" validate:"omitempty,max=255"`
+	IsActive *bool   `json:"is_active" form:"is_active"`
+
+	// RenewCode replaces the code of the area, the qr codes printed before stop working.
+	RenewCode bool `json:"renew_code" form:"renew_code"`
+}
+
+type AreaDeleteByIDRequest struct {
+	ID int `param:"id" validate:"required"`
+}
+
+type ServiceRequestPublicAreaRequest struct {
+	Code string `param:"code" validate:"required,max=32"`
+}
+
+type ServiceRequestPublicCreateRequest struct {
+	Code             string  `param:"code" validate:"required,max=32"`
+	Description      string  `json:"description" form:"description" validate:"required,max=1000"`
+	RequesterName    *string `json:"requester_name" form:"requester_name" validate:"omitempty,max=100"`
+	RequesterContact *string `json:"requester_contact" form:"requester_contact" validate:"omitempty,max=100"`
+	Photo            []*multipart.FileHeader
+}
+
+type ServiceRequestTrackRequest struct {
+	Token string `param:"token" validate:"required"`
+}
+
+type ServiceRequestFindByIDRequest struct {
+	ID int `param:"id" validate:"required"`
+}
+
+// ServiceRequestTriageRequest assigns the request to a staff, the assignment fields are
+// required then, or rejects it.
+type ServiceRequestTriageRequest struct {
+	ID         int     `param:"id" validate:"required"`
+	Action     string  `json:"action" validate:"required,oneof=assign reject"`
+	UserId     int     `json:"user_id" validate:"required_if=Action assign"`
+	TaskId     int     `json:"task_id" validate:"required_if=Action assign"`
+	TaskTypeId int     `json:"task_type_id" validate:"required_if=Action assign"`
+	DueAt      string  `json:"due_at" validate:"required_if=Action assign,omitempty,datetime=2006-01-02 15:04:05"`
+	Note       *string `json:"note" validate:"omitempty,max=255"`
+}
@@ -0,0 +1,25 @@
+package dto
+
+type SlaPolicyFindByIDRequest struct {
+	ID int `param:"id" validate:"required"`
+}
+
+type SlaPolicyCreateRequest struct {
+	TaskId          int  `json:"task_id" form:"task_id" validate:"required"`
+	TaskTypeId      *int `json:"task_type_id" form:"task_type_id" validate:"omitempty,min=1"`
+	StartMinutes    *int `json:"start_minutes" form:"start_minutes" validate:"omitempty,min=1"`
+	CompleteMinutes *int `json:"complete_minutes" form:"complete_minutes" validate:"omitempty,min=1"`
+	AtRiskPercent   *int `json:"at_risk_percent" form:"at_risk_percent" validate:"omitempty,min=1,max=99"`
+}
+
+// SlaPolicyUpdateRequest changes the limits of a policy, 0 removes a limit.
+type SlaPolicyUpdateRequest struct {
+	ID              int  `param:"id" validate:"required"`
+	StartMinutes    *int `json:"start_minutes" form:"start_minutes" validate:"omitempty,min=0"`
+	CompleteMinutes *int `json:"complete_minutes" form:"complete_minutes" validate:"omitempty,min=0"`
+	AtRiskPercent   *int `json:"at_risk_percent" form:"at_risk_percent" validate:"omitempty,min=1,max=99"`
+}
+
+type SlaPolicyDeleteByIDRequest struct {
+	ID int `param:"id" validate:"required"`
+}
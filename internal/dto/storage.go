@@ -0,0 +1,5 @@
+package dto
+
+type StorageGcRequest struct {
+	DryRun *bool `query:"dry_run" form:"dry_run"`
+}
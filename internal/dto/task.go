@@ -0,0 +1,28 @@
+package dto
+
+type TaskFindByIDRequest struct {
+	ID int `param:"id" validate:"required"`
+}
+
+type TaskCreateRequest struct {
+	Name                string `json:"name" form:"name" validate:"required,max=255"`
+	DashboardGroup      string `json:"dashboard_group" form:"dashboard_group" validate:"required,oneof=floor user"`
+	RequiresImageBefore bool   `json:"requires_image_before" form:"requires_image_before"`
+	RequiresImageAfter  bool   `json:"requires_image_after" form:"requires_image_after"`
+	SlaHours            *int   `json:"sla_hours" form:"sla_hours" validate:"omitempty,min=1"`
+}
+
+type TaskUpdateRequest struct {
+	ID                  int     `param:"id" validate:"required"`
+	Name                *string `json:"name" form:"name" validate:"omitempty,max=255"`
+	DashboardGroup      *string `json:"dashboard_group" form:"dashboard_group" validate:"omitempty,oneof=floor user"`
+	RequiresImageBefore *bool   `json:"requires_image_before" form:"requires_image_before"`
+	RequiresImageAfter  *bool   `json:"requires_image_after" form:"requires_image_after"`
+
+	// SlaHours of 0 removes the sla of the task.
+	SlaHours *int `json:"sla_hours" form:"sla_hours" validate:"omitempty,min=0"`
+}
+
+type TaskDeleteByIDRequest struct {
+	ID int `param:"id" validate:"required"`
+}
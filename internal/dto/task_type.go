@@ -14,3 +14,20 @@ type TaskTypeUpdateRequest struct {
 	Name   *string `json:"name" form:"name"`
 	TaskId *int    `json:"task_id" form:"task_id"`
 }
+
+type TaskTypeChecklistFindRequest struct {
+	ID int `param:"id" validate:"required"`
+}
+
+// TaskTypeChecklistUpdateRequest replaces the checklist template of the task type, items keep
+// the order of the list, an item with an id is changed and the items left out are removed.
+type TaskTypeChecklistUpdateRequest struct {
+	ID    int                             `param:"id" validate:"required"`
+	Items []*TaskTypeChecklistItemRequest `json:"items" validate:"max=50,dive"`
+}
+
+type TaskTypeChecklistItemRequest struct {
+	ID         int    `json:"id" validate:"omitempty,min=1"`
+	Name       string `json:"name" validate:"required,max=255"`
+	IsRequired bool   `json:"is_required"`
+}
@@ -0,0 +1,10 @@
+package dto
+
+type TrashFindRequest struct {
+	Entity string `param:"entity" validate:"required,oneof=user work task_type comment"`
+}
+
+type TrashByIDRequest struct {
+	Entity string `param:"entity" validate:"required,oneof=user work task_type comment"`
+	ID     int    `param:"id" validate:"required"`
+}
@@ -0,0 +1,19 @@
+package dto
+
+type UploadCreateRequest struct {
+	FileName string `json:"file_name" form:"file_name" validate:"required,max=255"`
+	Size     int64  `json:"size" form:"size" validate:"required,min=1"`
+	Checksum string `json:"checksum" form:"checksum" validate:"required,len=64,hexadecimal"`
+}
+
+type UploadByIDRequest struct {
+	ID string `param:"id" validate:"required,uuid"`
+}
+
+// UploadChunkRequest is bound by hand, the body is the raw chunk and Offset comes from
+// the Upload-Offset header.
+type UploadChunkRequest struct {
+	ID     string `param:"id" validate:"required,uuid"`
+	Offset int64  `validate:"min=0"`
+	Chunk  []byte
+}
@@ -22,6 +22,7 @@ type UserUpdateRequest struct {
 	Profile       []*multipart.FileHeader
 	DeleteProfile *bool   `json:"delete_profile" form:"delete_profile"`
 	Floor         *string `json:"floor" form:"floor"`
+	Language      *string `json:"language" form:"language" validate:"omitempty,oneof=id en"`
 }
 
 type UserDeleteByIDRequest struct {
@@ -35,5 +36,11 @@ type UserChangePasswordRequest struct {
 }
 
 type UserExportRequest struct {
-	Format string `query:"format" validate:"required"`
+	Format  string `query:"format" validate:"required"`
+	Columns string `query:"columns"`
+}
+
+type UserImportRequest struct {
+	DryRun bool `query:"dry_run" form:"dry_run"`
+	File   []*multipart.FileHeader
 }
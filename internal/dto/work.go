@@ -1,14 +1,25 @@
 package dto
 
-import "mime/multipart"
+import (
+	"mime/multipart"
+	"time"
+)
 
 type WorkCreateRequest struct {
-	TaskId      int    `json:"task_id" form:"task_id" validate:"required"`
-	TaskTypeId  int    `json:"task_type_id" form:"task_type_id" validate:"required"`
-	Floor       string `json:"floor" form:"floor" validate:"required"`
-	Info        string `json:"info" form:"info" validate:"required"`
-	ImageBefore []*multipart.FileHeader
-	ImageAfter  []*multipart.FileHeader
+	TaskId       int    `json:"task_id" form:"task_id" validate:"required"`
+	TaskTypeId   int    `json:"task_type_id" form:"task_type_id" validate:"required"`
+	Floor        string `json:"floor" form:"floor" validate:"required"`
+	Info         string `json:"info" form:"info" validate:"required"`
+	AssignmentId *int   `json:"assignment_id" form:"assignment_id"`
+	ImageBefore  []*multipart.FileHeader
+	ImageAfter   []*multipart.FileHeader
+
+	// ImageBeforeUploadId and ImageAfterUploadId use a completed resumable upload instead of a file in the form.
+	ImageBeforeUploadId *string `json:"image_before_upload_id" form:"image_before_upload_id" validate:"omitempty,uuid"`
+	ImageAfterUploadId  *string `json:"image_after_upload_id" form:"image_after_upload_id" validate:"omitempty,uuid"`
+
+	// Checklist holds the checklist item ids of the task type that are done.
+	Checklist []int `json:"checklist" form:"checklist" validate:"omitempty,dive,min=1"`
 }
 
 type WorkDeleteByIDRequest struct {
@@ -29,10 +40,17 @@ type WorkUpdateRequest struct {
 	DeleteImageBefore *string `json:"delete_image_before" form:"delete_image_before"`
 	ImageAfter        []*multipart.FileHeader
 	DeleteImageAfter  *string `json:"delete_image_after" form:"delete_image_after"`
+
+	ImageBeforeUploadId *string `json:"image_before_upload_id" form:"image_before_upload_id" validate:"omitempty,uuid"`
+	ImageAfterUploadId  *string `json:"image_after_upload_id" form:"image_after_upload_id" validate:"omitempty,uuid"`
+
+	// Checklist replaces the done checklist items of the work, nil keeps them.
+	Checklist []int `json:"checklist" form:"checklist" validate:"omitempty,dive,min=1"`
 }
 
 type WorkExportRequest struct {
-	Format string `query:"format" validate:"required"`
+	Format  string `query:"format" validate:"required"`
+	Columns string `query:"columns"`
 }
 
 type WorkDashboardAdminRequest struct {
@@ -44,3 +62,70 @@ type WorkDashboardStafRequest struct {
 	TaskId    int    `query:"task_id" validate:"required"`
 	CreatedAt string `query:"created_at" validate:"required"`
 }
+
+type WorkAnalyticsRequest struct {
+	TaskId    int    `query:"task_id"`
+	CreatedAt string `query:"created_at" validate:"required"`
+	Interval  string `query:"interval" validate:"omitempty,oneof=day week month"`
+	Top       int    `query:"top" validate:"omitempty,min=1"`
+}
+
+type WorkVerifyRequest struct {
+	ID     int     `param:"id" validate:"required"`
+	Status string  `json:"status" form:"status" validate:"required,oneof=approved rejected"`
+	Note   *string `json:"note" form:"note"`
+}
+
+type WorkScorecardLeaderboardRequest struct {
+	Month string `query:"month" validate:"omitempty,datetime=2006-01"`
+}
+
+type WorkScorecardExportRequest struct {
+	Month  string `query:"month" validate:"omitempty,datetime=2006-01"`
+	Format string `query:"format" validate:"required,oneof=pdf excel"`
+}
+
+type WorkScorecardHistoryRequest struct {
+	UserId int `param:"user_id"`
+	Months int `query:"months" validate:"omitempty,min=1,max=24"`
+}
+
+type WorkSyncRequest struct {
+	Cursor string          `json:"cursor"`
+	Items  []*WorkSyncItem `json:"items" validate:"max=200,dive"`
+}
+
+// WorkSyncItem is the full state of a work entry the app created or changed offline. BaseUpdatedAt
+// is the updated_at the app last received for it, nil for an entry the server has not seen yet.
+type WorkSyncItem struct {
+	ClientId        string     `json:"client_id" validate:"required,uuid"`
+	ClientUpdatedAt time.Time  `json:"client_updated_at" validate:"required"`
+	BaseUpdatedAt   *time.Time `json:"base_updated_at"`
+	TaskId          int        `json:"task_id" validate:"required"`
+	TaskTypeId      int        `json:"task_type_id" validate:"required"`
+	Floor           string     `json:"floor" validate:"required"`
+	Info            string     `json:"info" validate:"required"`
+	AssignmentId    *int       `json:"assignment_id"`
+}
+
+type WorkPhotoCreateRequest struct {
+	ID      int     `param:"id" validate:"required"`
+	Phase   string  `json:"phase" form:"phase" validate:"required,oneof=before after issue"`
+	Caption *string `json:"caption" form:"caption" validate:"omitempty,max=255"`
+	TakenAt *string `json:"taken_at" form:"taken_at" validate:"omitempty,datetime=2006-01-02T15:04:05Z07:00"`
+	File    []*multipart.FileHeader
+
+	// UploadId uses a completed resumable upload instead of a file in the form.
+	UploadId *string `json:"upload_id" form:"upload_id" validate:"omitempty,uuid"`
+}
+
+type WorkPhotoOrderRequest struct {
+	ID    int    `param:"id" validate:"required"`
+	Phase string `json:"phase" validate:"required,oneof=before after issue"`
+	Ids   []int  `json:"ids" validate:"required,min=1,dive,min=1"`
+}
+
+type WorkPhotoDeleteRequest struct {
+	ID      int `param:"id" validate:"required"`
+	PhotoId int `param:"photo_id" validate:"required"`
+}
@@ -29,6 +29,27 @@ type Repository_initiated struct {
 	UserRepository     repository.User
 	WorkRepository     repository.Work
 	CommentRepository  repository.Comment
+
+	AssignmentRepository      repository.Assignment
+	PasswordHistoryRepository repository.PasswordHistory
+	EmailOutboxRepository     repository.EmailOutbox
+	AuditLogRepository        repository.AuditLog
+	UploadSessionRepository   repository.UploadSession
+	WorkPhotoRepository       repository.WorkPhoto
+	PhotoFlagRepository       repository.PhotoFlag
+	IncidentRepository        repository.Incident
+
+	InventoryItemRepository         repository.InventoryItem
+	InventoryLocationRepository     repository.InventoryLocation
+	InventoryStockRepository        repository.InventoryStock
+	InventoryMovementRepository     repository.InventoryMovement
+	TaskTypeChecklistItemRepository repository.TaskTypeChecklistItem
+	WorkChecklistRepository         repository.WorkChecklist
+	SlaPolicyRepository             repository.SlaPolicy
+	AreaRepository                  repository.Area
+	ServiceRequestRepository        repository.ServiceRequest
+	FeedbackRepository              repository.Feedback
+	WorkVerificationRepository      repository.WorkVerification
 }
 
 type GoogleDrive struct {
@@ -50,6 +71,9 @@ func (f *Factory) SetupDb() {
 	if err != nil {
 		panic("Failed setup db, connection is undefined")
 	}
+	if err = repository.RegisterAuditCallbacks(db); err != nil {
+		panic("Failed setup db, audit callbacks are not registered")
+	}
 
 	// sqlDB, err := db.DB()
 	// if err != nil {
@@ -91,4 +115,23 @@ func (f *Factory) SetupRepository() {
 	f.UserRepository = repository.NewUser(f.Db)
 	f.WorkRepository = repository.NewWork(f.Db)
 	f.CommentRepository = repository.NewComment(f.Db)
+	f.AssignmentRepository = repository.NewAssignment(f.Db)
+	f.PasswordHistoryRepository = repository.NewPasswordHistory(f.Db)
+	f.EmailOutboxRepository = repository.NewEmailOutbox(f.Db)
+	f.AuditLogRepository = repository.NewAuditLog(f.Db)
+	f.UploadSessionRepository = repository.NewUploadSession(f.Db)
+	f.WorkPhotoRepository = repository.NewWorkPhoto(f.Db)
+	f.PhotoFlagRepository = repository.NewPhotoFlag(f.Db)
+	f.IncidentRepository = repository.NewIncident(f.Db)
+	f.InventoryItemRepository = repository.NewInventoryItem(f.Db)
+	f.InventoryLocationRepository = repository.NewInventoryLocation(f.Db)
+	f.InventoryStockRepository = repository.NewInventoryStock(f.Db)
+	f.InventoryMovementRepository = repository.NewInventoryMovement(f.Db)
+	f.TaskTypeChecklistItemRepository = repository.NewTaskTypeChecklistItem(f.Db)
+	f.WorkChecklistRepository = repository.NewWorkChecklist(f.Db)
+	f.SlaPolicyRepository = repository.NewSlaPolicy(f.Db)
+	f.AreaRepository = repository.NewArea(f.Db)
+	f.ServiceRequestRepository = repository.NewServiceRequest(f.Db)
+	f.FeedbackRepository = repository.NewFeedback(f.Db)
+	f.WorkVerificationRepository = repository.NewWorkVerification(f.Db)
 }
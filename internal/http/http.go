@@ -4,10 +4,20 @@ import (
 	"fmt"
 	"net/http"
 
+	"cleancare/internal/app/audit"
 	"cleancare/internal/app/auth"
+	"cleancare/internal/app/email"
+	"cleancare/internal/app/feedback"
+	"cleancare/internal/app/incident"
+	"cleancare/internal/app/inventory"
 	"cleancare/internal/app/role"
+	"cleancare/internal/app/servicerequest"
+	"cleancare/internal/app/sla"
+	"cleancare/internal/app/storage"
 	"cleancare/internal/app/task"
 	"cleancare/internal/app/test"
+	"cleancare/internal/app/trash"
+	"cleancare/internal/app/upload"
 	"cleancare/internal/app/user"
 	"cleancare/internal/app/work"
 	"cleancare/internal/config"
@@ -37,4 +47,14 @@ func Init(e *echo.Echo, f *factory.Factory) {
 	task.NewHandler(f).Route(e.Group("/task"))
 	user.NewHandler(f).Route(e.Group("/user"))
 	work.NewHandler(f).Route(e.Group("/work"))
+	email.NewHandler(f).Route(e.Group("/email"))
+	audit.NewHandler(f).Route(e.Group("/audit"))
+	trash.NewHandler(f).Route(e.Group("/trash"))
+	storage.NewHandler(f).Route(e.Group("/storage"))
+	upload.NewHandler(f).Route(e.Group("/upload"))
+	incident.NewHandler(f).Route(e.Group("/incident"))
+	inventory.NewHandler(f).Route(e.Group("/inventory"))
+	sla.NewHandler(f).Route(e.Group("/sla"))
+	servicerequest.NewHandler(f).Route(e.Group("/service-request"))
+	feedback.NewHandler(f).Route(e.Group("/feedback"))
 }
@@ -110,12 +110,15 @@ func Authentication(next echo.HandlerFunc) echo.HandlerFunc {
 			return response.ErrorBuilder(http.StatusUnprocessableEntity, errors.New("unprocessable"), "expired_token").SendError(c)
 		}
 
+		language, _ := dbRedis.Get(context.Background(), fmt.Sprintf(constant.REDIS_KEY_USER_LANGUAGE, id)).Result()
+
 		cc := c.(*abstraction.Context)
 		cc.Auth = &abstraction.AuthContext{
 			ID:        id,
 			RoleID:    role_id,
 			Email:     email,
 			UuidLogin: uuid_login,
+			Language:  language,
 		}
 
 		return next(cc)
@@ -0,0 +1,121 @@
+package middleware
+
+import (
+	"bytes"
+	"cleancare/internal/abstraction"
+	"cleancare/internal/config"
+	"cleancare/pkg/constant"
+	"cleancare/pkg/util/response"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+	"github.com/labstack/echo/v4"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	HeaderIdempotencyKey      = "Idempotency-Key"
+	HeaderIdempotentReplayed  = "Idempotent-Replayed"
+	idempotencyKeyMaxLength   = 128
+	idempotencyStateRunning   = "processing"
+	idempotencyStateCompleted = "completed"
+)
+
+type idempotencyRecord struct {
+	State       string `json:"state"`
+	Status      int    `json:"status"`
+	ContentType string `json:"content_type"`
+	Body        []byte `json:"body"`
+}
+
+type idempotencyRecorder struct {
+	http.ResponseWriter
+	body *bytes.Buffer
+}
+
+func (w *idempotencyRecorder) Write(b []byte) (int, error) {
+	w.body.Write(b)
+	return w.ResponseWriter.Write(b)
+}
+
+// Idempotency replays the stored response of a request carrying an Idempotency-Key header that
+// was already handled for the same user, method and path, and rejects a duplicate sent while the
+// first one is still running. Only successful responses are kept, so a failed request can be
+// retried with the same key. It must be placed after Authentication.
+func Idempotency(next echo.HandlerFunc) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		idempotencyKey := c.Request().Header.Get(HeaderIdempotencyKey)
+		if idempotencyKey == "" || dbRedis == nil {
+			return next(c)
+		}
+		if len(idempotencyKey) > idempotencyKeyMaxLength {
+			return response.ErrorBuilder(http.StatusBadRequest, errors.New("bad_request"), "invalid idempotency key").SendError(c)
+		}
+
+		cc := c.(*abstraction.Context)
+		var userId int
+		if cc.Auth != nil {
+			userId = cc.Auth.ID
+		}
+		key := fmt.Sprintf(constant.REDIS_KEY_IDEMPOTENCY, userId, c.Request().Method, c.Request().URL.Path, idempotencyKey)
+		// the client may hang up mid request, the result must still be stored for its retry
+		ctx := context.Background()
+
+		running, _ := json.Marshal(idempotencyRecord{State: idempotencyStateRunning})
+		acquired, err := dbRedis.SetNX(ctx, key, running, time.Duration(config.Get().App.IdempotencyLockTTL)*time.Minute).Result()
+		if err != nil {
+			// redis is down, handle the request as if no key was sent
+			logrus.Error("error acquire idempotency key:", err.Error())
+			return next(c)
+		}
+		if !acquired {
+			return replayIdempotent(c, key)
+		}
+
+		recorder := &idempotencyRecorder{ResponseWriter: c.Response().Writer, body: new(bytes.Buffer)}
+		c.Response().Writer = recorder
+
+		if err = next(c); err != nil {
+			dbRedis.Del(ctx, key)
+			return err
+		}
+
+		status := c.Response().Status
+		if status < http.StatusOK || status >= http.StatusMultipleChoices {
+			dbRedis.Del(ctx, key)
+			return nil
+		}
+
+		completed, _ := json.Marshal(idempotencyRecord{
+			State:       idempotencyStateCompleted,
+			Status:      status,
+			ContentType: c.Response().Header().Get(echo.HeaderContentType),
+			Body:        recorder.body.Bytes(),
+		})
+		if err := dbRedis.Set(ctx, key, completed, time.Duration(config.Get().App.IdempotencyKeyTTL)*time.Minute).Err(); err != nil {
+			logrus.Error("error store idempotency response:", err.Error())
+		}
+		return nil
+	}
+}
+
+func replayIdempotent(c echo.Context, key string) error {
+	val, err := dbRedis.Get(c.Request().Context(), key).Bytes()
+	if err != nil && err != redis.Nil {
+		return response.ErrorBuilder(http.StatusInternalServerError, err, "server_error").SendError(c)
+	}
+
+	var record idempotencyRecord
+	if err == redis.Nil || json.Unmarshal(val, &record) != nil || record.State != idempotencyStateCompleted {
+		return response.ErrorBuilder(http.StatusConflict, errors.New("conflict"), "a request with the same idempotency key is still in progress").SendError(c)
+	}
+
+	c.Response().Header().Set(HeaderIdempotentReplayed, strconv.FormatBool(true))
+	return c.Blob(record.Status, record.ContentType, record.Body)
+}
@@ -0,0 +1,57 @@
+package model
+
+import (
+	"cleancare/internal/abstraction"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type AssignmentEntity struct {
+	UserId     int       `json:"user_id"`
+	TaskId     int       `json:"task_id"`
+	TaskTypeId int       `json:"task_type_id"`
+	AreaId     *int      `json:"area_id"`
+	Floor      string    `json:"floor"`
+	Info       string    `json:"info"`
+	DueAt      time.Time `json:"due_at"`
+	WorkId     *int      `json:"work_id"`
+	IsDelete   bool      `json:"is_delete"`
+}
+
+// AssignmentEntityModel ...
+type AssignmentEntityModel struct {
+	ID int `json:"id" param:"id" form:"id" validate:"number,min=1" gorm:"primaryKey;autoIncrement;"`
+
+	// entity
+	AssignmentEntity
+
+	abstraction.EntityWithBy
+
+	User     UserEntityModel     `json:"user" gorm:"foreignKey:UserId"`
+	Task     TaskEntityModel     `json:"task" gorm:"foreignKey:TaskId"`
+	TaskType TaskTypeEntityModel `json:"task_type" gorm:"foreignKey:TaskTypeId"`
+	Work     *WorkEntityModel    `json:"work,omitempty" gorm:"foreignKey:WorkId"`
+
+	// context
+	Context *abstraction.Context `json:"-" gorm:"-"`
+}
+
+// TableName ...
+func (AssignmentEntityModel) TableName() string {
+	return "assignment"
+}
+
+type AssignmentCountDataModel struct {
+	Count int `json:"count"`
+}
+
+func (m *AssignmentEntityModel) BeforeUpdate(tx *gorm.DB) (err error) {
+	m.UpdatedBy = &m.Context.Auth.ID
+	return
+}
+
+func (m *AssignmentEntityModel) BeforeCreate(tx *gorm.DB) (err error) {
+	m.CreatedBy = m.Context.Auth.ID
+	return
+}
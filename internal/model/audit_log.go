@@ -0,0 +1,40 @@
+package model
+
+import (
+	"cleancare/internal/abstraction"
+)
+
+type AuditLogEntity struct {
+	ActorId   *int    `json:"actor_id"`
+	Action    string  `json:"action"`
+	Entity    string  `json:"entity"`
+	EntityId  *int    `json:"entity_id"`
+	OldValues *string `json:"old_values"`
+	NewValues *string `json:"new_values"`
+	Ip        *string `json:"ip"`
+	RequestId *string `json:"request_id"`
+}
+
+// AuditLogEntityModel ...
+type AuditLogEntityModel struct {
+	ID int `json:"id" param:"id" form:"id" validate:"number,min=1" gorm:"primaryKey;autoIncrement;"`
+
+	// entity
+	AuditLogEntity
+
+	abstraction.EntityJustCreated
+
+	Actor *UserEntityModel `json:"actor" gorm:"foreignKey:ActorId"`
+
+	// context
+	Context *abstraction.Context `json:"-" gorm:"-"`
+}
+
+// TableName ...
+func (AuditLogEntityModel) TableName() string {
+	return "audit_log"
+}
+
+type AuditLogCountDataModel struct {
+	Count int `json:"count"`
+}
@@ -2,14 +2,16 @@ package model
 
 import (
 	"cleancare/internal/abstraction"
+	"time"
 
 	"gorm.io/gorm"
 )
 
 type CommentEntity struct {
-	WorkId   int    `json:"work_id"`
-	Comment  string `json:"comment"`
-	IsDelete bool   `json:"is_delete"`
+	WorkId    int        `json:"work_id"`
+	Comment   string     `json:"comment"`
+	IsDelete  bool       `json:"is_delete"`
+	DeletedAt *time.Time `json:"deleted_at"`
 }
 
 // CommentEntityModel ...
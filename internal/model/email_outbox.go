@@ -0,0 +1,59 @@
+package model
+
+import (
+	"cleancare/internal/abstraction"
+	"cleancare/pkg/constant"
+	"time"
+)
+
+type EmailOutboxEntity struct {
+	Recipient     string     `json:"recipient"`
+	Subject       string     `json:"subject"`
+	Template      string     `json:"template"`
+	Lang          string     `json:"lang"`
+	Body          string     `json:"body"`
+	Status        string     `json:"status"`
+	Attempts      int        `json:"attempts"`
+	NextAttemptAt time.Time  `json:"next_attempt_at"`
+	LastError     *string    `json:"last_error"`
+	SentAt        *time.Time `json:"sent_at"`
+}
+
+// EmailOutboxEntityModel ...
+type EmailOutboxEntityModel struct {
+	ID int `json:"id" param:"id" form:"id" validate:"number,min=1" gorm:"primaryKey;autoIncrement;"`
+
+	// entity
+	EmailOutboxEntity
+
+	abstraction.Entity
+
+	// context
+	Context *abstraction.Context `json:"-" gorm:"-"`
+}
+
+// TableName ...
+func (EmailOutboxEntityModel) TableName() string {
+	return "email_outbox"
+}
+
+type EmailOutboxCountDataModel struct {
+	Count int `json:"count"`
+}
+
+// NewEmailOutbox queues an already rendered email, it is sent by the outbox worker once
+// the transaction that created it is committed.
+func NewEmailOutbox(ctx *abstraction.Context, recipient, template, lang, subject, body string) *EmailOutboxEntityModel {
+	return &EmailOutboxEntityModel{
+		Context: ctx,
+		EmailOutboxEntity: EmailOutboxEntity{
+			Recipient:     recipient,
+			Subject:       subject,
+			Template:      template,
+			Lang:          lang,
+			Body:          body,
+			Status:        constant.EMAIL_STATUS_PENDING,
+			NextAttemptAt: time.Now(),
+		},
+	}
+}
@@ -0,0 +1,57 @@
+package model
+
+import (
+	"cleancare/internal/abstraction"
+)
+
+type FeedbackEntity struct {
+	AreaId int `json:"area_id"`
+
+	// WorkId is the last work completed for the area when the feedback was sent, UserId and
+	// Floor are copied so the scores do not move when the work or the area is changed.
+	WorkId int    `json:"work_id"`
+	UserId int    `json:"user_id"`
+	Floor  string `json:"floor"`
+
+	Rating  int     `json:"rating"`
+	Comment *string `json:"comment"`
+	Ip      string  `json:"ip"`
+}
+
+// FeedbackEntityModel is sent by an occupant without an account, so it has no created_by.
+type FeedbackEntityModel struct {
+	ID int `json:"id" param:"id" form:"id" validate:"number,min=1" gorm:"primaryKey;autoIncrement;"`
+
+	// entity
+	FeedbackEntity
+
+	abstraction.Entity
+
+	Area AreaEntityModel `json:"area" gorm:"foreignKey:AreaId"`
+	User UserEntityModel `json:"user" gorm:"foreignKey:UserId"`
+
+	// context
+	Context *abstraction.Context `json:"-" gorm:"-"`
+}
+
+// TableName ...
+func (FeedbackEntityModel) TableName() string {
+	return "feedback"
+}
+
+type FeedbackCountDataModel struct {
+	Count int `json:"count"`
+}
+
+type FloorSatisfaction struct {
+	Floor   string  `json:"floor"`
+	Count   int     `json:"count"`
+	Average float64 `json:"average"`
+}
+
+type UserSatisfaction struct {
+	UserId  int     `json:"user_id"`
+	Name    string  `json:"name"`
+	Count   int     `json:"count"`
+	Average float64 `json:"average"`
+}
@@ -0,0 +1,107 @@
+package model
+
+import (
+	"cleancare/internal/abstraction"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type IncidentEntity struct {
+	Category    string `json:"category"`
+	Severity    string `json:"severity"`
+	Floor       string `json:"floor"`
+	Location    string `json:"location"`
+	Description string `json:"description"`
+
+	// WorkId is the work entry the incident was found during, if any.
+	WorkId     *int `json:"work_id"`
+	AssigneeId *int `json:"assignee_id"`
+
+	Status         string     `json:"status"`
+	AcknowledgedBy *int       `json:"acknowledged_by"`
+	AcknowledgedAt *time.Time `json:"acknowledged_at"`
+	ResolvedBy     *int       `json:"resolved_by"`
+	ResolvedAt     *time.Time `json:"resolved_at"`
+	ResolutionNote *string    `json:"resolution_note"`
+
+	IsDelete  bool       `json:"is_delete"`
+	DeletedAt *time.Time `json:"deleted_at"`
+}
+
+// IncidentEntityModel ...
+type IncidentEntityModel struct {
+	ID int `json:"id" param:"id" form:"id" validate:"number,min=1" gorm:"primaryKey;autoIncrement;"`
+
+	// entity
+	IncidentEntity
+
+	abstraction.EntityWithBy
+
+	Reporter UserEntityModel            `json:"reporter" gorm:"foreignKey:CreatedBy"`
+	Assignee *UserEntityModel           `json:"assignee" gorm:"foreignKey:AssigneeId"`
+	Photos   []IncidentPhotoEntityModel `json:"photos" gorm:"foreignKey:IncidentId"`
+
+	// context
+	Context *abstraction.Context `json:"-" gorm:"-"`
+}
+
+// TableName ...
+func (IncidentEntityModel) TableName() string {
+	return "incident"
+}
+
+type IncidentCountDataModel struct {
+	Count int `json:"count"`
+}
+
+func (m *IncidentEntityModel) BeforeUpdate(tx *gorm.DB) (err error) {
+	m.UpdatedBy = &m.Context.Auth.ID
+	return
+}
+
+func (m *IncidentEntityModel) BeforeCreate(tx *gorm.DB) (err error) {
+	m.CreatedBy = m.Context.Auth.ID
+	return
+}
+
+type IncidentPhotoEntity struct {
+	IncidentId int    `json:"incident_id"`
+	StorageKey string `json:"storage_key"`
+	FileName   string `json:"file_name"`
+	SortOrder  int    `json:"sort_order"`
+}
+
+// IncidentPhotoEntityModel ...
+type IncidentPhotoEntityModel struct {
+	ID int `json:"id" param:"id" form:"id" validate:"number,min=1" gorm:"primaryKey;autoIncrement;"`
+
+	// entity
+	IncidentPhotoEntity
+
+	abstraction.EntityWithBy
+
+	// context
+	Context *abstraction.Context `json:"-" gorm:"-"`
+}
+
+// TableName ...
+func (IncidentPhotoEntityModel) TableName() string {
+	return "incident_photo"
+}
+
+func (m *IncidentPhotoEntityModel) BeforeUpdate(tx *gorm.DB) (err error) {
+	m.UpdatedBy = &m.Context.Auth.ID
+	return
+}
+
+func (m *IncidentPhotoEntityModel) BeforeCreate(tx *gorm.DB) (err error) {
+	m.CreatedBy = m.Context.Auth.ID
+	return
+}
+
+type IncidentPhotoFileReference struct {
+	ID         int    `json:"id"`
+	IncidentId int    `json:"incident_id"`
+	StorageKey string `json:"storage_key"`
+}
@@ -0,0 +1,182 @@
+package model
+
+import (
+	"cleancare/internal/abstraction"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type InventoryItemEntity struct {
+	Name string `json:"name"`
+	Unit string `json:"unit"`
+
+	// MinStock is the low stock threshold of the item at every location, 0 turns it off.
+	MinStock float64 `json:"min_stock"`
+
+	IsDelete  bool       `json:"is_delete"`
+	DeletedAt *time.Time `json:"deleted_at"`
+}
+
+// InventoryItemEntityModel ...
+type InventoryItemEntityModel struct {
+	ID int `json:"id" param:"id" form:"id" validate:"number,min=1" gorm:"primaryKey;autoIncrement;"`
+
+	// entity
+	InventoryItemEntity
+
+	abstraction.EntityWithBy
+
+	// context
+	Context *abstraction.Context `json:"-" gorm:"-"`
+}
+
+// TableName ...
+func (InventoryItemEntityModel) TableName() string {
+	return "inventory_item"
+}
+
+type InventoryItemCountDataModel struct {
+	Count int `json:"count"`
+}
+
+func (m *InventoryItemEntityModel) BeforeUpdate(tx *gorm.DB) (err error) {
+	m.UpdatedBy = &m.Context.Auth.ID
+	return
+}
+
+func (m *InventoryItemEntityModel) BeforeCreate(tx *gorm.DB) (err error) {
+	m.CreatedBy = m.Context.Auth.ID
+	return
+}
+
+type InventoryLocationEntity struct {
+	Name      string     `json:"name"`
+	Floor     string     `json:"floor"`
+	IsDelete  bool       `json:"is_delete"`
+	DeletedAt *time.Time `json:"deleted_at"`
+}
+
+// InventoryLocationEntityModel ...
+type InventoryLocationEntityModel struct {
+	ID int `json:"id" param:"id" form:"id" validate:"number,min=1" gorm:"primaryKey;autoIncrement;"`
+
+	// entity
+	InventoryLocationEntity
+
+	abstraction.EntityWithBy
+
+	// context
+	Context *abstraction.Context `json:"-" gorm:"-"`
+}
+
+// TableName ...
+func (InventoryLocationEntityModel) TableName() string {
+	return "inventory_location"
+}
+
+type InventoryLocationCountDataModel struct {
+	Count int `json:"count"`
+}
+
+func (m *InventoryLocationEntityModel) BeforeUpdate(tx *gorm.DB) (err error) {
+	m.UpdatedBy = &m.Context.Auth.ID
+	return
+}
+
+func (m *InventoryLocationEntityModel) BeforeCreate(tx *gorm.DB) (err error) {
+	m.CreatedBy = m.Context.Auth.ID
+	return
+}
+
+type InventoryStockEntity struct {
+	ItemId     int     `json:"item_id"`
+	LocationId int     `json:"location_id"`
+	Quantity   float64 `json:"quantity"`
+}
+
+// InventoryStockEntityModel ...
+type InventoryStockEntityModel struct {
+	ID int `json:"id" param:"id" form:"id" validate:"number,min=1" gorm:"primaryKey;autoIncrement;"`
+
+	// entity
+	InventoryStockEntity
+
+	abstraction.Entity
+
+	Item     InventoryItemEntityModel     `json:"item" gorm:"foreignKey:ItemId"`
+	Location InventoryLocationEntityModel `json:"location" gorm:"foreignKey:LocationId"`
+
+	// context
+	Context *abstraction.Context `json:"-" gorm:"-"`
+}
+
+// TableName ...
+func (InventoryStockEntityModel) TableName() string {
+	return "inventory_stock"
+}
+
+type InventoryStockCountDataModel struct {
+	Count int `json:"count"`
+}
+
+type InventoryMovementEntity struct {
+	ItemId     int    `json:"item_id"`
+	LocationId int    `json:"location_id"`
+	Type       string `json:"type"`
+
+	// Quantity is signed, StockAfter is the stock of the item at the location after it.
+	Quantity   float64 `json:"quantity"`
+	StockAfter float64 `json:"stock_after"`
+
+	WorkId     *int    `json:"work_id"`
+	TaskTypeId *int    `json:"task_type_id"`
+	Note       *string `json:"note"`
+}
+
+// InventoryMovementEntityModel ...
+type InventoryMovementEntityModel struct {
+	ID int `json:"id" param:"id" form:"id" validate:"number,min=1" gorm:"primaryKey;autoIncrement;"`
+
+	// entity
+	InventoryMovementEntity
+
+	abstraction.EntityWithBy
+
+	Item     InventoryItemEntityModel     `json:"item" gorm:"foreignKey:ItemId"`
+	Location InventoryLocationEntityModel `json:"location" gorm:"foreignKey:LocationId"`
+	User     UserEntityModel              `json:"user" gorm:"foreignKey:CreatedBy"`
+	TaskType *TaskTypeEntityModel         `json:"task_type" gorm:"foreignKey:TaskTypeId"`
+
+	// context
+	Context *abstraction.Context `json:"-" gorm:"-"`
+}
+
+// TableName ...
+func (InventoryMovementEntityModel) TableName() string {
+	return "inventory_movement"
+}
+
+type InventoryMovementCountDataModel struct {
+	Count int `json:"count"`
+}
+
+func (m *InventoryMovementEntityModel) BeforeUpdate(tx *gorm.DB) (err error) {
+	m.UpdatedBy = &m.Context.Auth.ID
+	return
+}
+
+func (m *InventoryMovementEntityModel) BeforeCreate(tx *gorm.DB) (err error) {
+	m.CreatedBy = m.Context.Auth.ID
+	return
+}
+
+type InventoryConsumption struct {
+	ItemId   int     `json:"item_id"`
+	Name     string  `json:"name"`
+	Unit     string  `json:"unit"`
+	StockIn  float64 `json:"stock_in"`
+	StockOut float64 `json:"stock_out"`
+	Adjusted float64 `json:"adjusted"`
+	Stock    float64 `json:"stock"`
+}
@@ -0,0 +1,28 @@
+package model
+
+import (
+	"cleancare/internal/abstraction"
+)
+
+type PasswordHistoryEntity struct {
+	UserId   int    `json:"user_id"`
+	Password string `json:"-"`
+}
+
+// PasswordHistoryEntityModel ...
+type PasswordHistoryEntityModel struct {
+	ID int `json:"id" param:"id" form:"id" validate:"number,min=1" gorm:"primaryKey;autoIncrement;"`
+
+	// entity
+	PasswordHistoryEntity
+
+	abstraction.EntityJustCreated
+
+	// context
+	Context *abstraction.Context `json:"-" gorm:"-"`
+}
+
+// TableName ...
+func (PasswordHistoryEntityModel) TableName() string {
+	return "password_history"
+}
@@ -0,0 +1,61 @@
+package model
+
+import (
+	"cleancare/internal/abstraction"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type PhotoFlagEntity struct {
+	WorkId      int        `json:"work_id"`
+	WorkPhotoId int        `json:"work_photo_id"`
+	Type        string     `json:"type"`
+	CapturedAt  *time.Time `json:"captured_at"`
+	SubmittedAt time.Time  `json:"submitted_at"`
+
+	// MatchWorkId, MatchWorkPhotoId and Distance are set for a duplicate, the photo it matches.
+	MatchWorkId      *int `json:"match_work_id"`
+	MatchWorkPhotoId *int `json:"match_work_photo_id"`
+	Distance         *int `json:"distance"`
+
+	Status     string     `json:"status"`
+	ReviewNote *string    `json:"review_note"`
+	ReviewedBy *int       `json:"reviewed_by"`
+	ReviewedAt *time.Time `json:"reviewed_at"`
+}
+
+// PhotoFlagEntityModel ...
+type PhotoFlagEntityModel struct {
+	ID int `json:"id" param:"id" form:"id" validate:"number,min=1" gorm:"primaryKey;autoIncrement;"`
+
+	// entity
+	PhotoFlagEntity
+
+	abstraction.Entity
+
+	Work      WorkEntityModel      `json:"work" gorm:"foreignKey:WorkId"`
+	WorkPhoto WorkPhotoEntityModel `json:"work_photo" gorm:"foreignKey:WorkPhotoId"`
+
+	MatchWorkPhoto *WorkPhotoEntityModel `json:"match_work_photo" gorm:"foreignKey:MatchWorkPhotoId"`
+
+	// context
+	Context *abstraction.Context `json:"-" gorm:"-"`
+}
+
+// TableName ...
+func (PhotoFlagEntityModel) TableName() string {
+	return "photo_flag"
+}
+
+type PhotoFlagCountDataModel struct {
+	Count int `json:"count"`
+}
+
+func (m *PhotoFlagEntityModel) BeforeUpdate(tx *gorm.DB) (err error) {
+	return
+}
+
+func (m *PhotoFlagEntityModel) BeforeCreate(tx *gorm.DB) (err error) {
+	return
+}
@@ -0,0 +1,99 @@
+package model
+
+import (
+	"cleancare/internal/abstraction"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type AreaEntity struct {
+	Name     string `json:"name"`
+	Floor    string `json:"floor"`
+	Location string `json:"location"`
+
+	// Code identifies the area in the public request link, it is printed as a qr code.
+	Code     string `json:"code"`
+	IsActive bool   `json:"is_active"`
+
+	IsDelete  bool       `json:"is_delete"`
+	DeletedAt *time.Time `json:"deleted_at"`
+}
+
+// AreaEntityModel ...
+type AreaEntityModel struct {
+	ID int `json:"id" param:"id" form:"id" validate:"number,min=1" gorm:"primaryKey;autoIncrement;"`
+
+	// entity
+	AreaEntity
+
+	abstraction.EntityWithBy
+
+	// context
+	Context *abstraction.Context `json:"-" gorm:"-"`
+}
+
+// TableName ...
+func (AreaEntityModel) TableName() string {
+	return "area"
+}
+
+type AreaCountDataModel struct {
+	Count int `json:"count"`
+}
+
+func (m *AreaEntityModel) BeforeUpdate(tx *gorm.DB) (err error) {
+	m.UpdatedBy = &m.Context.Auth.ID
+	return
+}
+
+func (m *AreaEntityModel) BeforeCreate(tx *gorm.DB) (err error) {
+	m.CreatedBy = m.Context.Auth.ID
+	return
+}
+
+type ServiceRequestEntity struct {
+	AreaId           int     `json:"area_id"`
+	Description      string  `json:"description"`
+	RequesterName    *string `json:"requester_name"`
+	RequesterContact *string `json:"requester_contact"`
+	PhotoStorageKey  *string `json:"photo_storage_key"`
+	PhotoFileName    *string `json:"photo_file_name"`
+	Ip               string  `json:"ip"`
+
+	Status       string     `json:"status"`
+	AssignmentId *int       `json:"assignment_id"`
+	TriageNote   *string    `json:"triage_note"`
+	TriagedBy    *int       `json:"triaged_by"`
+	TriagedAt    *time.Time `json:"triaged_at"`
+}
+
+// ServiceRequestEntityModel is sent by an occupant without an account, so it has no created_by.
+type ServiceRequestEntityModel struct {
+	ID int `json:"id" param:"id" form:"id" validate:"number,min=1" gorm:"primaryKey;autoIncrement;"`
+
+	// entity
+	ServiceRequestEntity
+
+	abstraction.Entity
+
+	Area       AreaEntityModel        `json:"area" gorm:"foreignKey:AreaId"`
+	Assignment *AssignmentEntityModel `json:"assignment" gorm:"foreignKey:AssignmentId"`
+
+	// context
+	Context *abstraction.Context `json:"-" gorm:"-"`
+}
+
+// TableName ...
+func (ServiceRequestEntityModel) TableName() string {
+	return "service_request"
+}
+
+type ServiceRequestCountDataModel struct {
+	Count int `json:"count"`
+}
+
+type ServiceRequestFileReference struct {
+	ID              int    `json:"id"`
+	PhotoStorageKey string `json:"photo_storage_key"`
+}
@@ -0,0 +1,140 @@
+package model
+
+import (
+	"cleancare/internal/abstraction"
+	"cleancare/pkg/constant"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type SlaPolicyEntity struct {
+	TaskId     int  `json:"task_id"`
+	TaskTypeId *int `json:"task_type_id"`
+
+	// StartMinutes and CompleteMinutes count from the creation of the work, nil has no limit.
+	StartMinutes    *int `json:"start_minutes"`
+	CompleteMinutes *int `json:"complete_minutes"`
+
+	// AtRiskPercent is how much of its time a step may use before the work is at risk.
+	AtRiskPercent int `json:"at_risk_percent"`
+
+	IsDelete  bool       `json:"is_delete"`
+	DeletedAt *time.Time `json:"deleted_at"`
+}
+
+// SlaPolicyEntityModel ...
+type SlaPolicyEntityModel struct {
+	ID int `json:"id" param:"id" form:"id" validate:"number,min=1" gorm:"primaryKey;autoIncrement;"`
+
+	// entity
+	SlaPolicyEntity
+
+	abstraction.EntityWithBy
+
+	Task     TaskEntityModel      `json:"task" gorm:"foreignKey:TaskId"`
+	TaskType *TaskTypeEntityModel `json:"task_type" gorm:"foreignKey:TaskTypeId"`
+
+	// context
+	Context *abstraction.Context `json:"-" gorm:"-"`
+}
+
+// TableName ...
+func (SlaPolicyEntityModel) TableName() string {
+	return "sla_policy"
+}
+
+type SlaPolicyCountDataModel struct {
+	Count int `json:"count"`
+}
+
+func (m *SlaPolicyEntityModel) BeforeUpdate(tx *gorm.DB) (err error) {
+	m.UpdatedBy = &m.Context.Auth.ID
+	return
+}
+
+func (m *SlaPolicyEntityModel) BeforeCreate(tx *gorm.DB) (err error) {
+	m.CreatedBy = m.Context.Auth.ID
+	return
+}
+
+// SlaDue returns when a work created at from has to be started and completed under the policy,
+// a task without a policy only has its sla_hours to be completed in. The status is nil when the
+// work has no sla at all.
+func SlaDue(policy *SlaPolicyEntityModel, task *TaskEntityModel, from time.Time) (policyId *int, start, complete *time.Time, status *string) {
+	if policy != nil {
+		policyId = &policy.ID
+		if policy.StartMinutes != nil {
+			t := from.Add(time.Duration(*policy.StartMinutes) * time.Minute)
+			start = &t
+		}
+		if policy.CompleteMinutes != nil {
+			t := from.Add(time.Duration(*policy.CompleteMinutes) * time.Minute)
+			complete = &t
+		}
+	} else if task != nil && task.SlaHours != nil {
+		t := from.Add(time.Duration(*task.SlaHours) * time.Hour)
+		complete = &t
+	}
+	if start != nil || complete != nil {
+		s := constant.SLA_STATUS_ON_TRACK
+		status = &s
+	}
+	return
+}
+
+// WorkSlaCheck is a work with an open sla as the sla checker reads it. StartedAt is when the
+// first before photo was added.
+type WorkSlaCheck struct {
+	ID               int        `json:"id"`
+	UserId           int        `json:"user_id"`
+	UserName         string     `json:"user_name"`
+	TaskName         string     `json:"task_name"`
+	TaskTypeName     string     `json:"task_type_name"`
+	Floor            string     `json:"floor"`
+	CreatedAt        time.Time  `json:"created_at"`
+	StartedAt        *time.Time `json:"started_at"`
+	CompletedAt      *time.Time `json:"completed_at"`
+	SlaPolicyId      *int       `json:"sla_policy_id"`
+	SlaStartDueAt    *time.Time `json:"sla_start_due_at"`
+	SlaCompleteDueAt *time.Time `json:"sla_complete_due_at"`
+	SlaStatus        string     `json:"sla_status"`
+}
+
+// Evaluate returns the sla status of the work at now. A completed work counts as started.
+func (w *WorkSlaCheck) Evaluate(now time.Time, at_risk_percent int) string {
+	startedAt := w.StartedAt
+	if startedAt == nil {
+		startedAt = w.CompletedAt
+	}
+
+	late := func(done, due *time.Time) bool {
+		return due != nil && ((done == nil && now.After(*due)) || (done != nil && done.After(*due)))
+	}
+	if late(startedAt, w.SlaStartDueAt) || late(w.CompletedAt, w.SlaCompleteDueAt) {
+		return constant.SLA_STATUS_BREACHED
+	}
+	if (w.SlaStartDueAt == nil || startedAt != nil) && (w.SlaCompleteDueAt == nil || w.CompletedAt != nil) {
+		return constant.SLA_STATUS_MET
+	}
+
+	atRisk := func(done, due *time.Time) bool {
+		if due == nil || done != nil {
+			return false
+		}
+		span := due.Sub(w.CreatedAt) * time.Duration(at_risk_percent) / 100
+		return !now.Before(w.CreatedAt.Add(span))
+	}
+	if atRisk(startedAt, w.SlaStartDueAt) || atRisk(w.CompletedAt, w.SlaCompleteDueAt) {
+		return constant.SLA_STATUS_AT_RISK
+	}
+	return constant.SLA_STATUS_ON_TRACK
+}
+
+// WorkSlaSummary counts the works of a period by sla status.
+type WorkSlaSummary struct {
+	OnTrack  int `json:"on_track"`
+	AtRisk   int `json:"at_risk"`
+	Breached int `json:"breached"`
+	Met      int `json:"met"`
+}
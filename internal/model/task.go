@@ -1,9 +1,23 @@
 package model
 
-import "cleancare/internal/abstraction"
+import (
+	"cleancare/internal/abstraction"
+	"time"
+)
 
 type TaskEntity struct {
 	Name string `json:"name"`
+
+	// DashboardGroup is how the admin dashboard sums up the works of the task, by floor or by staff.
+	DashboardGroup      string `json:"dashboard_group"`
+	RequiresImageBefore bool   `json:"requires_image_before"`
+	RequiresImageAfter  bool   `json:"requires_image_after"`
+
+	// SlaHours is the time a work of the task is expected to take, nil when there is none.
+	SlaHours *int `json:"sla_hours"`
+
+	IsDelete  bool       `json:"is_delete"`
+	DeletedAt *time.Time `json:"deleted_at"`
 }
 
 // TaskEntityModel ...
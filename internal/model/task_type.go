@@ -1,11 +1,15 @@
 package model
 
-import "cleancare/internal/abstraction"
+import (
+	"cleancare/internal/abstraction"
+	"time"
+)
 
 type TaskTypeEntity struct {
-	Name     string `json:"name"`
-	TaskId   int    `json:"task_id"`
-	IsDelete bool   `json:"is_delete"`
+	Name      string     `json:"name"`
+	TaskId    int        `json:"task_id"`
+	IsDelete  bool       `json:"is_delete"`
+	DeletedAt *time.Time `json:"deleted_at"`
 }
 
 // TaskTypeEntityModel ...
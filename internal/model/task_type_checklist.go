@@ -0,0 +1,72 @@
+package model
+
+import (
+	"cleancare/internal/abstraction"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type TaskTypeChecklistItemEntity struct {
+	TaskTypeId int        `json:"task_type_id"`
+	Name       string     `json:"name"`
+	IsRequired bool       `json:"is_required"`
+	SortOrder  int        `json:"sort_order"`
+	IsDelete   bool       `json:"is_delete"`
+	DeletedAt  *time.Time `json:"deleted_at"`
+}
+
+// TaskTypeChecklistItemEntityModel ...
+type TaskTypeChecklistItemEntityModel struct {
+	ID int `json:"id" param:"id" form:"id" validate:"number,min=1" gorm:"primaryKey;autoIncrement;"`
+
+	// entity
+	TaskTypeChecklistItemEntity
+
+	abstraction.EntityWithBy
+
+	// context
+	Context *abstraction.Context `json:"-" gorm:"-"`
+}
+
+// TableName ...
+func (TaskTypeChecklistItemEntityModel) TableName() string {
+	return "task_type_checklist_item"
+}
+
+func (m *TaskTypeChecklistItemEntityModel) BeforeUpdate(tx *gorm.DB) (err error) {
+	m.UpdatedBy = &m.Context.Auth.ID
+	return
+}
+
+func (m *TaskTypeChecklistItemEntityModel) BeforeCreate(tx *gorm.DB) (err error) {
+	m.CreatedBy = m.Context.Auth.ID
+	return
+}
+
+type WorkChecklistEntity struct {
+	WorkId          int    `json:"work_id"`
+	ChecklistItemId int    `json:"checklist_item_id"`
+	Name            string `json:"name"`
+	IsRequired      bool   `json:"is_required"`
+	IsDone          bool   `json:"is_done"`
+	SortOrder       int    `json:"sort_order"`
+}
+
+// WorkChecklistEntityModel ...
+type WorkChecklistEntityModel struct {
+	ID int `json:"id" param:"id" form:"id" validate:"number,min=1" gorm:"primaryKey;autoIncrement;"`
+
+	// entity
+	WorkChecklistEntity
+
+	abstraction.Entity
+
+	// context
+	Context *abstraction.Context `json:"-" gorm:"-"`
+}
+
+// TableName ...
+func (WorkChecklistEntityModel) TableName() string {
+	return "work_checklist"
+}
@@ -8,6 +8,7 @@ import (
 	"crypto/sha1"
 	"encoding/base64"
 	"encoding/json"
+	"errors"
 	"io"
 
 	"github.com/golang-jwt/jwt/v4"
@@ -34,6 +35,17 @@ func (t *AuthToken) Token() (string, error) {
 }
 
 func (data *AuthEksternalToken) GenerateTokenEksternal() (*string, error) {
+	return sealTokenEksternal(data)
+}
+
+func ValidateTokenEksternal(token string) (data AuthEksternalToken, err error) {
+	err = openTokenEksternal(token, &data)
+	return data, err
+}
+
+// sealTokenEksternal encrypts data with SECRET_KEY_EKSTERNAL, the gcm tag makes sure the
+// token cannot be changed without the key.
+func sealTokenEksternal(data interface{}) (*string, error) {
 	sha1 := sha1.New()
 	io.WriteString(sha1, config.Get().JWT.SecretKeyEksternal)
 
@@ -65,37 +77,35 @@ func (data *AuthEksternalToken) GenerateTokenEksternal() (*string, error) {
 	return &token, nil
 }
 
-func ValidateTokenEksternal(token string) (data AuthEksternalToken, err error) {
+func openTokenEksternal(token string, data interface{}) error {
 	sha1 := sha1.New()
 	io.WriteString(sha1, config.Get().JWT.SecretKeyEksternal)
 
 	salt := string(sha1.Sum(nil))[0:16]
 	block, err := aes.NewCipher([]byte(salt))
 	if err != nil {
-		return data, err
+		return err
 	}
 
 	gcm, err := cipher.NewGCM(block)
 	if err != nil {
-		return data, err
+		return err
 	}
 
 	decode, err := base64.URLEncoding.DecodeString(token)
 	if err != nil {
-		return data, err
+		return err
 	}
 
 	nonceSize := gcm.NonceSize()
+	if len(decode) < nonceSize {
+		return errors.New("token is too short")
+	}
 	nonce, ciphertext := decode[:nonceSize], decode[nonceSize:]
 	plain, err := gcm.Open(nil, nonce, ciphertext, nil)
 	if err != nil {
-		return data, err
-	}
-
-	err = json.Unmarshal(plain, &data)
-	if err != nil {
-		return data, err
+		return err
 	}
 
-	return data, nil
+	return json.Unmarshal(plain, data)
 }
@@ -0,0 +1,40 @@
+package token
+
+import (
+	"errors"
+	"strings"
+	"time"
+)
+
+// EmailVerificationToken is sent by email to prove the user owns Email, it is only valid
+// for the address it was generated for.
+type EmailVerificationToken struct {
+	UserId    int    `json:"user_id"`
+	Email     string `json:"email"`
+	ExpiredAt int64  `json:"expired_at"`
+}
+
+func NewEmailVerificationToken(userId int, email string, ttl time.Duration) *EmailVerificationToken {
+	return &EmailVerificationToken{
+		UserId:    userId,
+		Email:     strings.ToLower(email),
+		ExpiredAt: time.Now().Add(ttl).Unix(),
+	}
+}
+
+func (data *EmailVerificationToken) GenerateToken() (*string, error) {
+	return sealTokenEksternal(data)
+}
+
+func ValidateEmailVerificationToken(token string) (data EmailVerificationToken, err error) {
+	if err = openTokenEksternal(token, &data); err != nil {
+		return data, err
+	}
+	if data.UserId == 0 || data.Email == "" {
+		return data, errors.New("your token is invalid")
+	}
+	if time.Now().Unix() > data.ExpiredAt {
+		return data, errors.New("your token has expired")
+	}
+	return data, nil
+}
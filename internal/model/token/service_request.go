@@ -0,0 +1,23 @@
+package token
+
+import "errors"
+
+// ServiceRequestToken is given to the occupant who sent a service request to follow it, it
+// does not expire since the request cannot be changed with it.
+type ServiceRequestToken struct {
+	ServiceRequestId int `json:"service_request_id"`
+}
+
+func (data *ServiceRequestToken) GenerateToken() (*string, error) {
+	return sealTokenEksternal(data)
+}
+
+func ValidateServiceRequestToken(token string) (data ServiceRequestToken, err error) {
+	if err = openTokenEksternal(token, &data); err != nil {
+		return data, err
+	}
+	if data.ServiceRequestId == 0 {
+		return data, errors.New("your token is invalid")
+	}
+	return data, nil
+}
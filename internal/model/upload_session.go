@@ -0,0 +1,44 @@
+package model
+
+import (
+	"cleancare/internal/abstraction"
+	"time"
+)
+
+type UploadSessionEntity struct {
+	UserId     int        `json:"user_id"`
+	FileName   string     `json:"file_name"`
+	Size       int64      `json:"size"`
+	Checksum   string     `json:"checksum"`
+	Received   int64      `json:"received"`
+	Status     string     `json:"status"`
+	FileId     *string    `json:"file_id"`
+	DriveName  *string    `json:"drive_name"`
+	CapturedAt *time.Time `json:"captured_at"`
+	Phash      *uint64    `json:"phash"`
+	ConsumedAt *time.Time `json:"consumed_at"`
+	ExpiresAt  time.Time  `json:"expires_at"`
+}
+
+// UploadSessionEntityModel ...
+type UploadSessionEntityModel struct {
+	ID string `json:"id" param:"id" gorm:"primaryKey;"`
+
+	// entity
+	UploadSessionEntity
+
+	abstraction.Entity
+
+	// context
+	Context *abstraction.Context `json:"-" gorm:"-"`
+}
+
+// TableName ...
+func (UploadSessionEntityModel) TableName() string {
+	return "upload_session"
+}
+
+type UploadSessionFileReference struct {
+	ID     string `json:"id"`
+	FileId string `json:"file_id"`
+}
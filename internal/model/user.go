@@ -2,20 +2,25 @@ package model
 
 import (
 	"cleancare/internal/abstraction"
+	"time"
 
 	"gorm.io/gorm"
 )
 
 type UserEntity struct {
-	NumberId    string  `json:"number_id"`
-	Name        string  `json:"name"`
-	Email       *string `json:"email"`
-	Password    *string `json:"password"`
-	RoleId      int     `json:"role_id"`
-	IsDelete    bool    `json:"is_delete"`
-	Profile     *string `json:"profile"`
-	ProfileName *string `json:"profile_name"`
-	Floor       string  `json:"floor"`
+	NumberId        string     `json:"number_id"`
+	Name            string     `json:"name"`
+	Email           *string    `json:"email"`
+	EmailVerifiedAt *time.Time `json:"email_verified_at"`
+	PendingEmail    *string    `json:"pending_email"`
+	Password        *string    `json:"password"`
+	RoleId          int        `json:"role_id"`
+	IsDelete        bool       `json:"is_delete"`
+	DeletedAt       *time.Time `json:"deleted_at"`
+	Profile         *string    `json:"profile"`
+	ProfileName     *string    `json:"profile_name"`
+	Floor           string     `json:"floor"`
+	Language        *string    `json:"language"`
 }
 
 // UserEntityModel ...
@@ -51,3 +56,8 @@ func (m *UserEntityModel) BeforeCreate(tx *gorm.DB) (err error) {
 	// m.CreatedAt = *general.Now()
 	return
 }
+
+type UserFileReference struct {
+	ID      int     `json:"id"`
+	Profile *string `json:"profile"`
+}
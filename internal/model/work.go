@@ -2,19 +2,36 @@ package model
 
 import (
 	"cleancare/internal/abstraction"
+	"time"
 
 	"gorm.io/gorm"
 )
 
 type WorkEntity struct {
-	UserId      int     `json:"user_id"`
-	TaskId      int     `json:"task_id"`
-	TaskTypeId  int     `json:"task_type_id"`
-	Floor       string  `json:"floor"`
-	Info        string  `json:"info"`
-	ImageBefore *string `json:"image_before"`
-	ImageAfter  *string `json:"image_after"`
-	IsDelete    bool    `json:"is_delete"`
+	UserId      int        `json:"user_id"`
+	ClientId    *string    `json:"client_id"`
+	TaskId      int        `json:"task_id"`
+	TaskTypeId  int        `json:"task_type_id"`
+	Floor       string     `json:"floor"`
+	Info        string     `json:"info"`
+	ImageBefore *string    `json:"image_before"`
+	ImageAfter  *string    `json:"image_after"`
+	CompletedAt *time.Time `json:"completed_at"`
+	IsDelete    bool       `json:"is_delete"`
+	DeletedAt   *time.Time `json:"deleted_at"`
+
+	VerificationStatus string     `json:"verification_status"`
+	VerifiedBy         *int       `json:"verified_by"`
+	VerifiedAt         *time.Time `json:"verified_at"`
+	VerificationNote   *string    `json:"verification_note"`
+
+	// ClientUpdatedAt is when the mobile app last changed the entry offline, see work sync.
+	ClientUpdatedAt *time.Time `json:"client_updated_at"`
+
+	SlaPolicyId      *int       `json:"sla_policy_id"`
+	SlaStartDueAt    *time.Time `json:"sla_start_due_at"`
+	SlaCompleteDueAt *time.Time `json:"sla_complete_due_at"`
+	SlaStatus        *string    `json:"sla_status"`
 }
 
 // WorkEntityModel ...
@@ -53,6 +70,12 @@ func (m *WorkEntityModel) BeforeCreate(tx *gorm.DB) (err error) {
 	return
 }
 
+type WorkFileReference struct {
+	ID          int     `json:"id"`
+	ImageBefore *string `json:"image_before"`
+	ImageAfter  *string `json:"image_after"`
+}
+
 type FloorSummary struct {
 	Floor string `json:"floor"`
 	Count int    `json:"count"`
@@ -69,3 +92,33 @@ type TaskTypeSummary struct {
 	Name       string `json:"name"`
 	Count      int    `json:"count"`
 }
+
+type WorkTrendSummary struct {
+	Period    string `json:"period"`
+	Total     int    `json:"total"`
+	Completed int    `json:"completed"`
+}
+
+type WorkCompletionSummary struct {
+	Total                int     `json:"total"`
+	Completed            int     `json:"completed"`
+	AvgCompletionSeconds float64 `json:"avg_completion_seconds"`
+}
+
+type WorkStaffSummary struct {
+	UserId               int     `json:"user_id"`
+	Name                 string  `json:"name"`
+	Total                int     `json:"total"`
+	Completed            int     `json:"completed"`
+	AvgCompletionSeconds float64 `json:"avg_completion_seconds"`
+}
+
+type WorkScorecard struct {
+	UserId                      int      `json:"user_id"`
+	Name                        string   `json:"name"`
+	Completed                   int      `json:"completed"`
+	Assigned                    int      `json:"assigned"`
+	OnTime                      int      `json:"on_time"`
+	Rejected                    int      `json:"rejected"`
+	AvgCommentTurnaroundSeconds *float64 `json:"avg_comment_turnaround_seconds"`
+}
@@ -0,0 +1,60 @@
+package model
+
+import (
+	"cleancare/internal/abstraction"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type WorkPhotoEntity struct {
+	WorkId     int        `json:"work_id"`
+	Phase      string     `json:"phase"`
+	StorageKey string     `json:"storage_key"`
+	FileName   string     `json:"file_name"`
+	Caption    *string    `json:"caption"`
+	SortOrder  int        `json:"sort_order"`
+	TakenAt    *time.Time `json:"taken_at"`
+
+	// CapturedAt and Phash are read from the file, see imagecheck.Inspect.
+	CapturedAt *time.Time `json:"captured_at"`
+	Phash      *uint64    `json:"phash"`
+}
+
+// WorkPhotoEntityModel ...
+type WorkPhotoEntityModel struct {
+	ID int `json:"id" param:"id" form:"id" validate:"number,min=1" gorm:"primaryKey;autoIncrement;"`
+
+	// entity
+	WorkPhotoEntity
+
+	abstraction.EntityWithBy
+
+	// context
+	Context *abstraction.Context `json:"-" gorm:"-"`
+}
+
+// TableName ...
+func (WorkPhotoEntityModel) TableName() string {
+	return "work_photo"
+}
+
+type WorkPhotoCountDataModel struct {
+	Count int `json:"count"`
+}
+
+func (m *WorkPhotoEntityModel) BeforeUpdate(tx *gorm.DB) (err error) {
+	m.UpdatedBy = &m.Context.Auth.ID
+	return
+}
+
+func (m *WorkPhotoEntityModel) BeforeCreate(tx *gorm.DB) (err error) {
+	m.CreatedBy = m.Context.Auth.ID
+	return
+}
+
+type WorkPhotoFileReference struct {
+	ID         int    `json:"id"`
+	WorkId     int    `json:"work_id"`
+	StorageKey string `json:"storage_key"`
+}
@@ -0,0 +1,30 @@
+package model
+
+import (
+	"cleancare/internal/abstraction"
+)
+
+type WorkVerificationEntity struct {
+	WorkId     int     `json:"work_id"`
+	Status     string  `json:"status"`
+	Note       *string `json:"note"`
+	VerifiedBy int     `json:"verified_by"`
+}
+
+// WorkVerificationEntityModel is one verification of a work, kept after the work is sent again.
+type WorkVerificationEntityModel struct {
+	ID int `json:"id" param:"id" form:"id" validate:"number,min=1" gorm:"primaryKey;autoIncrement;"`
+
+	// entity
+	WorkVerificationEntity
+
+	abstraction.EntityJustCreated
+
+	// context
+	Context *abstraction.Context `json:"-" gorm:"-"`
+}
+
+// TableName ...
+func (WorkVerificationEntityModel) TableName() string {
+	return "work_verification"
+}
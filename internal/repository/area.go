@@ -0,0 +1,111 @@
+package repository
+
+import (
+	"cleancare/internal/abstraction"
+	"cleancare/internal/model"
+	"cleancare/pkg/util/general"
+
+	"gorm.io/gorm"
+)
+
+type Area interface {
+	Create(ctx *abstraction.Context, data *model.AreaEntityModel) *gorm.DB
+	FindById(ctx *abstraction.Context, id int) (*model.AreaEntityModel, error)
+	FindByCode(ctx *abstraction.Context, code string) (*model.AreaEntityModel, error)
+	Find(ctx *abstraction.Context, no_paging bool) (data []*model.AreaEntityModel, err error)
+	Count(ctx *abstraction.Context) (data *int, err error)
+	Update(ctx *abstraction.Context, data *model.AreaEntityModel) *gorm.DB
+	Delete(ctx *abstraction.Context, data *model.AreaEntityModel) *gorm.DB
+}
+
+type area struct {
+	abstraction.Repository
+}
+
+func NewArea(db *gorm.DB) *area {
+	return &area{
+		Repository: abstraction.Repository{
+			Db: db,
+		},
+	}
+}
+
+func (r *area) Create(ctx *abstraction.Context, data *model.AreaEntityModel) *gorm.DB {
+	return r.CheckTrx(ctx).Create(data)
+}
+
+func (r *area) FindById(ctx *abstraction.Context, id int) (*model.AreaEntityModel, error) {
+	conn := r.CheckTrx(ctx)
+
+	var data model.AreaEntityModel
+	err := conn.
+		Where("id = ? AND is_delete = ?", id, false).
+		First(&data).
+		Error
+	if err != nil {
+		return nil, err
+	}
+	return &data, nil
+}
+
+// FindByCode returns the area of a public request link, an inactive area takes no requests.
+func (r *area) FindByCode(ctx *abstraction.Context, code string) (*model.AreaEntityModel, error) {
+	conn := r.CheckTrx(ctx)
+
+	var data model.AreaEntityModel
+	err := conn.
+		Where("code = ? AND is_active = ? AND is_delete = ?", code, true, false).
+		First(&data).
+		Error
+	if err != nil {
+		return nil, err
+	}
+	return &data, nil
+}
+
+func (r *area) Find(ctx *abstraction.Context, no_paging bool) (data []*model.AreaEntityModel, err error) {
+	where, whereParam := general.ProcessWhereParam(ctx, "area", "is_delete = @false")
+	limit, offset := general.ProcessLimitOffset(ctx, no_paging)
+	order := general.ProcessOrder(ctx)
+	err = r.CheckTrx(ctx).
+		Where(where, whereParam).
+		Order(order).
+		Limit(limit).
+		Offset(offset).
+		Find(&data).
+		Error
+	return
+}
+
+func (r *area) Count(ctx *abstraction.Context) (data *int, err error) {
+	where, whereParam := general.ProcessWhereParam(ctx, "area", "is_delete = @false")
+	var count model.AreaCountDataModel
+	err = r.CheckTrx(ctx).
+		Table("area").
+		Select("COUNT(*) AS count").
+		Where(where, whereParam).
+		Find(&count).
+		Error
+	data = &count.Count
+	return
+}
+
+// Update writes the area with a map, so it can be made inactive.
+func (r *area) Update(ctx *abstraction.Context, data *model.AreaEntityModel) *gorm.DB {
+	return r.CheckTrx(ctx).Model(data).Where("id = ?", data.ID).Updates(map[string]interface{}{
+		"name":       data.Name,
+		"floor":      data.Floor,
+		"location":   data.Location,
+		"code":       data.Code,
+		"is_active":  data.IsActive,
+		"updated_by": ctx.Auth.ID,
+	})
+}
+
+func (r *area) Delete(ctx *abstraction.Context, data *model.AreaEntityModel) *gorm.DB {
+	return r.CheckTrx(ctx).Model(data).Where("id = ?", data.ID).Updates(map[string]interface{}{
+		"is_delete":  true,
+		"deleted_at": general.Now(),
+		"updated_by": ctx.Auth.ID,
+	})
+}
@@ -0,0 +1,158 @@
+package repository
+
+import (
+	"cleancare/internal/abstraction"
+	"cleancare/internal/model"
+	"cleancare/pkg/util/general"
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type Assignment interface {
+	Create(ctx *abstraction.Context, data *model.AssignmentEntityModel) *gorm.DB
+	FindById(ctx *abstraction.Context, id int) (*model.AssignmentEntityModel, error)
+	Update(ctx *abstraction.Context, data *model.AssignmentEntityModel) *gorm.DB
+	Find(ctx *abstraction.Context, user_id int, no_paging bool) (data []*model.AssignmentEntityModel, err error)
+	Count(ctx *abstraction.Context, user_id int) (data *int, err error)
+	UpdateToNull(ctx *abstraction.Context, data *model.AssignmentEntityModel, column string) *gorm.DB
+	FindByWorkId(ctx *abstraction.Context, work_id int) (*model.AssignmentEntityModel, error)
+	CountByUserId(ctx *abstraction.Context, user_id int) (data *int, err error)
+	CountByTaskTypeId(ctx *abstraction.Context, task_type_id int) (data *int, err error)
+	FindChangedSince(ctx *abstraction.Context, user_id int, since *time.Time) (data []*model.AssignmentEntityModel, err error)
+}
+
+type assignment struct {
+	abstraction.Repository
+}
+
+func NewAssignment(db *gorm.DB) *assignment {
+	return &assignment{
+		Repository: abstraction.Repository{
+			Db: db,
+		},
+	}
+}
+
+func (r *assignment) Create(ctx *abstraction.Context, data *model.AssignmentEntityModel) *gorm.DB {
+	return r.CheckTrx(ctx).Create(data)
+}
+
+func (r *assignment) FindById(ctx *abstraction.Context, id int) (*model.AssignmentEntityModel, error) {
+	conn := r.CheckTrx(ctx)
+
+	var data model.AssignmentEntityModel
+	err := conn.
+		Where("id = ? AND is_delete = ?", id, false).
+		Preload("User").
+		Preload("Task").
+		Preload("TaskType").
+		First(&data).
+		Error
+	if err != nil {
+		return nil, err
+	}
+	return &data, nil
+}
+
+func (r *assignment) Update(ctx *abstraction.Context, data *model.AssignmentEntityModel) *gorm.DB {
+	return r.CheckTrx(ctx).Model(data).Where("id = ?", data.ID).Updates(data)
+}
+
+// Find returns the assignments of user_id, or of every user when user_id is 0.
+func (r *assignment) Find(ctx *abstraction.Context, user_id int, no_paging bool) (data []*model.AssignmentEntityModel, err error) {
+	whereStr := "is_delete = @false"
+	if user_id != 0 {
+		whereStr += fmt.Sprintf(" AND user_id = %d", user_id)
+	}
+	where, whereParam := general.ProcessWhereParam(ctx, "assignment", whereStr)
+	limit, offset := general.ProcessLimitOffset(ctx, no_paging)
+	order := general.ProcessOrder(ctx)
+	err = r.CheckTrx(ctx).
+		Where(where, whereParam).
+		Order(order).
+		Limit(limit).
+		Offset(offset).
+		Preload("User").
+		Preload("Task").
+		Preload("TaskType").
+		Find(&data).
+		Error
+	return
+}
+
+func (r *assignment) Count(ctx *abstraction.Context, user_id int) (data *int, err error) {
+	whereStr := "is_delete = @false"
+	if user_id != 0 {
+		whereStr += fmt.Sprintf(" AND user_id = %d", user_id)
+	}
+	where, whereParam := general.ProcessWhereParam(ctx, "assignment", whereStr)
+	var count model.AssignmentCountDataModel
+	err = r.CheckTrx(ctx).
+		Table("assignment").
+		Select("COUNT(*) AS count").
+		Where(where, whereParam).
+		Find(&count).
+		Error
+	data = &count.Count
+	return
+}
+
+func (r *assignment) UpdateToNull(ctx *abstraction.Context, data *model.AssignmentEntityModel, column string) *gorm.DB {
+	return r.CheckTrx(ctx).Model(data).Where("id = ?", data.ID).Update(column, nil)
+}
+
+func (r *assignment) FindByWorkId(ctx *abstraction.Context, work_id int) (*model.AssignmentEntityModel, error) {
+	conn := r.CheckTrx(ctx)
+
+	var data model.AssignmentEntityModel
+	err := conn.
+		Where("work_id = ? AND is_delete = ?", work_id, false).
+		First(&data).
+		Error
+	if err != nil {
+		return nil, err
+	}
+	return &data, nil
+}
+
+func (r *assignment) CountByUserId(ctx *abstraction.Context, user_id int) (data *int, err error) {
+	var count model.AssignmentCountDataModel
+	err = r.CheckTrx(ctx).
+		Table("assignment").
+		Select("COUNT(*) AS count").
+		Where("user_id = ?", user_id).
+		Find(&count).
+		Error
+	data = &count.Count
+	return
+}
+
+func (r *assignment) CountByTaskTypeId(ctx *abstraction.Context, task_type_id int) (data *int, err error) {
+	var count model.AssignmentCountDataModel
+	err = r.CheckTrx(ctx).
+		Table("assignment").
+		Select("COUNT(*) AS count").
+		Where("task_type_id = ?", task_type_id).
+		Find(&count).
+		Error
+	data = &count.Count
+	return
+}
+
+// FindChangedSince returns the assignments of user_id changed at or after since, see
+// Work.FindChangedSince.
+func (r *assignment) FindChangedSince(ctx *abstraction.Context, user_id int, since *time.Time) (data []*model.AssignmentEntityModel, err error) {
+	conn := r.CheckTrx(ctx).Where("user_id = ?", user_id)
+	if since != nil {
+		conn = conn.Where("COALESCE(updated_at, created_at) >= ?", since)
+	} else {
+		conn = conn.Where("is_delete = ?", false)
+	}
+	err = conn.
+		Order("id ASC").
+		Find(&data).
+		Error
+	return
+}
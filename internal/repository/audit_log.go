@@ -0,0 +1,399 @@
+package repository
+
+import (
+	"cleancare/internal/abstraction"
+	"cleancare/internal/model"
+	"cleancare/pkg/constant"
+	"cleancare/pkg/util/general"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type AuditLog interface {
+	Create(ctx *abstraction.Context, data *model.AuditLogEntityModel) *gorm.DB
+	FindById(ctx *abstraction.Context, id int) (*model.AuditLogEntityModel, error)
+	Find(ctx *abstraction.Context, no_paging bool) (data []*model.AuditLogEntityModel, err error)
+	Count(ctx *abstraction.Context) (data *int, err error)
+}
+
+type auditLog struct {
+	abstraction.Repository
+}
+
+func NewAuditLog(db *gorm.DB) *auditLog {
+	return &auditLog{
+		Repository: abstraction.Repository{
+			Db: db,
+		},
+	}
+}
+
+func (r *auditLog) Create(ctx *abstraction.Context, data *model.AuditLogEntityModel) *gorm.DB {
+	return r.CheckTrx(ctx).Create(data)
+}
+
+func (r *auditLog) FindById(ctx *abstraction.Context, id int) (*model.AuditLogEntityModel, error) {
+	conn := r.CheckTrx(ctx)
+
+	var data model.AuditLogEntityModel
+	err := conn.
+		Where("id = ?", id).
+		Preload("Actor").
+		First(&data).
+		Error
+	if err != nil {
+		return nil, err
+	}
+	return &data, nil
+}
+
+func (r *auditLog) Find(ctx *abstraction.Context, no_paging bool) (data []*model.AuditLogEntityModel, err error) {
+	where, whereParam := general.ProcessWhereParam(ctx, "audit_log", "")
+	limit, offset := general.ProcessLimitOffset(ctx, no_paging)
+	order := general.ProcessOrder(ctx)
+	err = r.CheckTrx(ctx).
+		Where(where, whereParam).
+		Preload("Actor").
+		Order(order).
+		Limit(limit).
+		Offset(offset).
+		Find(&data).
+		Error
+	return
+}
+
+func (r *auditLog) Count(ctx *abstraction.Context) (data *int, err error) {
+	where, whereParam := general.ProcessWhereParam(ctx, "audit_log", "")
+	var count model.AuditLogCountDataModel
+	err = r.CheckTrx(ctx).
+		Table("audit_log").
+		Select("COUNT(*) AS count").
+		Where(where, whereParam).
+		Find(&count).
+		Error
+	data = &count.Count
+	return
+}
+
+// tables that are not audited: the log itself, and rows written by the system
+// or holding secrets.
+var auditSkipTables = map[string]bool{
+	"audit_log":        true,
+	"email_outbox":     true,
+	"upload_session":   true,
+	"password_history": true,
+	"inventory_stock":  true,
+}
+
+var auditSkipColumns = map[string]bool{
+	"created_at": true,
+	"updated_at": true,
+	"created_by": true,
+	"updated_by": true,
+}
+
+var auditRedactColumns = map[string]bool{
+	"password": true,
+}
+
+// RegisterAuditCallbacks hooks the audit log into every create, update and delete made
+// through the repositories, the entry is written in the same transaction as the change.
+func RegisterAuditCallbacks(db *gorm.DB) error {
+	if err := db.Callback().Create().After("gorm:create").Register("audit:create", auditCreate); err != nil {
+		return err
+	}
+	if err := db.Callback().Update().Before("gorm:update").Register("audit:before_update", auditBefore); err != nil {
+		return err
+	}
+	if err := db.Callback().Update().After("gorm:update").Register("audit:update", auditUpdate); err != nil {
+		return err
+	}
+	if err := db.Callback().Delete().Before("gorm:delete").Register("audit:before_delete", auditBefore); err != nil {
+		return err
+	}
+	return db.Callback().Delete().After("gorm:delete").Register("audit:delete", auditDelete)
+}
+
+func auditEnabled(db *gorm.DB) bool {
+	return db.Error == nil && db.Statement.Schema != nil && !auditSkipTables[db.Statement.Table]
+}
+
+func auditCreate(db *gorm.DB) {
+	// association upserts run as creates with an on conflict clause, they are not new rows
+	if _, ok := db.Statement.Clauses["ON CONFLICT"]; ok || !auditEnabled(db) || db.RowsAffected == 0 {
+		return
+	}
+
+	values := []reflect.Value{db.Statement.ReflectValue}
+	if db.Statement.ReflectValue.Kind() == reflect.Slice || db.Statement.ReflectValue.Kind() == reflect.Array {
+		values = values[:0]
+		for i := 0; i < db.Statement.ReflectValue.Len(); i++ {
+			values = append(values, reflect.Indirect(db.Statement.ReflectValue.Index(i)))
+		}
+	}
+
+	for _, rv := range values {
+		if rv.Kind() != reflect.Struct {
+			continue
+		}
+		newValues := map[string]interface{}{}
+		for _, field := range db.Statement.Schema.Fields {
+			if field.DBName == "" || field.PrimaryKey || auditSkipColumns[field.DBName] {
+				continue
+			}
+			val, _ := field.ValueOf(db.Statement.Context, rv)
+			newValues[field.DBName] = auditValue(field.DBName, val)
+		}
+		writeAudit(db, constant.AUDIT_ACTION_CREATE, auditEntityId(db, rv), nil, newValues)
+	}
+}
+
+// auditBefore keeps the rows the statement is about to change. A statement made on a
+// model with its primary key set touches that row, a bulk statement such as
+// Where("work_id = ?", id).Delete(...) touches every row matching its where clause,
+// each of them gets its own entry.
+func auditBefore(db *gorm.DB) {
+	if !auditEnabled(db) {
+		return
+	}
+	var before []map[string]interface{}
+	if id := auditEntityId(db, db.Statement.ReflectValue); id != nil {
+		if row := auditSnapshot(db, *id); row != nil {
+			before = append(before, row)
+		}
+	} else {
+		before = auditSnapshotWhere(db)
+	}
+	if len(before) > 0 {
+		db.InstanceSet("audit:before", before)
+	}
+}
+
+func auditUpdate(db *gorm.DB) {
+	if !auditEnabled(db) || db.RowsAffected == 0 {
+		return
+	}
+	val, ok := db.InstanceGet("audit:before")
+	if !ok {
+		return
+	}
+	rows, _ := val.([]map[string]interface{})
+	for _, before := range rows {
+		id := auditRowId(db, before)
+		if id == nil {
+			continue
+		}
+		after := auditSnapshot(db, *id)
+		if after == nil {
+			continue
+		}
+
+		action := constant.AUDIT_ACTION_UPDATE
+		oldValues := map[string]interface{}{}
+		newValues := map[string]interface{}{}
+		for col, v := range after {
+			if auditSkipColumns[col] {
+				continue
+			}
+			oldVal := auditValue(col, before[col])
+			newVal := auditValue(col, v)
+			if auditEqual(oldVal, newVal) {
+				continue
+			}
+			if col == "is_delete" && auditEqual(newVal, true) {
+				action = constant.AUDIT_ACTION_DELETE
+			} else if col == "is_delete" {
+				action = constant.AUDIT_ACTION_RESTORE
+			}
+			oldValues[col] = oldVal
+			newValues[col] = newVal
+		}
+		if len(newValues) == 0 {
+			continue
+		}
+		writeAudit(db, action, id, oldValues, newValues)
+	}
+}
+
+func auditDelete(db *gorm.DB) {
+	if !auditEnabled(db) || db.RowsAffected == 0 {
+		return
+	}
+	val, ok := db.InstanceGet("audit:before")
+	if !ok {
+		return
+	}
+	rows, _ := val.([]map[string]interface{})
+	for _, before := range rows {
+		oldValues := map[string]interface{}{}
+		for col, v := range before {
+			if auditSkipColumns[col] {
+				continue
+			}
+			oldValues[col] = auditValue(col, v)
+		}
+		writeAudit(db, constant.AUDIT_ACTION_PURGE, auditRowId(db, before), oldValues, nil)
+	}
+}
+
+// auditSnapshot reads the current row within the same connection, so an update made
+// in a transaction sees its own changes.
+func auditSnapshot(db *gorm.DB, id int) map[string]interface{} {
+	row := map[string]interface{}{}
+	err := db.Session(&gorm.Session{NewDB: true}).
+		Table(db.Statement.Table).
+		Where(db.Statement.Schema.PrioritizedPrimaryField.DBName+" = ?", id).
+		Take(&row).
+		Error
+	if err != nil {
+		return nil
+	}
+	return row
+}
+
+// auditSnapshotWhere reads the rows matched by the where clause of a bulk statement, a
+// statement without one is left out, gorm refuses to run it anyway.
+func auditSnapshotWhere(db *gorm.DB) []map[string]interface{} {
+	where, ok := db.Statement.Clauses["WHERE"]
+	if !ok || where.Expression == nil {
+		return nil
+	}
+	var rows []map[string]interface{}
+	err := db.Session(&gorm.Session{NewDB: true}).
+		Table(db.Statement.Table).
+		Clauses(where.Expression).
+		Find(&rows).
+		Error
+	if err != nil {
+		return nil
+	}
+	return rows
+}
+
+func writeAudit(db *gorm.DB, action string, entityId *int, oldValues, newValues map[string]interface{}) {
+	entry := &model.AuditLogEntityModel{
+		AuditLogEntity: model.AuditLogEntity{
+			Action:    action,
+			Entity:    db.Statement.Table,
+			EntityId:  entityId,
+			OldValues: auditJSON(oldValues),
+			NewValues: auditJSON(newValues),
+		},
+	}
+	if ctx := abstraction.FromStdContext(db.Statement.Context); ctx != nil {
+		if ctx.Auth != nil && ctx.Auth.ID != 0 {
+			entry.ActorId = &ctx.Auth.ID
+		}
+		if ctx.Context != nil {
+			ip := ctx.RealIP()
+			entry.Ip = &ip
+		}
+		if requestId := ctx.RequestID(); requestId != "" {
+			entry.RequestId = &requestId
+		}
+	}
+
+	if err := db.Session(&gorm.Session{NewDB: true}).Create(entry).Error; err != nil {
+		db.AddError(err)
+	}
+}
+
+func auditEntityId(db *gorm.DB, rv reflect.Value) *int {
+	field := db.Statement.Schema.PrioritizedPrimaryField
+	rv = reflect.Indirect(rv)
+	if field == nil || rv.Kind() != reflect.Struct {
+		return nil
+	}
+	val, isZero := field.ValueOf(db.Statement.Context, rv)
+	if isZero {
+		return nil
+	}
+	id, ok := val.(int)
+	if !ok {
+		return nil
+	}
+	return &id
+}
+
+// auditRowId reads the primary key of a row read back by auditSnapshot or auditSnapshotWhere,
+// the driver hands integers back as int64.
+func auditRowId(db *gorm.DB, row map[string]interface{}) *int {
+	field := db.Statement.Schema.PrioritizedPrimaryField
+	if field == nil {
+		return nil
+	}
+	var id int
+	switch v := row[field.DBName].(type) {
+	case int:
+		id = v
+	case int64:
+		id = int(v)
+	case uint64:
+		id = int(v)
+	case int32:
+		id = int(v)
+	case uint32:
+		id = int(v)
+	default:
+		return nil
+	}
+	return &id
+}
+
+// auditValue makes values read back from the database comparable with the values
+// being written, and hides secrets.
+func auditValue(column string, val interface{}) interface{} {
+	if val == nil {
+		return nil
+	}
+	if auditRedactColumns[column] {
+		return "***"
+	}
+	rv := reflect.ValueOf(val)
+	for rv.Kind() == reflect.Ptr {
+		if rv.IsNil() {
+			return nil
+		}
+		rv = rv.Elem()
+	}
+	switch v := rv.Interface().(type) {
+	case []byte:
+		return string(v)
+	case time.Time:
+		return general.FormatWithZWithoutChangingTime(v)
+	default:
+		return v
+	}
+}
+
+func auditEqual(a, b interface{}) bool {
+	normalize := func(v interface{}) string {
+		if v == nil {
+			return ""
+		}
+		if b, ok := v.(bool); ok {
+			if b {
+				return "1"
+			}
+			return "0"
+		}
+		return fmt.Sprint(v)
+	}
+	return (a == nil) == (b == nil) && normalize(a) == normalize(b)
+}
+
+func auditJSON(values map[string]interface{}) *string {
+	if values == nil {
+		return nil
+	}
+	b, err := json.Marshal(values)
+	if err != nil {
+		return nil
+	}
+	res := string(b)
+	return &res
+}
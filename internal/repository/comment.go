@@ -5,17 +5,30 @@ import (
 	"cleancare/internal/model"
 	"cleancare/pkg/util/general"
 	"fmt"
+	"time"
+
+	"slices"
 
 	"gorm.io/gorm"
 )
 
 type Comment interface {
 	FindByWorkId(ctx *abstraction.Context, workId int, no_paging bool) (data []*model.CommentEntityModel, err error)
+	FindByWorkIdCursor(ctx *abstraction.Context, workId int, paging *general.CursorPaging) (data []*model.CommentEntityModel, hasMore bool, err error)
 	CountByWorkId(ctx *abstraction.Context, workId int) (data *int, err error)
 	Create(ctx *abstraction.Context, data *model.CommentEntityModel) *gorm.DB
 	FindById(ctx *abstraction.Context, id int) (*model.CommentEntityModel, error)
 	Update(ctx *abstraction.Context, data *model.CommentEntityModel) *gorm.DB
 	FindByWorkIdArr(ctx *abstraction.Context, work_id int, no_paging bool) (data []*model.CommentEntityModel, err error)
+	FindDeleted(ctx *abstraction.Context, no_paging bool) (data []*model.CommentEntityModel, err error)
+	CountDeleted(ctx *abstraction.Context) (data *int, err error)
+	FindDeletedById(ctx *abstraction.Context, id int) (*model.CommentEntityModel, error)
+	FindDeletedBefore(ctx *abstraction.Context, before time.Time, after_id int, limit int) (data []*model.CommentEntityModel, err error)
+	Restore(ctx *abstraction.Context, data *model.CommentEntityModel) *gorm.DB
+	Purge(ctx *abstraction.Context, data *model.CommentEntityModel) *gorm.DB
+	CountByCreatedBy(ctx *abstraction.Context, created_by int) (data *int, err error)
+	FindAllByWorkId(ctx *abstraction.Context, work_id int) (data []*model.CommentEntityModel, err error)
+	FindChangedSince(ctx *abstraction.Context, user_id int, since *time.Time) (data []*model.CommentEntityModel, err error)
 }
 
 type comment struct {
@@ -96,3 +109,134 @@ func (r *comment) FindByWorkIdArr(ctx *abstraction.Context, work_id int, no_pagi
 		Error
 	return
 }
+
+func (r *comment) FindByWorkIdCursor(ctx *abstraction.Context, workId int, paging *general.CursorPaging) (data []*model.CommentEntityModel, hasMore bool, err error) {
+	where, whereParam := general.ProcessWhereParam(ctx, "comment", "is_delete = @false"+fmt.Sprintf(" AND work_id = %d", workId))
+	where, order := paging.Apply(where, whereParam)
+	err = r.CheckTrx(ctx).
+		Where(where, whereParam).
+		Order(order).
+		Limit(paging.Limit + 1).
+		Preload("CreateBy").
+		Preload("CreateBy.Role").
+		Preload("UpdateBy").
+		Preload("UpdateBy.Role").
+		Find(&data).
+		Error
+	if err != nil {
+		return
+	}
+	if hasMore = len(data) > paging.Limit; hasMore {
+		data = data[:paging.Limit]
+	}
+	if paging.Reverse() {
+		slices.Reverse(data)
+	}
+	return
+}
+
+func (r *comment) FindDeleted(ctx *abstraction.Context, no_paging bool) (data []*model.CommentEntityModel, err error) {
+	where, whereParam := general.ProcessWhereParam(ctx, "comment", "is_delete = @true")
+	limit, offset := general.ProcessLimitOffset(ctx, no_paging)
+	order := general.ProcessOrder(ctx)
+	err = r.CheckTrx(ctx).
+		Where(where, whereParam).
+		Order(order).
+		Limit(limit).
+		Offset(offset).
+		Preload("Work").
+		Preload("CreateBy").
+		Find(&data).
+		Error
+	return
+}
+
+func (r *comment) CountDeleted(ctx *abstraction.Context) (data *int, err error) {
+	where, whereParam := general.ProcessWhereParam(ctx, "comment", "is_delete = @true")
+	var count model.CommentCountDataModel
+	err = r.CheckTrx(ctx).
+		Table("comment").
+		Select("COUNT(*) AS count").
+		Where(where, whereParam).
+		Find(&count).
+		Error
+	data = &count.Count
+	return
+}
+
+func (r *comment) FindDeletedById(ctx *abstraction.Context, id int) (*model.CommentEntityModel, error) {
+	conn := r.CheckTrx(ctx)
+
+	var data model.CommentEntityModel
+	err := conn.
+		Where("id = ? AND is_delete = ?", id, true).
+		Preload("Work").
+		Preload("CreateBy").
+		First(&data).
+		Error
+	if err != nil {
+		return nil, err
+	}
+	return &data, nil
+}
+
+func (r *comment) FindDeletedBefore(ctx *abstraction.Context, before time.Time, after_id int, limit int) (data []*model.CommentEntityModel, err error) {
+	err = r.CheckTrx(ctx).
+		Where("is_delete = ? AND deleted_at < ? AND id > ?", true, before, after_id).
+		Order("id ASC").
+		Limit(limit).
+		Find(&data).
+		Error
+	return
+}
+
+func (r *comment) Restore(ctx *abstraction.Context, data *model.CommentEntityModel) *gorm.DB {
+	return r.CheckTrx(ctx).Model(data).Where("id = ?", data.ID).Updates(map[string]interface{}{
+		"is_delete":  false,
+		"deleted_at": nil,
+	})
+}
+
+func (r *comment) Purge(ctx *abstraction.Context, data *model.CommentEntityModel) *gorm.DB {
+	return r.CheckTrx(ctx).Where("id = ?", data.ID).Delete(data)
+}
+
+func (r *comment) CountByCreatedBy(ctx *abstraction.Context, created_by int) (data *int, err error) {
+	var count model.CommentCountDataModel
+	err = r.CheckTrx(ctx).
+		Table("comment").
+		Select("COUNT(*) AS count").
+		Where("created_by = ?", created_by).
+		Find(&count).
+		Error
+	data = &count.Count
+	return
+}
+
+func (r *comment) FindAllByWorkId(ctx *abstraction.Context, work_id int) (data []*model.CommentEntityModel, err error) {
+	err = r.CheckTrx(ctx).
+		Where("work_id = ?", work_id).
+		Order("id ASC").
+		Find(&data).
+		Error
+	return
+}
+
+// FindChangedSince returns the comments on the works of user_id changed at or after since, see
+// Work.FindChangedSince.
+func (r *comment) FindChangedSince(ctx *abstraction.Context, user_id int, since *time.Time) (data []*model.CommentEntityModel, err error) {
+	conn := r.CheckTrx(ctx).
+		Joins("JOIN work ON work.id = comment.work_id").
+		Where("work.user_id = ?", user_id)
+	if since != nil {
+		conn = conn.Where("COALESCE(comment.updated_at, comment.created_at) >= ?", since)
+	} else {
+		conn = conn.Where("comment.is_delete = ?", false)
+	}
+	err = conn.
+		Order("comment.id ASC").
+		Preload("CreateBy").
+		Find(&data).
+		Error
+	return
+}
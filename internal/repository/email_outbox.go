@@ -0,0 +1,130 @@
+package repository
+
+import (
+	"cleancare/internal/abstraction"
+	"cleancare/internal/model"
+	"cleancare/pkg/constant"
+	"cleancare/pkg/util/general"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type EmailOutbox interface {
+	Create(ctx *abstraction.Context, data *model.EmailOutboxEntityModel) *gorm.DB
+	FindById(ctx *abstraction.Context, id int) (*model.EmailOutboxEntityModel, error)
+	Find(ctx *abstraction.Context, no_paging bool) (data []*model.EmailOutboxEntityModel, err error)
+	Count(ctx *abstraction.Context) (data *int, err error)
+	Update(ctx *abstraction.Context, data *model.EmailOutboxEntityModel) *gorm.DB
+	UpdateToNull(ctx *abstraction.Context, data *model.EmailOutboxEntityModel, column string) *gorm.DB
+	FindDue(ctx *abstraction.Context, stuckBefore time.Time, limit int) (data []*model.EmailOutboxEntityModel, err error)
+	Claim(ctx *abstraction.Context, data *model.EmailOutboxEntityModel) (bool, error)
+	Requeue(ctx *abstraction.Context, id int) *gorm.DB
+}
+
+type emailOutbox struct {
+	abstraction.Repository
+}
+
+func NewEmailOutbox(db *gorm.DB) *emailOutbox {
+	return &emailOutbox{
+		Repository: abstraction.Repository{
+			Db: db,
+		},
+	}
+}
+
+func (r *emailOutbox) Create(ctx *abstraction.Context, data *model.EmailOutboxEntityModel) *gorm.DB {
+	return r.CheckTrx(ctx).Create(data)
+}
+
+func (r *emailOutbox) FindById(ctx *abstraction.Context, id int) (*model.EmailOutboxEntityModel, error) {
+	conn := r.CheckTrx(ctx)
+
+	var data model.EmailOutboxEntityModel
+	err := conn.
+		Where("id = ?", id).
+		First(&data).
+		Error
+	if err != nil {
+		return nil, err
+	}
+	return &data, nil
+}
+
+func (r *emailOutbox) Find(ctx *abstraction.Context, no_paging bool) (data []*model.EmailOutboxEntityModel, err error) {
+	where, whereParam := general.ProcessWhereParam(ctx, "email_outbox", "")
+	limit, offset := general.ProcessLimitOffset(ctx, no_paging)
+	order := general.ProcessOrder(ctx)
+	err = r.CheckTrx(ctx).
+		Omit("body").
+		Where(where, whereParam).
+		Order(order).
+		Limit(limit).
+		Offset(offset).
+		Find(&data).
+		Error
+	return
+}
+
+func (r *emailOutbox) Count(ctx *abstraction.Context) (data *int, err error) {
+	where, whereParam := general.ProcessWhereParam(ctx, "email_outbox", "")
+	var count model.EmailOutboxCountDataModel
+	err = r.CheckTrx(ctx).
+		Table("email_outbox").
+		Select("COUNT(*) AS count").
+		Where(where, whereParam).
+		Find(&count).
+		Error
+	data = &count.Count
+	return
+}
+
+func (r *emailOutbox) Update(ctx *abstraction.Context, data *model.EmailOutboxEntityModel) *gorm.DB {
+	return r.CheckTrx(ctx).Model(data).Where("id = ?", data.ID).Updates(data)
+}
+
+func (r *emailOutbox) UpdateToNull(ctx *abstraction.Context, data *model.EmailOutboxEntityModel, column string) *gorm.DB {
+	return r.CheckTrx(ctx).Model(data).Where("id = ?", data.ID).Update(column, nil)
+}
+
+// FindDue returns the emails waiting for their next attempt, and the ones left in sending
+// since before stuckBefore by a worker that stopped halfway.
+func (r *emailOutbox) FindDue(ctx *abstraction.Context, stuckBefore time.Time, limit int) (data []*model.EmailOutboxEntityModel, err error) {
+	err = r.CheckTrx(ctx).
+		Where("(status = ? AND next_attempt_at <= ?) OR (status = ? AND updated_at < ?)", constant.EMAIL_STATUS_PENDING, time.Now(), constant.EMAIL_STATUS_SENDING, stuckBefore).
+		Order("next_attempt_at ASC, id ASC").
+		Limit(limit).
+		Find(&data).
+		Error
+	return
+}
+
+// Claim marks data as sending and counts the attempt, it returns false when another
+// worker claimed the same email first.
+func (r *emailOutbox) Claim(ctx *abstraction.Context, data *model.EmailOutboxEntityModel) (bool, error) {
+	res := r.CheckTrx(ctx).
+		Model(&model.EmailOutboxEntityModel{}).
+		Where("id = ? AND status = ? AND attempts = ?", data.ID, data.Status, data.Attempts).
+		Updates(map[string]interface{}{
+			"status":     constant.EMAIL_STATUS_SENDING,
+			"attempts":   gorm.Expr("attempts + 1"),
+			"updated_at": time.Now(),
+		})
+	if res.Error != nil {
+		return false, res.Error
+	}
+	return res.RowsAffected == 1, nil
+}
+
+// Requeue sends a dead email again with a fresh number of attempts.
+func (r *emailOutbox) Requeue(ctx *abstraction.Context, id int) *gorm.DB {
+	return r.CheckTrx(ctx).
+		Model(&model.EmailOutboxEntityModel{}).
+		Where("id = ?", id).
+		Updates(map[string]interface{}{
+			"status":          constant.EMAIL_STATUS_PENDING,
+			"attempts":        0,
+			"next_attempt_at": time.Now(),
+		})
+}
@@ -0,0 +1,100 @@
+package repository
+
+import (
+	"cleancare/internal/abstraction"
+	"cleancare/internal/model"
+	"cleancare/pkg/util/general"
+	"strings"
+
+	"gorm.io/gorm"
+)
+
+type Feedback interface {
+	Create(ctx *abstraction.Context, data *model.FeedbackEntityModel) *gorm.DB
+	Find(ctx *abstraction.Context, no_paging bool) (data []*model.FeedbackEntityModel, err error)
+	Count(ctx *abstraction.Context) (data *int, err error)
+	SatisfactionByFloor(ctx *abstraction.Context, task_id int, created_at string) (data []*model.FloorSatisfaction, err error)
+	SatisfactionByUser(ctx *abstraction.Context, task_id int, created_at string) (data []*model.UserSatisfaction, err error)
+	DeleteByWorkId(ctx *abstraction.Context, work_id int) *gorm.DB
+}
+
+type feedback struct {
+	abstraction.Repository
+}
+
+func NewFeedback(db *gorm.DB) *feedback {
+	return &feedback{
+		Repository: abstraction.Repository{
+			Db: db,
+		},
+	}
+}
+
+func (r *feedback) Create(ctx *abstraction.Context, data *model.FeedbackEntityModel) *gorm.DB {
+	return r.CheckTrx(ctx).Create(data)
+}
+
+func (r *feedback) Find(ctx *abstraction.Context, no_paging bool) (data []*model.FeedbackEntityModel, err error) {
+	where, whereParam := general.ProcessWhereParam(ctx, "feedback", "")
+	limit, offset := general.ProcessLimitOffset(ctx, no_paging)
+	order := general.ProcessOrder(ctx)
+	err = r.CheckTrx(ctx).
+		Where(where, whereParam).
+		Order(order).
+		Limit(limit).
+		Offset(offset).
+		Preload("Area").
+		Preload("User").
+		Find(&data).
+		Error
+	return
+}
+
+func (r *feedback) Count(ctx *abstraction.Context) (data *int, err error) {
+	where, whereParam := general.ProcessWhereParam(ctx, "feedback", "")
+	var count model.FeedbackCountDataModel
+	err = r.CheckTrx(ctx).
+		Table("feedback").
+		Select("COUNT(*) AS count").
+		Where(where, whereParam).
+		Find(&count).
+		Error
+	data = &count.Count
+	return
+}
+
+// satisfaction works on the feedback sent in a created_at range formatted as "YYYY-MM-DD_YYYY-MM-DD"
+// about the works of the task.
+func (r *feedback) satisfaction(ctx *abstraction.Context, task_id int, created_at string) *gorm.DB {
+	valDate := strings.Split(created_at, "_")
+	startDate := valDate[0] + " 00:00:00"
+	endDate := valDate[1] + " 23:59:59"
+
+	return r.CheckTrx(ctx).
+		Model(&model.FeedbackEntityModel{}).
+		Joins("JOIN work ON work.id = feedback.work_id").
+		Where("work.task_id = ? AND feedback.created_at BETWEEN ? AND ?", task_id, startDate, endDate)
+}
+
+func (r *feedback) SatisfactionByFloor(ctx *abstraction.Context, task_id int, created_at string) (data []*model.FloorSatisfaction, err error) {
+	err = r.satisfaction(ctx, task_id, created_at).
+		Select("feedback.floor, COUNT(*) AS count, ROUND(AVG(feedback.rating), 2) AS average").
+		Group("feedback.floor").
+		Order("CAST(SUBSTRING(feedback.floor, 8, LENGTH(feedback.floor)) AS UNSIGNED) ASC").
+		Scan(&data).Error
+	return
+}
+
+func (r *feedback) SatisfactionByUser(ctx *abstraction.Context, task_id int, created_at string) (data []*model.UserSatisfaction, err error) {
+	err = r.satisfaction(ctx, task_id, created_at).
+		Joins("JOIN user ON user.id = feedback.user_id").
+		Select("feedback.user_id, user.name, COUNT(*) AS count, ROUND(AVG(feedback.rating), 2) AS average").
+		Group("feedback.user_id, user.name").
+		Order("feedback.user_id ASC").
+		Scan(&data).Error
+	return
+}
+
+func (r *feedback) DeleteByWorkId(ctx *abstraction.Context, work_id int) *gorm.DB {
+	return r.CheckTrx(ctx).Where("work_id = ?", work_id).Delete(&model.FeedbackEntityModel{})
+}
@@ -0,0 +1,187 @@
+package repository
+
+import (
+	"cleancare/internal/abstraction"
+	"cleancare/internal/model"
+	"cleancare/pkg/util/general"
+
+	"gorm.io/gorm"
+)
+
+type Incident interface {
+	Create(ctx *abstraction.Context, data *model.IncidentEntityModel) *gorm.DB
+	FindById(ctx *abstraction.Context, id int) (*model.IncidentEntityModel, error)
+	Find(ctx *abstraction.Context, user_id int, no_paging bool) (data []*model.IncidentEntityModel, err error)
+	Count(ctx *abstraction.Context, user_id int) (data *int, err error)
+	CountByUserId(ctx *abstraction.Context, user_id int) (data *int, err error)
+	FindByWorkId(ctx *abstraction.Context, work_id int) (data []*model.IncidentEntityModel, err error)
+	Update(ctx *abstraction.Context, data *model.IncidentEntityModel) *gorm.DB
+	UpdateToNull(ctx *abstraction.Context, data *model.IncidentEntityModel, columns ...string) *gorm.DB
+	UnlinkWork(ctx *abstraction.Context, work_id int) *gorm.DB
+	CreatePhoto(ctx *abstraction.Context, data *model.IncidentPhotoEntityModel) *gorm.DB
+	FindPhotoById(ctx *abstraction.Context, id int) (*model.IncidentPhotoEntityModel, error)
+	CountPhoto(ctx *abstraction.Context, incident_id int) (data *int, err error)
+	DeletePhoto(ctx *abstraction.Context, data *model.IncidentPhotoEntityModel) *gorm.DB
+	FindPhotoFileReferences(ctx *abstraction.Context) (data []*model.IncidentPhotoFileReference, err error)
+}
+
+type incident struct {
+	abstraction.Repository
+}
+
+func NewIncident(db *gorm.DB) *incident {
+	return &incident{
+		Repository: abstraction.Repository{
+			Db: db,
+		},
+	}
+}
+
+func (r *incident) Create(ctx *abstraction.Context, data *model.IncidentEntityModel) *gorm.DB {
+	return r.CheckTrx(ctx).Create(data)
+}
+
+func (r *incident) FindById(ctx *abstraction.Context, id int) (*model.IncidentEntityModel, error) {
+	conn := r.CheckTrx(ctx)
+
+	var data model.IncidentEntityModel
+	err := conn.
+		Where("id = ? AND is_delete = ?", id, false).
+		Preload("Reporter").
+		Preload("Assignee").
+		Preload("Photos", func(db *gorm.DB) *gorm.DB {
+			return db.Order("sort_order ASC, id ASC")
+		}).
+		First(&data).
+		Error
+	if err != nil {
+		return nil, err
+	}
+	return &data, nil
+}
+
+// Find lists the incidents, a user_id other than 0 limits them to the ones the user
+// reported or is assigned to.
+func (r *incident) Find(ctx *abstraction.Context, user_id int, no_paging bool) (data []*model.IncidentEntityModel, err error) {
+	where, whereParam := general.ProcessWhereParam(ctx, "incident", "is_delete = @false")
+	if user_id != 0 {
+		where += " AND (created_by = @reporter_id OR assignee_id = @reporter_id)"
+		whereParam["reporter_id"] = user_id
+	}
+	limit, offset := general.ProcessLimitOffset(ctx, no_paging)
+	order := general.ProcessOrder(ctx)
+	err = r.CheckTrx(ctx).
+		Where(where, whereParam).
+		Order(order).
+		Limit(limit).
+		Offset(offset).
+		Preload("Reporter").
+		Preload("Assignee").
+		Preload("Photos", func(db *gorm.DB) *gorm.DB {
+			return db.Order("sort_order ASC, id ASC")
+		}).
+		Find(&data).
+		Error
+	return
+}
+
+func (r *incident) Count(ctx *abstraction.Context, user_id int) (data *int, err error) {
+	where, whereParam := general.ProcessWhereParam(ctx, "incident", "is_delete = @false")
+	if user_id != 0 {
+		where += " AND (created_by = @reporter_id OR assignee_id = @reporter_id)"
+		whereParam["reporter_id"] = user_id
+	}
+	var count model.IncidentCountDataModel
+	err = r.CheckTrx(ctx).
+		Table("incident").
+		Select("COUNT(*) AS count").
+		Where(where, whereParam).
+		Find(&count).
+		Error
+	data = &count.Count
+	return
+}
+
+// CountByUserId counts the incidents reported by, assigned to or holding a photo of the user,
+// deleted incidents included.
+func (r *incident) CountByUserId(ctx *abstraction.Context, user_id int) (data *int, err error) {
+	var count model.IncidentCountDataModel
+	err = r.CheckTrx(ctx).
+		Table("incident").
+		Select("COUNT(*) AS count").
+		Where("created_by = ? OR assignee_id = ? OR id IN (SELECT incident_id FROM incident_photo WHERE created_by = ?)", user_id, user_id, user_id).
+		Find(&count).
+		Error
+	data = &count.Count
+	return
+}
+
+func (r *incident) FindByWorkId(ctx *abstraction.Context, work_id int) (data []*model.IncidentEntityModel, err error) {
+	err = r.CheckTrx(ctx).
+		Where("work_id = ? AND is_delete = ?", work_id, false).
+		Order("id ASC").
+		Find(&data).
+		Error
+	return
+}
+
+func (r *incident) Update(ctx *abstraction.Context, data *model.IncidentEntityModel) *gorm.DB {
+	return r.CheckTrx(ctx).Model(data).Where("id = ?", data.ID).Updates(data)
+}
+
+func (r *incident) UpdateToNull(ctx *abstraction.Context, data *model.IncidentEntityModel, columns ...string) *gorm.DB {
+	values := make(map[string]interface{}, len(columns))
+	for _, v := range columns {
+		values[v] = nil
+	}
+	return r.CheckTrx(ctx).Model(data).Where("id = ?", data.ID).Updates(values)
+}
+
+// UnlinkWork clears the work of every incident found during it, deleted incidents included.
+func (r *incident) UnlinkWork(ctx *abstraction.Context, work_id int) *gorm.DB {
+	return r.CheckTrx(ctx).Model(&model.IncidentEntityModel{Context: ctx}).Where("work_id = ?", work_id).Update("work_id", nil)
+}
+
+func (r *incident) CreatePhoto(ctx *abstraction.Context, data *model.IncidentPhotoEntityModel) *gorm.DB {
+	return r.CheckTrx(ctx).Create(data)
+}
+
+func (r *incident) FindPhotoById(ctx *abstraction.Context, id int) (*model.IncidentPhotoEntityModel, error) {
+	var data model.IncidentPhotoEntityModel
+	err := r.CheckTrx(ctx).
+		Where("id = ?", id).
+		First(&data).
+		Error
+	if err != nil {
+		return nil, err
+	}
+	return &data, nil
+}
+
+func (r *incident) CountPhoto(ctx *abstraction.Context, incident_id int) (data *int, err error) {
+	var count model.IncidentCountDataModel
+	err = r.CheckTrx(ctx).
+		Table("incident_photo").
+		Select("COUNT(*) AS count").
+		Where("incident_id = ?", incident_id).
+		Find(&count).
+		Error
+	data = &count.Count
+	return
+}
+
+func (r *incident) DeletePhoto(ctx *abstraction.Context, data *model.IncidentPhotoEntityModel) *gorm.DB {
+	return r.CheckTrx(ctx).Where("id = ?", data.ID).Delete(data)
+}
+
+// FindPhotoFileReferences returns the drive file of every incident photo, deleted incidents
+// included.
+func (r *incident) FindPhotoFileReferences(ctx *abstraction.Context) (data []*model.IncidentPhotoFileReference, err error) {
+	err = r.CheckTrx(ctx).
+		Table("incident_photo").
+		Select("id, incident_id, storage_key").
+		Order("id ASC").
+		Find(&data).
+		Error
+	return
+}
@@ -0,0 +1,84 @@
+package repository
+
+import (
+	"cleancare/internal/abstraction"
+	"cleancare/internal/model"
+	"cleancare/pkg/util/general"
+
+	"gorm.io/gorm"
+)
+
+type InventoryItem interface {
+	Create(ctx *abstraction.Context, data *model.InventoryItemEntityModel) *gorm.DB
+	FindById(ctx *abstraction.Context, id int) (*model.InventoryItemEntityModel, error)
+	Find(ctx *abstraction.Context, no_paging bool) (data []*model.InventoryItemEntityModel, err error)
+	Count(ctx *abstraction.Context) (data *int, err error)
+	Update(ctx *abstraction.Context, data *model.InventoryItemEntityModel) *gorm.DB
+	UpdateMinStock(ctx *abstraction.Context, data *model.InventoryItemEntityModel, min_stock float64) *gorm.DB
+}
+
+type inventory_item struct {
+	abstraction.Repository
+}
+
+func NewInventoryItem(db *gorm.DB) *inventory_item {
+	return &inventory_item{
+		Repository: abstraction.Repository{
+			Db: db,
+		},
+	}
+}
+
+func (r *inventory_item) Create(ctx *abstraction.Context, data *model.InventoryItemEntityModel) *gorm.DB {
+	return r.CheckTrx(ctx).Create(data)
+}
+
+func (r *inventory_item) FindById(ctx *abstraction.Context, id int) (*model.InventoryItemEntityModel, error) {
+	conn := r.CheckTrx(ctx)
+
+	var data model.InventoryItemEntityModel
+	err := conn.
+		Where("id = ? AND is_delete = ?", id, false).
+		First(&data).
+		Error
+	if err != nil {
+		return nil, err
+	}
+	return &data, nil
+}
+
+func (r *inventory_item) Find(ctx *abstraction.Context, no_paging bool) (data []*model.InventoryItemEntityModel, err error) {
+	where, whereParam := general.ProcessWhereParam(ctx, "inventory_item", "is_delete = @false")
+	limit, offset := general.ProcessLimitOffset(ctx, no_paging)
+	order := general.ProcessOrder(ctx)
+	err = r.CheckTrx(ctx).
+		Where(where, whereParam).
+		Order(order).
+		Limit(limit).
+		Offset(offset).
+		Find(&data).
+		Error
+	return
+}
+
+func (r *inventory_item) Count(ctx *abstraction.Context) (data *int, err error) {
+	where, whereParam := general.ProcessWhereParam(ctx, "inventory_item", "is_delete = @false")
+	var count model.InventoryItemCountDataModel
+	err = r.CheckTrx(ctx).
+		Table("inventory_item").
+		Select("COUNT(*) AS count").
+		Where(where, whereParam).
+		Find(&count).
+		Error
+	data = &count.Count
+	return
+}
+
+func (r *inventory_item) Update(ctx *abstraction.Context, data *model.InventoryItemEntityModel) *gorm.DB {
+	return r.CheckTrx(ctx).Model(data).Where("id = ?", data.ID).Updates(data)
+}
+
+// UpdateMinStock is separate from Update since a threshold of 0 is skipped by Updates.
+func (r *inventory_item) UpdateMinStock(ctx *abstraction.Context, data *model.InventoryItemEntityModel, min_stock float64) *gorm.DB {
+	return r.CheckTrx(ctx).Model(data).Where("id = ?", data.ID).Update("min_stock", min_stock)
+}
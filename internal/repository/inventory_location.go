@@ -0,0 +1,78 @@
+package repository
+
+import (
+	"cleancare/internal/abstraction"
+	"cleancare/internal/model"
+	"cleancare/pkg/util/general"
+
+	"gorm.io/gorm"
+)
+
+type InventoryLocation interface {
+	Create(ctx *abstraction.Context, data *model.InventoryLocationEntityModel) *gorm.DB
+	FindById(ctx *abstraction.Context, id int) (*model.InventoryLocationEntityModel, error)
+	Find(ctx *abstraction.Context, no_paging bool) (data []*model.InventoryLocationEntityModel, err error)
+	Count(ctx *abstraction.Context) (data *int, err error)
+	Update(ctx *abstraction.Context, data *model.InventoryLocationEntityModel) *gorm.DB
+}
+
+type inventory_location struct {
+	abstraction.Repository
+}
+
+func NewInventoryLocation(db *gorm.DB) *inventory_location {
+	return &inventory_location{
+		Repository: abstraction.Repository{
+			Db: db,
+		},
+	}
+}
+
+func (r *inventory_location) Create(ctx *abstraction.Context, data *model.InventoryLocationEntityModel) *gorm.DB {
+	return r.CheckTrx(ctx).Create(data)
+}
+
+func (r *inventory_location) FindById(ctx *abstraction.Context, id int) (*model.InventoryLocationEntityModel, error) {
+	conn := r.CheckTrx(ctx)
+
+	var data model.InventoryLocationEntityModel
+	err := conn.
+		Where("id = ? AND is_delete = ?", id, false).
+		First(&data).
+		Error
+	if err != nil {
+		return nil, err
+	}
+	return &data, nil
+}
+
+func (r *inventory_location) Find(ctx *abstraction.Context, no_paging bool) (data []*model.InventoryLocationEntityModel, err error) {
+	where, whereParam := general.ProcessWhereParam(ctx, "inventory_location", "is_delete = @false")
+	limit, offset := general.ProcessLimitOffset(ctx, no_paging)
+	order := general.ProcessOrder(ctx)
+	err = r.CheckTrx(ctx).
+		Where(where, whereParam).
+		Order(order).
+		Limit(limit).
+		Offset(offset).
+		Find(&data).
+		Error
+	return
+}
+
+func (r *inventory_location) Count(ctx *abstraction.Context) (data *int, err error) {
+	where, whereParam := general.ProcessWhereParam(ctx, "inventory_location", "is_delete = @false")
+	var count model.InventoryLocationCountDataModel
+	err = r.CheckTrx(ctx).
+		Table("inventory_location").
+		Select("COUNT(*) AS count").
+		Where(where, whereParam).
+		Find(&count).
+		Error
+	data = &count.Count
+	return
+}
+
+func (r *inventory_location) Update(ctx *abstraction.Context, data *model.InventoryLocationEntityModel) *gorm.DB {
+	return r.CheckTrx(ctx).Model(data).Where("id = ?", data.ID).Updates(data)
+}
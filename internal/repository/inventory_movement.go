@@ -0,0 +1,131 @@
+package repository
+
+import (
+	"cleancare/internal/abstraction"
+	"cleancare/internal/model"
+	"cleancare/pkg/constant"
+	"cleancare/pkg/util/general"
+
+	"gorm.io/gorm"
+)
+
+type InventoryMovement interface {
+	Create(ctx *abstraction.Context, data *model.InventoryMovementEntityModel) *gorm.DB
+	Find(ctx *abstraction.Context, user_id int, no_paging bool) (data []*model.InventoryMovementEntityModel, err error)
+	Count(ctx *abstraction.Context, user_id int) (data *int, err error)
+	Consumption(ctx *abstraction.Context, start_date, end_date string, location_id int) (data []*model.InventoryConsumption, err error)
+	CountByCreatedBy(ctx *abstraction.Context, created_by int) (data *int, err error)
+	UnlinkWork(ctx *abstraction.Context, work_id int) *gorm.DB
+	UnlinkTaskType(ctx *abstraction.Context, task_type_id int) *gorm.DB
+}
+
+type inventory_movement struct {
+	abstraction.Repository
+}
+
+func NewInventoryMovement(db *gorm.DB) *inventory_movement {
+	return &inventory_movement{
+		Repository: abstraction.Repository{
+			Db: db,
+		},
+	}
+}
+
+func (r *inventory_movement) Create(ctx *abstraction.Context, data *model.InventoryMovementEntityModel) *gorm.DB {
+	return r.CheckTrx(ctx).Create(data)
+}
+
+// Find lists the movements, a user_id other than 0 limits them to the ones the user recorded.
+func (r *inventory_movement) Find(ctx *abstraction.Context, user_id int, no_paging bool) (data []*model.InventoryMovementEntityModel, err error) {
+	where, whereParam := general.ProcessWhereParam(ctx, "inventory_movement", "")
+	if user_id != 0 {
+		where += " AND created_by = @recorded_by"
+		whereParam["recorded_by"] = user_id
+	}
+	limit, offset := general.ProcessLimitOffset(ctx, no_paging)
+	order := general.ProcessOrder(ctx)
+	err = r.CheckTrx(ctx).
+		Where(where, whereParam).
+		Order(order).
+		Limit(limit).
+		Offset(offset).
+		Preload("Item").
+		Preload("Location").
+		Preload("User").
+		Preload("TaskType").
+		Find(&data).
+		Error
+	return
+}
+
+func (r *inventory_movement) Count(ctx *abstraction.Context, user_id int) (data *int, err error) {
+	where, whereParam := general.ProcessWhereParam(ctx, "inventory_movement", "")
+	if user_id != 0 {
+		where += " AND created_by = @recorded_by"
+		whereParam["recorded_by"] = user_id
+	}
+	var count model.InventoryMovementCountDataModel
+	err = r.CheckTrx(ctx).
+		Table("inventory_movement").
+		Select("COUNT(*) AS count").
+		Where(where, whereParam).
+		Find(&count).
+		Error
+	data = &count.Count
+	return
+}
+
+// Consumption sums the movements of every item between the dates, stock is the current stock
+// of the item. A location_id other than 0 limits both to that location.
+func (r *inventory_movement) Consumption(ctx *abstraction.Context, start_date, end_date string, location_id int) (data []*model.InventoryConsumption, err error) {
+	movementJoin := "LEFT JOIN inventory_movement ON inventory_movement.item_id = inventory_item.id AND inventory_movement.created_at BETWEEN ? AND ?"
+	movementParams := []interface{}{start_date, end_date}
+	stockSelect := "(SELECT COALESCE(SUM(quantity), 0) FROM inventory_stock WHERE inventory_stock.item_id = inventory_item.id) AS stock"
+	var stockParams []interface{}
+	if location_id != 0 {
+		movementJoin += " AND inventory_movement.location_id = ?"
+		movementParams = append(movementParams, location_id)
+		stockSelect = "(SELECT COALESCE(SUM(quantity), 0) FROM inventory_stock WHERE inventory_stock.item_id = inventory_item.id AND inventory_stock.location_id = ?) AS stock"
+		stockParams = append(stockParams, location_id)
+	}
+
+	err = r.CheckTrx(ctx).
+		Table("inventory_item").
+		Select("inventory_item.id AS item_id, inventory_item.name, inventory_item.unit, "+
+			"COALESCE(SUM(CASE WHEN inventory_movement.type = ? THEN inventory_movement.quantity END), 0) AS stock_in, "+
+			"COALESCE(-SUM(CASE WHEN inventory_movement.type = ? THEN inventory_movement.quantity END), 0) AS stock_out, "+
+			"COALESCE(SUM(CASE WHEN inventory_movement.type = ? THEN inventory_movement.quantity END), 0) AS adjusted, "+
+			stockSelect,
+			append([]interface{}{constant.INVENTORY_MOVEMENT_IN, constant.INVENTORY_MOVEMENT_OUT, constant.INVENTORY_MOVEMENT_ADJUST}, stockParams...)...).
+		Joins(movementJoin, movementParams...).
+		Where("inventory_item.is_delete = ?", false).
+		Group("inventory_item.id, inventory_item.name, inventory_item.unit").
+		Order("inventory_item.name ASC").
+		Scan(&data).
+		Error
+	return
+}
+
+func (r *inventory_movement) CountByCreatedBy(ctx *abstraction.Context, created_by int) (data *int, err error) {
+	var count model.InventoryMovementCountDataModel
+	err = r.CheckTrx(ctx).
+		Table("inventory_movement").
+		Select("COUNT(*) AS count").
+		Where("created_by = ?", created_by).
+		Find(&count).
+		Error
+	data = &count.Count
+	return
+}
+
+// UnlinkWork clears the work of every movement recorded for it, the movements stay in the stock
+// history.
+func (r *inventory_movement) UnlinkWork(ctx *abstraction.Context, work_id int) *gorm.DB {
+	return r.CheckTrx(ctx).Model(&model.InventoryMovementEntityModel{Context: ctx}).Where("work_id = ?", work_id).Update("work_id", nil)
+}
+
+// UnlinkTaskType clears the task type of every movement recorded for it, the movements stay in
+// the stock history.
+func (r *inventory_movement) UnlinkTaskType(ctx *abstraction.Context, task_type_id int) *gorm.DB {
+	return r.CheckTrx(ctx).Model(&model.InventoryMovementEntityModel{Context: ctx}).Where("task_type_id = ?", task_type_id).Update("task_type_id", nil)
+}
@@ -0,0 +1,127 @@
+package repository
+
+import (
+	"cleancare/internal/abstraction"
+	"cleancare/internal/model"
+	"cleancare/pkg/util/general"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type InventoryStock interface {
+	FindForUpdate(ctx *abstraction.Context, item_id, location_id int) (*model.InventoryStockEntityModel, error)
+	UpdateQuantity(ctx *abstraction.Context, data *model.InventoryStockEntityModel, quantity float64) *gorm.DB
+	Find(ctx *abstraction.Context, no_paging bool) (data []*model.InventoryStockEntityModel, err error)
+	Count(ctx *abstraction.Context) (data *int, err error)
+	CountByItemId(ctx *abstraction.Context, item_id int) (data *int, err error)
+	CountByLocationId(ctx *abstraction.Context, location_id int) (data *int, err error)
+}
+
+type inventory_stock struct {
+	abstraction.Repository
+}
+
+func NewInventoryStock(db *gorm.DB) *inventory_stock {
+	return &inventory_stock{
+		Repository: abstraction.Repository{
+			Db: db,
+		},
+	}
+}
+
+// FindForUpdate locks the stock of the item at the location until the transaction ends, the
+// row is created empty on the first movement.
+func (r *inventory_stock) FindForUpdate(ctx *abstraction.Context, item_id, location_id int) (*model.InventoryStockEntityModel, error) {
+	conn := r.CheckTrx(ctx)
+
+	newData := &model.InventoryStockEntityModel{
+		InventoryStockEntity: model.InventoryStockEntity{
+			ItemId:     item_id,
+			LocationId: location_id,
+		},
+	}
+	if err := conn.Clauses(clause.OnConflict{DoNothing: true}).Create(newData).Error; err != nil {
+		return nil, err
+	}
+
+	var data model.InventoryStockEntityModel
+	err := conn.
+		Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("item_id = ? AND location_id = ?", item_id, location_id).
+		First(&data).
+		Error
+	if err != nil {
+		return nil, err
+	}
+	return &data, nil
+}
+
+func (r *inventory_stock) UpdateQuantity(ctx *abstraction.Context, data *model.InventoryStockEntityModel, quantity float64) *gorm.DB {
+	return r.CheckTrx(ctx).Model(data).Where("id = ?", data.ID).Update("quantity", quantity)
+}
+
+// stockWhere leaves out the stock of deleted items and locations, low=yes keeps the stock
+// below the threshold of its item.
+func stockWhere(ctx *abstraction.Context) (string, map[string]interface{}) {
+	where, whereParam := general.ProcessWhereParam(ctx, "inventory_stock", "item_id IN (SELECT id FROM inventory_item WHERE is_delete = @false) AND location_id IN (SELECT id FROM inventory_location WHERE is_delete = @false)")
+	if ctx.QueryParam("low") == "yes" {
+		where += " AND quantity < (SELECT min_stock FROM inventory_item WHERE inventory_item.id = inventory_stock.item_id)"
+	}
+	return where, whereParam
+}
+
+func (r *inventory_stock) Find(ctx *abstraction.Context, no_paging bool) (data []*model.InventoryStockEntityModel, err error) {
+	where, whereParam := stockWhere(ctx)
+	limit, offset := general.ProcessLimitOffset(ctx, no_paging)
+	order := general.ProcessOrder(ctx)
+	err = r.CheckTrx(ctx).
+		Where(where, whereParam).
+		Order(order).
+		Limit(limit).
+		Offset(offset).
+		Preload("Item").
+		Preload("Location").
+		Find(&data).
+		Error
+	return
+}
+
+func (r *inventory_stock) Count(ctx *abstraction.Context) (data *int, err error) {
+	where, whereParam := stockWhere(ctx)
+	var count model.InventoryStockCountDataModel
+	err = r.CheckTrx(ctx).
+		Table("inventory_stock").
+		Select("COUNT(*) AS count").
+		Where(where, whereParam).
+		Find(&count).
+		Error
+	data = &count.Count
+	return
+}
+
+// CountByItemId counts the locations still holding the item.
+func (r *inventory_stock) CountByItemId(ctx *abstraction.Context, item_id int) (data *int, err error) {
+	var count model.InventoryStockCountDataModel
+	err = r.CheckTrx(ctx).
+		Table("inventory_stock").
+		Select("COUNT(*) AS count").
+		Where("item_id = ? AND quantity > 0", item_id).
+		Find(&count).
+		Error
+	data = &count.Count
+	return
+}
+
+// CountByLocationId counts the items still stored at the location.
+func (r *inventory_stock) CountByLocationId(ctx *abstraction.Context, location_id int) (data *int, err error) {
+	var count model.InventoryStockCountDataModel
+	err = r.CheckTrx(ctx).
+		Table("inventory_stock").
+		Select("COUNT(*) AS count").
+		Where("location_id = ? AND quantity > 0", location_id).
+		Find(&count).
+		Error
+	data = &count.Count
+	return
+}
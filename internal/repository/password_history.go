@@ -0,0 +1,58 @@
+package repository
+
+import (
+	"cleancare/internal/abstraction"
+	"cleancare/internal/model"
+
+	"gorm.io/gorm"
+)
+
+type PasswordHistory interface {
+	Create(ctx *abstraction.Context, data *model.PasswordHistoryEntityModel) *gorm.DB
+	Add(ctx *abstraction.Context, user_id int, password string) *gorm.DB
+	FindLastPasswords(ctx *abstraction.Context, user_id int, limit int) (data []string, err error)
+	DeleteByUserId(ctx *abstraction.Context, user_id int) *gorm.DB
+}
+
+type passwordHistory struct {
+	abstraction.Repository
+}
+
+func NewPasswordHistory(db *gorm.DB) *passwordHistory {
+	return &passwordHistory{
+		Repository: abstraction.Repository{
+			Db: db,
+		},
+	}
+}
+
+func (r *passwordHistory) Create(ctx *abstraction.Context, data *model.PasswordHistoryEntityModel) *gorm.DB {
+	return r.CheckTrx(ctx).Create(data)
+}
+
+// Add keeps password, already hashed, as the newest password of user_id.
+func (r *passwordHistory) Add(ctx *abstraction.Context, user_id int, password string) *gorm.DB {
+	return r.Create(ctx, &model.PasswordHistoryEntityModel{
+		Context: ctx,
+		PasswordHistoryEntity: model.PasswordHistoryEntity{
+			UserId:   user_id,
+			Password: password,
+		},
+	})
+}
+
+// FindLastPasswords returns the last limit password hashes of user_id, newest first.
+func (r *passwordHistory) FindLastPasswords(ctx *abstraction.Context, user_id int, limit int) (data []string, err error) {
+	err = r.CheckTrx(ctx).
+		Model(&model.PasswordHistoryEntityModel{}).
+		Where("user_id = ?", user_id).
+		Order("created_at DESC, id DESC").
+		Limit(limit).
+		Pluck("password", &data).
+		Error
+	return
+}
+
+func (r *passwordHistory) DeleteByUserId(ctx *abstraction.Context, user_id int) *gorm.DB {
+	return r.CheckTrx(ctx).Where("user_id = ?", user_id).Delete(&model.PasswordHistoryEntityModel{})
+}
@@ -0,0 +1,132 @@
+package repository
+
+import (
+	"cleancare/internal/abstraction"
+	"cleancare/internal/model"
+	"cleancare/pkg/constant"
+	"cleancare/pkg/util/general"
+
+	"gorm.io/gorm"
+)
+
+type PhotoFlag interface {
+	Create(ctx *abstraction.Context, data *model.PhotoFlagEntityModel) *gorm.DB
+	FindById(ctx *abstraction.Context, id int) (*model.PhotoFlagEntityModel, error)
+	Find(ctx *abstraction.Context, no_paging bool) (data []*model.PhotoFlagEntityModel, err error)
+	Count(ctx *abstraction.Context) (data *int, err error)
+	FindByWorkId(ctx *abstraction.Context, work_id int) (data []*model.PhotoFlagEntityModel, err error)
+	FindOpenWorkIds(ctx *abstraction.Context, work_ids []int) (data []int, err error)
+	Update(ctx *abstraction.Context, data *model.PhotoFlagEntityModel) *gorm.DB
+	DeleteByWorkPhotoId(ctx *abstraction.Context, work_photo_id int) *gorm.DB
+	DeleteByWorkId(ctx *abstraction.Context, work_id int) *gorm.DB
+	UnlinkMatchWork(ctx *abstraction.Context, work_id int) *gorm.DB
+}
+
+type photo_flag struct {
+	abstraction.Repository
+}
+
+func NewPhotoFlag(db *gorm.DB) *photo_flag {
+	return &photo_flag{
+		Repository: abstraction.Repository{
+			Db: db,
+		},
+	}
+}
+
+func (r *photo_flag) Create(ctx *abstraction.Context, data *model.PhotoFlagEntityModel) *gorm.DB {
+	return r.CheckTrx(ctx).Create(data)
+}
+
+func (r *photo_flag) FindById(ctx *abstraction.Context, id int) (*model.PhotoFlagEntityModel, error) {
+	conn := r.CheckTrx(ctx)
+
+	var data model.PhotoFlagEntityModel
+	err := conn.
+		Where("id = ?", id).
+		Preload("Work").
+		Preload("Work.User").
+		Preload("WorkPhoto").
+		Preload("MatchWorkPhoto").
+		First(&data).
+		Error
+	if err != nil {
+		return nil, err
+	}
+	return &data, nil
+}
+
+func (r *photo_flag) Find(ctx *abstraction.Context, no_paging bool) (data []*model.PhotoFlagEntityModel, err error) {
+	where, whereParam := general.ProcessWhereParam(ctx, "photo_flag", "")
+	limit, offset := general.ProcessLimitOffset(ctx, no_paging)
+	order := general.ProcessOrder(ctx)
+	err = r.CheckTrx(ctx).
+		Where(where, whereParam).
+		Order(order).
+		Limit(limit).
+		Offset(offset).
+		Preload("Work").
+		Preload("Work.User").
+		Preload("WorkPhoto").
+		Preload("MatchWorkPhoto").
+		Find(&data).
+		Error
+	return
+}
+
+func (r *photo_flag) Count(ctx *abstraction.Context) (data *int, err error) {
+	where, whereParam := general.ProcessWhereParam(ctx, "photo_flag", "")
+	var count model.PhotoFlagCountDataModel
+	err = r.CheckTrx(ctx).
+		Table("photo_flag").
+		Select("COUNT(*) AS count").
+		Where(where, whereParam).
+		Find(&count).
+		Error
+	data = &count.Count
+	return
+}
+
+func (r *photo_flag) FindByWorkId(ctx *abstraction.Context, work_id int) (data []*model.PhotoFlagEntityModel, err error) {
+	err = r.CheckTrx(ctx).
+		Where("work_id = ?", work_id).
+		Order("id ASC").
+		Find(&data).
+		Error
+	return
+}
+
+// FindOpenWorkIds returns which of work_ids have a flag that is not reviewed yet.
+func (r *photo_flag) FindOpenWorkIds(ctx *abstraction.Context, work_ids []int) (data []int, err error) {
+	if len(work_ids) == 0 {
+		return
+	}
+	err = r.CheckTrx(ctx).
+		Table("photo_flag").
+		Distinct("work_id").
+		Where("work_id IN ? AND status = ?", work_ids, constant.PHOTO_FLAG_STATUS_OPEN).
+		Pluck("work_id", &data).
+		Error
+	return
+}
+
+func (r *photo_flag) Update(ctx *abstraction.Context, data *model.PhotoFlagEntityModel) *gorm.DB {
+	return r.CheckTrx(ctx).Model(data).Where("id = ?", data.ID).Updates(data)
+}
+
+func (r *photo_flag) DeleteByWorkPhotoId(ctx *abstraction.Context, work_photo_id int) *gorm.DB {
+	return r.CheckTrx(ctx).Where("work_photo_id = ?", work_photo_id).Delete(&model.PhotoFlagEntityModel{})
+}
+
+func (r *photo_flag) DeleteByWorkId(ctx *abstraction.Context, work_id int) *gorm.DB {
+	return r.CheckTrx(ctx).Where("work_id = ?", work_id).Delete(&model.PhotoFlagEntityModel{})
+}
+
+// UnlinkMatchWork clears the match of the duplicate flags of other works pointing to the work,
+// the flags stay for their own photos.
+func (r *photo_flag) UnlinkMatchWork(ctx *abstraction.Context, work_id int) *gorm.DB {
+	return r.CheckTrx(ctx).Model(&model.PhotoFlagEntityModel{Context: ctx}).Where("match_work_id = ?", work_id).Updates(map[string]interface{}{
+		"match_work_id":       nil,
+		"match_work_photo_id": nil,
+	})
+}
@@ -0,0 +1,124 @@
+package repository
+
+import (
+	"cleancare/internal/abstraction"
+	"cleancare/internal/model"
+	"cleancare/pkg/util/general"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type ServiceRequest interface {
+	Create(ctx *abstraction.Context, data *model.ServiceRequestEntityModel) *gorm.DB
+	FindById(ctx *abstraction.Context, id int) (*model.ServiceRequestEntityModel, error)
+	FindByIdForUpdate(ctx *abstraction.Context, id int) (*model.ServiceRequestEntityModel, error)
+	Find(ctx *abstraction.Context, no_paging bool) (data []*model.ServiceRequestEntityModel, err error)
+	Count(ctx *abstraction.Context) (data *int, err error)
+	Triage(ctx *abstraction.Context, data *model.ServiceRequestEntityModel) *gorm.DB
+	FindFileReferences(ctx *abstraction.Context) (data []*model.ServiceRequestFileReference, err error)
+}
+
+type service_request struct {
+	abstraction.Repository
+}
+
+func NewServiceRequest(db *gorm.DB) *service_request {
+	return &service_request{
+		Repository: abstraction.Repository{
+			Db: db,
+		},
+	}
+}
+
+func (r *service_request) Create(ctx *abstraction.Context, data *model.ServiceRequestEntityModel) *gorm.DB {
+	return r.CheckTrx(ctx).Create(data)
+}
+
+func (r *service_request) FindById(ctx *abstraction.Context, id int) (*model.ServiceRequestEntityModel, error) {
+	conn := r.CheckTrx(ctx)
+
+	var data model.ServiceRequestEntityModel
+	err := conn.
+		Where("id = ?", id).
+		Preload("Area").
+		Preload("Assignment").
+		Preload("Assignment.Work", "is_delete = ?", false).
+		First(&data).
+		Error
+	if err != nil {
+		return nil, err
+	}
+	return &data, nil
+}
+
+// FindByIdForUpdate locks the request until the transaction ends, so two admins triaging it at
+// once cannot both see it pending.
+func (r *service_request) FindByIdForUpdate(ctx *abstraction.Context, id int) (*model.ServiceRequestEntityModel, error) {
+	conn := r.CheckTrx(ctx)
+
+	var data model.ServiceRequestEntityModel
+	err := conn.
+		Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("id = ?", id).
+		Preload("Area").
+		First(&data).
+		Error
+	if err != nil {
+		return nil, err
+	}
+	return &data, nil
+}
+
+func (r *service_request) Find(ctx *abstraction.Context, no_paging bool) (data []*model.ServiceRequestEntityModel, err error) {
+	where, whereParam := general.ProcessWhereParam(ctx, "service_request", "")
+	limit, offset := general.ProcessLimitOffset(ctx, no_paging)
+	order := general.ProcessOrder(ctx)
+	err = r.CheckTrx(ctx).
+		Where(where, whereParam).
+		Order(order).
+		Limit(limit).
+		Offset(offset).
+		Preload("Area").
+		Preload("Assignment").
+		Preload("Assignment.Work", "is_delete = ?", false).
+		Find(&data).
+		Error
+	return
+}
+
+func (r *service_request) Count(ctx *abstraction.Context) (data *int, err error) {
+	where, whereParam := general.ProcessWhereParam(ctx, "service_request", "")
+	var count model.ServiceRequestCountDataModel
+	err = r.CheckTrx(ctx).
+		Table("service_request").
+		Select("COUNT(*) AS count").
+		Where(where, whereParam).
+		Find(&count).
+		Error
+	data = &count.Count
+	return
+}
+
+// Triage writes the decision of an admin with a map, a rejected request has no assignment.
+func (r *service_request) Triage(ctx *abstraction.Context, data *model.ServiceRequestEntityModel) *gorm.DB {
+	return r.CheckTrx(ctx).Model(data).Where("id = ?", data.ID).Updates(map[string]interface{}{
+		"status":        data.Status,
+		"assignment_id": data.AssignmentId,
+		"triage_note":   data.TriageNote,
+		"triaged_by":    data.TriagedBy,
+		"triaged_at":    data.TriagedAt,
+	})
+}
+
+// FindFileReferences returns the drive file of every service request with a photo.
+func (r *service_request) FindFileReferences(ctx *abstraction.Context) (data []*model.ServiceRequestFileReference, err error) {
+	err = r.CheckTrx(ctx).
+		Table("service_request").
+		Select("id, photo_storage_key").
+		Where("photo_storage_key IS NOT NULL AND photo_storage_key <> ''").
+		Order("id ASC").
+		Find(&data).
+		Error
+	return
+}
@@ -0,0 +1,147 @@
+package repository
+
+import (
+	"cleancare/internal/abstraction"
+	"cleancare/internal/model"
+	"cleancare/pkg/util/general"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type SlaPolicy interface {
+	FindById(ctx *abstraction.Context, id int) (*model.SlaPolicyEntityModel, error)
+	Find(ctx *abstraction.Context, no_paging bool) (data []*model.SlaPolicyEntityModel, err error)
+	Count(ctx *abstraction.Context) (data *int, err error)
+	FindByTaskAndType(ctx *abstraction.Context, task_id int, task_type_id *int) (*model.SlaPolicyEntityModel, error)
+	FindForWork(ctx *abstraction.Context, task_id int, task_type_id int) (*model.SlaPolicyEntityModel, error)
+	SetWorkSla(ctx *abstraction.Context, data *model.WorkEntity, task *model.TaskEntityModel, task_type_id int, from time.Time) error
+	Create(ctx *abstraction.Context, data *model.SlaPolicyEntityModel) *gorm.DB
+	Update(ctx *abstraction.Context, data *model.SlaPolicyEntityModel) *gorm.DB
+	Delete(ctx *abstraction.Context, data *model.SlaPolicyEntityModel) *gorm.DB
+	DeleteByTaskTypeId(ctx *abstraction.Context, task_type_id int) *gorm.DB
+}
+
+type sla_policy struct {
+	abstraction.Repository
+}
+
+func NewSlaPolicy(db *gorm.DB) *sla_policy {
+	return &sla_policy{
+		Repository: abstraction.Repository{
+			Db: db,
+		},
+	}
+}
+
+func (r *sla_policy) FindById(ctx *abstraction.Context, id int) (*model.SlaPolicyEntityModel, error) {
+	conn := r.CheckTrx(ctx)
+
+	var data model.SlaPolicyEntityModel
+	err := conn.
+		Where("id = ? AND is_delete = ?", id, false).
+		Preload("Task").
+		Preload("TaskType").
+		First(&data).
+		Error
+	if err != nil {
+		return nil, err
+	}
+	return &data, nil
+}
+
+func (r *sla_policy) Find(ctx *abstraction.Context, no_paging bool) (data []*model.SlaPolicyEntityModel, err error) {
+	where, whereParam := general.ProcessWhereParam(ctx, "sla_policy", "is_delete = @false")
+	limit, offset := general.ProcessLimitOffset(ctx, no_paging)
+	order := general.ProcessOrder(ctx)
+	err = r.CheckTrx(ctx).
+		Where(where, whereParam).
+		Order(order).
+		Limit(limit).
+		Offset(offset).
+		Preload("Task").
+		Preload("TaskType").
+		Find(&data).
+		Error
+	return
+}
+
+func (r *sla_policy) Count(ctx *abstraction.Context) (data *int, err error) {
+	where, whereParam := general.ProcessWhereParam(ctx, "sla_policy", "is_delete = @false")
+	var count model.SlaPolicyCountDataModel
+	err = r.CheckTrx(ctx).
+		Table("sla_policy").
+		Select("COUNT(*) AS count").
+		Where(where, whereParam).
+		Find(&count).
+		Error
+	data = &count.Count
+	return
+}
+
+// FindByTaskAndType returns the policy set for exactly the task and task type, a nil task
+// type is the policy of the whole task.
+func (r *sla_policy) FindByTaskAndType(ctx *abstraction.Context, task_id int, task_type_id *int) (*model.SlaPolicyEntityModel, error) {
+	conn := r.CheckTrx(ctx).Where("task_id = ? AND is_delete = ?", task_id, false)
+	if task_type_id != nil {
+		conn = conn.Where("task_type_id = ?", *task_type_id)
+	} else {
+		conn = conn.Where("task_type_id IS NULL")
+	}
+
+	var data model.SlaPolicyEntityModel
+	if err := conn.First(&data).Error; err != nil {
+		return nil, err
+	}
+	return &data, nil
+}
+
+// FindForWork returns the policy of the task type of a work, or else the policy of its task.
+func (r *sla_policy) FindForWork(ctx *abstraction.Context, task_id int, task_type_id int) (*model.SlaPolicyEntityModel, error) {
+	var data model.SlaPolicyEntityModel
+	err := r.CheckTrx(ctx).
+		Where("task_id = ? AND (task_type_id = ? OR task_type_id IS NULL) AND is_delete = ?", task_id, task_type_id, false).
+		Order("task_type_id IS NULL ASC, id DESC").
+		First(&data).
+		Error
+	if err != nil {
+		return nil, err
+	}
+	return &data, nil
+}
+
+// SetWorkSla gives the work the due times of the policy of its task type or task, counted from from.
+func (r *sla_policy) SetWorkSla(ctx *abstraction.Context, data *model.WorkEntity, task *model.TaskEntityModel, task_type_id int, from time.Time) error {
+	policyData, err := r.FindForWork(ctx, task.ID, task_type_id)
+	if err != nil && err.Error() != "record not found" {
+		return err
+	}
+	data.SlaPolicyId, data.SlaStartDueAt, data.SlaCompleteDueAt, data.SlaStatus = model.SlaDue(policyData, task, from)
+	return nil
+}
+
+func (r *sla_policy) Create(ctx *abstraction.Context, data *model.SlaPolicyEntityModel) *gorm.DB {
+	return r.CheckTrx(ctx).Create(data)
+}
+
+// Update writes the limits with a map, either of them may be removed.
+func (r *sla_policy) Update(ctx *abstraction.Context, data *model.SlaPolicyEntityModel) *gorm.DB {
+	return r.CheckTrx(ctx).Model(data).Where("id = ?", data.ID).Updates(map[string]interface{}{
+		"start_minutes":    data.StartMinutes,
+		"complete_minutes": data.CompleteMinutes,
+		"at_risk_percent":  data.AtRiskPercent,
+		"updated_by":       ctx.Auth.ID,
+	})
+}
+
+func (r *sla_policy) Delete(ctx *abstraction.Context, data *model.SlaPolicyEntityModel) *gorm.DB {
+	return r.CheckTrx(ctx).Model(data).Where("id = ?", data.ID).Updates(map[string]interface{}{
+		"is_delete":  true,
+		"deleted_at": general.Now(),
+		"updated_by": ctx.Auth.ID,
+	})
+}
+
+func (r *sla_policy) DeleteByTaskTypeId(ctx *abstraction.Context, task_type_id int) *gorm.DB {
+	return r.CheckTrx(ctx).Where("task_type_id = ?", task_type_id).Delete(&model.SlaPolicyEntityModel{})
+}
@@ -12,6 +12,9 @@ type Task interface {
 	FindById(ctx *abstraction.Context, id int) (*model.TaskEntityModel, error)
 	Find(ctx *abstraction.Context, no_paging bool) (data []*model.TaskEntityModel, err error)
 	Count(ctx *abstraction.Context) (data *int, err error)
+	Create(ctx *abstraction.Context, data *model.TaskEntityModel) *gorm.DB
+	Update(ctx *abstraction.Context, data *model.TaskEntityModel) *gorm.DB
+	Delete(ctx *abstraction.Context, data *model.TaskEntityModel) *gorm.DB
 }
 
 type task struct {
@@ -31,7 +34,7 @@ func (r *task) FindById(ctx *abstraction.Context, id int) (*model.TaskEntityMode
 
 	var data model.TaskEntityModel
 	err := conn.
-		Where("id = ?", id).
+		Where("id = ? AND is_delete = ?", id, false).
 		First(&data).
 		Error
 	if err != nil {
@@ -41,7 +44,7 @@ func (r *task) FindById(ctx *abstraction.Context, id int) (*model.TaskEntityMode
 }
 
 func (r *task) Find(ctx *abstraction.Context, no_paging bool) (data []*model.TaskEntityModel, err error) {
-	where, whereParam := general.ProcessWhereParam(ctx, "task", "")
+	where, whereParam := general.ProcessWhereParam(ctx, "task", "is_delete = @false")
 	limit, offset := general.ProcessLimitOffset(ctx, no_paging)
 	order := general.ProcessOrder(ctx)
 	err = r.CheckTrx(ctx).
@@ -55,7 +58,7 @@ func (r *task) Find(ctx *abstraction.Context, no_paging bool) (data []*model.Tas
 }
 
 func (r *task) Count(ctx *abstraction.Context) (data *int, err error) {
-	where, whereParam := general.ProcessWhereParam(ctx, "task", "")
+	where, whereParam := general.ProcessWhereParam(ctx, "task", "is_delete = @false")
 	var count model.TaskCountDataModel
 	err = r.CheckTrx(ctx).
 		Table("task").
@@ -66,3 +69,25 @@ func (r *task) Count(ctx *abstraction.Context) (data *int, err error) {
 	data = &count.Count
 	return
 }
+
+func (r *task) Create(ctx *abstraction.Context, data *model.TaskEntityModel) *gorm.DB {
+	return r.CheckTrx(ctx).Create(data)
+}
+
+// Update writes every attribute of the task with a map, the flags may be false and sla_hours nil.
+func (r *task) Update(ctx *abstraction.Context, data *model.TaskEntityModel) *gorm.DB {
+	return r.CheckTrx(ctx).Model(data).Where("id = ?", data.ID).Updates(map[string]interface{}{
+		"name":                  data.Name,
+		"dashboard_group":       data.DashboardGroup,
+		"requires_image_before": data.RequiresImageBefore,
+		"requires_image_after":  data.RequiresImageAfter,
+		"sla_hours":             data.SlaHours,
+	})
+}
+
+func (r *task) Delete(ctx *abstraction.Context, data *model.TaskEntityModel) *gorm.DB {
+	return r.CheckTrx(ctx).Model(data).Where("id = ?", data.ID).Updates(map[string]interface{}{
+		"is_delete":  true,
+		"deleted_at": general.Now(),
+	})
+}
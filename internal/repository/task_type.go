@@ -4,6 +4,9 @@ import (
 	"cleancare/internal/abstraction"
 	"cleancare/internal/model"
 	"cleancare/pkg/util/general"
+	"time"
+
+	"slices"
 
 	"gorm.io/gorm"
 )
@@ -11,9 +14,18 @@ import (
 type TaskType interface {
 	FindById(ctx *abstraction.Context, id int) (*model.TaskTypeEntityModel, error)
 	Find(ctx *abstraction.Context, no_paging bool) (data []*model.TaskTypeEntityModel, err error)
+	FindCursor(ctx *abstraction.Context, paging *general.CursorPaging) (data []*model.TaskTypeEntityModel, hasMore bool, err error)
 	Count(ctx *abstraction.Context) (data *int, err error)
 	Create(ctx *abstraction.Context, data *model.TaskTypeEntityModel) *gorm.DB
 	Update(ctx *abstraction.Context, data *model.TaskTypeEntityModel) *gorm.DB
+	FindDeleted(ctx *abstraction.Context, no_paging bool) (data []*model.TaskTypeEntityModel, err error)
+	CountDeleted(ctx *abstraction.Context) (data *int, err error)
+	FindDeletedById(ctx *abstraction.Context, id int) (*model.TaskTypeEntityModel, error)
+	FindDeletedBefore(ctx *abstraction.Context, before time.Time, after_id int, limit int) (data []*model.TaskTypeEntityModel, err error)
+	Restore(ctx *abstraction.Context, data *model.TaskTypeEntityModel) *gorm.DB
+	Purge(ctx *abstraction.Context, data *model.TaskTypeEntityModel) *gorm.DB
+	FindChangedSince(ctx *abstraction.Context, since *time.Time) (data []*model.TaskTypeEntityModel, err error)
+	CountByTaskId(ctx *abstraction.Context, task_id int) (data *int, err error)
 }
 
 type task_type struct {
@@ -77,3 +89,116 @@ func (r *task_type) Create(ctx *abstraction.Context, data *model.TaskTypeEntityM
 func (r *task_type) Update(ctx *abstraction.Context, data *model.TaskTypeEntityModel) *gorm.DB {
 	return r.CheckTrx(ctx).Model(data).Where("id = ?", data.ID).Updates(data)
 }
+
+func (r *task_type) FindCursor(ctx *abstraction.Context, paging *general.CursorPaging) (data []*model.TaskTypeEntityModel, hasMore bool, err error) {
+	where, whereParam := general.ProcessWhereParam(ctx, "task_type", "is_delete = @false")
+	where, order := paging.Apply(where, whereParam)
+	err = r.CheckTrx(ctx).
+		Where(where, whereParam).
+		Order(order).
+		Limit(paging.Limit + 1).
+		Preload("Task").
+		Find(&data).
+		Error
+	if err != nil {
+		return
+	}
+	if hasMore = len(data) > paging.Limit; hasMore {
+		data = data[:paging.Limit]
+	}
+	if paging.Reverse() {
+		slices.Reverse(data)
+	}
+	return
+}
+
+func (r *task_type) FindDeleted(ctx *abstraction.Context, no_paging bool) (data []*model.TaskTypeEntityModel, err error) {
+	where, whereParam := general.ProcessWhereParam(ctx, "task_type", "is_delete = @true")
+	limit, offset := general.ProcessLimitOffset(ctx, no_paging)
+	order := general.ProcessOrder(ctx)
+	err = r.CheckTrx(ctx).
+		Where(where, whereParam).
+		Order(order).
+		Limit(limit).
+		Offset(offset).
+		Preload("Task").
+		Find(&data).
+		Error
+	return
+}
+
+func (r *task_type) CountDeleted(ctx *abstraction.Context) (data *int, err error) {
+	where, whereParam := general.ProcessWhereParam(ctx, "task_type", "is_delete = @true")
+	var count model.TaskTypeCountDataModel
+	err = r.CheckTrx(ctx).
+		Table("task_type").
+		Select("COUNT(*) AS count").
+		Where(where, whereParam).
+		Find(&count).
+		Error
+	data = &count.Count
+	return
+}
+
+func (r *task_type) FindDeletedById(ctx *abstraction.Context, id int) (*model.TaskTypeEntityModel, error) {
+	conn := r.CheckTrx(ctx)
+
+	var data model.TaskTypeEntityModel
+	err := conn.
+		Where("id = ? AND is_delete = ?", id, true).
+		Preload("Task").
+		First(&data).
+		Error
+	if err != nil {
+		return nil, err
+	}
+	return &data, nil
+}
+
+func (r *task_type) FindDeletedBefore(ctx *abstraction.Context, before time.Time, after_id int, limit int) (data []*model.TaskTypeEntityModel, err error) {
+	err = r.CheckTrx(ctx).
+		Where("is_delete = ? AND deleted_at < ? AND id > ?", true, before, after_id).
+		Order("id ASC").
+		Limit(limit).
+		Find(&data).
+		Error
+	return
+}
+
+func (r *task_type) Restore(ctx *abstraction.Context, data *model.TaskTypeEntityModel) *gorm.DB {
+	return r.CheckTrx(ctx).Model(data).Where("id = ?", data.ID).Updates(map[string]interface{}{
+		"is_delete":  false,
+		"deleted_at": nil,
+	})
+}
+
+func (r *task_type) Purge(ctx *abstraction.Context, data *model.TaskTypeEntityModel) *gorm.DB {
+	return r.CheckTrx(ctx).Where("id = ?", data.ID).Delete(data)
+}
+
+// FindChangedSince returns the task types changed at or after since, see Work.FindChangedSince.
+func (r *task_type) FindChangedSince(ctx *abstraction.Context, since *time.Time) (data []*model.TaskTypeEntityModel, err error) {
+	conn := r.CheckTrx(ctx)
+	if since != nil {
+		conn = conn.Where("COALESCE(updated_at, created_at) >= ?", since)
+	} else {
+		conn = conn.Where("is_delete = ?", false)
+	}
+	err = conn.
+		Order("id ASC").
+		Find(&data).
+		Error
+	return
+}
+
+func (r *task_type) CountByTaskId(ctx *abstraction.Context, task_id int) (data *int, err error) {
+	var count model.TaskTypeCountDataModel
+	err = r.CheckTrx(ctx).
+		Table("task_type").
+		Select("COUNT(*) AS count").
+		Where("task_id = ? AND is_delete = ?", task_id, false).
+		Find(&count).
+		Error
+	data = &count.Count
+	return
+}
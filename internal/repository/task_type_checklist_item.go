@@ -0,0 +1,65 @@
+package repository
+
+import (
+	"cleancare/internal/abstraction"
+	"cleancare/internal/model"
+	"cleancare/pkg/util/general"
+
+	"gorm.io/gorm"
+)
+
+type TaskTypeChecklistItem interface {
+	FindByTaskTypeId(ctx *abstraction.Context, task_type_id int) (data []*model.TaskTypeChecklistItemEntityModel, err error)
+	Create(ctx *abstraction.Context, data *model.TaskTypeChecklistItemEntityModel) *gorm.DB
+	Update(ctx *abstraction.Context, data *model.TaskTypeChecklistItemEntityModel) *gorm.DB
+	Delete(ctx *abstraction.Context, data *model.TaskTypeChecklistItemEntityModel) *gorm.DB
+	DeleteByTaskTypeId(ctx *abstraction.Context, task_type_id int) *gorm.DB
+}
+
+type task_type_checklist_item struct {
+	abstraction.Repository
+}
+
+func NewTaskTypeChecklistItem(db *gorm.DB) *task_type_checklist_item {
+	return &task_type_checklist_item{
+		Repository: abstraction.Repository{
+			Db: db,
+		},
+	}
+}
+
+// FindByTaskTypeId returns the template of the task type in checklist order.
+func (r *task_type_checklist_item) FindByTaskTypeId(ctx *abstraction.Context, task_type_id int) (data []*model.TaskTypeChecklistItemEntityModel, err error) {
+	err = r.CheckTrx(ctx).
+		Where("task_type_id = ? AND is_delete = ?", task_type_id, false).
+		Order("sort_order ASC, id ASC").
+		Find(&data).
+		Error
+	return
+}
+
+func (r *task_type_checklist_item) Create(ctx *abstraction.Context, data *model.TaskTypeChecklistItemEntityModel) *gorm.DB {
+	return r.CheckTrx(ctx).Create(data)
+}
+
+// Update writes the item with a map, is_required and sort_order may be zero.
+func (r *task_type_checklist_item) Update(ctx *abstraction.Context, data *model.TaskTypeChecklistItemEntityModel) *gorm.DB {
+	return r.CheckTrx(ctx).Model(data).Where("id = ?", data.ID).Updates(map[string]interface{}{
+		"name":        data.Name,
+		"is_required": data.IsRequired,
+		"sort_order":  data.SortOrder,
+		"updated_by":  ctx.Auth.ID,
+	})
+}
+
+func (r *task_type_checklist_item) Delete(ctx *abstraction.Context, data *model.TaskTypeChecklistItemEntityModel) *gorm.DB {
+	return r.CheckTrx(ctx).Model(data).Where("id = ?", data.ID).Updates(map[string]interface{}{
+		"is_delete":  true,
+		"deleted_at": general.Now(),
+		"updated_by": ctx.Auth.ID,
+	})
+}
+
+func (r *task_type_checklist_item) DeleteByTaskTypeId(ctx *abstraction.Context, task_type_id int) *gorm.DB {
+	return r.CheckTrx(ctx).Where("task_type_id = ?", task_type_id).Delete(&model.TaskTypeChecklistItemEntityModel{})
+}
@@ -0,0 +1,123 @@
+package repository
+
+import (
+	"cleancare/internal/abstraction"
+	"cleancare/internal/model"
+	"cleancare/pkg/constant"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type UploadSession interface {
+	Create(ctx *abstraction.Context, data *model.UploadSessionEntityModel) *gorm.DB
+	FindById(ctx *abstraction.Context, id string) (*model.UploadSessionEntityModel, error)
+	FindByIdForUpdate(ctx *abstraction.Context, id string) (*model.UploadSessionEntityModel, error)
+	Update(ctx *abstraction.Context, data *model.UploadSessionEntityModel) *gorm.DB
+	Delete(ctx *abstraction.Context, data *model.UploadSessionEntityModel) *gorm.DB
+	Rewind(ctx *abstraction.Context, data *model.UploadSessionEntityModel) *gorm.DB
+	Consume(ctx *abstraction.Context, data *model.UploadSessionEntityModel) (bool, error)
+	FindExpired(ctx *abstraction.Context, before time.Time, limit int) (data []*model.UploadSessionEntityModel, err error)
+	FindFileReferences(ctx *abstraction.Context) (data []*model.UploadSessionFileReference, err error)
+}
+
+type uploadSession struct {
+	abstraction.Repository
+}
+
+func NewUploadSession(db *gorm.DB) *uploadSession {
+	return &uploadSession{
+		Repository: abstraction.Repository{
+			Db: db,
+		},
+	}
+}
+
+func (r *uploadSession) Create(ctx *abstraction.Context, data *model.UploadSessionEntityModel) *gorm.DB {
+	return r.CheckTrx(ctx).Create(data)
+}
+
+func (r *uploadSession) FindById(ctx *abstraction.Context, id string) (*model.UploadSessionEntityModel, error) {
+	conn := r.CheckTrx(ctx)
+
+	var data model.UploadSessionEntityModel
+	err := conn.
+		Where("id = ?", id).
+		First(&data).
+		Error
+	if err != nil {
+		return nil, err
+	}
+	return &data, nil
+}
+
+// FindByIdForUpdate locks the session until the transaction ends, so chunks of the same
+// upload are written one at a time.
+func (r *uploadSession) FindByIdForUpdate(ctx *abstraction.Context, id string) (*model.UploadSessionEntityModel, error) {
+	conn := r.CheckTrx(ctx)
+
+	var data model.UploadSessionEntityModel
+	err := conn.
+		Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("id = ?", id).
+		First(&data).
+		Error
+	if err != nil {
+		return nil, err
+	}
+	return &data, nil
+}
+
+func (r *uploadSession) Update(ctx *abstraction.Context, data *model.UploadSessionEntityModel) *gorm.DB {
+	return r.CheckTrx(ctx).Model(data).Where("id = ?", data.ID).Updates(data)
+}
+
+func (r *uploadSession) Delete(ctx *abstraction.Context, data *model.UploadSessionEntityModel) *gorm.DB {
+	return r.CheckTrx(ctx).Where("id = ?", data.ID).Delete(data)
+}
+
+// Rewind sets the received bytes back to 0, Update skips the zero value.
+func (r *uploadSession) Rewind(ctx *abstraction.Context, data *model.UploadSessionEntityModel) *gorm.DB {
+	return r.CheckTrx(ctx).Model(data).Where("id = ?", data.ID).Updates(map[string]interface{}{
+		"received": 0,
+	})
+}
+
+// Consume marks a completed upload as used by a work, it returns false when the upload was
+// used already.
+func (r *uploadSession) Consume(ctx *abstraction.Context, data *model.UploadSessionEntityModel) (bool, error) {
+	res := r.CheckTrx(ctx).
+		Model(&model.UploadSessionEntityModel{}).
+		Where("id = ? AND status = ? AND consumed_at IS NULL", data.ID, constant.UPLOAD_STATUS_COMPLETED).
+		Updates(map[string]interface{}{
+			"consumed_at": time.Now(),
+		})
+	if res.Error != nil {
+		return false, res.Error
+	}
+	return res.RowsAffected == 1, nil
+}
+
+func (r *uploadSession) FindExpired(ctx *abstraction.Context, before time.Time, limit int) (data []*model.UploadSessionEntityModel, err error) {
+	err = r.CheckTrx(ctx).
+		Where("expires_at < ?", before).
+		Order("expires_at ASC").
+		Limit(limit).
+		Find(&data).
+		Error
+	return
+}
+
+// FindFileReferences returns the completed uploads that are not used by a work yet, their
+// files are kept on the drive until the session expires.
+func (r *uploadSession) FindFileReferences(ctx *abstraction.Context) (data []*model.UploadSessionFileReference, err error) {
+	err = r.CheckTrx(ctx).
+		Table("upload_session").
+		Select("id, file_id").
+		Where("file_id IS NOT NULL AND consumed_at IS NULL").
+		Order("id ASC").
+		Find(&data).
+		Error
+	return
+}
@@ -4,6 +4,9 @@ import (
 	"cleancare/internal/abstraction"
 	"cleancare/internal/model"
 	"cleancare/pkg/util/general"
+	"time"
+
+	"slices"
 
 	"gorm.io/gorm"
 )
@@ -11,13 +14,23 @@ import (
 type User interface {
 	FindByNumberId(ctx *abstraction.Context, numberId string) (*model.UserEntityModel, error)
 	FindByEmail(ctx *abstraction.Context, email string) (*model.UserEntityModel, error)
+	FindByPendingEmail(ctx *abstraction.Context, email string) (*model.UserEntityModel, error)
 	Create(ctx *abstraction.Context, data *model.UserEntityModel) *gorm.DB
 	Find(ctx *abstraction.Context, no_paging bool) (data []*model.UserEntityModel, err error)
+	FindCursor(ctx *abstraction.Context, paging *general.CursorPaging) (data []*model.UserEntityModel, hasMore bool, err error)
 	Count(ctx *abstraction.Context) (data *int, err error)
 	FindById(ctx *abstraction.Context, id int) (*model.UserEntityModel, error)
 	Update(ctx *abstraction.Context, data *model.UserEntityModel) *gorm.DB
 	FindByRoleIdArr(ctx *abstraction.Context, role_id int, no_paging bool) (data []*model.UserEntityModel, err error)
+	FindAllByRoleId(ctx *abstraction.Context, role_id int) (data []*model.UserEntityModel, err error)
 	UpdateToNull(ctx *abstraction.Context, data *model.UserEntityModel, column string) *gorm.DB
+	FindDeleted(ctx *abstraction.Context, no_paging bool) (data []*model.UserEntityModel, err error)
+	CountDeleted(ctx *abstraction.Context) (data *int, err error)
+	FindDeletedById(ctx *abstraction.Context, id int) (*model.UserEntityModel, error)
+	FindDeletedBefore(ctx *abstraction.Context, before time.Time, after_id int, limit int) (data []*model.UserEntityModel, err error)
+	Restore(ctx *abstraction.Context, data *model.UserEntityModel) *gorm.DB
+	Purge(ctx *abstraction.Context, data *model.UserEntityModel) *gorm.DB
+	FindFileReferences(ctx *abstraction.Context) (data []*model.UserFileReference, err error)
 }
 
 type user struct {
@@ -62,6 +75,21 @@ func (r *user) FindByEmail(ctx *abstraction.Context, email string) (*model.UserE
 	return &data, nil
 }
 
+func (r *user) FindByPendingEmail(ctx *abstraction.Context, email string) (*model.UserEntityModel, error) {
+	conn := r.CheckTrx(ctx)
+
+	var data model.UserEntityModel
+	err := conn.
+		Where("LOWER(pending_email) = LOWER(?) AND is_delete = ?", email, false).
+		Preload("Role").
+		First(&data).
+		Error
+	if err != nil {
+		return nil, err
+	}
+	return &data, nil
+}
+
 func (r *user) Create(ctx *abstraction.Context, data *model.UserEntityModel) *gorm.DB {
 	return r.CheckTrx(ctx).Create(data)
 }
@@ -127,6 +155,113 @@ func (r *user) FindByRoleIdArr(ctx *abstraction.Context, role_id int, no_paging
 	return
 }
 
+// FindAllByRoleId is FindByRoleIdArr without the query params, for jobs running outside a request.
+func (r *user) FindAllByRoleId(ctx *abstraction.Context, role_id int) (data []*model.UserEntityModel, err error) {
+	err = r.CheckTrx(ctx).
+		Where("role_id = ? AND is_delete = ?", role_id, false).
+		Order("id ASC").
+		Find(&data).
+		Error
+	return
+}
+
 func (r *user) UpdateToNull(ctx *abstraction.Context, data *model.UserEntityModel, column string) *gorm.DB {
 	return r.CheckTrx(ctx).Model(data).Where("id = ?", data.ID).Update(column, nil)
 }
+
+func (r *user) FindCursor(ctx *abstraction.Context, paging *general.CursorPaging) (data []*model.UserEntityModel, hasMore bool, err error) {
+	where, whereParam := general.ProcessWhereParam(ctx, "user", "is_delete = @false")
+	where, order := paging.Apply(where, whereParam)
+	err = r.CheckTrx(ctx).
+		Where(where, whereParam).
+		Order(order).
+		Limit(paging.Limit + 1).
+		Preload("Role").
+		Find(&data).
+		Error
+	if err != nil {
+		return
+	}
+	if hasMore = len(data) > paging.Limit; hasMore {
+		data = data[:paging.Limit]
+	}
+	if paging.Reverse() {
+		slices.Reverse(data)
+	}
+	return
+}
+
+func (r *user) FindDeleted(ctx *abstraction.Context, no_paging bool) (data []*model.UserEntityModel, err error) {
+	where, whereParam := general.ProcessWhereParam(ctx, "user", "is_delete = @true")
+	limit, offset := general.ProcessLimitOffset(ctx, no_paging)
+	order := general.ProcessOrder(ctx)
+	err = r.CheckTrx(ctx).
+		Where(where, whereParam).
+		Order(order).
+		Limit(limit).
+		Offset(offset).
+		Preload("Role").
+		Find(&data).
+		Error
+	return
+}
+
+func (r *user) CountDeleted(ctx *abstraction.Context) (data *int, err error) {
+	where, whereParam := general.ProcessWhereParam(ctx, "user", "is_delete = @true")
+	var count model.UserCountDataModel
+	err = r.CheckTrx(ctx).
+		Table("user").
+		Select("COUNT(*) AS count").
+		Where(where, whereParam).
+		Find(&count).
+		Error
+	data = &count.Count
+	return
+}
+
+func (r *user) FindDeletedById(ctx *abstraction.Context, id int) (*model.UserEntityModel, error) {
+	conn := r.CheckTrx(ctx)
+
+	var data model.UserEntityModel
+	err := conn.
+		Where("id = ? AND is_delete = ?", id, true).
+		Preload("Role").
+		First(&data).
+		Error
+	if err != nil {
+		return nil, err
+	}
+	return &data, nil
+}
+
+func (r *user) FindDeletedBefore(ctx *abstraction.Context, before time.Time, after_id int, limit int) (data []*model.UserEntityModel, err error) {
+	err = r.CheckTrx(ctx).
+		Where("is_delete = ? AND deleted_at < ? AND id > ?", true, before, after_id).
+		Order("id ASC").
+		Limit(limit).
+		Find(&data).
+		Error
+	return
+}
+
+func (r *user) Restore(ctx *abstraction.Context, data *model.UserEntityModel) *gorm.DB {
+	return r.CheckTrx(ctx).Model(data).Where("id = ?", data.ID).Updates(map[string]interface{}{
+		"is_delete":  false,
+		"deleted_at": nil,
+	})
+}
+
+func (r *user) Purge(ctx *abstraction.Context, data *model.UserEntityModel) *gorm.DB {
+	return r.CheckTrx(ctx).Where("id = ?", data.ID).Delete(data)
+}
+
+func (r *user) FindFileReferences(ctx *abstraction.Context) (data []*model.UserFileReference, err error) {
+	err = r.CheckTrx(ctx).
+		Table("user").
+		Select("id, profile").
+		Where("profile IS NOT NULL AND profile != ''").
+		Order("id ASC").
+		Find(&data).
+		Error
+	return
+}
@@ -3,8 +3,12 @@ package repository
 import (
 	"cleancare/internal/abstraction"
 	"cleancare/internal/model"
+	"cleancare/pkg/constant"
 	"cleancare/pkg/util/general"
 	"strings"
+	"time"
+
+	"slices"
 
 	"gorm.io/gorm"
 )
@@ -14,10 +18,32 @@ type Work interface {
 	FindById(ctx *abstraction.Context, id int) (*model.WorkEntityModel, error)
 	Update(ctx *abstraction.Context, data *model.WorkEntityModel) *gorm.DB
 	Find(ctx *abstraction.Context, no_paging bool) (data []*model.WorkEntityModel, err error)
+	FindCursor(ctx *abstraction.Context, paging *general.CursorPaging) (data []*model.WorkEntityModel, hasMore bool, err error)
 	Count(ctx *abstraction.Context) (data *int, err error)
 	UpdateToNull(ctx *abstraction.Context, data *model.WorkEntityModel, column string) *gorm.DB
 	FindByTaskIdArrAdmin(ctx *abstraction.Context, task_id int, created_at string, no_paging bool) (floorSummary []*model.FloorSummary, userSummary []*model.UserSummary, errFloor, errUser error)
 	FindByTaskIdArrStaf(ctx *abstraction.Context, task_id int, created_at string, no_paging bool) (taskTypeSummary []*model.TaskTypeSummary, err error)
+	AnalyticsTrend(ctx *abstraction.Context, task_id int, created_at string, interval string) (data []*model.WorkTrendSummary, err error)
+	AnalyticsCompletion(ctx *abstraction.Context, task_id int, created_at string) (data *model.WorkCompletionSummary, err error)
+	AnalyticsStaff(ctx *abstraction.Context, task_id int, created_at string) (data []*model.WorkStaffSummary, err error)
+	AnalyticsTaskType(ctx *abstraction.Context, task_id int, created_at string) (data []*model.TaskTypeSummary, err error)
+	Scorecard(ctx *abstraction.Context, start_date, end_date string, user_id int) (data []*model.WorkScorecard, err error)
+	FindDeleted(ctx *abstraction.Context, no_paging bool) (data []*model.WorkEntityModel, err error)
+	CountDeleted(ctx *abstraction.Context) (data *int, err error)
+	FindDeletedById(ctx *abstraction.Context, id int) (*model.WorkEntityModel, error)
+	FindDeletedBefore(ctx *abstraction.Context, before time.Time, after_id int, limit int) (data []*model.WorkEntityModel, err error)
+	Restore(ctx *abstraction.Context, data *model.WorkEntityModel) *gorm.DB
+	Purge(ctx *abstraction.Context, data *model.WorkEntityModel) *gorm.DB
+	CountByUserId(ctx *abstraction.Context, user_id int) (data *int, err error)
+	CountByTaskTypeId(ctx *abstraction.Context, task_type_id int) (data *int, err error)
+	FindFileReferences(ctx *abstraction.Context) (data []*model.WorkFileReference, err error)
+	FindByClientId(ctx *abstraction.Context, user_id int, client_id string) (*model.WorkEntityModel, error)
+	FindChangedSince(ctx *abstraction.Context, user_id int, since *time.Time) (data []*model.WorkEntityModel, err error)
+	UpdateSla(ctx *abstraction.Context, data *model.WorkEntityModel) *gorm.DB
+	UpdateSlaStatus(ctx *abstraction.Context, id int, sla_status string) *gorm.DB
+	FindSlaOpen(ctx *abstraction.Context, after_id int, limit int) (data []*model.WorkSlaCheck, err error)
+	SlaSummary(ctx *abstraction.Context, task_id int, created_at string) (data *model.WorkSlaSummary, err error)
+	FindLastCompletedByAreaId(ctx *abstraction.Context, area_id int, since time.Time) (*model.WorkEntityModel, error)
 }
 
 type work struct {
@@ -140,3 +166,331 @@ func (r *work) FindByTaskIdArrStaf(ctx *abstraction.Context, task_id int, create
 
 	return
 }
+
+// analytics works on a created_at range formatted as "YYYY-MM-DD_YYYY-MM-DD", task_id 0 means all tasks.
+func (r *work) analytics(ctx *abstraction.Context, task_id int, created_at string) *gorm.DB {
+	valDate := strings.Split(created_at, "_")
+	startDate := valDate[0] + " 00:00:00"
+	endDate := valDate[1] + " 23:59:59"
+
+	query := r.CheckTrx(ctx).
+		Model(&model.WorkEntityModel{}).
+		Where("work.is_delete = ? AND work.created_at BETWEEN ? AND ?", false, startDate, endDate)
+	if task_id != 0 {
+		query = query.Where("work.task_id = ?", task_id)
+	}
+	return query
+}
+
+func (r *work) AnalyticsTrend(ctx *abstraction.Context, task_id int, created_at string, interval string) (data []*model.WorkTrendSummary, err error) {
+	period := "DATE_FORMAT(work.created_at, '%Y-%m-%d')"
+	switch interval {
+	case "week":
+		period = "DATE_FORMAT(DATE_SUB(work.created_at, INTERVAL WEEKDAY(work.created_at) DAY), '%Y-%m-%d')"
+	case "month":
+		period = "DATE_FORMAT(work.created_at, '%Y-%m')"
+	}
+
+	err = r.analytics(ctx, task_id, created_at).
+		Select(period + " AS period, COUNT(*) AS total, COUNT(work.completed_at) AS completed").
+		Group("period").
+		Order("period ASC").
+		Scan(&data).Error
+	return
+}
+
+func (r *work) AnalyticsCompletion(ctx *abstraction.Context, task_id int, created_at string) (data *model.WorkCompletionSummary, err error) {
+	data = new(model.WorkCompletionSummary)
+	err = r.analytics(ctx, task_id, created_at).
+		Select("COUNT(*) AS total, COUNT(work.completed_at) AS completed, COALESCE(AVG(TIMESTAMPDIFF(SECOND, work.created_at, work.completed_at)), 0) AS avg_completion_seconds").
+		Scan(data).Error
+	return
+}
+
+func (r *work) AnalyticsStaff(ctx *abstraction.Context, task_id int, created_at string) (data []*model.WorkStaffSummary, err error) {
+	err = r.analytics(ctx, task_id, created_at).
+		Joins("JOIN user ON user.id = work.user_id").
+		Select("work.user_id, user.name, COUNT(*) AS total, COUNT(work.completed_at) AS completed, COALESCE(AVG(TIMESTAMPDIFF(SECOND, work.created_at, work.completed_at)), 0) AS avg_completion_seconds").
+		Group("work.user_id, user.name").
+		Order("completed DESC, total DESC, work.user_id ASC").
+		Scan(&data).Error
+	return
+}
+
+func (r *work) AnalyticsTaskType(ctx *abstraction.Context, task_id int, created_at string) (data []*model.TaskTypeSummary, err error) {
+	err = r.analytics(ctx, task_id, created_at).
+		Joins("JOIN task_type ON task_type.id = work.task_type_id").
+		Select("work.task_type_id, task_type.name, COUNT(*) AS count").
+		Group("work.task_type_id, task_type.name").
+		Order("count DESC, work.task_type_id ASC").
+		Scan(&data).Error
+	return
+}
+
+// Scorecard summarizes every staff (or only user_id when it is not 0) between start_date and end_date.
+// Rejected counts every rejection, a work sent again after it keeps the ones before. Comment turnaround is the time between a comment from someone else on the staff's work and the
+// first reply of the staff after it, unanswered comments are left out of the average.
+func (r *work) Scorecard(ctx *abstraction.Context, start_date, end_date string, user_id int) (data []*model.WorkScorecard, err error) {
+	query := `
+		SELECT
+			user.id AS user_id,
+			user.name,
+			(SELECT COUNT(*) FROM work w
+				WHERE w.user_id = user.id AND w.is_delete = @false AND w.completed_at BETWEEN @start AND @end) AS completed,
+			(SELECT COUNT(*) FROM assignment a
+				WHERE a.user_id = user.id AND a.is_delete = @false AND a.due_at BETWEEN @start AND @end) AS assigned,
+			(SELECT COUNT(*) FROM assignment a JOIN work w ON w.id = a.work_id
+				WHERE a.user_id = user.id AND a.is_delete = @false AND w.is_delete = @false AND a.due_at BETWEEN @start AND @end
+				AND w.completed_at IS NOT NULL AND w.completed_at <= a.due_at) AS on_time,
+			(SELECT COUNT(*) FROM work_verification v JOIN work w ON w.id = v.work_id
+				WHERE w.user_id = user.id AND w.is_delete = @false AND v.status = @rejected AND v.created_at BETWEEN @start AND @end) AS rejected,
+			(SELECT AVG(TIMESTAMPDIFF(SECOND, c.created_at, (
+					SELECT MIN(reply.created_at) FROM comment reply
+					WHERE reply.work_id = c.work_id AND reply.is_delete = @false AND reply.created_by = w.user_id AND reply.created_at > c.created_at
+				)))
+				FROM comment c JOIN work w ON w.id = c.work_id
+				WHERE w.user_id = user.id AND w.is_delete = @false AND c.is_delete = @false AND c.created_by <> w.user_id
+				AND c.created_at BETWEEN @start AND @end) AS avg_comment_turnaround_seconds
+		FROM user
+		WHERE user.role_id = @role_id AND user.is_delete = @false AND (@user_id = 0 OR user.id = @user_id)
+		ORDER BY user.id ASC`
+
+	err = r.CheckTrx(ctx).
+		Raw(query, map[string]interface{}{
+			"false":    false,
+			"start":    start_date,
+			"end":      end_date,
+			"rejected": constant.WORK_VERIFICATION_REJECTED,
+			"role_id":  constant.ROLE_ID_STAFF,
+			"user_id":  user_id,
+		}).
+		Scan(&data).Error
+	return
+}
+
+func (r *work) FindCursor(ctx *abstraction.Context, paging *general.CursorPaging) (data []*model.WorkEntityModel, hasMore bool, err error) {
+	where, whereParam := general.ProcessWhereParam(ctx, "work", "is_delete = @false")
+	where, order := paging.Apply(where, whereParam)
+	err = r.CheckTrx(ctx).
+		Where(where, whereParam).
+		Order(order).
+		Limit(paging.Limit + 1).
+		Preload("User").
+		Preload("Task").
+		Preload("TaskType").
+		Find(&data).
+		Error
+	if err != nil {
+		return
+	}
+	if hasMore = len(data) > paging.Limit; hasMore {
+		data = data[:paging.Limit]
+	}
+	if paging.Reverse() {
+		slices.Reverse(data)
+	}
+	return
+}
+
+func (r *work) FindDeleted(ctx *abstraction.Context, no_paging bool) (data []*model.WorkEntityModel, err error) {
+	where, whereParam := general.ProcessWhereParam(ctx, "work", "is_delete = @true")
+	limit, offset := general.ProcessLimitOffset(ctx, no_paging)
+	order := general.ProcessOrder(ctx)
+	err = r.CheckTrx(ctx).
+		Where(where, whereParam).
+		Order(order).
+		Limit(limit).
+		Offset(offset).
+		Preload("User").
+		Preload("Task").
+		Preload("TaskType").
+		Find(&data).
+		Error
+	return
+}
+
+func (r *work) CountDeleted(ctx *abstraction.Context) (data *int, err error) {
+	where, whereParam := general.ProcessWhereParam(ctx, "work", "is_delete = @true")
+	var count model.WorkCountDataModel
+	err = r.CheckTrx(ctx).
+		Table("work").
+		Select("COUNT(*) AS count").
+		Where(where, whereParam).
+		Find(&count).
+		Error
+	data = &count.Count
+	return
+}
+
+func (r *work) FindDeletedById(ctx *abstraction.Context, id int) (*model.WorkEntityModel, error) {
+	conn := r.CheckTrx(ctx)
+
+	var data model.WorkEntityModel
+	err := conn.
+		Where("id = ? AND is_delete = ?", id, true).
+		Preload("User").
+		Preload("Task").
+		Preload("TaskType").
+		First(&data).
+		Error
+	if err != nil {
+		return nil, err
+	}
+	return &data, nil
+}
+
+func (r *work) FindDeletedBefore(ctx *abstraction.Context, before time.Time, after_id int, limit int) (data []*model.WorkEntityModel, err error) {
+	err = r.CheckTrx(ctx).
+		Where("is_delete = ? AND deleted_at < ? AND id > ?", true, before, after_id).
+		Order("id ASC").
+		Limit(limit).
+		Find(&data).
+		Error
+	return
+}
+
+func (r *work) Restore(ctx *abstraction.Context, data *model.WorkEntityModel) *gorm.DB {
+	return r.CheckTrx(ctx).Model(data).Where("id = ?", data.ID).Updates(map[string]interface{}{
+		"is_delete":  false,
+		"deleted_at": nil,
+	})
+}
+
+func (r *work) Purge(ctx *abstraction.Context, data *model.WorkEntityModel) *gorm.DB {
+	return r.CheckTrx(ctx).Where("id = ?", data.ID).Delete(data)
+}
+
+func (r *work) CountByUserId(ctx *abstraction.Context, user_id int) (data *int, err error) {
+	var count model.WorkCountDataModel
+	err = r.CheckTrx(ctx).
+		Table("work").
+		Select("COUNT(*) AS count").
+		Where("user_id = ?", user_id).
+		Find(&count).
+		Error
+	data = &count.Count
+	return
+}
+
+func (r *work) CountByTaskTypeId(ctx *abstraction.Context, task_type_id int) (data *int, err error) {
+	var count model.WorkCountDataModel
+	err = r.CheckTrx(ctx).
+		Table("work").
+		Select("COUNT(*) AS count").
+		Where("task_type_id = ?", task_type_id).
+		Find(&count).
+		Error
+	data = &count.Count
+	return
+}
+
+func (r *work) FindFileReferences(ctx *abstraction.Context) (data []*model.WorkFileReference, err error) {
+	err = r.CheckTrx(ctx).
+		Table("work").
+		Select("id, image_before, image_after").
+		Where("(image_before IS NOT NULL AND image_before != '') OR (image_after IS NOT NULL AND image_after != '')").
+		Order("id ASC").
+		Find(&data).
+		Error
+	return
+}
+
+// FindByClientId returns the work the mobile app created under client_id, deleted or not.
+func (r *work) FindByClientId(ctx *abstraction.Context, user_id int, client_id string) (*model.WorkEntityModel, error) {
+	conn := r.CheckTrx(ctx)
+
+	var data model.WorkEntityModel
+	err := conn.
+		Where("user_id = ? AND client_id = ?", user_id, client_id).
+		First(&data).
+		Error
+	if err != nil {
+		return nil, err
+	}
+	return &data, nil
+}
+
+// FindChangedSince returns the works of user_id changed at or after since, deleted ones included
+// so the app can drop them. A nil since returns every work that is not deleted.
+func (r *work) FindChangedSince(ctx *abstraction.Context, user_id int, since *time.Time) (data []*model.WorkEntityModel, err error) {
+	conn := r.CheckTrx(ctx).Where("user_id = ?", user_id)
+	if since != nil {
+		conn = conn.Where("COALESCE(updated_at, created_at) >= ?", since)
+	} else {
+		conn = conn.Where("is_delete = ?", false)
+	}
+	err = conn.
+		Order("id ASC").
+		Find(&data).
+		Error
+	return
+}
+
+// UpdateSla writes the sla of the work with a map, a work moved to a task without sla loses it.
+// A met or breached status is final and is kept, only the due times change then.
+func (r *work) UpdateSla(ctx *abstraction.Context, data *model.WorkEntityModel) *gorm.DB {
+	return r.CheckTrx(ctx).Model(data).Where("id = ?", data.ID).Updates(map[string]interface{}{
+		"sla_policy_id":       data.SlaPolicyId,
+		"sla_start_due_at":    data.SlaStartDueAt,
+		"sla_complete_due_at": data.SlaCompleteDueAt,
+		"sla_status":          gorm.Expr("IF(sla_status IN (?, ?), sla_status, ?)", constant.SLA_STATUS_MET, constant.SLA_STATUS_BREACHED, data.SlaStatus),
+	})
+}
+
+// UpdateSlaStatus keeps updated_at as it is, the status is not a change of the work for the sync
+// clients.
+func (r *work) UpdateSlaStatus(ctx *abstraction.Context, id int, sla_status string) *gorm.DB {
+	return r.CheckTrx(ctx).Model(&model.WorkEntityModel{}).Where("id = ?", id).UpdateColumns(map[string]interface{}{
+		"sla_status": sla_status,
+		"updated_at": gorm.Expr("updated_at"),
+	})
+}
+
+// FindSlaOpen returns the works whose sla is not met or breached yet, in id order after after_id.
+func (r *work) FindSlaOpen(ctx *abstraction.Context, after_id int, limit int) (data []*model.WorkSlaCheck, err error) {
+	err = r.CheckTrx(ctx).
+		Model(&model.WorkEntityModel{}).
+		Joins("JOIN user ON user.id = work.user_id").
+		Joins("JOIN task ON task.id = work.task_id").
+		Joins("JOIN task_type ON task_type.id = work.task_type_id").
+		Select("work.id, work.user_id, user.name AS user_name, task.name AS task_name, task_type.name AS task_type_name, "+
+			"work.floor, work.created_at, work.completed_at, work.sla_policy_id, work.sla_start_due_at, work.sla_complete_due_at, work.sla_status, "+
+			"(SELECT MIN(work_photo.created_at) FROM work_photo WHERE work_photo.work_id = work.id AND work_photo.phase = ?) AS started_at", constant.WORK_PHOTO_PHASE_BEFORE).
+		Where("work.is_delete = ? AND work.sla_status IN ? AND work.id > ?", false, []string{constant.SLA_STATUS_ON_TRACK, constant.SLA_STATUS_AT_RISK}, after_id).
+		Order("work.id ASC").
+		Limit(limit).
+		Scan(&data).Error
+	return
+}
+
+// SlaSummary counts the works of the task created in the range by sla status, works without sla
+// are left out.
+func (r *work) SlaSummary(ctx *abstraction.Context, task_id int, created_at string) (data *model.WorkSlaSummary, err error) {
+	data = new(model.WorkSlaSummary)
+	err = r.analytics(ctx, task_id, created_at).
+		Select("COUNT(CASE WHEN work.sla_status = ? THEN 1 END) AS on_track, "+
+			"COUNT(CASE WHEN work.sla_status = ? THEN 1 END) AS at_risk, "+
+			"COUNT(CASE WHEN work.sla_status = ? THEN 1 END) AS breached, "+
+			"COUNT(CASE WHEN work.sla_status = ? THEN 1 END) AS met",
+			constant.SLA_STATUS_ON_TRACK, constant.SLA_STATUS_AT_RISK, constant.SLA_STATUS_BREACHED, constant.SLA_STATUS_MET).
+		Scan(data).Error
+	return
+}
+
+// FindLastCompletedByAreaId returns the work of an assignment for the area completed last, when it
+// was completed after since.
+func (r *work) FindLastCompletedByAreaId(ctx *abstraction.Context, area_id int, since time.Time) (*model.WorkEntityModel, error) {
+	conn := r.CheckTrx(ctx)
+
+	var data model.WorkEntityModel
+	err := conn.
+		Joins("JOIN assignment ON assignment.work_id = work.id").
+		Where("assignment.area_id = ? AND work.completed_at >= ? AND work.is_delete = ?", area_id, since, false).
+		Order("work.completed_at DESC, work.id DESC").
+		First(&data).
+		Error
+	if err != nil {
+		return nil, err
+	}
+	return &data, nil
+}
@@ -0,0 +1,62 @@
+package repository
+
+import (
+	"cleancare/internal/abstraction"
+	"cleancare/internal/model"
+
+	"gorm.io/gorm"
+)
+
+type WorkChecklist interface {
+	Create(ctx *abstraction.Context, data *model.WorkChecklistEntityModel) *gorm.DB
+	FindByWorkId(ctx *abstraction.Context, work_id int) (data []*model.WorkChecklistEntityModel, err error)
+	FindByWorkIds(ctx *abstraction.Context, work_ids []int) (data []*model.WorkChecklistEntityModel, err error)
+	UpdateDone(ctx *abstraction.Context, data *model.WorkChecklistEntityModel, is_done bool) *gorm.DB
+	DeleteByWorkId(ctx *abstraction.Context, work_id int) *gorm.DB
+}
+
+type work_checklist struct {
+	abstraction.Repository
+}
+
+func NewWorkChecklist(db *gorm.DB) *work_checklist {
+	return &work_checklist{
+		Repository: abstraction.Repository{
+			Db: db,
+		},
+	}
+}
+
+func (r *work_checklist) Create(ctx *abstraction.Context, data *model.WorkChecklistEntityModel) *gorm.DB {
+	return r.CheckTrx(ctx).Create(data)
+}
+
+func (r *work_checklist) FindByWorkId(ctx *abstraction.Context, work_id int) (data []*model.WorkChecklistEntityModel, err error) {
+	err = r.CheckTrx(ctx).
+		Where("work_id = ?", work_id).
+		Order("sort_order ASC, id ASC").
+		Find(&data).
+		Error
+	return
+}
+
+// FindByWorkIds returns the checklists of several works at once, used by the exports.
+func (r *work_checklist) FindByWorkIds(ctx *abstraction.Context, work_ids []int) (data []*model.WorkChecklistEntityModel, err error) {
+	if len(work_ids) == 0 {
+		return
+	}
+	err = r.CheckTrx(ctx).
+		Where("work_id IN ?", work_ids).
+		Order("work_id ASC, sort_order ASC, id ASC").
+		Find(&data).
+		Error
+	return
+}
+
+func (r *work_checklist) UpdateDone(ctx *abstraction.Context, data *model.WorkChecklistEntityModel, is_done bool) *gorm.DB {
+	return r.CheckTrx(ctx).Model(data).Where("id = ?", data.ID).Update("is_done", is_done)
+}
+
+func (r *work_checklist) DeleteByWorkId(ctx *abstraction.Context, work_id int) *gorm.DB {
+	return r.CheckTrx(ctx).Where("work_id = ?", work_id).Delete(&model.WorkChecklistEntityModel{})
+}
@@ -0,0 +1,204 @@
+package repository
+
+import (
+	"cleancare/internal/abstraction"
+	"cleancare/internal/model"
+	"cleancare/pkg/constant"
+	"cleancare/pkg/util/general"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type WorkPhoto interface {
+	Create(ctx *abstraction.Context, data *model.WorkPhotoEntityModel) *gorm.DB
+	FindById(ctx *abstraction.Context, id int) (*model.WorkPhotoEntityModel, error)
+	FindByWorkId(ctx *abstraction.Context, work_id int) (data []*model.WorkPhotoEntityModel, err error)
+	FindByWorkIds(ctx *abstraction.Context, work_ids []int) (data []*model.WorkPhotoEntityModel, err error)
+	FindByWorkIdPhase(ctx *abstraction.Context, work_id int, phase string) (data []*model.WorkPhotoEntityModel, err error)
+	FindCover(ctx *abstraction.Context, work_id int, phase string) (*model.WorkPhotoEntityModel, error)
+	CountByWorkIdPhase(ctx *abstraction.Context, work_id int, phase string) (data *int, err error)
+	Update(ctx *abstraction.Context, data *model.WorkPhotoEntityModel) *gorm.DB
+	UpdateFile(ctx *abstraction.Context, data *model.WorkPhotoEntityModel) *gorm.DB
+	UpdateSortOrder(ctx *abstraction.Context, data *model.WorkPhotoEntityModel, sort_order int) *gorm.DB
+	Delete(ctx *abstraction.Context, data *model.WorkPhotoEntityModel) *gorm.DB
+	DeleteByWorkId(ctx *abstraction.Context, work_id int) *gorm.DB
+	FindFileReferences(ctx *abstraction.Context) (data []*model.WorkPhotoFileReference, err error)
+	SyncCover(ctx *abstraction.Context, work_id int, phase string) *gorm.DB
+	FindSimilar(ctx *abstraction.Context, data *model.WorkPhotoEntityModel, since time.Time, max_distance int, limit int) (res []*model.WorkPhotoEntityModel, err error)
+}
+
+type work_photo struct {
+	abstraction.Repository
+}
+
+func NewWorkPhoto(db *gorm.DB) *work_photo {
+	return &work_photo{
+		Repository: abstraction.Repository{
+			Db: db,
+		},
+	}
+}
+
+func (r *work_photo) Create(ctx *abstraction.Context, data *model.WorkPhotoEntityModel) *gorm.DB {
+	return r.CheckTrx(ctx).Create(data)
+}
+
+func (r *work_photo) FindById(ctx *abstraction.Context, id int) (*model.WorkPhotoEntityModel, error) {
+	conn := r.CheckTrx(ctx)
+
+	var data model.WorkPhotoEntityModel
+	err := conn.
+		Where("id = ?", id).
+		First(&data).
+		Error
+	if err != nil {
+		return nil, err
+	}
+	return &data, nil
+}
+
+func (r *work_photo) FindByWorkId(ctx *abstraction.Context, work_id int) (data []*model.WorkPhotoEntityModel, err error) {
+	err = r.CheckTrx(ctx).
+		Where("work_id = ?", work_id).
+		Order("phase ASC, sort_order ASC, id ASC").
+		Find(&data).
+		Error
+	return
+}
+
+// FindByWorkIds returns the photos of several works at once, used by the exports.
+func (r *work_photo) FindByWorkIds(ctx *abstraction.Context, work_ids []int) (data []*model.WorkPhotoEntityModel, err error) {
+	if len(work_ids) == 0 {
+		return
+	}
+	err = r.CheckTrx(ctx).
+		Where("work_id IN ?", work_ids).
+		Order("work_id ASC, phase ASC, sort_order ASC, id ASC").
+		Find(&data).
+		Error
+	return
+}
+
+func (r *work_photo) FindByWorkIdPhase(ctx *abstraction.Context, work_id int, phase string) (data []*model.WorkPhotoEntityModel, err error) {
+	err = r.CheckTrx(ctx).
+		Where("work_id = ? AND phase = ?", work_id, phase).
+		Order("sort_order ASC, id ASC").
+		Find(&data).
+		Error
+	return
+}
+
+// FindCover returns the first photo of the phase, it is mirrored in work.image_before and
+// work.image_after.
+func (r *work_photo) FindCover(ctx *abstraction.Context, work_id int, phase string) (*model.WorkPhotoEntityModel, error) {
+	conn := r.CheckTrx(ctx)
+
+	var data model.WorkPhotoEntityModel
+	err := conn.
+		Where("work_id = ? AND phase = ?", work_id, phase).
+		Order("sort_order ASC, id ASC").
+		First(&data).
+		Error
+	if err != nil {
+		return nil, err
+	}
+	return &data, nil
+}
+
+func (r *work_photo) CountByWorkIdPhase(ctx *abstraction.Context, work_id int, phase string) (data *int, err error) {
+	var count model.WorkPhotoCountDataModel
+	err = r.CheckTrx(ctx).
+		Table("work_photo").
+		Select("COUNT(*) AS count").
+		Where("work_id = ? AND phase = ?", work_id, phase).
+		Find(&count).
+		Error
+	data = &count.Count
+	return
+}
+
+func (r *work_photo) Update(ctx *abstraction.Context, data *model.WorkPhotoEntityModel) *gorm.DB {
+	return r.CheckTrx(ctx).Model(data).Where("id = ?", data.ID).Updates(data)
+}
+
+// UpdateFile replaces the file of the photo, the checks of a file without capture time or hash
+// are cleared as well.
+func (r *work_photo) UpdateFile(ctx *abstraction.Context, data *model.WorkPhotoEntityModel) *gorm.DB {
+	return r.CheckTrx(ctx).Model(data).Where("id = ?", data.ID).Updates(map[string]interface{}{
+		"storage_key": data.StorageKey,
+		"file_name":   data.FileName,
+		"captured_at": data.CapturedAt,
+		"phash":       data.Phash,
+		"updated_by":  ctx.Auth.ID,
+	})
+}
+
+// UpdateSortOrder sets the position of the photo, Update skips the zero value.
+func (r *work_photo) UpdateSortOrder(ctx *abstraction.Context, data *model.WorkPhotoEntityModel, sort_order int) *gorm.DB {
+	return r.CheckTrx(ctx).Model(data).Where("id = ?", data.ID).Updates(map[string]interface{}{
+		"sort_order": sort_order,
+		"updated_by": ctx.Auth.ID,
+	})
+}
+
+func (r *work_photo) Delete(ctx *abstraction.Context, data *model.WorkPhotoEntityModel) *gorm.DB {
+	return r.CheckTrx(ctx).Where("id = ?", data.ID).Delete(data)
+}
+
+func (r *work_photo) DeleteByWorkId(ctx *abstraction.Context, work_id int) *gorm.DB {
+	return r.CheckTrx(ctx).Where("work_id = ?", work_id).Delete(&model.WorkPhotoEntityModel{})
+}
+
+func (r *work_photo) FindFileReferences(ctx *abstraction.Context) (data []*model.WorkPhotoFileReference, err error) {
+	err = r.CheckTrx(ctx).
+		Table("work_photo").
+		Select("id, work_id, storage_key").
+		Order("id ASC").
+		Find(&data).
+		Error
+	return
+}
+
+// SyncCover copies the first photo of the phase to work.image_before or work.image_after, or
+// clears the column when the phase has no photo left. The after cover also sets completed_at,
+// a work without an after photo is not completed.
+func (r *work_photo) SyncCover(ctx *abstraction.Context, work_id int, phase string) *gorm.DB {
+	cover := r.CheckTrx(ctx).
+		Table("work_photo").
+		Select("CONCAT(storage_key, '||DELIMITER_FILE||', file_name)").
+		Where("work_id = ? AND phase = ?", work_id, phase).
+		Order("sort_order ASC, id ASC").
+		Limit(1)
+
+	updates := map[string]interface{}{}
+	switch phase {
+	case constant.WORK_PHOTO_PHASE_BEFORE:
+		updates["image_before"] = cover
+	case constant.WORK_PHOTO_PHASE_AFTER:
+		updates["image_after"] = cover
+		updates["completed_at"] = gorm.Expr("CASE WHEN EXISTS (SELECT 1 FROM work_photo WHERE work_id = ? AND phase = ?) THEN COALESCE(completed_at, ?) ELSE NULL END", work_id, phase, general.Now())
+	default:
+		return r.CheckTrx(ctx)
+	}
+	return r.CheckTrx(ctx).Model(&model.WorkEntityModel{}).Where("id = ?", work_id).Updates(updates)
+}
+
+// FindSimilar returns the photos of the other works uploaded since since whose hash is at most
+// max_distance bits away from the hash of data, the closest first. The photos of deleted works
+// are left out. The hash distance cannot use an index, the window keeps the scan small.
+func (r *work_photo) FindSimilar(ctx *abstraction.Context, data *model.WorkPhotoEntityModel, since time.Time, max_distance int, limit int) (res []*model.WorkPhotoEntityModel, err error) {
+	if data.Phash == nil {
+		return
+	}
+	err = r.CheckTrx(ctx).
+		Where("created_at >= ?", since).
+		Where("work_id <> ? AND phash IS NOT NULL AND BIT_COUNT(phash ^ ?) <= ?", data.WorkId, *data.Phash, max_distance).
+		Where("work_id IN (SELECT id FROM work WHERE is_delete = ?)", false).
+		Order(clause.OrderBy{Expression: clause.Expr{SQL: "BIT_COUNT(phash ^ ?) ASC, id DESC", Vars: []interface{}{*data.Phash}, WithoutParentheses: true}}).
+		Limit(limit).
+		Find(&res).
+		Error
+	return
+}
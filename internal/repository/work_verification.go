@@ -0,0 +1,33 @@
+package repository
+
+import (
+	"cleancare/internal/abstraction"
+	"cleancare/internal/model"
+
+	"gorm.io/gorm"
+)
+
+type WorkVerification interface {
+	Create(ctx *abstraction.Context, data *model.WorkVerificationEntityModel) *gorm.DB
+	DeleteByWorkId(ctx *abstraction.Context, work_id int) *gorm.DB
+}
+
+type workVerification struct {
+	abstraction.Repository
+}
+
+func NewWorkVerification(db *gorm.DB) *workVerification {
+	return &workVerification{
+		Repository: abstraction.Repository{
+			Db: db,
+		},
+	}
+}
+
+func (r *workVerification) Create(ctx *abstraction.Context, data *model.WorkVerificationEntityModel) *gorm.DB {
+	return r.CheckTrx(ctx).Create(data)
+}
+
+func (r *workVerification) DeleteByWorkId(ctx *abstraction.Context, work_id int) *gorm.DB {
+	return r.CheckTrx(ctx).Where("work_id = ?", work_id).Delete(&model.WorkVerificationEntityModel{})
+}
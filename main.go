@@ -1,6 +1,11 @@
 package main
 
 import (
+	"cleancare/internal/app/email"
+	"cleancare/internal/app/sla"
+	"cleancare/internal/app/storage"
+	"cleancare/internal/app/trash"
+	"cleancare/internal/app/upload"
 	"cleancare/internal/config"
 	"cleancare/internal/factory"
 	httpcleancare "cleancare/internal/http"
@@ -48,6 +53,16 @@ func main() {
 
 	ws.InitCentrifugal(ctx, e, f)
 
+	email.StartWorker(ctx, f)
+
+	trash.StartRetention(ctx, f)
+
+	storage.StartGc(ctx, f)
+
+	upload.StartCleanup(ctx, f)
+
+	sla.StartChecker(ctx, f)
+
 	go func() {
 		runNgrok := false
 		addr := ""
@@ -0,0 +1 @@
+ALTER TABLE `user` ADD COLUMN `language` VARCHAR(5) NULL DEFAULT NULL AFTER `floor`;
@@ -0,0 +1,5 @@
+ALTER TABLE `work` ADD COLUMN `completed_at` DATETIME NULL DEFAULT NULL AFTER `image_after`;
+
+UPDATE `work` SET `completed_at` = COALESCE(`updated_at`, `created_at`) WHERE `image_after` IS NOT NULL;
+
+CREATE INDEX `idx_work_created_at` ON `work` (`created_at`);
@@ -0,0 +1,24 @@
+CREATE TABLE IF NOT EXISTS `assignment` (
+  `id` INT NOT NULL AUTO_INCREMENT,
+  `user_id` INT NOT NULL,
+  `task_id` INT NOT NULL,
+  `task_type_id` INT NOT NULL,
+  `floor` VARCHAR(255) NOT NULL,
+  `info` TEXT NULL,
+  `due_at` DATETIME NOT NULL,
+  `work_id` INT NULL DEFAULT NULL,
+  `is_delete` TINYINT(1) NOT NULL DEFAULT 0,
+  `created_at` DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  `updated_at` DATETIME NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+  `created_by` INT NOT NULL,
+  `updated_by` INT NULL DEFAULT NULL,
+  PRIMARY KEY (`id`),
+  KEY `idx_assignment_user_due_at` (`user_id`, `due_at`),
+  KEY `idx_assignment_work_id` (`work_id`)
+);
+
+ALTER TABLE `work`
+  ADD COLUMN `verification_status` VARCHAR(20) NOT NULL DEFAULT 'pending' AFTER `completed_at`,
+  ADD COLUMN `verified_by` INT NULL DEFAULT NULL AFTER `verification_status`,
+  ADD COLUMN `verified_at` DATETIME NULL DEFAULT NULL AFTER `verified_by`,
+  ADD COLUMN `verification_note` TEXT NULL AFTER `verified_at`;
@@ -0,0 +1,11 @@
+CREATE TABLE IF NOT EXISTS `password_history` (
+  `id` INT NOT NULL AUTO_INCREMENT,
+  `user_id` INT NOT NULL,
+  `password` VARCHAR(255) NOT NULL,
+  `created_at` DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  PRIMARY KEY (`id`),
+  KEY `idx_password_history_user_id` (`user_id`, `created_at`)
+);
+
+INSERT INTO `password_history` (`user_id`, `password`, `created_at`)
+SELECT `id`, `password`, COALESCE(`updated_at`, `created_at`) FROM `user` WHERE `password` IS NOT NULL AND `password` <> '';
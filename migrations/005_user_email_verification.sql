@@ -0,0 +1,6 @@
+ALTER TABLE `user`
+  ADD COLUMN `email_verified_at` DATETIME NULL DEFAULT NULL AFTER `email`,
+  ADD COLUMN `pending_email` VARCHAR(255) NULL DEFAULT NULL AFTER `email_verified_at`;
+
+-- addresses registered before verification existed are trusted as they are
+UPDATE `user` SET `email_verified_at` = COALESCE(`updated_at`, `created_at`) WHERE `email` IS NOT NULL;
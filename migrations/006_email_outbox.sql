@@ -0,0 +1,17 @@
+CREATE TABLE IF NOT EXISTS `email_outbox` (
+  `id` INT NOT NULL AUTO_INCREMENT,
+  `recipient` VARCHAR(255) NOT NULL,
+  `subject` VARCHAR(255) NOT NULL,
+  `template` VARCHAR(100) NOT NULL,
+  `lang` VARCHAR(5) NOT NULL,
+  `body` MEDIUMTEXT NOT NULL,
+  `status` VARCHAR(20) NOT NULL DEFAULT 'pending',
+  `attempts` INT NOT NULL DEFAULT 0,
+  `next_attempt_at` DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  `last_error` TEXT NULL,
+  `sent_at` DATETIME NULL DEFAULT NULL,
+  `created_at` DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  `updated_at` DATETIME NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+  PRIMARY KEY (`id`),
+  KEY `idx_email_outbox_status_next_attempt_at` (`status`, `next_attempt_at`)
+);
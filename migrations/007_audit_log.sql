@@ -0,0 +1,16 @@
+CREATE TABLE IF NOT EXISTS `audit_log` (
+  `id` INT NOT NULL AUTO_INCREMENT,
+  `actor_id` INT NULL DEFAULT NULL,
+  `action` VARCHAR(20) NOT NULL,
+  `entity` VARCHAR(100) NOT NULL,
+  `entity_id` INT NULL DEFAULT NULL,
+  `old_values` TEXT NULL,
+  `new_values` TEXT NULL,
+  `ip` VARCHAR(45) NULL DEFAULT NULL,
+  `request_id` VARCHAR(64) NULL DEFAULT NULL,
+  `created_at` DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  PRIMARY KEY (`id`),
+  KEY `idx_audit_log_entity` (`entity`, `entity_id`),
+  KEY `idx_audit_log_actor_id` (`actor_id`),
+  KEY `idx_audit_log_created_at` (`created_at`)
+);
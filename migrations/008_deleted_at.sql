@@ -0,0 +1,21 @@
+ALTER TABLE `user`
+  ADD COLUMN `deleted_at` DATETIME NULL DEFAULT NULL AFTER `is_delete`,
+  ADD KEY `idx_user_is_delete_deleted_at` (`is_delete`, `deleted_at`);
+
+ALTER TABLE `work`
+  ADD COLUMN `deleted_at` DATETIME NULL DEFAULT NULL AFTER `is_delete`,
+  ADD KEY `idx_work_is_delete_deleted_at` (`is_delete`, `deleted_at`);
+
+ALTER TABLE `task_type`
+  ADD COLUMN `deleted_at` DATETIME NULL DEFAULT NULL AFTER `is_delete`,
+  ADD KEY `idx_task_type_is_delete_deleted_at` (`is_delete`, `deleted_at`);
+
+ALTER TABLE `comment`
+  ADD COLUMN `deleted_at` DATETIME NULL DEFAULT NULL AFTER `is_delete`,
+  ADD KEY `idx_comment_is_delete_deleted_at` (`is_delete`, `deleted_at`);
+
+-- records deleted before this migration start their retention period now
+UPDATE `user` SET `deleted_at` = COALESCE(`updated_at`, NOW()) WHERE `is_delete` = 1;
+UPDATE `work` SET `deleted_at` = COALESCE(`updated_at`, NOW()) WHERE `is_delete` = 1;
+UPDATE `task_type` SET `deleted_at` = NOW() WHERE `is_delete` = 1;
+UPDATE `comment` SET `deleted_at` = COALESCE(`updated_at`, NOW()) WHERE `is_delete` = 1;
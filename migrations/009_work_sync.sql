@@ -0,0 +1,14 @@
+ALTER TABLE `work`
+  ADD COLUMN `client_id` VARCHAR(36) NULL DEFAULT NULL AFTER `user_id`,
+  ADD COLUMN `client_updated_at` DATETIME(3) NULL DEFAULT NULL AFTER `client_id`,
+  ADD UNIQUE KEY `uq_work_user_id_client_id` (`user_id`, `client_id`),
+  ADD KEY `idx_work_updated_at` (`updated_at`);
+
+-- task types had no timestamps, the sync endpoint needs to know when they changed
+ALTER TABLE `task_type`
+  ADD COLUMN `created_at` DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  ADD COLUMN `updated_at` DATETIME NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+  ADD KEY `idx_task_type_updated_at` (`updated_at`);
+
+CREATE INDEX `idx_comment_updated_at` ON `comment` (`updated_at`);
+CREATE INDEX `idx_assignment_updated_at` ON `assignment` (`updated_at`);
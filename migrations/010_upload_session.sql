@@ -0,0 +1,18 @@
+CREATE TABLE IF NOT EXISTS `upload_session` (
+  `id` VARCHAR(36) NOT NULL,
+  `user_id` INT NOT NULL,
+  `file_name` VARCHAR(255) NOT NULL,
+  `size` BIGINT NOT NULL,
+  `checksum` VARCHAR(64) NOT NULL,
+  `received` BIGINT NOT NULL DEFAULT 0,
+  `status` VARCHAR(20) NOT NULL DEFAULT 'pending',
+  `file_id` VARCHAR(255) NULL DEFAULT NULL,
+  `drive_name` VARCHAR(255) NULL DEFAULT NULL,
+  `consumed_at` DATETIME NULL DEFAULT NULL,
+  `expires_at` DATETIME NOT NULL,
+  `created_at` DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  `updated_at` DATETIME NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+  PRIMARY KEY (`id`),
+  KEY `idx_upload_session_user_id` (`user_id`),
+  KEY `idx_upload_session_expires_at` (`expires_at`)
+);
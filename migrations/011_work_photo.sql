@@ -0,0 +1,33 @@
+CREATE TABLE IF NOT EXISTS `work_photo` (
+  `id` INT NOT NULL AUTO_INCREMENT,
+  `work_id` INT NOT NULL,
+  `phase` VARCHAR(20) NOT NULL,
+  `storage_key` VARCHAR(255) NOT NULL,
+  `file_name` VARCHAR(255) NOT NULL DEFAULT '',
+  `caption` VARCHAR(255) NULL DEFAULT NULL,
+  `sort_order` INT NOT NULL DEFAULT 0,
+  `taken_at` DATETIME NULL DEFAULT NULL,
+  `created_at` DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  `updated_at` DATETIME NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+  `created_by` INT NOT NULL,
+  `updated_by` INT NULL DEFAULT NULL,
+  PRIMARY KEY (`id`),
+  KEY `idx_work_photo_work_id_phase_sort_order` (`work_id`, `phase`, `sort_order`),
+  KEY `idx_work_photo_storage_key` (`storage_key`)
+);
+
+-- image_before and image_after hold "<file id>||DELIMITER_FILE||<file name>", they become the
+-- first photo of their phase and are kept afterwards as the cover of the phase
+INSERT INTO `work_photo` (`work_id`, `phase`, `storage_key`, `file_name`, `sort_order`, `created_at`, `created_by`)
+SELECT `id`, 'before',
+  SUBSTRING_INDEX(`image_before`, '||DELIMITER_FILE||', 1),
+  IF(LOCATE('||DELIMITER_FILE||', `image_before`) > 0, SUBSTRING(`image_before`, LOCATE('||DELIMITER_FILE||', `image_before`) + 18), ''),
+  0, `created_at`, `user_id`
+FROM `work` WHERE `image_before` IS NOT NULL AND `image_before` != '';
+
+INSERT INTO `work_photo` (`work_id`, `phase`, `storage_key`, `file_name`, `sort_order`, `created_at`, `created_by`)
+SELECT `id`, 'after',
+  SUBSTRING_INDEX(`image_after`, '||DELIMITER_FILE||', 1),
+  IF(LOCATE('||DELIMITER_FILE||', `image_after`) > 0, SUBSTRING(`image_after`, LOCATE('||DELIMITER_FILE||', `image_after`) + 18), ''),
+  0, COALESCE(`completed_at`, `created_at`), `user_id`
+FROM `work` WHERE `image_after` IS NOT NULL AND `image_after` != '';
@@ -0,0 +1,29 @@
+ALTER TABLE `work_photo`
+  ADD COLUMN `captured_at` DATETIME NULL DEFAULT NULL AFTER `taken_at`,
+  ADD COLUMN `phash` BIGINT UNSIGNED NULL DEFAULT NULL AFTER `captured_at`;
+
+ALTER TABLE `upload_session`
+  ADD COLUMN `captured_at` DATETIME NULL DEFAULT NULL AFTER `drive_name`,
+  ADD COLUMN `phash` BIGINT UNSIGNED NULL DEFAULT NULL AFTER `captured_at`;
+
+CREATE TABLE IF NOT EXISTS `photo_flag` (
+  `id` INT NOT NULL AUTO_INCREMENT,
+  `work_id` INT NOT NULL,
+  `work_photo_id` INT NOT NULL,
+  `type` VARCHAR(30) NOT NULL,
+  `captured_at` DATETIME NULL DEFAULT NULL,
+  `submitted_at` DATETIME NOT NULL,
+  `match_work_id` INT NULL DEFAULT NULL,
+  `match_work_photo_id` INT NULL DEFAULT NULL,
+  `distance` INT NULL DEFAULT NULL,
+  `status` VARCHAR(20) NOT NULL DEFAULT 'open',
+  `review_note` VARCHAR(255) NULL DEFAULT NULL,
+  `reviewed_by` INT NULL DEFAULT NULL,
+  `reviewed_at` DATETIME NULL DEFAULT NULL,
+  `created_at` DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  `updated_at` DATETIME NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+  PRIMARY KEY (`id`),
+  KEY `idx_photo_flag_work_id` (`work_id`),
+  KEY `idx_photo_flag_work_photo_id` (`work_photo_id`),
+  KEY `idx_photo_flag_status_created_at` (`status`, `created_at`)
+);
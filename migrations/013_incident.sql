@@ -0,0 +1,42 @@
+CREATE TABLE IF NOT EXISTS `incident` (
+  `id` INT NOT NULL AUTO_INCREMENT,
+  `category` VARCHAR(20) NOT NULL,
+  `severity` VARCHAR(20) NOT NULL,
+  `floor` VARCHAR(100) NOT NULL,
+  `location` VARCHAR(255) NOT NULL DEFAULT '',
+  `description` TEXT NOT NULL,
+  `work_id` INT NULL DEFAULT NULL,
+  `assignee_id` INT NULL DEFAULT NULL,
+  `status` VARCHAR(20) NOT NULL DEFAULT 'open',
+  `acknowledged_by` INT NULL DEFAULT NULL,
+  `acknowledged_at` DATETIME NULL DEFAULT NULL,
+  `resolved_by` INT NULL DEFAULT NULL,
+  `resolved_at` DATETIME NULL DEFAULT NULL,
+  `resolution_note` VARCHAR(255) NULL DEFAULT NULL,
+  `is_delete` TINYINT(1) NOT NULL DEFAULT 0,
+  `deleted_at` DATETIME NULL DEFAULT NULL,
+  `created_at` DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  `updated_at` DATETIME NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+  `created_by` INT NOT NULL,
+  `updated_by` INT NULL DEFAULT NULL,
+  PRIMARY KEY (`id`),
+  KEY `idx_incident_status_severity` (`status`, `severity`),
+  KEY `idx_incident_work_id` (`work_id`),
+  KEY `idx_incident_assignee_id` (`assignee_id`),
+  KEY `idx_incident_created_by` (`created_by`)
+);
+
+CREATE TABLE IF NOT EXISTS `incident_photo` (
+  `id` INT NOT NULL AUTO_INCREMENT,
+  `incident_id` INT NOT NULL,
+  `storage_key` VARCHAR(255) NOT NULL,
+  `file_name` VARCHAR(255) NOT NULL DEFAULT '',
+  `sort_order` INT NOT NULL DEFAULT 0,
+  `created_at` DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  `updated_at` DATETIME NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+  `created_by` INT NOT NULL,
+  `updated_by` INT NULL DEFAULT NULL,
+  PRIMARY KEY (`id`),
+  KEY `idx_incident_photo_incident_id_sort_order` (`incident_id`, `sort_order`),
+  KEY `idx_incident_photo_storage_key` (`storage_key`)
+);
@@ -0,0 +1,60 @@
+CREATE TABLE IF NOT EXISTS `inventory_item` (
+  `id` INT NOT NULL AUTO_INCREMENT,
+  `name` VARCHAR(100) NOT NULL,
+  `unit` VARCHAR(20) NOT NULL,
+  `min_stock` DECIMAL(12,2) NOT NULL DEFAULT 0,
+  `is_delete` TINYINT(1) NOT NULL DEFAULT 0,
+  `deleted_at` DATETIME NULL DEFAULT NULL,
+  `created_at` DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  `updated_at` DATETIME NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+  `created_by` INT NOT NULL,
+  `updated_by` INT NULL DEFAULT NULL,
+  PRIMARY KEY (`id`)
+);
+
+CREATE TABLE IF NOT EXISTS `inventory_location` (
+  `id` INT NOT NULL AUTO_INCREMENT,
+  `name` VARCHAR(100) NOT NULL,
+  `floor` VARCHAR(100) NOT NULL DEFAULT '',
+  `is_delete` TINYINT(1) NOT NULL DEFAULT 0,
+  `deleted_at` DATETIME NULL DEFAULT NULL,
+  `created_at` DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  `updated_at` DATETIME NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+  `created_by` INT NOT NULL,
+  `updated_by` INT NULL DEFAULT NULL,
+  PRIMARY KEY (`id`)
+);
+
+CREATE TABLE IF NOT EXISTS `inventory_stock` (
+  `id` INT NOT NULL AUTO_INCREMENT,
+  `item_id` INT NOT NULL,
+  `location_id` INT NOT NULL,
+  `quantity` DECIMAL(12,2) NOT NULL DEFAULT 0,
+  `created_at` DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  `updated_at` DATETIME NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+  PRIMARY KEY (`id`),
+  UNIQUE KEY `uq_inventory_stock_item_id_location_id` (`item_id`, `location_id`),
+  KEY `idx_inventory_stock_location_id` (`location_id`)
+);
+
+-- quantity is signed: positive for in, negative for out, either for an adjustment
+CREATE TABLE IF NOT EXISTS `inventory_movement` (
+  `id` INT NOT NULL AUTO_INCREMENT,
+  `item_id` INT NOT NULL,
+  `location_id` INT NOT NULL,
+  `type` VARCHAR(20) NOT NULL,
+  `quantity` DECIMAL(12,2) NOT NULL,
+  `stock_after` DECIMAL(12,2) NOT NULL,
+  `work_id` INT NULL DEFAULT NULL,
+  `task_type_id` INT NULL DEFAULT NULL,
+  `note` VARCHAR(255) NULL DEFAULT NULL,
+  `created_at` DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  `updated_at` DATETIME NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+  `created_by` INT NOT NULL,
+  `updated_by` INT NULL DEFAULT NULL,
+  PRIMARY KEY (`id`),
+  KEY `idx_inventory_movement_item_id_created_at` (`item_id`, `created_at`),
+  KEY `idx_inventory_movement_location_id` (`location_id`),
+  KEY `idx_inventory_movement_work_id` (`work_id`),
+  KEY `idx_inventory_movement_created_at` (`created_at`)
+);
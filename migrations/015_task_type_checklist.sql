@@ -0,0 +1,31 @@
+CREATE TABLE IF NOT EXISTS `task_type_checklist_item` (
+  `id` INT NOT NULL AUTO_INCREMENT,
+  `task_type_id` INT NOT NULL,
+  `name` VARCHAR(255) NOT NULL,
+  `is_required` TINYINT(1) NOT NULL DEFAULT 0,
+  `sort_order` INT NOT NULL DEFAULT 0,
+  `is_delete` TINYINT(1) NOT NULL DEFAULT 0,
+  `deleted_at` DATETIME NULL DEFAULT NULL,
+  `created_at` DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  `updated_at` DATETIME NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+  `created_by` INT NOT NULL,
+  `updated_by` INT NULL DEFAULT NULL,
+  PRIMARY KEY (`id`),
+  KEY `idx_task_type_checklist_item_task_type_id_sort_order` (`task_type_id`, `sort_order`)
+);
+
+-- the checklist of a work is a copy of the template of its task type taken when the work is
+-- created, later changes to the template do not rewrite what was done on earlier works
+CREATE TABLE IF NOT EXISTS `work_checklist` (
+  `id` INT NOT NULL AUTO_INCREMENT,
+  `work_id` INT NOT NULL,
+  `checklist_item_id` INT NOT NULL,
+  `name` VARCHAR(255) NOT NULL,
+  `is_required` TINYINT(1) NOT NULL DEFAULT 0,
+  `is_done` TINYINT(1) NOT NULL DEFAULT 0,
+  `sort_order` INT NOT NULL DEFAULT 0,
+  `created_at` DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  `updated_at` DATETIME NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+  PRIMARY KEY (`id`),
+  UNIQUE KEY `uq_work_checklist_work_id_checklist_item_id` (`work_id`, `checklist_item_id`)
+);
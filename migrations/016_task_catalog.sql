@@ -0,0 +1,10 @@
+ALTER TABLE `task`
+  ADD COLUMN `dashboard_group` VARCHAR(20) NOT NULL DEFAULT 'user' AFTER `name`,
+  ADD COLUMN `requires_image_before` TINYINT(1) NOT NULL DEFAULT 0 AFTER `dashboard_group`,
+  ADD COLUMN `requires_image_after` TINYINT(1) NOT NULL DEFAULT 0 AFTER `requires_image_before`,
+  ADD COLUMN `sla_hours` INT NULL DEFAULT NULL AFTER `requires_image_after`,
+  ADD COLUMN `is_delete` TINYINT(1) NOT NULL DEFAULT 0 AFTER `sla_hours`,
+  ADD COLUMN `deleted_at` DATETIME NULL DEFAULT NULL AFTER `is_delete`;
+
+-- the daily task was grouped by floor on the admin dashboard, every other task by staff
+UPDATE `task` SET `dashboard_group` = 'floor' WHERE `id` = 1;
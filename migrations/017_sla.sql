@@ -0,0 +1,26 @@
+-- a policy without task_type_id covers every task type of the task that has no policy of its own
+CREATE TABLE IF NOT EXISTS `sla_policy` (
+  `id` INT NOT NULL AUTO_INCREMENT,
+  `task_id` INT NOT NULL,
+  `task_type_id` INT NULL DEFAULT NULL,
+  `start_minutes` INT NULL DEFAULT NULL,
+  `complete_minutes` INT NULL DEFAULT NULL,
+  `at_risk_percent` INT NOT NULL DEFAULT 80,
+  `is_delete` TINYINT(1) NOT NULL DEFAULT 0,
+  `deleted_at` DATETIME NULL DEFAULT NULL,
+  `created_at` DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  `updated_at` DATETIME NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+  `created_by` INT NOT NULL,
+  `updated_by` INT NULL DEFAULT NULL,
+  PRIMARY KEY (`id`),
+  KEY `idx_sla_policy_task_id_task_type_id` (`task_id`, `task_type_id`)
+);
+
+-- the due times are taken from the policy when the work is created, sla_status is NULL for a
+-- work without sla and is final once met or breached
+ALTER TABLE `work`
+  ADD COLUMN `sla_policy_id` INT NULL DEFAULT NULL AFTER `verification_note`,
+  ADD COLUMN `sla_start_due_at` DATETIME NULL DEFAULT NULL AFTER `sla_policy_id`,
+  ADD COLUMN `sla_complete_due_at` DATETIME NULL DEFAULT NULL AFTER `sla_start_due_at`,
+  ADD COLUMN `sla_status` VARCHAR(20) NULL DEFAULT NULL AFTER `sla_complete_due_at`,
+  ADD KEY `idx_work_sla_status` (`sla_status`);
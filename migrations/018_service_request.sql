@@ -0,0 +1,41 @@
+-- an area is a place occupants can ask for cleaning from, code is printed as a qr code
+CREATE TABLE IF NOT EXISTS `area` (
+  `id` INT NOT NULL AUTO_INCREMENT,
+  `name` VARCHAR(100) NOT NULL,
+  `floor` VARCHAR(100) NOT NULL,
+  `location` VARCHAR(255) NOT NULL DEFAULT '',
+  `code` VARCHAR(32) NOT NULL,
+  `is_active` TINYINT(1) NOT NULL DEFAULT 1,
+  `is_delete` TINYINT(1) NOT NULL DEFAULT 0,
+  `deleted_at` DATETIME NULL DEFAULT NULL,
+  `created_at` DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  `updated_at` DATETIME NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+  `created_by` INT NOT NULL,
+  `updated_by` INT NULL DEFAULT NULL,
+  PRIMARY KEY (`id`),
+  UNIQUE KEY `uq_area_code` (`code`)
+);
+
+-- service requests are sent without an account, so they have no created_by
+CREATE TABLE IF NOT EXISTS `service_request` (
+  `id` INT NOT NULL AUTO_INCREMENT,
+  `area_id` INT NOT NULL,
+  `description` TEXT NOT NULL,
+  `requester_name` VARCHAR(100) NULL DEFAULT NULL,
+  `requester_contact` VARCHAR(100) NULL DEFAULT NULL,
+  `photo_storage_key` VARCHAR(255) NULL DEFAULT NULL,
+  `photo_file_name` VARCHAR(255) NULL DEFAULT NULL,
+  `ip` VARCHAR(45) NOT NULL DEFAULT '',
+  `status` VARCHAR(20) NOT NULL DEFAULT 'pending',
+  `assignment_id` INT NULL DEFAULT NULL,
+  `triage_note` VARCHAR(255) NULL DEFAULT NULL,
+  `triaged_by` INT NULL DEFAULT NULL,
+  `triaged_at` DATETIME NULL DEFAULT NULL,
+  `created_at` DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  `updated_at` DATETIME NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+  PRIMARY KEY (`id`),
+  KEY `idx_service_request_status` (`status`),
+  KEY `idx_service_request_area_id` (`area_id`),
+  KEY `idx_service_request_assignment_id` (`assignment_id`),
+  KEY `idx_service_request_photo_storage_key` (`photo_storage_key`)
+);
@@ -0,0 +1,24 @@
+-- an assignment may be for an area, its work is the one the occupants of the area rate
+ALTER TABLE `assignment`
+  ADD COLUMN `area_id` INT NULL DEFAULT NULL AFTER `task_type_id`,
+  ADD KEY `idx_assignment_area_id` (`area_id`);
+
+-- feedback is sent by occupants from the link of an area, without an account, and rates
+-- the last work completed for the area
+CREATE TABLE IF NOT EXISTS `feedback` (
+  `id` INT NOT NULL AUTO_INCREMENT,
+  `area_id` INT NOT NULL,
+  `work_id` INT NOT NULL,
+  `user_id` INT NOT NULL,
+  `floor` VARCHAR(100) NOT NULL,
+  `rating` TINYINT NOT NULL,
+  `comment` VARCHAR(1000) NULL DEFAULT NULL,
+  `ip` VARCHAR(45) NOT NULL DEFAULT '',
+  `created_at` DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  `updated_at` DATETIME NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+  PRIMARY KEY (`id`),
+  KEY `idx_feedback_area_id` (`area_id`),
+  KEY `idx_feedback_work_id` (`work_id`),
+  KEY `idx_feedback_user_id_created_at` (`user_id`, `created_at`),
+  KEY `idx_feedback_floor_created_at` (`floor`, `created_at`)
+);
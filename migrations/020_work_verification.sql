@@ -0,0 +1,17 @@
+-- every verification of a work is kept, the work only holds the last one and goes back to
+-- pending when the staff sends new proof after a rejection
+CREATE TABLE IF NOT EXISTS `work_verification` (
+  `id` INT NOT NULL AUTO_INCREMENT,
+  `work_id` INT NOT NULL,
+  `status` VARCHAR(20) NOT NULL,
+  `note` VARCHAR(255) NULL DEFAULT NULL,
+  `verified_by` INT NOT NULL,
+  `created_at` DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  PRIMARY KEY (`id`),
+  KEY `idx_work_verification_work_id` (`work_id`),
+  KEY `idx_work_verification_status_created_at` (`status`, `created_at`)
+);
+
+INSERT INTO `work_verification` (`work_id`, `status`, `note`, `verified_by`, `created_at`)
+SELECT `id`, `verification_status`, `verification_note`, `verified_by`, `verified_at` FROM `work`
+WHERE `verified_by` IS NOT NULL AND `verified_at` IS NOT NULL AND `verification_status` <> 'pending';
@@ -0,0 +1,3 @@
+-- the duplicate photo check only compares hashes of photos taken in the recent window
+ALTER TABLE `work_photo`
+  ADD KEY `idx_work_photo_created_at` (`created_at`);
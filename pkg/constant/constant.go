@@ -5,20 +5,74 @@ const (
 
 	ROLE_ID_ADMIN                             = 1
 	ROLE_ID_STAFF                             = 2
-	TASK_ID_DAILY                             = 1
-	TASK_ID_SERVICE                           = 2
+	TASK_DASHBOARD_GROUP_FLOOR                = "floor"
+	TASK_DASHBOARD_GROUP_USER                 = "user"
+	SLA_STATUS_ON_TRACK                       = "on_track"
+	SLA_STATUS_AT_RISK                        = "at_risk"
+	SLA_STATUS_BREACHED                       = "breached"
+	SLA_STATUS_MET                            = "met"
+	SLA_AT_RISK_PERCENT_DEFAULT               = 80
+	WORK_VERIFICATION_PENDING                 = "pending"
+	WORK_VERIFICATION_APPROVED                = "approved"
+	WORK_VERIFICATION_REJECTED                = "rejected"
+	EMAIL_STATUS_PENDING                      = "pending"
+	EMAIL_STATUS_SENDING                      = "sending"
+	EMAIL_STATUS_SENT                         = "sent"
+	EMAIL_STATUS_DEAD                         = "dead"
+	AUDIT_ACTION_CREATE                       = "create"
+	AUDIT_ACTION_UPDATE                       = "update"
+	AUDIT_ACTION_DELETE                       = "delete"
+	AUDIT_ACTION_RESTORE                      = "restore"
+	AUDIT_ACTION_PURGE                        = "purge"
+	WORK_PHOTO_PHASE_BEFORE                   = "before"
+	WORK_PHOTO_PHASE_AFTER                    = "after"
+	WORK_PHOTO_PHASE_ISSUE                    = "issue"
+	WORK_PHOTO_MAX_PER_PHASE                  = 10
+	PHOTO_FLAG_TYPE_CAPTURE_TIME              = "capture_time"
+	PHOTO_FLAG_TYPE_NO_CAPTURE_TIME           = "no_capture_time"
+	PHOTO_FLAG_TYPE_DUPLICATE                 = "duplicate"
+	PHOTO_FLAG_STATUS_OPEN                    = "open"
+	PHOTO_FLAG_STATUS_CONFIRMED               = "confirmed"
+	PHOTO_FLAG_STATUS_DISMISSED               = "dismissed"
+	INCIDENT_STATUS_OPEN                      = "open"
+	INCIDENT_STATUS_ACKNOWLEDGED              = "acknowledged"
+	INCIDENT_STATUS_RESOLVED                  = "resolved"
+	INCIDENT_PHOTO_MAX                        = 10
+	SERVICE_REQUEST_STATUS_PENDING            = "pending"
+	SERVICE_REQUEST_STATUS_ASSIGNED           = "assigned"
+	SERVICE_REQUEST_STATUS_REJECTED           = "rejected"
+	SERVICE_REQUEST_STATUS_IN_PROGRESS        = "in_progress"
+	SERVICE_REQUEST_STATUS_DONE               = "done"
+	INVENTORY_MOVEMENT_IN                     = "in"
+	INVENTORY_MOVEMENT_OUT                    = "out"
+	INVENTORY_MOVEMENT_ADJUST                 = "adjust"
+	UPLOAD_STATUS_PENDING                     = "pending"
+	UPLOAD_STATUS_COMPLETED                   = "completed"
+	USER_IMPORT_MAX_ROWS                      = 1000
+	USER_IMPORT_MAX_FLOOR_LENGTH              = 100
 	REDIS_REQUEST_RESET_PASSWORD_IP_KEYS      = "cleancare-reset-password:ip:%s"
 	REDIS_REQUEST_VERIFY_NUMBER_IP_KEYS       = "cleancare-verify-mumber:ip:%s"
 	REDIS_REQUEST_REGISTER_IP_KEYS            = "cleancare-register:ip:%s"
+	REDIS_REQUEST_VERIFY_EMAIL_IP_KEYS        = "cleancare-verify-email:ip:%s"
+	REDIS_REQUEST_SERVICE_REQUEST_IP_KEYS     = "cleancare-service-request:ip:%s"
+	REDIS_REQUEST_FEEDBACK_IP_KEYS            = "cleancare-feedback:ip:%s"
 	REDIS_REQUEST_MAX_ATTEMPTS_RESET_PASSWORD = 10
 	REDIS_REQUEST_MAX_ATTEMPTS_VERIFY_NUMBER  = 10
 	REDIS_REQUEST_MAX_ATTEMPTS_REGISTER       = 10
+	REDIS_REQUEST_MAX_ATTEMPTS_VERIFY_EMAIL   = 10
+	REDIS_REQUEST_MAX_ATTEMPTS_PUBLIC_REQUEST = 10
+	REDIS_REQUEST_MAX_ATTEMPTS_FEEDBACK       = 10
 	REDIS_REQUEST_IP_EXPIRE                   = 240
 	REDIS_KEY_USER_LOGIN                      = "cleancare_login_token_user_"
 	REDIS_KEY_AUTO_LOGOUT                     = "cleancare_user_auto_logout"
 	REDIS_KEY_REFRESH_TOKEN                   = "cleancare-refresh-token:%s"
 	REDIS_KEY_UNREAD_COMMENT                  = "cleancare-unread-comment:%d"
+	REDIS_KEY_USER_LANGUAGE                   = "cleancare-user-language:%d"
+	REDIS_KEY_RESET_PASSWORD_TOKEN            = "cleancare-reset-password-token:%d"
 	REDIS_MAX_REFRESH_TOKEN                   = 30
+	REDIS_KEY_WORK_ANALYTICS                  = "cleancare-work-analytics:%s:%s"
+	REDIS_WORK_ANALYTICS_EXPIRE               = 5
+	REDIS_KEY_IDEMPOTENCY                     = "cleancare-idempotency:%d:%s:%s:%s"
 
 	PATH_FILE_SAVED    = "../file_saved"
 	PATH_ASSETS_IMAGES = "assets/images"
@@ -131,6 +131,22 @@ func CheckFolderByName(service *drive.Service, name string, parentId string) (*d
 	return nil, nil
 }
 
+func ListFiles(service *drive.Service, parentId string) ([]*drive.File, error) {
+	query := fmt.Sprintf("'%s' in parents and trashed = false and mimeType != 'application/vnd.google-apps.folder'", parentId)
+
+	var files []*drive.File
+	err := service.Files.List().Q(query).Fields("nextPageToken, files(id, name, size, createdTime)").PageSize(1000).
+		Pages(context.Background(), func(fileList *drive.FileList) error {
+			files = append(files, fileList.Files...)
+			return nil
+		})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list files: %v", err)
+	}
+
+	return files, nil
+}
+
 func CreateFolder(service *drive.Service, name string, parentId string) (*drive.File, error) {
 	d := &drive.File{
 		Name:     name,
@@ -4,12 +4,22 @@ import (
 	"cleancare/internal/config"
 	"cleancare/pkg/util/general"
 	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
 	"strconv"
+	"strings"
+	"time"
 
 	"github.com/sirupsen/logrus"
 	"gopkg.in/gomail.v2"
 )
 
+const (
+	DRIVER_SMTP = "smtp"
+	DRIVER_FILE = "file"
+)
+
 func SendMail(recipient, subject, bodyHtml string) error {
 	if bodyHtml == "" {
 		return errors.New("error parsing body html")
@@ -22,6 +32,10 @@ func SendMail(recipient, subject, bodyHtml string) error {
 	mailer.SetBody("text/plain", general.ParseTemplateEmailToPlainText(bodyHtml))
 	mailer.AddAlternative("text/html", bodyHtml)
 
+	if config.Get().Gomail.Driver == DRIVER_FILE {
+		return writeMail(mailer, recipient)
+	}
+
 	portMail, _ := strconv.Atoi(config.Get().Gomail.SmtpPort)
 	dialer := gomail.NewDialer(
 		config.Get().Gomail.SmtpHost,
@@ -39,3 +53,34 @@ func SendMail(recipient, subject, bodyHtml string) error {
 	logrus.Info("Mail sent!")
 	return nil
 }
+
+// writeMail saves the message as an .eml file, used for local runs without an smtp server.
+func writeMail(mailer *gomail.Message, recipient string) error {
+	dir := config.Get().Gomail.FileDir
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return err
+	}
+
+	name := fmt.Sprintf("%s_%s.eml", time.Now().Format("20060102150405.000000"), fileSafe(recipient))
+	f, err := os.Create(filepath.Join(dir, name))
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	if _, err = mailer.WriteTo(f); err != nil {
+		return err
+	}
+
+	logrus.Info("Mail written to ", filepath.Join(dir, name))
+	return nil
+}
+
+func fileSafe(val string) string {
+	return strings.Map(func(r rune) rune {
+		if (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z') || (r >= '0' && r <= '9') || r == '.' || r == '@' || r == '-' {
+			return r
+		}
+		return '_'
+	}, val)
+}
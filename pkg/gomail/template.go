@@ -0,0 +1,138 @@
+package gomail
+
+import (
+	"cleancare/pkg/i18n"
+	"cleancare/pkg/util/general"
+	"errors"
+	"sort"
+)
+
+// Template is an email under assets/html/email, T is the data the html expects so a
+// caller cannot forget a field the template uses.
+type Template[T any] struct {
+	Name    string
+	File    string
+	Subject string
+}
+
+type TemplateInfo struct {
+	Name    string `json:"name"`
+	File    string `json:"file"`
+	Subject string `json:"subject"`
+}
+
+var registry = map[string]TemplateInfo{}
+
+func register[T any](t Template[T]) Template[T] {
+	registry[t.Name] = TemplateInfo{
+		Name:    t.Name,
+		File:    t.File,
+		Subject: t.Subject,
+	}
+	return t
+}
+
+type ForgotPasswordData struct {
+	NAME   string
+	EMAIL  string
+	LINK   string
+	EXPIRE int
+}
+
+type VerifyEmailData struct {
+	NAME   string
+	LINK   string
+	EXPIRE int
+}
+
+type IncidentReportedData struct {
+	NAME        string
+	REPORTER    string
+	CATEGORY    string
+	SEVERITY    string
+	FLOOR       string
+	LOCATION    string
+	DESCRIPTION string
+}
+
+type IncidentAssignedData struct {
+	NAME        string
+	CATEGORY    string
+	SEVERITY    string
+	FLOOR       string
+	LOCATION    string
+	DESCRIPTION string
+}
+
+type InventoryLowStockData struct {
+	NAME      string
+	ITEM      string
+	LOCATION  string
+	STOCK     string
+	MIN_STOCK string
+	UNIT      string
+}
+
+type SlaEscalationData struct {
+	NAME      string
+	STATUS    string
+	STAFF     string
+	TASK      string
+	TASK_TYPE string
+	FLOOR     string
+	DUE       string
+}
+
+var (
+	TemplateForgotPassword = register(Template[ForgotPasswordData]{
+		Name:    "forgot_password",
+		File:    "./assets/html/email/notif_forgot_password.html",
+		Subject: "email.forgot_password.subject",
+	})
+	TemplateVerifyEmail = register(Template[VerifyEmailData]{
+		Name:    "verify_email",
+		File:    "./assets/html/email/notif_verify_email.html",
+		Subject: "email.verify_email.subject",
+	})
+	TemplateIncidentReported = register(Template[IncidentReportedData]{
+		Name:    "incident_reported",
+		File:    "./assets/html/email/notif_incident_reported.html",
+		Subject: "email.incident_reported.subject",
+	})
+	TemplateIncidentAssigned = register(Template[IncidentAssignedData]{
+		Name:    "incident_assigned",
+		File:    "./assets/html/email/notif_incident_assigned.html",
+		Subject: "email.incident_assigned.subject",
+	})
+	TemplateInventoryLowStock = register(Template[InventoryLowStockData]{
+		Name:    "inventory_low_stock",
+		File:    "./assets/html/email/notif_inventory_low_stock.html",
+		Subject: "email.inventory_low_stock.subject",
+	})
+	TemplateSlaEscalation = register(Template[SlaEscalationData]{
+		Name:    "sla_escalation",
+		File:    "./assets/html/email/notif_sla_escalation.html",
+		Subject: "email.sla_escalation.subject",
+	})
+)
+
+// Render returns the subject and html body of the template in lang.
+func (t Template[T]) Render(lang string, data T) (string, string, error) {
+	body := general.ParseTemplateEmailToHtml(t.File, lang, data)
+	if body == "" {
+		return "", "", errors.New("error parsing body html")
+	}
+	return i18n.T(lang, t.Subject), body, nil
+}
+
+// Templates lists every registered template sorted by name.
+func Templates() []TemplateInfo {
+	res := make([]TemplateInfo, 0, len(registry))
+	for _, v := range registry {
+		res = append(res, v)
+	}
+	sort.Slice(res, func(i, j int) bool {
+		return res[i].Name < res[j].Name
+	})
+	return res
+}
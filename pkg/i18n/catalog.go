@@ -0,0 +1,377 @@
+package i18n
+
+var labelsID = map[string]string{
+	"day.0":    "Minggu",
+	"day.1":    "Senin",
+	"day.2":    "Selasa",
+	"day.3":    "Rabu",
+	"day.4":    "Kamis",
+	"day.5":    "Jumat",
+	"day.6":    "Sabtu",
+	"month.1":  "Januari",
+	"month.2":  "Februari",
+	"month.3":  "Maret",
+	"month.4":  "April",
+	"month.5":  "Mei",
+	"month.6":  "Juni",
+	"month.7":  "Juli",
+	"month.8":  "Agustus",
+	"month.9":  "September",
+	"month.10": "Oktober",
+	"month.11": "November",
+	"month.12": "Desember",
+
+	"role.staff":   "Petugas Kebersihan",
+	"role.admin":   "Supervisor",
+	"verified.yes": "Sudah",
+	"verified.no":  "Belum",
+
+	"export.work.title":         "CleanCare - Laporan Pekerjaan Petugas Kebersihan",
+	"export.work.all_date":      "semua tanggal",
+	"export.work.no":            "No",
+	"export.work.staff":         "Petugas Kebersihan",
+	"export.work.task":          "Pekerjaan",
+	"export.work.task_type":     "Jenis Pekerjaan",
+	"export.work.floor":         "Lantai",
+	"export.work.info":          "Keterangan",
+	"export.work.image_before":  "Sebelum",
+	"export.work.image_after":   "Sesudah",
+	"export.work.checklist":     "Checklist",
+	"export.work.date":          "Tanggal",
+	"export.user.title":         "CleanCare - Laporan Data Pengguna",
+	"export.user.no":            "No",
+	"export.user.number_id":     "Nomor ID",
+	"export.user.name":          "Nama",
+	"export.user.email":         "Email",
+	"export.user.role":          "Jabatan",
+	"export.user.registered_at": "Tanggal Terdaftar",
+	"export.user.verified":      "Status Verifikasi",
+	"export.user.floor":         "Penempatan",
+
+	"export.scorecard.title":              "CleanCare - Papan Peringkat Petugas Kebersihan",
+	"export.scorecard.rank":               "Peringkat",
+	"export.scorecard.staff":              "Petugas Kebersihan",
+	"export.scorecard.completed":          "Pekerjaan Selesai",
+	"export.scorecard.assigned":           "Penugasan",
+	"export.scorecard.on_time_rate":       "Tepat Waktu (%)",
+	"export.scorecard.rejected":           "Ditolak",
+	"export.scorecard.comment_turnaround": "Respon Komentar (menit)",
+
+	"email.footer":                  "Email ini dibuat secara otomatis. Mohon tidak mengirimkan balasan ke email ini",
+	"email.forgot_password.subject": "Lupa Kata Sandi CleanCare",
+	"email.forgot_password.body":    "%s, kami menerima permintaan lupa kata sandi, silakan klik tautan di bawah ini untuk mengatur ulang kata sandi Anda.",
+	"email.forgot_password.button":  "Klik tautan",
+	"email.forgot_password.ignore":  "Jika Anda tidak meminta pengaturan ulang kata sandi, abaikan email ini. Hanya orang yang memiliki akses ke email Anda yang dapat mengatur ulang kata sandi akun Anda.",
+	"email.forgot_password.expire":  "Tautan ini berlaku selama %d menit dan hanya dapat digunakan sekali.",
+
+	"webview.reset_password.title":            "Atur Ulang Kata Sandi",
+	"webview.reset_password.new_password":     "Kata Sandi Baru",
+	"webview.reset_password.confirm_password": "Konfirmasi Kata Sandi",
+	"webview.reset_password.min_length":       "Minimal %d karakter",
+	"webview.reset_password.submit":           "Simpan Kata Sandi",
+
+	"email.verify_email.subject": "Verifikasi Email CleanCare",
+	"email.verify_email.body":    "%s, silakan klik tautan di bawah ini untuk memverifikasi alamat email Anda di CleanCare.",
+	"email.verify_email.button":  "Verifikasi email",
+	"email.verify_email.expire":  "Tautan ini berlaku selama %d jam.",
+	"email.verify_email.ignore":  "Jika Anda tidak mendaftarkan atau mengubah email di CleanCare, abaikan email ini.",
+
+	"webview.verify_email.success": "Berhasil Memverifikasi Email",
+	"webview.verify_email.failed":  "Gagal Memverifikasi Email Karena",
+
+	"email.incident_reported.subject": "Laporan Insiden Baru CleanCare",
+	"email.incident_reported.body":    "%s, %s melaporkan insiden baru yang perlu ditindaklanjuti.",
+	"email.incident_assigned.subject": "Penugasan Insiden CleanCare",
+	"email.incident_assigned.body":    "%s, Anda ditugaskan untuk menangani insiden berikut.",
+	"email.incident.category":         "Kategori",
+	"email.incident.severity":         "Tingkat",
+	"email.incident.floor":            "Lantai",
+	"email.incident.location":         "Lokasi",
+	"email.incident.description":      "Keterangan",
+
+	"incident.category.fixture":  "Fasilitas Rusak",
+	"incident.category.leak":     "Kebocoran",
+	"incident.category.supply":   "Kekurangan Perlengkapan",
+	"incident.category.safety":   "Keselamatan",
+	"incident.category.other":    "Lainnya",
+	"incident.severity.low":      "Rendah",
+	"incident.severity.medium":   "Sedang",
+	"incident.severity.high":     "Tinggi",
+	"incident.severity.critical": "Kritis",
+
+	"email.inventory_low_stock.subject":   "Stok Perlengkapan CleanCare Menipis",
+	"email.inventory_low_stock.body":      "%s, stok %s di %s sudah di bawah batas minimum.",
+	"email.inventory_low_stock.stock":     "Stok saat ini",
+	"email.inventory_low_stock.min_stock": "Batas minimum",
+
+	"email.sla_escalation.subject": "Eskalasi SLA Pekerjaan CleanCare",
+	"email.sla_escalation.body":    "%s, sebuah pekerjaan sekarang berstatus %s.",
+	"email.sla_escalation.task":    "Pekerjaan",
+	"email.sla_escalation.staff":   "Petugas",
+	"email.sla_escalation.floor":   "Lantai",
+	"email.sla_escalation.due":     "Tenggat",
+
+	"sla.status.on_track": "sesuai jadwal",
+	"sla.status.at_risk":  "berisiko terlambat",
+	"sla.status.breached": "terlambat",
+	"sla.status.met":      "terpenuhi",
+
+	"export.inventory.title":     "CleanCare - Laporan Pemakaian Perlengkapan",
+	"export.inventory.no":        "No",
+	"export.inventory.item":      "Perlengkapan",
+	"export.inventory.unit":      "Satuan",
+	"export.inventory.stock_in":  "Masuk",
+	"export.inventory.stock_out": "Terpakai",
+	"export.inventory.adjusted":  "Penyesuaian",
+	"export.inventory.stock":     "Stok Saat Ini",
+}
+
+var labelsEN = map[string]string{
+	"day.0":    "Sunday",
+	"day.1":    "Monday",
+	"day.2":    "Tuesday",
+	"day.3":    "Wednesday",
+	"day.4":    "Thursday",
+	"day.5":    "Friday",
+	"day.6":    "Saturday",
+	"month.1":  "January",
+	"month.2":  "February",
+	"month.3":  "March",
+	"month.4":  "April",
+	"month.5":  "May",
+	"month.6":  "June",
+	"month.7":  "July",
+	"month.8":  "August",
+	"month.9":  "September",
+	"month.10": "October",
+	"month.11": "November",
+	"month.12": "December",
+
+	"role.staff":   "Cleaning Staff",
+	"role.admin":   "Supervisor",
+	"verified.yes": "Yes",
+	"verified.no":  "No",
+
+	"export.work.title":         "CleanCare - Cleaning Staff Work Report",
+	"export.work.all_date":      "all date",
+	"export.work.no":            "No",
+	"export.work.staff":         "Cleaning Staff",
+	"export.work.task":          "Task",
+	"export.work.task_type":     "Task Type",
+	"export.work.floor":         "Floor",
+	"export.work.info":          "Info",
+	"export.work.image_before":  "Before",
+	"export.work.image_after":   "After",
+	"export.work.checklist":     "Checklist",
+	"export.work.date":          "Date",
+	"export.user.title":         "CleanCare - User Data Report",
+	"export.user.no":            "No",
+	"export.user.number_id":     "Number ID",
+	"export.user.name":          "Name",
+	"export.user.email":         "Email",
+	"export.user.role":          "Position",
+	"export.user.registered_at": "Registered At",
+	"export.user.verified":      "Verification Status",
+	"export.user.floor":         "Placement",
+
+	"export.scorecard.title":              "CleanCare - Cleaning Staff Leaderboard",
+	"export.scorecard.rank":               "Rank",
+	"export.scorecard.staff":              "Cleaning Staff",
+	"export.scorecard.completed":          "Jobs Completed",
+	"export.scorecard.assigned":           "Assignments",
+	"export.scorecard.on_time_rate":       "On Time (%)",
+	"export.scorecard.rejected":           "Rejected",
+	"export.scorecard.comment_turnaround": "Comment Turnaround (minutes)",
+
+	"email.footer":                  "This email was generated automatically. Please do not reply to this email",
+	"email.forgot_password.subject": "Forgot Password for CleanCare",
+	"email.forgot_password.body":    "%s, we received a request for forgetting your password, please click the link below to reset your password.",
+	"email.forgot_password.button":  "Click to link",
+	"email.forgot_password.ignore":  "If you did not request a password reset, you can safely ignore this email. Only a person with access to your email can reset your account password.",
+	"email.forgot_password.expire":  "This link is valid for %d minutes and can only be used once.",
+
+	"webview.reset_password.title":            "Reset Password",
+	"webview.reset_password.new_password":     "New Password",
+	"webview.reset_password.confirm_password": "Confirm Password",
+	"webview.reset_password.min_length":       "At least %d characters",
+	"webview.reset_password.submit":           "Save Password",
+
+	"email.verify_email.subject": "Verify your CleanCare email",
+	"email.verify_email.body":    "%s, please click the link below to verify your email address for CleanCare.",
+	"email.verify_email.button":  "Verify email",
+	"email.verify_email.expire":  "This link is valid for %d hours.",
+	"email.verify_email.ignore":  "If you did not register or change your email on CleanCare, you can safely ignore this email.",
+
+	"webview.verify_email.success": "Successfully Verified Email",
+	"webview.verify_email.failed":  "Failed Verifying Email Because",
+
+	"email.incident_reported.subject": "New CleanCare Incident Report",
+	"email.incident_reported.body":    "%s, %s reported a new incident that needs a follow up.",
+	"email.incident_assigned.subject": "CleanCare Incident Assignment",
+	"email.incident_assigned.body":    "%s, you are assigned to handle the following incident.",
+	"email.incident.category":         "Category",
+	"email.incident.severity":         "Severity",
+	"email.incident.floor":            "Floor",
+	"email.incident.location":         "Location",
+	"email.incident.description":      "Description",
+
+	"incident.category.fixture":  "Broken Fixture",
+	"incident.category.leak":     "Leak",
+	"incident.category.supply":   "Supply Shortage",
+	"incident.category.safety":   "Safety",
+	"incident.category.other":    "Other",
+	"incident.severity.low":      "Low",
+	"incident.severity.medium":   "Medium",
+	"incident.severity.high":     "High",
+	"incident.severity.critical": "Critical",
+
+	"email.inventory_low_stock.subject":   "CleanCare Supplies Running Low",
+	"email.inventory_low_stock.body":      "%s, the stock of %s at %s is below its minimum.",
+	"email.inventory_low_stock.stock":     "Current stock",
+	"email.inventory_low_stock.min_stock": "Minimum stock",
+
+	"email.sla_escalation.subject": "CleanCare Work SLA Escalation",
+	"email.sla_escalation.body":    "%s, a work is now %s.",
+	"email.sla_escalation.task":    "Task",
+	"email.sla_escalation.staff":   "Staff",
+	"email.sla_escalation.floor":   "Floor",
+	"email.sla_escalation.due":     "Due",
+
+	"sla.status.on_track": "on track",
+	"sla.status.at_risk":  "at risk",
+	"sla.status.breached": "breached",
+	"sla.status.met":      "met",
+
+	"export.inventory.title":     "CleanCare - Supplies Consumption Report",
+	"export.inventory.no":        "No",
+	"export.inventory.item":      "Item",
+	"export.inventory.unit":      "Unit",
+	"export.inventory.stock_in":  "Stock In",
+	"export.inventory.stock_out": "Consumed",
+	"export.inventory.adjusted":  "Adjusted",
+	"export.inventory.stock":     "Current Stock",
+}
+
+// messagesID translates the English API messages used across the services.
+var messagesID = map[string]string{
+	"success create!":                                "berhasil dibuat!",
+	"success update!":                                "berhasil diperbarui!",
+	"success delete!":                                "berhasil dihapus!",
+	"success logout!":                                "berhasil keluar!",
+	"success register!":                              "berhasil mendaftar!",
+	"success change password!":                       "berhasil mengubah kata sandi!",
+	"success send email forgot password!":            "berhasil mengirim email lupa kata sandi!",
+	"error bind payload":                             "gagal membaca payload",
+	"error validate payload":                         "payload tidak valid",
+	"error bind multipart/form-data":                 "gagal membaca multipart/form-data",
+	"not found":                                      "tidak ditemukan",
+	"internal server error":                          "terjadi kesalahan pada server",
+	"this role is not permitted":                     "peran ini tidak diizinkan",
+	"this user is not permitted":                     "pengguna ini tidak diizinkan",
+	"user not found":                                 "pengguna tidak ditemukan",
+	"profile not found":                              "foto profil tidak ditemukan",
+	"work not found":                                 "pekerjaan tidak ditemukan",
+	"task not found":                                 "pekerjaan tidak ditemukan",
+	"task id not found":                              "pekerjaan tidak ditemukan",
+	"task type not found":                            "jenis pekerjaan tidak ditemukan",
+	"task type id not found":                         "jenis pekerjaan tidak ditemukan",
+	"role not found":                                 "jabatan tidak ditemukan",
+	"assignment not found":                           "penugasan tidak ditemukan",
+	"assignment already done":                        "penugasan sudah dikerjakan",
+	"work is not done yet":                           "pekerjaan belum selesai",
+	"cursor is invalid":                              "cursor tidak valid",
+	"comment not found":                              "komentar tidak ditemukan",
+	"file not found":                                 "file tidak ditemukan",
+	"image_before not found":                         "foto sebelum tidak ditemukan",
+	"image_after not found":                          "foto sesudah tidak ditemukan",
+	"email not found":                                "email tidak ditemukan",
+	"email already exist":                            "email sudah digunakan",
+	"number id already exist":                        "nomor ID sudah digunakan",
+	"number id or password is incorrect":             "nomor ID atau kata sandi salah",
+	"wrong id number":                                "nomor ID salah",
+	"user already registered":                        "pengguna sudah terdaftar",
+	"old password is wrong":                          "kata sandi lama salah",
+	"too many attempts, please try again in 4 hours": "terlalu banyak percobaan, silakan coba lagi dalam 4 jam",
+	"the new password cannot be the same as the old password":        "kata sandi baru tidak boleh sama dengan kata sandi lama",
+	"account is locked. please contact admin to unlock your account": "akun terkunci. silakan hubungi admin untuk membuka akun Anda",
+	"your token is invalid":                                        "token Anda tidak valid",
+	"your token has expired":                                       "token Anda sudah kedaluwarsa",
+	"password must contain an uppercase letter":                    "kata sandi harus mengandung huruf besar",
+	"password must contain a lowercase letter":                     "kata sandi harus mengandung huruf kecil",
+	"password must contain a number":                               "kata sandi harus mengandung angka",
+	"password does not meet the policy":                            "kata sandi tidak memenuhi ketentuan",
+	"password is too short":                                        "kata sandi terlalu pendek",
+	"password must contain a symbol":                               "kata sandi harus mengandung simbol",
+	"password is too common":                                       "kata sandi terlalu umum dan mudah ditebak",
+	"password was used recently":                                   "kata sandi sudah pernah digunakan sebelumnya",
+	"success verify email!":                                        "berhasil memverifikasi email!",
+	"success send email verification!":                             "berhasil mengirim email verifikasi!",
+	"email is not verified":                                        "email belum diverifikasi",
+	"email is not valid":                                           "email tidak valid",
+	"email is already verified":                                    "email sudah diverifikasi",
+	"only dead emails can be retried":                              "hanya email yang gagal terkirim yang dapat dikirim ulang",
+	"password confirmation does not match":                         "konfirmasi kata sandi tidak sesuai",
+	"audit log not found":                                          "log audit tidak ditemukan",
+	"success restore!":                                             "berhasil dipulihkan!",
+	"deleted record not found":                                     "data terhapus tidak ditemukan",
+	"user still has related data":                                  "pengguna masih memiliki data terkait",
+	"task type is still used":                                      "jenis pekerjaan masih digunakan",
+	"success import!":                                              "berhasil diimpor!",
+	"import file is not valid":                                     "file impor tidak valid",
+	"import file is empty":                                         "file impor kosong",
+	"import file must be xlsx or csv":                              "file impor harus berformat xlsx atau csv",
+	"import file has missing columns":                              "kolom pada file impor tidak lengkap",
+	"import file has too many rows":                                "jumlah baris pada file impor terlalu banyak",
+	"import file has invalid rows":                                 "terdapat baris yang tidak valid pada file impor",
+	"number id is required":                                        "nomor ID wajib diisi",
+	"number id is duplicated in the file":                          "nomor ID ganda di dalam file",
+	"name is required":                                             "nama wajib diisi",
+	"role is required":                                             "jabatan wajib diisi",
+	"floor is required":                                            "penempatan wajib diisi",
+	"floor is too long":                                            "penempatan terlalu panjang",
+	"invalid idempotency key":                                      "kunci idempotensi tidak valid",
+	"a request with the same idempotency key is still in progress": "permintaan dengan kunci idempotensi yang sama masih diproses",
+	"invalid sync cursor":                                          "kursor sinkronisasi tidak valid",
+	"work was deleted on the server":                               "pekerjaan sudah dihapus di server",
+	"work was changed on the server":                               "pekerjaan sudah diubah di server",
+	"file is too large":                                            "ukuran file terlalu besar",
+	"upload not found":                                             "unggahan tidak ditemukan",
+	"upload is already completed":                                  "unggahan sudah selesai",
+	"upload offset does not match":                                 "posisi unggahan tidak sesuai",
+	"chunk is empty":                                               "potongan file kosong",
+	"chunk is too large":                                           "potongan file terlalu besar",
+	"chunk exceeds the upload size":                                "potongan file melebihi ukuran unggahan",
+	"upload is not complete yet":                                   "unggahan belum selesai",
+	"upload checksum does not match":                               "checksum unggahan tidak sesuai",
+	"upload is already used":                                       "unggahan sudah digunakan",
+	"photo limit of the phase is reached":                          "batas jumlah foto untuk tahap ini sudah tercapai",
+	"invalid taken_at":                                             "taken_at tidak valid",
+	"photo is required":                                            "foto wajib diisi",
+	"photo ids do not match the photos of the phase":               "id foto tidak sesuai dengan foto pada tahap ini",
+	"photo not found":                                              "foto tidak ditemukan",
+	"photo flag not found":                                         "tanda foto tidak ditemukan",
+	"incident not found":                                           "insiden tidak ditemukan",
+	"incident status cannot be changed":                            "status insiden tidak dapat diubah",
+	"photo limit of the incident is reached":                       "batas jumlah foto untuk insiden ini sudah tercapai",
+	"inventory item not found":                                     "perlengkapan tidak ditemukan",
+	"inventory location not found":                                 "lokasi penyimpanan tidak ditemukan",
+	"item is still in stock":                                       "perlengkapan masih memiliki stok",
+	"location still holds stock":                                   "lokasi penyimpanan masih menyimpan stok",
+	"quantity must be more than 0":                                 "jumlah harus lebih dari 0",
+	"stock is not enough":                                          "stok tidak mencukupi",
+	"checklist item not found":                                     "item checklist tidak ditemukan",
+	"required checklist items are not done":                        "item checklist wajib belum selesai",
+	"task is still used":                                           "pekerjaan masih digunakan",
+	"image_before is required for this task":                       "foto sebelum wajib untuk pekerjaan ini",
+	"image_after is required for this task":                        "foto sesudah wajib untuk pekerjaan ini",
+	"sla policy not found":                                         "kebijakan SLA tidak ditemukan",
+	"sla policy already exists":                                    "kebijakan SLA sudah ada",
+	"sla policy needs a start or complete time":                    "kebijakan SLA membutuhkan waktu mulai atau selesai",
+	"start time of the sla policy is after its complete time":      "waktu mulai kebijakan SLA melebihi waktu selesainya",
+	"area not found":                                               "area tidak ditemukan",
+	"only one photo can be sent":                                   "hanya satu foto yang dapat dikirim",
+	"service request not found":                                    "permintaan layanan tidak ditemukan",
+	"service request is already triaged":                           "permintaan layanan sudah ditindaklanjuti",
+	"area has no recently completed work":                          "belum ada pekerjaan yang baru selesai di area ini",
+	"work does not match the assignment":                           "pekerjaan tidak sesuai dengan penugasan",
+}
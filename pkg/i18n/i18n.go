@@ -0,0 +1,141 @@
+package i18n
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+)
+
+const (
+	LANG_ID = "id"
+	LANG_EN = "en"
+)
+
+// Default is the language of the labels, exports and emails when the request and the user
+// give no preference.
+var Default = LANG_ID
+
+// MessageDefault is the language of the API messages when the request and the user give no
+// preference. The messages are written in English, clients matching on them keep working.
+var MessageDefault = LANG_EN
+
+var catalogs = map[string]map[string]string{
+	LANG_ID: labelsID,
+	LANG_EN: labelsEN,
+}
+
+// Normalize maps values like "en-US" or "ID" to a supported language code,
+// returning an empty string when the language is not supported.
+func Normalize(lang string) string {
+	lang = strings.ToLower(strings.TrimSpace(lang))
+	if i := strings.IndexAny(lang, "-_"); i > 0 {
+		lang = lang[:i]
+	}
+	if _, ok := catalogs[lang]; ok {
+		return lang
+	}
+	return ""
+}
+
+// ParseAcceptLanguage returns the supported language with the highest
+// quality in an Accept-Language header, or an empty string.
+func ParseAcceptLanguage(header string) string {
+	var (
+		best  string
+		bestQ = -1.0
+	)
+	for _, part := range strings.Split(header, ",") {
+		fields := strings.Split(strings.TrimSpace(part), ";")
+		lang := Normalize(fields[0])
+		if lang == "" {
+			continue
+		}
+		q := 1.0
+		for _, f := range fields[1:] {
+			f = strings.TrimSpace(f)
+			if strings.HasPrefix(f, "q=") {
+				fmt.Sscanf(strings.TrimPrefix(f, "q="), "%g", &q)
+			}
+		}
+		if q > bestQ {
+			best, bestQ = lang, q
+		}
+	}
+	return best
+}
+
+// FromRequest resolves the language of a request from the lang query param,
+// then the Accept-Language header, then falls back to Default.
+func FromRequest(r *http.Request) string {
+	return FromRequestOr(r, Default)
+}
+
+// FromRequestOr is FromRequest with its own fallback.
+func FromRequestOr(r *http.Request, fallback string) string {
+	if r == nil {
+		return fallback
+	}
+	if lang := Normalize(r.URL.Query().Get("lang")); lang != "" {
+		return lang
+	}
+	if lang := ParseAcceptLanguage(r.Header.Get("Accept-Language")); lang != "" {
+		return lang
+	}
+	return fallback
+}
+
+// Resolve picks the stored user preference when there is one, otherwise the given fallback.
+func Resolve(preference *string, fallback string) string {
+	if preference != nil {
+		if lang := Normalize(*preference); lang != "" {
+			return lang
+		}
+	}
+	if lang := Normalize(fallback); lang != "" {
+		return lang
+	}
+	return Default
+}
+
+// T returns the label for key in lang, formatted with args when given.
+func T(lang, key string, args ...interface{}) string {
+	text, ok := catalogs[Normalize(lang)][key]
+	if !ok {
+		if text, ok = catalogs[Default][key]; !ok {
+			text = key
+		}
+	}
+	if len(args) > 0 {
+		return fmt.Sprintf(text, args...)
+	}
+	return text
+}
+
+// Message translates an API message. Messages are written in English in the
+// code, so they are returned as is unless a translation exists for lang.
+func Message(lang, msg string) string {
+	if Normalize(lang) == LANG_EN {
+		return msg
+	}
+	if text, ok := messagesID[msg]; ok {
+		return text
+	}
+	return msg
+}
+
+func FormatDate(lang string, t time.Time) string {
+	return fmt.Sprintf("%s, %d %s %d", dayName(lang, t), t.Day(), monthName(lang, t), t.Year())
+}
+
+func FormatDateTime(lang string, t time.Time) string {
+	return fmt.Sprintf("%s, %d %s %d %02d:%02d", dayName(lang, t), t.Day(), monthName(lang, t), t.Year(), t.Hour(), t.Minute())
+}
+
+func dayName(lang string, t time.Time) string {
+	return T(lang, fmt.Sprintf("day.%d", int(t.Weekday())))
+}
+
+func monthName(lang string, t time.Time) string {
+	return T(lang, fmt.Sprintf("month.%d", int(t.Month())))
+}
@@ -0,0 +1,122 @@
+package password
+
+import (
+	"bufio"
+	"cleancare/internal/config"
+	"cleancare/pkg/util/response"
+	_ "embed"
+	"strings"
+	"unicode"
+
+	"golang.org/x/crypto/bcrypt"
+)
+
+//go:embed common_passwords.txt
+var commonPasswordsFile string
+
+var commonPasswords = parseCommonPasswords(commonPasswordsFile)
+
+type Policy struct {
+	MinLength     int
+	RequireUpper  bool
+	RequireLower  bool
+	RequireNumber bool
+	RequireSymbol bool
+	History       int
+}
+
+// CurrentPolicy returns the policy configured through the PASSWORD_* env.
+func CurrentPolicy() Policy {
+	cfg := config.Get().Password
+	return Policy{
+		MinLength:     cfg.MinLength,
+		RequireUpper:  cfg.RequireUpper,
+		RequireLower:  cfg.RequireLower,
+		RequireNumber: cfg.RequireNumber,
+		RequireSymbol: cfg.RequireSymbol,
+		History:       cfg.History,
+	}
+}
+
+// Validate checks password against the policy, usedHashes are the bcrypt hashes of the
+// passwords the user had before and may be nil for a user without history.
+// Every failed rule is returned so the frontend can show them at once.
+func (p Policy) Validate(field, password string, usedHashes []string) []response.FieldError {
+	var fields []response.FieldError
+	add := func(code, message string, params map[string]interface{}) {
+		fields = append(fields, response.FieldError{
+			Field:   field,
+			Code:    code,
+			Message: message,
+			Params:  params,
+		})
+	}
+
+	if len([]rune(password)) < p.MinLength {
+		add("min_length", "password is too short", map[string]interface{}{"min": p.MinLength})
+	}
+
+	var hasUpper, hasLower, hasNumber, hasSymbol bool
+	for _, r := range password {
+		switch {
+		case unicode.IsUpper(r):
+			hasUpper = true
+		case unicode.IsLower(r):
+			hasLower = true
+		case unicode.IsDigit(r):
+			hasNumber = true
+		case unicode.IsPunct(r) || unicode.IsSymbol(r):
+			hasSymbol = true
+		}
+	}
+	if p.RequireUpper && !hasUpper {
+		add("uppercase", "password must contain an uppercase letter", nil)
+	}
+	if p.RequireLower && !hasLower {
+		add("lowercase", "password must contain a lowercase letter", nil)
+	}
+	if p.RequireNumber && !hasNumber {
+		add("number", "password must contain a number", nil)
+	}
+	if p.RequireSymbol && !hasSymbol {
+		add("symbol", "password must contain a symbol", nil)
+	}
+
+	if IsCommon(password) {
+		add("common", "password is too common", nil)
+	}
+
+	// the hashes are only compared when the rest passed, bcrypt is slow on purpose
+	if len(fields) == 0 && p.History > 0 {
+		for i, hash := range usedHashes {
+			if i >= p.History {
+				break
+			}
+			if bcrypt.CompareHashAndPassword([]byte(hash), []byte(password)) == nil {
+				add("reused", "password was used recently", map[string]interface{}{"history": p.History})
+				break
+			}
+		}
+	}
+
+	return fields
+}
+
+// IsCommon reports whether password is on the bundled list of common passwords.
+func IsCommon(password string) bool {
+	_, ok := commonPasswords[strings.ToLower(password)]
+	return ok
+}
+
+func parseCommonPasswords(file string) map[string]struct{} {
+	res := make(map[string]struct{})
+	scanner := bufio.NewScanner(strings.NewReader(file))
+	for scanner.Scan() {
+		line := strings.ToLower(strings.TrimSpace(scanner.Text()))
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		res[line] = struct{}{}
+	}
+	return res
+}
@@ -0,0 +1,115 @@
+package password
+
+import (
+	"testing"
+
+	"golang.org/x/crypto/bcrypt"
+)
+
+func codes(t *testing.T, p Policy, password string, usedHashes []string) []string {
+	t.Helper()
+	var res []string
+	for _, v := range p.Validate("password", password, usedHashes) {
+		if v.Field != "password" {
+			t.Fatalf("field = %q, want password", v.Field)
+		}
+		res = append(res, v.Code)
+	}
+	return res
+}
+
+func hash(t *testing.T, password string) string {
+	t.Helper()
+	h, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.MinCost)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(h)
+}
+
+func TestValidate(t *testing.T) {
+	strict := Policy{MinLength: 8, RequireUpper: true, RequireLower: true, RequireNumber: true, RequireSymbol: true}
+
+	tests := []struct {
+		name     string
+		policy   Policy
+		password string
+		want     []string
+	}{
+		{"passes every rule", strict, "Kebersihan#2024", nil},
+		{"too short", strict, "Ab1#", []string{"min_length"}},
+		{"length counts runes", Policy{MinLength: 4}, "ÄÖÜß", nil},
+		{"missing classes", strict, "kebersihanku", []string{"uppercase", "number", "symbol"}},
+		{"only digits", strict, "83927461", []string{"uppercase", "lowercase", "symbol"}},
+		{"rules off", Policy{}, "x", nil},
+		{"common regardless of case", Policy{MinLength: 8}, "PassWord123", []string{"common"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := codes(t, tt.policy, tt.password, nil)
+			if len(got) != len(tt.want) {
+				t.Fatalf("codes = %v, want %v", got, tt.want)
+			}
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Fatalf("codes = %v, want %v", got, tt.want)
+				}
+			}
+		})
+	}
+}
+
+func TestValidateHistory(t *testing.T) {
+	used := []string{hash(t, "Newest#Pass1"), hash(t, "Middle#Pass2"), hash(t, "Oldest#Pass3")}
+
+	tests := []struct {
+		name     string
+		history  int
+		password string
+		reused   bool
+	}{
+		{"newest is reused", 3, "Newest#Pass1", true},
+		{"oldest within history", 3, "Oldest#Pass3", true},
+		{"older than history", 2, "Oldest#Pass3", false},
+		{"history turned off", 0, "Newest#Pass1", false},
+		{"new password", 3, "Another#Pass4", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := codes(t, Policy{History: tt.history}, tt.password, used)
+			reused := len(got) == 1 && got[0] == "reused"
+			if reused != tt.reused || (!tt.reused && len(got) > 0) {
+				t.Fatalf("codes = %v, want reused %v", got, tt.reused)
+			}
+		})
+	}
+}
+
+func TestValidateSkipsHistoryWhenRulesFail(t *testing.T) {
+	used := []string{hash(t, "short")}
+	got := codes(t, Policy{MinLength: 8, History: 5}, "short", used)
+	if len(got) != 1 || got[0] != "min_length" {
+		t.Fatalf("codes = %v, want [min_length]", got)
+	}
+}
+
+func TestParseCommonPasswords(t *testing.T) {
+	got := parseCommonPasswords("# comment\n\n  Secret  \nqwerty\n")
+	if len(got) != 2 {
+		t.Fatalf("len = %d, want 2", len(got))
+	}
+	for _, v := range []string{"secret", "qwerty"} {
+		if _, ok := got[v]; !ok {
+			t.Fatalf("%q is missing", v)
+		}
+	}
+}
+
+func TestIsCommon(t *testing.T) {
+	if !IsCommon("QWERTY123") {
+		t.Fatal("QWERTY123 should be common")
+	}
+	if IsCommon("Kebersihan#2024") {
+		t.Fatal("Kebersihan#2024 should not be common")
+	}
+}
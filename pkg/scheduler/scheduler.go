@@ -0,0 +1,37 @@
+package scheduler
+
+import (
+	"context"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// Every runs job in its own goroutine each interval until ctx is done. A panicking job
+// is logged and does not stop the next run.
+func Every(ctx context.Context, name string, interval time.Duration, job func(ctx context.Context)) {
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		logrus.Infof("scheduler %s started, every %s", name, interval)
+		for {
+			run(ctx, name, job)
+			select {
+			case <-ctx.Done():
+				logrus.Infof("scheduler %s stopped", name)
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
+
+func run(ctx context.Context, name string, job func(ctx context.Context)) {
+	defer func() {
+		if r := recover(); r != nil {
+			logrus.Errorf("scheduler %s panic: %v", name, r)
+		}
+	}()
+	job(ctx)
+}
@@ -0,0 +1,148 @@
+package export
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+const (
+	FORMAT_PDF    = "pdf"
+	FORMAT_EXCEL  = "excel"
+	FORMAT_CSV    = "csv"
+	FORMAT_JSON   = "json"
+	FORMAT_NDJSON = "ndjson"
+)
+
+// IsDataFormat reports whether the format is one of the machine readable
+// formats (csv, json, ndjson) that support column selection.
+func IsDataFormat(format string) bool {
+	switch format {
+	case FORMAT_CSV, FORMAT_JSON, FORMAT_NDJSON:
+		return true
+	}
+	return false
+}
+
+// SelectColumns picks and orders columns from the comma separated param.
+// An empty param returns every available column in its default order.
+func SelectColumns(available []string, param string) ([]string, error) {
+	if strings.TrimSpace(param) == "" {
+		return available, nil
+	}
+	var selected []string
+	for _, v := range strings.Split(param, ",") {
+		col := strings.ToLower(strings.TrimSpace(v))
+		if col == "" {
+			continue
+		}
+		found := false
+		for _, a := range available {
+			if a == col {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return nil, fmt.Errorf("column %s is not available", col)
+		}
+		selected = append(selected, col)
+	}
+	if len(selected) == 0 {
+		return available, nil
+	}
+	return selected, nil
+}
+
+// Write encodes rows into the given data format, keeping only the selected
+// columns in the selected order.
+func Write(format string, columns []string, rows []map[string]interface{}) (*bytes.Buffer, error) {
+	switch format {
+	case FORMAT_CSV:
+		return writeCSV(columns, rows)
+	case FORMAT_JSON:
+		return writeJSON(columns, rows)
+	case FORMAT_NDJSON:
+		return writeNDJSON(columns, rows)
+	}
+	return nil, fmt.Errorf("format %s is not supported", format)
+}
+
+func writeCSV(columns []string, rows []map[string]interface{}) (*bytes.Buffer, error) {
+	var buf bytes.Buffer
+	w := csv.NewWriter(&buf)
+	if err := w.Write(columns); err != nil {
+		return nil, err
+	}
+	for _, row := range rows {
+		record := make([]string, len(columns))
+		for i, col := range columns {
+			if row[col] != nil {
+				record[i] = fmt.Sprint(row[col])
+			}
+		}
+		if err := w.Write(record); err != nil {
+			return nil, err
+		}
+	}
+	w.Flush()
+	if err := w.Error(); err != nil {
+		return nil, err
+	}
+	return &buf, nil
+}
+
+func writeJSON(columns []string, rows []map[string]interface{}) (*bytes.Buffer, error) {
+	data := make([]orderedRow, 0, len(rows))
+	for _, row := range rows {
+		data = append(data, orderedRow{columns: columns, row: row})
+	}
+	var buf bytes.Buffer
+	if err := json.NewEncoder(&buf).Encode(data); err != nil {
+		return nil, err
+	}
+	return &buf, nil
+}
+
+func writeNDJSON(columns []string, rows []map[string]interface{}) (*bytes.Buffer, error) {
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	for _, row := range rows {
+		if err := enc.Encode(orderedRow{columns: columns, row: row}); err != nil {
+			return nil, err
+		}
+	}
+	return &buf, nil
+}
+
+// orderedRow marshals a row as a JSON object keeping the column order,
+// which a plain map would lose.
+type orderedRow struct {
+	columns []string
+	row     map[string]interface{}
+}
+
+func (o orderedRow) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteByte('{')
+	for i, col := range o.columns {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		key, err := json.Marshal(col)
+		if err != nil {
+			return nil, err
+		}
+		val, err := json.Marshal(o.row[col])
+		if err != nil {
+			return nil, err
+		}
+		buf.Write(key)
+		buf.WriteByte(':')
+		buf.Write(val)
+	}
+	buf.WriteByte('}')
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,174 @@
+package general
+
+import (
+	"cleancare/internal/abstraction"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"math"
+	"strconv"
+	"time"
+)
+
+const (
+	CURSOR_DEFAULT_LIMIT = 10
+	CURSOR_MAX_LIMIT     = 100
+)
+
+// Cursor points at the row a page starts after, Prev marks a cursor that pages backwards.
+type Cursor struct {
+	CreatedAt string `json:"c,omitempty"`
+	ID        int    `json:"i"`
+	Prev      bool   `json:"p,omitempty"`
+}
+
+// CursorPaging is used by list endpoints when the cursor query param is sent,
+// an empty cursor returns the first page.
+type CursorPaging struct {
+	Limit       int
+	Desc        bool
+	ByCreatedAt bool
+	Cursor      *Cursor
+}
+
+// ProcessCursor returns nil when the request uses offset paging.
+func ProcessCursor(ctx *abstraction.Context, byCreatedAt bool) (*CursorPaging, error) {
+	if !ctx.QueryParams().Has("cursor") {
+		return nil, nil
+	}
+
+	paging := &CursorPaging{
+		Limit:       CURSOR_DEFAULT_LIMIT,
+		Desc:        ctx.QueryParam("order_by") == "" || ValidationOrderBy(ctx.QueryParam("order_by")) == "DESC",
+		ByCreatedAt: byCreatedAt,
+	}
+	if ctx.QueryParam("limit") != "" {
+		limit, _ := strconv.Atoi(SanitizeStringOfNumber(ctx.QueryParam("limit")))
+		if limit > 0 {
+			paging.Limit = min(limit, CURSOR_MAX_LIMIT)
+		}
+	}
+	if val := ctx.QueryParam("cursor"); val != "" {
+		cursor, err := DecodeCursor(val)
+		if err != nil {
+			return nil, err
+		}
+		if byCreatedAt && cursor.CreatedAt == "" {
+			return nil, errors.New("cursor is invalid")
+		}
+		paging.Cursor = cursor
+	}
+	return paging, nil
+}
+
+func EncodeCursor(cursor Cursor) string {
+	val, _ := json.Marshal(cursor)
+	return base64.RawURLEncoding.EncodeToString(val)
+}
+
+func DecodeCursor(val string) (*Cursor, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(val)
+	if err != nil {
+		return nil, errors.New("cursor is invalid")
+	}
+	cursor := new(Cursor)
+	if err = json.Unmarshal(raw, cursor); err != nil || cursor.ID == 0 {
+		return nil, errors.New("cursor is invalid")
+	}
+	if cursor.CreatedAt != "" {
+		if _, err = time.Parse("2006-01-02 15:04:05", cursor.CreatedAt); err != nil {
+			return nil, errors.New("cursor is invalid")
+		}
+	}
+	return cursor, nil
+}
+
+// Apply adds the keyset condition of the page to where and returns the order to query with.
+// Rows are fetched in the direction of the cursor, so a previous page has to be reversed
+// with Reverse after the query.
+func (p *CursorPaging) Apply(where string, whereParam map[string]interface{}) (string, string) {
+	forward := p.Cursor == nil || !p.Cursor.Prev
+	cmp, dir := ">", "ASC"
+	if p.Desc == forward {
+		cmp, dir = "<", "DESC"
+	}
+
+	if p.Cursor != nil {
+		if p.ByCreatedAt {
+			where += " AND (created_at " + cmp + " @cursor_created_at OR (created_at = @cursor_created_at AND id " + cmp + " @cursor_id))"
+			whereParam["cursor_created_at"] = p.Cursor.CreatedAt
+		} else {
+			where += " AND id " + cmp + " @cursor_id"
+		}
+		whereParam["cursor_id"] = p.Cursor.ID
+	}
+
+	if p.ByCreatedAt {
+		return where, "created_at " + dir + ", id " + dir
+	}
+	return where, "id " + dir
+}
+
+// Reverse tells whether the fetched rows are in the opposite order of the list.
+func (p *CursorPaging) Reverse() bool {
+	return p.Cursor != nil && p.Cursor.Prev
+}
+
+// Meta builds the meta block of a cursor page, first and last are the keys of the
+// first and last row of the page as returned to the client.
+func (p *CursorPaging) Meta(hasMore bool, first, last *Cursor, total *int) map[string]interface{} {
+	var nextCursor, prevCursor interface{}
+	hasNext := hasMore
+	hasPrev := p.Cursor != nil
+	if p.Reverse() {
+		hasNext, hasPrev = true, hasMore
+	}
+	if hasNext && last != nil {
+		nextCursor = EncodeCursor(Cursor{CreatedAt: last.CreatedAt, ID: last.ID})
+	}
+	if hasPrev && first != nil {
+		prevCursor = EncodeCursor(Cursor{CreatedAt: first.CreatedAt, ID: first.ID, Prev: true})
+	}
+
+	meta := map[string]interface{}{
+		"limit":       p.Limit,
+		"has_more":    hasMore,
+		"next_cursor": nextCursor,
+		"prev_cursor": prevCursor,
+	}
+	if total != nil {
+		meta["total"] = *total
+	}
+	return meta
+}
+
+func NewCursor(createdAt *time.Time, id int) *Cursor {
+	cursor := &Cursor{ID: id}
+	if createdAt != nil {
+		cursor.CreatedAt = createdAt.Format("2006-01-02 15:04:05")
+	}
+	return cursor
+}
+
+// OffsetMeta builds the meta block of an offset page.
+func OffsetMeta(ctx *abstraction.Context, no_paging bool, length int, total *int) map[string]interface{} {
+	limit, offset := ProcessLimitOffset(ctx, no_paging)
+	meta := map[string]interface{}{
+		"limit":    limit,
+		"offset":   offset,
+		"has_more": false,
+		"total":    nil,
+	}
+	if total != nil {
+		meta["has_more"] = offset+length < *total
+		meta["total"] = *total
+	}
+	if no_paging || limit == math.MaxInt64 {
+		meta["limit"] = nil
+	}
+	return meta
+}
+
+func IncludeTotal(ctx *abstraction.Context) bool {
+	return ctx.QueryParam("include_total") == "yes"
+}
@@ -4,7 +4,9 @@ import (
 	"bytes"
 	"cleancare/internal/abstraction"
 	"cleancare/pkg/constant"
+	"cleancare/pkg/i18n"
 	"context"
+	"encoding/json"
 	"fmt"
 	"io"
 	"io/ioutil"
@@ -141,51 +143,6 @@ func StringInSlice(text string, data []string) bool {
 	return false
 }
 
-// generate random password
-func GeneratePassword(passwordLength, minSpecialChar, minNum, minUpperCase, minLowerCase int) string {
-	var password strings.Builder
-	var lowerCharSet string = "abcdedfghijklmnopqrstuvwxyz"
-	var upperCharSet string = "ABCDEFGHIJKLMNOPQRSTUVWXYZ"
-	var specialCharSet string = "!@#$%&*"
-	var numberSet string = "0123456789"
-	var allCharSet string = lowerCharSet + upperCharSet + specialCharSet + numberSet
-
-	//Set special character
-	for i := 0; i < minSpecialChar; i++ {
-		random := rand.Intn(len(specialCharSet))
-		password.WriteString(string(specialCharSet[random]))
-	}
-
-	//Set numeric
-	for i := 0; i < minNum; i++ {
-		random := rand.Intn(len(numberSet))
-		password.WriteString(string(numberSet[random]))
-	}
-
-	//Set uppercase
-	for i := 0; i < minUpperCase; i++ {
-		random := rand.Intn(len(upperCharSet))
-		password.WriteString(string(upperCharSet[random]))
-	}
-
-	//Set lowercase
-	for i := 0; i < minLowerCase; i++ {
-		random := rand.Intn(len(lowerCharSet))
-		password.WriteString(string(lowerCharSet[random]))
-	}
-
-	remainingLength := passwordLength - minSpecialChar - minNum - minUpperCase - minLowerCase
-	for i := 0; i < remainingLength; i++ {
-		random := rand.Intn(len(allCharSet))
-		password.WriteString(string(allCharSet[random]))
-	}
-	inRune := []rune(password.String())
-	rand.Shuffle(len(inRune), func(i, j int) {
-		inRune[i], inRune[j] = inRune[j], inRune[i]
-	})
-	return string(inRune)
-}
-
 func SanitizeStringOfAlphabet(input string) string {
 	// Menghapus karakter yang bukan huruf, underscore
 	return strings.Map(func(r rune) rune {
@@ -284,6 +241,44 @@ func ProcessWhereParam(ctx *abstraction.Context, searchType string, whereStr str
 			where += " AND (LOWER(floor) LIKE @search_floor OR LOWER(info) LIKE @search_info)"
 			whereParam["search_floor"] = val
 			whereParam["search_info"] = val
+		case "assignment":
+			where += " AND (LOWER(floor) LIKE @search_floor OR LOWER(info) LIKE @search_info)"
+			whereParam["search_floor"] = val
+			whereParam["search_info"] = val
+		case "inventory_item":
+			where += " AND (LOWER(name) LIKE @search_name)"
+			whereParam["search_name"] = val
+		case "inventory_location":
+			where += " AND (LOWER(name) LIKE @search_name OR LOWER(floor) LIKE @search_floor)"
+			whereParam["search_name"] = val
+			whereParam["search_floor"] = val
+		case "incident":
+			where += " AND (LOWER(floor) LIKE @search_floor OR LOWER(location) LIKE @search_location OR LOWER(description) LIKE @search_description)"
+			whereParam["search_floor"] = val
+			whereParam["search_location"] = val
+			whereParam["search_description"] = val
+		case "area":
+			where += " AND (LOWER(name) LIKE @search_name OR LOWER(floor) LIKE @search_floor OR LOWER(location) LIKE @search_location)"
+			whereParam["search_name"] = val
+			whereParam["search_floor"] = val
+			whereParam["search_location"] = val
+		case "service_request":
+			where += " AND (LOWER(description) LIKE @search_description OR LOWER(requester_name) LIKE @search_requester_name)"
+			whereParam["search_description"] = val
+			whereParam["search_requester_name"] = val
+		case "feedback":
+			where += " AND (LOWER(floor) LIKE @search_floor OR LOWER(comment) LIKE @search_comment)"
+			whereParam["search_floor"] = val
+			whereParam["search_comment"] = val
+		case "email_outbox":
+			where += " AND (LOWER(recipient) LIKE @search_recipient OR LOWER(subject) LIKE @search_subject)"
+			whereParam["search_recipient"] = val
+			whereParam["search_subject"] = val
+		case "audit_log":
+			where += " AND (LOWER(entity) LIKE @search_entity OR LOWER(action) LIKE @search_action OR LOWER(request_id) LIKE @search_request_id)"
+			whereParam["search_entity"] = val
+			whereParam["search_action"] = val
+			whereParam["search_request_id"] = val
 		}
 	}
 
@@ -364,9 +359,104 @@ func ProcessWhereParam(ctx *abstraction.Context, searchType string, whereStr str
 	}
 	if ctx.QueryParam("not_finished") != "" {
 		if ctx.QueryParam("not_finished") == "yes" {
-			where += " AND image_after IS NULL"
+			switch searchType {
+			case "assignment":
+				where += " AND work_id IS NULL"
+			default:
+				where += " AND image_after IS NULL"
+			}
 		}
 	}
+	if ctx.QueryParam("status") != "" {
+		val := SanitizeString(ctx.QueryParam("status"))
+		where += " AND status = @status"
+		whereParam["status"] = val
+	}
+	if ctx.QueryParam("template") != "" {
+		val := SanitizeString(ctx.QueryParam("template"))
+		where += " AND template = @template"
+		whereParam["template"] = val
+	}
+	if ctx.QueryParam("actor_id") != "" {
+		val, _ := strconv.Atoi(SanitizeStringOfNumber(ctx.QueryParam("actor_id")))
+		where += " AND actor_id = @actor_id"
+		whereParam["actor_id"] = val
+	}
+	if ctx.QueryParam("action") != "" {
+		val := SanitizeString(ctx.QueryParam("action"))
+		where += " AND action = @action"
+		whereParam["action"] = val
+	}
+	if ctx.QueryParam("entity") != "" {
+		val := SanitizeString(ctx.QueryParam("entity"))
+		where += " AND entity = @entity"
+		whereParam["entity"] = val
+	}
+	if ctx.QueryParam("entity_id") != "" {
+		val, _ := strconv.Atoi(SanitizeStringOfNumber(ctx.QueryParam("entity_id")))
+		where += " AND entity_id = @entity_id"
+		whereParam["entity_id"] = val
+	}
+	if ctx.QueryParam("request_id") != "" {
+		val := SanitizeString(ctx.QueryParam("request_id"))
+		where += " AND request_id = @request_id"
+		whereParam["request_id"] = val
+	}
+	if ctx.QueryParam("type") != "" {
+		val := SanitizeString(ctx.QueryParam("type"))
+		where += " AND type = @type"
+		whereParam["type"] = val
+	}
+	if ctx.QueryParam("work_id") != "" {
+		val, _ := strconv.Atoi(SanitizeStringOfNumber(ctx.QueryParam("work_id")))
+		where += " AND work_id = @work_id"
+		whereParam["work_id"] = val
+	}
+	if ctx.QueryParam("category") != "" {
+		val := SanitizeString(ctx.QueryParam("category"))
+		where += " AND category = @category"
+		whereParam["category"] = val
+	}
+	if ctx.QueryParam("severity") != "" {
+		val := SanitizeString(ctx.QueryParam("severity"))
+		where += " AND severity = @severity"
+		whereParam["severity"] = val
+	}
+	if ctx.QueryParam("assignee_id") != "" {
+		val, _ := strconv.Atoi(SanitizeStringOfNumber(ctx.QueryParam("assignee_id")))
+		where += " AND assignee_id = @assignee_id"
+		whereParam["assignee_id"] = val
+	}
+	if ctx.QueryParam("item_id") != "" {
+		val, _ := strconv.Atoi(SanitizeStringOfNumber(ctx.QueryParam("item_id")))
+		where += " AND item_id = @item_id"
+		whereParam["item_id"] = val
+	}
+	if ctx.QueryParam("location_id") != "" {
+		val, _ := strconv.Atoi(SanitizeStringOfNumber(ctx.QueryParam("location_id")))
+		where += " AND location_id = @location_id"
+		whereParam["location_id"] = val
+	}
+	if ctx.QueryParam("area_id") != "" {
+		val, _ := strconv.Atoi(SanitizeStringOfNumber(ctx.QueryParam("area_id")))
+		where += " AND area_id = @area_id"
+		whereParam["area_id"] = val
+	}
+	if ctx.QueryParam("rating") != "" {
+		val, _ := strconv.Atoi(SanitizeStringOfNumber(ctx.QueryParam("rating")))
+		where += " AND rating = @rating"
+		whereParam["rating"] = val
+	}
+	if ctx.QueryParam("verification_status") != "" {
+		val := SanitizeString(ctx.QueryParam("verification_status"))
+		where += " AND verification_status = @verification_status"
+		whereParam["verification_status"] = val
+	}
+	if ctx.QueryParam("sla_status") != "" {
+		val := SanitizeString(ctx.QueryParam("sla_status"))
+		where += " AND sla_status = @sla_status"
+		whereParam["sla_status"] = val
+	}
 
 	return where, whereParam
 }
@@ -413,7 +503,7 @@ func ProcessOrder(ctx *abstraction.Context) string {
 func ValidationOrder(str string) string {
 	str = SanitizeString(str)
 	str = strings.ToLower(str)
-	orderStack := []string{"id", "name", "email", "task_id", "number_id", "role_id", "user_id", "task_type_id", "floor", "info", "created_at", "updated_at"} // fill query order
+	orderStack := []string{"id", "name", "email", "task_id", "number_id", "role_id", "user_id", "task_type_id", "floor", "info", "due_at", "quantity", "next_attempt_at", "deleted_at", "created_at", "updated_at"} // fill query order
 	for _, item := range orderStack {
 		if item == str {
 			return str
@@ -434,8 +524,12 @@ func ValidationOrderBy(str string) string {
 	return "ASC"
 }
 
-func ParseTemplateEmailToHtml(templateFileName string, data interface{}) string {
-	t, err := template.ParseFiles(templateFileName)
+func ParseTemplateEmailToHtml(templateFileName, lang string, data interface{}) string {
+	t, err := template.New(filepath.Base(templateFileName)).Funcs(template.FuncMap{
+		"t": func(key string, args ...interface{}) string {
+			return i18n.T(lang, key, args...)
+		},
+	}).ParseFiles(templateFileName)
 	if err != nil {
 		logrus.Error("Error paring template email: ", err.Error())
 		return ""
@@ -891,40 +985,17 @@ func SplitFileAndNameWithDelimiter(input string) (string, string) {
 	return parts[0], parts[1]
 }
 
-func ConvertDateToIndonesian(dateStr string) string {
-	t, _ := time.Parse("2006-01-02", dateStr)
-	days := []string{
-		"Minggu", "Senin", "Selasa", "Rabu", "Kamis", "Jumat", "Sabtu",
-	}
-	months := []string{
-		"", "Januari", "Februari", "Maret", "April", "Mei", "Juni",
-		"Juli", "Agustus", "September", "Oktober", "November", "Desember",
-	}
-	dayName := days[int(t.Weekday())]
-	monthName := months[int(t.Month())]
-	formatted := fmt.Sprintf("%s, %d %s %d", dayName, t.Day(), monthName, t.Year())
-	return formatted
+func GenerateRedisKeyUnreadComment(commentId int) string {
+	return fmt.Sprintf(constant.REDIS_KEY_UNREAD_COMMENT, commentId)
 }
 
-func ConvertDateTimeToIndonesian(datetimeStr string) string {
-	t, _ := time.Parse("2006-01-02 15:04:05", datetimeStr)
-	days := []string{
-		"Minggu", "Senin", "Selasa", "Rabu", "Kamis", "Jumat", "Sabtu",
-	}
-	months := []string{
-		"", "Januari", "Februari", "Maret", "April", "Mei", "Juni",
-		"Juli", "Agustus", "September", "Oktober", "November", "Desember",
+func SetUserLanguageToRedis(client *redis.Client, userId int, language *string) {
+	key := fmt.Sprintf(constant.REDIS_KEY_USER_LANGUAGE, userId)
+	if language == nil || *language == "" {
+		client.Del(context.Background(), key)
+		return
 	}
-	dayName := days[int(t.Weekday())]
-	monthName := months[int(t.Month())]
-	formatted := fmt.Sprintf("%s, %d %s %d %02d:%02d",
-		dayName, t.Day(), monthName, t.Year(), t.Hour(), t.Minute())
-
-	return formatted
-}
-
-func GenerateRedisKeyUnreadComment(commentId int) string {
-	return fmt.Sprintf(constant.REDIS_KEY_UNREAD_COMMENT, commentId)
+	client.Set(context.Background(), key, *language, 0)
 }
 
 func GetUserIdArrayFromKeyRedis(client *redis.Client, key string) []string {
@@ -962,3 +1033,20 @@ func RemoveUserIdFromKeyRedis(client *redis.Client, key string, userId int) {
 	newVal := strings.Join(filtered, "/")
 	client.Set(ctx, key, newVal, 0)
 }
+
+func GetCacheRedis(client *redis.Client, key string, dest interface{}) bool {
+	val, err := client.Get(context.Background(), key).Result()
+	if err != nil || val == "" {
+		return false
+	}
+	return json.Unmarshal([]byte(val), dest) == nil
+}
+
+func SetCacheRedis(client *redis.Client, key string, value interface{}, expiration time.Duration) {
+	val, err := json.Marshal(value)
+	if err != nil {
+		logrus.Error("error marshal cache redis: ", err.Error())
+		return
+	}
+	client.Set(context.Background(), key, val, expiration)
+}
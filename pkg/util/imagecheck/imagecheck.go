@@ -0,0 +1,217 @@
+package imagecheck
+
+import (
+	"bytes"
+	"encoding/binary"
+	"image"
+	_ "image/gif"
+	_ "image/jpeg"
+	_ "image/png"
+	"math/bits"
+	"strings"
+	"time"
+)
+
+// Result is what can be read from an uploaded photo, both fields are nil when the format is
+// not supported or the photo carries no such data.
+type Result struct {
+	// CapturedAt is the EXIF capture time of a jpeg.
+	CapturedAt *time.Time
+	// Hash is the 64 bit difference hash of the pixels, photos of the same scene are a few
+	// bits apart even after resizing or recompression.
+	Hash *uint64
+}
+
+// MaxPixels is the largest photo that is hashed, decoding holds every pixel in memory and a
+// small file can claim a huge size. Larger photos are only checked for their capture time.
+const MaxPixels = 50 * 1000 * 1000
+
+func Inspect(data []byte) *Result {
+	res := &Result{
+		CapturedAt: captureTime(data),
+	}
+	cfg, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil || cfg.Width <= 0 || cfg.Height <= 0 || cfg.Width*cfg.Height > MaxPixels {
+		return res
+	}
+	if img, _, err := image.Decode(bytes.NewReader(data)); err == nil {
+		hash := dHash(img)
+		res.Hash = &hash
+	}
+	return res
+}
+
+// Distance is the number of differing bits of two hashes, 0 is the same image.
+func Distance(a, b uint64) int {
+	return bits.OnesCount64(a ^ b)
+}
+
+// dHash shrinks the image to 9x8 grey cells and sets a bit for every cell brighter than its
+// right neighbour.
+func dHash(img image.Image) uint64 {
+	const w, h = 9, 8
+	var cells [h][w]float64
+
+	bounds := img.Bounds()
+	dx, dy := bounds.Dx(), bounds.Dy()
+	for cy := 0; cy < h; cy++ {
+		y0, y1 := bounds.Min.Y+cy*dy/h, bounds.Min.Y+(cy+1)*dy/h
+		for cx := 0; cx < w; cx++ {
+			x0, x1 := bounds.Min.X+cx*dx/w, bounds.Min.X+(cx+1)*dx/w
+			// at most 16x16 samples per cell, a full average of a large photo is slow and
+			// changes nothing at this size
+			stepX, stepY := max((x1-x0)/16, 1), max((y1-y0)/16, 1)
+			var sum, n float64
+			for y := y0; y < max(y1, y0+1); y += stepY {
+				for x := x0; x < max(x1, x0+1); x += stepX {
+					r, g, b, _ := img.At(x, y).RGBA()
+					sum += 0.299*float64(r) + 0.587*float64(g) + 0.114*float64(b)
+					n++
+				}
+			}
+			cells[cy][cx] = sum / n
+		}
+	}
+
+	var hash uint64
+	for cy := 0; cy < h; cy++ {
+		for cx := 0; cx < w-1; cx++ {
+			hash <<= 1
+			if cells[cy][cx] > cells[cy][cx+1] {
+				hash |= 1
+			}
+		}
+	}
+	return hash
+}
+
+const (
+	tagDateTime           = 0x0132
+	tagExifIFD            = 0x8769
+	tagDateTimeOriginal   = 0x9003
+	tagDateTimeDigitized  = 0x9004
+	tagOffsetTimeOriginal = 0x9011
+)
+
+// captureTime reads the EXIF APP1 segment of a jpeg, the original capture time is preferred
+// over the digitized and the modified time. A time without offset is read in local time.
+func captureTime(data []byte) *time.Time {
+	if len(data) < 4 || data[0] != 0xFF || data[1] != 0xD8 {
+		return nil
+	}
+	for i := 2; i+4 <= len(data); {
+		if data[i] != 0xFF {
+			return nil
+		}
+		marker := data[i+1]
+		if marker == 0xDA || marker == 0xD9 {
+			return nil
+		}
+		length := int(binary.BigEndian.Uint16(data[i+2:]))
+		if length < 2 || i+2+length > len(data) {
+			return nil
+		}
+		segment := data[i+4 : i+2+length]
+		if marker == 0xE1 && bytes.HasPrefix(segment, []byte("Exif\x00\x00")) {
+			return exifTime(segment[6:])
+		}
+		i += 2 + length
+	}
+	return nil
+}
+
+func exifTime(tiff []byte) *time.Time {
+	if len(tiff) < 8 {
+		return nil
+	}
+	var order binary.ByteOrder
+	switch string(tiff[:2]) {
+	case "II":
+		order = binary.LittleEndian
+	case "MM":
+		order = binary.BigEndian
+	default:
+		return nil
+	}
+	if order.Uint16(tiff[2:]) != 42 {
+		return nil
+	}
+
+	ifd0 := readIFD(tiff, order, order.Uint32(tiff[4:]))
+	values := map[uint16]string{
+		tagDateTime: ifd0.ascii(tagDateTime),
+	}
+	if offset, ok := ifd0.long(tagExifIFD); ok {
+		exif := readIFD(tiff, order, offset)
+		for _, tag := range []uint16{tagDateTimeOriginal, tagDateTimeDigitized, tagOffsetTimeOriginal} {
+			values[tag] = exif.ascii(tag)
+		}
+	}
+
+	for _, tag := range []uint16{tagDateTimeOriginal, tagDateTimeDigitized, tagDateTime} {
+		if values[tag] == "" {
+			continue
+		}
+		loc := time.Local
+		if zone := values[tagOffsetTimeOriginal]; tag == tagDateTimeOriginal && zone != "" {
+			if t, err := time.Parse("-07:00", zone); err == nil {
+				loc = t.Location()
+			}
+		}
+		if t, err := time.ParseInLocation("2006:01:02 15:04:05", values[tag], loc); err == nil {
+			return &t
+		}
+	}
+	return nil
+}
+
+type ifd struct {
+	tiff    []byte
+	order   binary.ByteOrder
+	entries map[uint16][]byte
+}
+
+// readIFD returns the 12 byte entries of the directory at offset by tag.
+func readIFD(tiff []byte, order binary.ByteOrder, offset uint32) *ifd {
+	res := &ifd{tiff: tiff, order: order, entries: map[uint16][]byte{}}
+	if uint64(offset)+2 > uint64(len(tiff)) {
+		return res
+	}
+	count := int(order.Uint16(tiff[offset:]))
+	for i := 0; i < count; i++ {
+		start := int(offset) + 2 + i*12
+		if start+12 > len(tiff) {
+			break
+		}
+		entry := tiff[start : start+12]
+		res.entries[order.Uint16(entry)] = entry
+	}
+	return res
+}
+
+func (d *ifd) long(tag uint16) (uint32, bool) {
+	entry, ok := d.entries[tag]
+	if !ok {
+		return 0, false
+	}
+	return d.order.Uint32(entry[8:]), true
+}
+
+func (d *ifd) ascii(tag uint16) string {
+	entry, ok := d.entries[tag]
+	if !ok || d.order.Uint16(entry[2:]) != 2 {
+		return ""
+	}
+	count := d.order.Uint32(entry[4:])
+	var value []byte
+	if count <= 4 {
+		value = entry[8 : 8+count]
+	} else {
+		offset := d.order.Uint32(entry[8:])
+		if uint64(offset)+uint64(count) > uint64(len(d.tiff)) {
+			return ""
+		}
+		value = d.tiff[offset : offset+count]
+	}
+	return strings.TrimSpace(strings.TrimRight(string(value), "\x00"))
+}
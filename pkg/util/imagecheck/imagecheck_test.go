@@ -0,0 +1,295 @@
+package imagecheck
+
+import (
+	"bytes"
+	"encoding/binary"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"testing"
+	"time"
+)
+
+type asciiTag struct {
+	tag   uint16
+	value string
+}
+
+// buildTiff writes a tiff header with ifd0 and, when exif is not empty, an exif directory
+// pointed to by ifd0. Values longer than 4 bytes go to a data area after the directories.
+func buildTiff(order binary.ByteOrder, ifd0 []asciiTag, exif []asciiTag) []byte {
+	ifd0Count := len(ifd0)
+	if len(exif) > 0 {
+		ifd0Count++
+	}
+	ifd0Offset := 8
+	exifOffset := ifd0Offset + 2 + ifd0Count*12 + 4
+	dataOffset := exifOffset
+	if len(exif) > 0 {
+		dataOffset += 2 + len(exif)*12 + 4
+	}
+
+	var data []byte
+	entry := func(t asciiTag) []byte {
+		b := make([]byte, 12)
+		value := append([]byte(t.value), 0)
+		order.PutUint16(b, t.tag)
+		order.PutUint16(b[2:], 2)
+		order.PutUint32(b[4:], uint32(len(value)))
+		if len(value) <= 4 {
+			copy(b[8:], value)
+		} else {
+			order.PutUint32(b[8:], uint32(dataOffset+len(data)))
+			data = append(data, value...)
+		}
+		return b
+	}
+	directory := func(tags []asciiTag, extra []byte) []byte {
+		count := len(tags)
+		if extra != nil {
+			count++
+		}
+		b := make([]byte, 2)
+		order.PutUint16(b, uint16(count))
+		for _, t := range tags {
+			b = append(b, entry(t)...)
+		}
+		b = append(b, extra...)
+		return append(b, 0, 0, 0, 0)
+	}
+
+	var pointer []byte
+	if len(exif) > 0 {
+		pointer = make([]byte, 12)
+		order.PutUint16(pointer, tagExifIFD)
+		order.PutUint16(pointer[2:], 4)
+		order.PutUint32(pointer[4:], 1)
+		order.PutUint32(pointer[8:], uint32(exifOffset))
+	}
+
+	header := make([]byte, 8)
+	if order == binary.LittleEndian {
+		copy(header, "II")
+	} else {
+		copy(header, "MM")
+	}
+	order.PutUint16(header[2:], 42)
+	order.PutUint32(header[4:], uint32(ifd0Offset))
+
+	res := append(header, directory(ifd0, pointer)...)
+	if len(exif) > 0 {
+		res = append(res, directory(exif, nil)...)
+	}
+	return append(res, data...)
+}
+
+func encodeJpeg(t *testing.T, w, h int) []byte {
+	t.Helper()
+	img := image.NewRGBA(image.Rect(0, 0, w, h))
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			img.Set(x, y, color.RGBA{R: uint8(x * 255 / w), G: uint8(y * 255 / h), B: 128, A: 255})
+		}
+	}
+	var buf bytes.Buffer
+	if err := jpeg.Encode(&buf, img, &jpeg.Options{Quality: 90}); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+// withExif puts an APP1 segment holding tiff right after the start of image marker.
+func withExif(jpg []byte, tiff []byte) []byte {
+	payload := append([]byte("Exif\x00\x00"), tiff...)
+	segment := []byte{0xFF, 0xE1, 0, 0}
+	binary.BigEndian.PutUint16(segment[2:], uint16(len(payload)+2))
+	segment = append(segment, payload...)
+
+	res := append([]byte{}, jpg[:2]...)
+	res = append(res, segment...)
+	return append(res, jpg[2:]...)
+}
+
+func TestCaptureTime(t *testing.T) {
+	jpg := encodeJpeg(t, 32, 24)
+	plus7 := time.FixedZone("", 7*60*60)
+
+	tests := []struct {
+		name string
+		data []byte
+		want *time.Time
+	}{
+		{
+			name: "original time with offset",
+			data: withExif(jpg, buildTiff(binary.LittleEndian,
+				[]asciiTag{{tagDateTime, "2024:05:01 10:00:00"}},
+				[]asciiTag{{tagDateTimeOriginal, "2024:04:30 08:15:30"}, {tagOffsetTimeOriginal, "+07:00"}})),
+			want: ptr(time.Date(2024, 4, 30, 8, 15, 30, 0, plus7)),
+		},
+		{
+			name: "digitized before modified",
+			data: withExif(jpg, buildTiff(binary.BigEndian,
+				[]asciiTag{{tagDateTime, "2024:05:01 10:00:00"}},
+				[]asciiTag{{tagDateTimeDigitized, "2024:04:29 07:00:00"}})),
+			want: ptr(time.Date(2024, 4, 29, 7, 0, 0, 0, time.Local)),
+		},
+		{
+			name: "modified time only",
+			data: withExif(jpg, buildTiff(binary.BigEndian, []asciiTag{{tagDateTime, "2024:05:01 10:00:00"}}, nil)),
+			want: ptr(time.Date(2024, 5, 1, 10, 0, 0, 0, time.Local)),
+		},
+		{
+			name: "offset only applies to the original time",
+			data: withExif(jpg, buildTiff(binary.LittleEndian, nil,
+				[]asciiTag{{tagDateTimeDigitized, "2024:04:29 07:00:00"}, {tagOffsetTimeOriginal, "+07:00"}})),
+			want: ptr(time.Date(2024, 4, 29, 7, 0, 0, 0, time.Local)),
+		},
+		{
+			name: "unreadable time",
+			data: withExif(jpg, buildTiff(binary.LittleEndian, []asciiTag{{tagDateTime, "yesterday"}}, nil)),
+		},
+		{name: "no exif", data: jpg},
+		{name: "not a jpeg", data: []byte("\x89PNG\r\n\x1a\n")},
+		{name: "empty", data: nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := captureTime(tt.data)
+			if (got == nil) != (tt.want == nil) {
+				t.Fatalf("captureTime = %v, want %v", got, tt.want)
+			}
+			if got != nil && !got.Equal(*tt.want) {
+				t.Fatalf("captureTime = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCaptureTimeTruncated(t *testing.T) {
+	tiff := buildTiff(binary.LittleEndian,
+		[]asciiTag{{tagDateTime, "2024:05:01 10:00:00"}},
+		[]asciiTag{{tagDateTimeOriginal, "2024:04:30 08:15:30"}})
+	data := withExif(encodeJpeg(t, 16, 16), tiff)
+	segmentEnd := 2 + 4 + 6 + len(tiff)
+
+	// every cut inside the exif segment makes its length point past the end of the data
+	for n := 0; n < segmentEnd; n++ {
+		if got := captureTime(data[:n]); got != nil {
+			t.Fatalf("captureTime of %d bytes = %v, want nil", n, got)
+		}
+	}
+	for n := segmentEnd; n <= len(data); n++ {
+		if got := captureTime(data[:n]); got == nil {
+			t.Fatalf("captureTime of %d bytes = nil, want the original time", n)
+		}
+	}
+}
+
+func TestExifTimeTruncated(t *testing.T) {
+	tiff := buildTiff(binary.BigEndian,
+		[]asciiTag{{tagDateTime, "2024:05:01 10:00:00"}},
+		[]asciiTag{{tagDateTimeOriginal, "2024:04:30 08:15:30"}})
+
+	// a tiff cut anywhere must not panic, the strings are its last bytes and no time can be
+	// read before the first one ends
+	firstTimeEnd := len(tiff) - len("2024:04:30 08:15:30\x00")
+	for n := 0; n < len(tiff); n++ {
+		if got := exifTime(tiff[:n]); got != nil && n < firstTimeEnd {
+			t.Fatalf("exifTime of %d bytes = %v, want nil", n, got)
+		}
+	}
+	if got := exifTime(tiff); got == nil {
+		t.Fatal("exifTime of the full tiff = nil")
+	}
+
+	bad := append([]byte{}, tiff...)
+	copy(bad, "XX")
+	if got := exifTime(bad); got != nil {
+		t.Fatalf("exifTime with an unknown byte order = %v, want nil", got)
+	}
+	bad = append([]byte{}, tiff...)
+	binary.BigEndian.PutUint16(bad[2:], 43)
+	if got := exifTime(bad); got != nil {
+		t.Fatalf("exifTime with a wrong magic number = %v, want nil", got)
+	}
+}
+
+func TestReadIFD(t *testing.T) {
+	tiff := buildTiff(binary.LittleEndian, []asciiTag{{tagDateTime, "2024:05:01 10:00:00"}, {0x010F, "ACM"}}, nil)
+
+	tests := []struct {
+		name    string
+		tiff    []byte
+		offset  uint32
+		entries int
+	}{
+		{"full directory", tiff, 8, 2},
+		{"last entry cut", tiff[:8+2+12+6], 8, 1},
+		{"only the count", tiff[:8+2], 8, 0},
+		{"count cut", tiff[:8+1], 8, 0},
+		{"offset past the end", tiff, uint32(len(tiff)), 0},
+		{"offset overflowing", tiff, 0xFFFFFFFF, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := readIFD(tt.tiff, binary.LittleEndian, tt.offset)
+			if len(got.entries) != tt.entries {
+				t.Fatalf("entries = %d, want %d", len(got.entries), tt.entries)
+			}
+		})
+	}
+
+	d := readIFD(tiff, binary.LittleEndian, 8)
+	if got := d.ascii(0x010F); got != "ACM" {
+		t.Fatalf("inline ascii = %q, want ACM", got)
+	}
+	if got := d.ascii(tagDateTime); got != "2024:05:01 10:00:00" {
+		t.Fatalf("ascii = %q, want the date time", got)
+	}
+	if _, ok := d.long(tagExifIFD); ok {
+		t.Fatal("long of a missing tag should not be found")
+	}
+
+	// the string data sits at the end, a directory read from a cut tiff cannot reach it
+	cut := readIFD(tiff[:len(tiff)-4], binary.LittleEndian, 8)
+	if got := cut.ascii(tagDateTime); got != "" {
+		t.Fatalf("ascii past the end = %q, want empty", got)
+	}
+}
+
+func TestInspect(t *testing.T) {
+	small := encodeJpeg(t, 90, 80)
+	large := encodeJpeg(t, 360, 320)
+
+	a, b := Inspect(small), Inspect(large)
+	if a.Hash == nil || b.Hash == nil {
+		t.Fatal("jpeg photos should be hashed")
+	}
+	if d := Distance(*a.Hash, *b.Hash); d > 6 {
+		t.Fatalf("distance of the same picture resized = %d, want at most 6", d)
+	}
+
+	if res := Inspect([]byte("not an image")); res.Hash != nil || res.CapturedAt != nil {
+		t.Fatalf("Inspect of garbage = %+v, want nothing", res)
+	}
+}
+
+func TestInspectSkipsHugePhotos(t *testing.T) {
+	data := encodeJpeg(t, 16, 16)
+
+	// claim 60000x60000 pixels in the start of frame header, only the header is read
+	sof := bytes.Index(data, []byte{0xFF, 0xC0})
+	if sof < 0 {
+		t.Fatal("no start of frame marker")
+	}
+	binary.BigEndian.PutUint16(data[sof+5:], 60000)
+	binary.BigEndian.PutUint16(data[sof+7:], 60000)
+
+	if res := Inspect(data); res.Hash != nil {
+		t.Fatalf("a photo above %d pixels should not be hashed", MaxPixels)
+	}
+}
+
+func ptr(t time.Time) *time.Time {
+	return &t
+}
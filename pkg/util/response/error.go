@@ -1,6 +1,7 @@
 package response
 
 import (
+	"errors"
 	"fmt"
 	"net/http"
 
@@ -21,6 +22,38 @@ func ErrorBuilder(code int, err error, msg string) *MetaError {
 	}
 }
 
+// FieldError describes why a single field of the payload was rejected, code and params
+// are stable for the frontend while message is translated with the request.
+type FieldError struct {
+	Field   string                 `json:"field"`
+	Code    string                 `json:"code"`
+	Message string                 `json:"message"`
+	Params  map[string]interface{} `json:"params,omitempty"`
+}
+
+func FieldErrorBuilder(msg string, fields []FieldError) *MetaError {
+	return &MetaError{
+		Success: false,
+		Data: map[string]interface{}{
+			"error":   "bad_request",
+			"message": msg,
+			"fields":  fields,
+		},
+		Code:         http.StatusBadRequest,
+		errorMessage: errors.New(msg),
+	}
+}
+
+// Fields returns the field errors of e, or nil when it was not built by FieldErrorBuilder.
+func (e *MetaError) Fields() []FieldError {
+	m, ok := e.Data.(map[string]interface{})
+	if !ok {
+		return nil
+	}
+	fields, _ := m["fields"].([]FieldError)
+	return fields
+}
+
 func ErrorResponse(err error) *MetaError {
 	re, ok := err.(*MetaError)
 	if ok {
@@ -53,5 +86,7 @@ func (m *MetaError) SendError(c echo.Context) error {
 		}).Info("This is error code 500")
 	}
 
+	translateMessage(c, m.Data)
+
 	return c.JSON(m.Code, m)
 }
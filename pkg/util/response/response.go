@@ -1,5 +1,11 @@
 package response
 
+import (
+	"cleancare/pkg/i18n"
+
+	"github.com/labstack/echo/v4"
+)
+
 type MetaSuccess struct {
 	Success bool        `json:"success"`
 	Code    int         `json:"code"`
@@ -12,3 +18,31 @@ type MetaError struct {
 	Data         interface{} `json:"data"`
 	errorMessage error
 }
+
+type langContext interface {
+	MessageLang() string
+}
+
+// translateMessage translates the "message" of a response, and of its field errors,
+// into the language of the request.
+func translateMessage(c echo.Context, data interface{}) {
+	m, ok := data.(map[string]interface{})
+	if !ok {
+		return
+	}
+	lang := i18n.FromRequestOr(c.Request(), i18n.MessageDefault)
+	if lc, ok := c.(langContext); ok {
+		lang = lc.MessageLang()
+	}
+	if msg, ok := m["message"].(string); ok {
+		m["message"] = i18n.Message(lang, msg)
+	}
+	if fields, ok := m["fields"].([]FieldError); ok {
+		translated := make([]FieldError, len(fields))
+		for i, v := range fields {
+			v.Message = i18n.Message(lang, v.Message)
+			translated[i] = v
+		}
+		m["fields"] = translated
+	}
+}
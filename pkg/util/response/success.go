@@ -21,6 +21,7 @@ func SuccessResponse(data interface{}) *MetaSuccess {
 }
 
 func (m *MetaSuccess) SendSuccess(c echo.Context) error {
+	translateMessage(c, m.Data)
 	return c.JSON(m.Code, m)
 }
 
@@ -35,6 +36,12 @@ func SendBlobData(c echo.Context, filename string, data bytes.Buffer, format str
 		mimeType = "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
 	case "pdf":
 		mimeType = "application/pdf"
+	case "csv":
+		mimeType = "text/csv; charset=utf-8"
+	case "json":
+		mimeType = "application/json"
+	case "ndjson":
+		mimeType = "application/x-ndjson"
 	}
 	c.Response().Header().Set(echo.HeaderContentType, mimeType)
 	c.Response().Header().Set(echo.HeaderContentDisposition, fmt.Sprintf("attachment; filename=%s", filename))
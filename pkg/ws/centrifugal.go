@@ -313,6 +313,19 @@ func PublishNotificationWithoutTransaction(usersId int, db *gorm.DB, ctx *abstra
 	return nil
 }
 
+// Publish sends data as json on the channel of the user, a user that is not connected misses it.
+func Publish(usersId int, data interface{}) error {
+	if NodeCentrifugal == nil {
+		return nil
+	}
+	byteData, err := json.Marshal(data)
+	if err != nil {
+		return err
+	}
+	_, err = NodeCentrifugal.Publish(strconv.Itoa(usersId), byteData)
+	return err
+}
+
 // func GetUnreadNotification(usersId string, cpfId string, db *gorm.DB) (map[string]interface{}, error) {
 
 // 	data := make(map[string]interface{})